@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/genesis/pkg/genesis/allocation"
+)
+
+// Allocation schedule flags
+var (
+	scheduleUnlocksFile string
+	scheduleCurveFile   string
+)
+
+// NewAllocationsCommand creates the allocations command
+func NewAllocationsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allocations",
+		Short: "Inspect genesis allocations",
+	}
+
+	cmd.AddCommand(allocationsScheduleCmd())
+	return cmd
+}
+
+func allocationsScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule <spec>",
+		Short: "Print the unlock table and circulating-supply curve of a spec",
+		Long: `Expand every allocation of a genesis spec into its unlocks and print two
+CSV tables:
+
+- the unlock table: one row per unlock, ordered by time, then address
+- the supply curve: the cumulative circulating supply at each unlock time
+
+Unlocked (simple) allocations count as circulating at the network start time.
+Vested allocations use their schedule template: linear, monthly, back-loaded,
+custom or tge-linear. Amounts are in nLUX, with a LUX column for reading.
+
+Both tables go to stdout, separated by a blank line, unless --unlocks or
+--curve send them to files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAllocationsSchedule,
+	}
+
+	cmd.Flags().StringVar(&scheduleUnlocksFile, "unlocks", "", "Write the unlock table to this CSV file")
+	cmd.Flags().StringVar(&scheduleCurveFile, "curve", "", "Write the supply curve to this CSV file")
+
+	return cmd
+}
+
+func runAllocationsSchedule(cmd *cobra.Command, args []string) error {
+	spec, err := genesis.LoadSpec(args[0])
+	if err != nil {
+		return err
+	}
+	builder, err := genesis.FromSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	allocs := builder.Allocations()
+	genesisTime := uint64(builder.Network().StartTime.Unix())
+	unlocks := allocation.UnlockTable(allocs, genesisTime)
+	curve := allocation.SupplyCurve(allocs, genesisTime)
+
+	out := cmd.OutOrStdout()
+	printed := false
+	write := func(path string, table func(io.Writer) error) error {
+		if path == "" {
+			if printed {
+				fmt.Fprintln(out)
+			}
+			printed = true
+			return table(out)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer f.Close()
+		if err := table(f); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "✅ Wrote %s\n", path)
+		return f.Close()
+	}
+
+	if err := write(scheduleUnlocksFile, func(w io.Writer) error { return writeUnlockTable(w, unlocks) }); err != nil {
+		return err
+	}
+	return write(scheduleCurveFile, func(w io.Writer) error { return writeSupplyCurve(w, curve) })
+}
+
+// writeUnlockTable writes one CSV row per unlock
+func writeUnlockTable(w io.Writer, unlocks []allocation.Unlock) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"address", "date", "locktime", "amount_nlux", "amount_lux"})
+	for _, u := range unlocks {
+		cw.Write([]string{
+			u.Address,
+			formatLocktime(u.Locktime),
+			strconv.FormatUint(u.Locktime, 10),
+			u.Amount.String(),
+			formatLUX(u.Amount),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeSupplyCurve writes one CSV row per distinct unlock time
+func writeSupplyCurve(w io.Writer, curve []allocation.SupplyPoint) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "locktime", "unlocked_nlux", "circulating_nlux", "circulating_lux", "circulating_percent"})
+	for _, p := range curve {
+		percent := "0%"
+		if p.Total.Sign() > 0 {
+			bp := new(big.Int).Mul(p.Circulating, big.NewInt(int64(allocation.Whole)))
+			bp.Quo(bp, p.Total)
+			percent = allocation.BasisPoints(bp.Uint64()).String()
+		}
+		cw.Write([]string{
+			formatLocktime(p.Locktime),
+			strconv.FormatUint(p.Locktime, 10),
+			p.Unlocked.String(),
+			p.Circulating.String(),
+			formatLUX(p.Circulating),
+			percent,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatLocktime(locktime uint64) string {
+	return time.Unix(int64(locktime), 0).UTC().Format(time.RFC3339)
+}
+
+// formatLUX formats an nLUX amount as a plain LUX number
+func formatLUX(nlux *big.Int) string {
+	s := allocation.FormatLUXAmount(nlux)
+	return s[:len(s)-len(" LUX")]
+}
@@ -30,7 +30,10 @@ Available analyses:
 - blocks: Analyze block data and heights
 - subnet: Analyze subnet-specific data
 - structure: Analyze overall data structure
-- balance: Analyze account balances`,
+- balance: Analyze account balances
+- account: Reconstruct the history of an account
+- stats: Chain activity statistics over time
+- contracts: Catalogue contracts and detect token standards`,
 	}
 
 	// Add subcommands
@@ -40,6 +43,9 @@ Available analyses:
 		newAnalyzeSubnetCmd(),
 		newAnalyzeStructureCmd(),
 		newAnalyzeBalanceCmd(),
+		newAnalyzeAccountCmd(),
+		newAnalyzeStatsCmd(),
+		newAnalyzeContractsCmd(),
 	)
 
 	return analyzeCmd
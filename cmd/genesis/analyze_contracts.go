@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/catalog"
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+// Contract catalogue flags
+var (
+	contractsBlock         int64
+	contractsNamespace     string
+	contractsScanBlocks    bool
+	contractsMetadata      bool
+	contractsFormat        string
+	contractsOutput        string
+	contractsProject       string
+	contractsChain         string
+	contractsTokenSymbol   string
+	contractsNFTSymbol     string
+	contractsProjectOutput string
+)
+
+// newAnalyzeContractsCmd creates the contract catalogue command
+func newAnalyzeContractsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contracts <database-path>",
+		Short: "Catalogue every contract in a state",
+		Long: `Enumerate every account with code at a block's state root.
+
+This produces:
+- Unique bytecodes (deduplicated by code hash) and how many accounts share them
+- ERC-20 / ERC-721 / ERC-1155 detection from function selectors
+- EIP-1967 and EIP-1167 proxy detection, classified by the implementation
+- name, symbol and decimals read with an in-process EVM call
+
+Addresses are recovered from stored trie preimages; use --scan-blocks to
+also recover them from transaction, creation and log addresses.
+
+With --project, the matching token/NFT contracts are written as a scanner
+project config (--project-output), which the scanner package loads through
+Config.ProjectFile instead of the hand-maintained contract maps.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAnalyzeContracts,
+	}
+
+	cmd.Flags().Int64Var(&contractsBlock, "block", -1, "Block whose state to catalogue (-1 = head)")
+	cmd.Flags().StringVar(&contractsNamespace, "namespace", "", "Subnet-EVM key namespace (hex, empty for none)")
+	cmd.Flags().BoolVar(&contractsScanBlocks, "scan-blocks", false, "Recover addresses without preimages from blocks")
+	cmd.Flags().BoolVar(&contractsMetadata, "metadata", true, "Read name/symbol/decimals via EVM calls")
+	cmd.Flags().StringVar(&contractsFormat, "format", "text", "Output format (text, csv, json)")
+	cmd.Flags().StringVarP(&contractsOutput, "output", "o", "", "Output file for csv/json")
+	cmd.Flags().StringVar(&contractsProject, "project", "", "Scanner project to derive a config for (lux, zoo, ...)")
+	cmd.Flags().StringVar(&contractsChain, "chain", "lux", "Chain name used in the project config")
+	cmd.Flags().StringVar(&contractsTokenSymbol, "token-symbol", "", "Symbol of the project's token contract")
+	cmd.Flags().StringVar(&contractsNFTSymbol, "nft-symbol", "", "Symbol of the project's NFT contract")
+	cmd.Flags().StringVar(&contractsProjectOutput, "project-output", "", "Write the derived project config (JSON)")
+
+	return cmd
+}
+
+func runAnalyzeContracts(cmd *cobra.Command, args []string) error {
+	dbPath := args[0]
+
+	switch contractsFormat {
+	case "text":
+	case "csv", "json":
+		if contractsOutput == "" {
+			return fmt.Errorf("--output is required for %s format", contractsFormat)
+		}
+	default:
+		return fmt.Errorf("unknown format: %s", contractsFormat)
+	}
+
+	namespace, err := parseNamespace(contractsNamespace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== Contract Catalogue for %s ===\n", dbPath)
+
+	source, err := catalog.Open(dbPath, namespace)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	var block *uint64
+	if contractsBlock >= 0 {
+		n := uint64(contractsBlock)
+		block = &n
+	}
+	header, err := source.Header(block)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Block: %d, state root: %s\n", header.Number.Uint64(), header.Root.Hex())
+
+	start := time.Now()
+	opts := catalog.Options{
+		Metadata: contractsMetadata,
+		Progress: func(accounts, contracts int) {
+			fmt.Printf("  %d accounts, %d contracts\n", accounts, contracts)
+		},
+	}
+	if contractsScanBlocks {
+		fmt.Println("Collecting candidate addresses from blocks...")
+		opts.Candidates, err = source.CandidateAddresses(header.Number.Uint64(), func(number uint64) {
+			fmt.Printf("  block %d\n", number)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to collect addresses: %w", err)
+		}
+		fmt.Printf("Found %d candidate addresses\n", len(opts.Candidates))
+	}
+
+	cat, err := catalog.Build(source, header, opts)
+	if err != nil {
+		return fmt.Errorf("failed to build catalogue: %w", err)
+	}
+
+	fmt.Printf("\nScanned %d accounts in %s\n", cat.Accounts, time.Since(start).Round(time.Second))
+	fmt.Printf("Contracts:        %d (%d with unknown address)\n", len(cat.Contracts), cat.Unresolved)
+	fmt.Printf("Unique bytecodes: %d\n", len(cat.Codes))
+	for _, standard := range []string{catalog.StandardERC20, catalog.StandardERC721, catalog.StandardERC1155} {
+		fmt.Printf("%-17s %d\n", strings.ToUpper(standard)+":", len(cat.Tokens(standard)))
+	}
+
+	switch contractsFormat {
+	case "csv":
+		if err := cat.ExportToCSV(contractsOutput); err != nil {
+			return fmt.Errorf("failed to export CSV: %w", err)
+		}
+		fmt.Printf("\nCatalogue written to %s\n", contractsOutput)
+	case "json":
+		if err := cat.ExportToJSON(contractsOutput); err != nil {
+			return fmt.Errorf("failed to export JSON: %w", err)
+		}
+		fmt.Printf("\nCatalogue written to %s\n", contractsOutput)
+	default:
+		fmt.Println("\nTokens and proxies:")
+		for _, contract := range cat.Contracts {
+			if contract.Address == nil || (contract.Symbol == "" && contract.Implementation == nil) {
+				continue
+			}
+			decimals := "-"
+			if contract.Decimals != nil {
+				decimals = fmt.Sprintf("%d", *contract.Decimals)
+			}
+			fmt.Printf("  %s %-10s %-30s dec=%-3s %s\n",
+				contract.Address.Hex(), contract.Symbol, contract.Name, decimals, strings.Join(contract.Standards, ","))
+		}
+	}
+
+	if contractsProject != "" {
+		return writeProjectConfig(cat)
+	}
+	return nil
+}
+
+// writeProjectConfig derives a scanner project config from the catalogue
+func writeProjectConfig(cat *catalog.Catalog) error {
+	base, ok := scanner.GetProjectConfigs()[contractsProject]
+	if !ok {
+		return fmt.Errorf("unknown project: %s", contractsProject)
+	}
+	config, err := cat.ProjectConfig(base, contractsChain, contractsTokenSymbol, contractsNFTSymbol)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nProject %s on %s:\n", contractsProject, contractsChain)
+	fmt.Printf("  Token: %s\n", config.TokenContracts[contractsChain])
+	fmt.Printf("  NFT:   %s\n", config.NFTContracts[contractsChain])
+
+	if contractsProjectOutput == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(contractsProjectOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write project config: %w", err)
+	}
+	fmt.Printf("Project config written to %s\n", contractsProjectOutput)
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/analysis"
+)
+
+// Chain statistics flags
+var (
+	statsFrom      uint64
+	statsTo        uint64
+	statsBucket    string
+	statsTop       int
+	statsWorkers   int
+	statsNamespace string
+	statsFormat    string
+	statsOutput    string
+)
+
+// newAnalyzeStatsCmd creates the chain statistics command
+func newAnalyzeStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats <database-path>",
+		Short: "Chain activity statistics over time",
+		Long: `Compute per-day or per-week chain activity from stored blocks.
+
+For every bucket (by header timestamp, UTC) this reports:
+- Block and transaction counts
+- Gas used and average base fee
+- Unique senders
+- Top-level contract creations (contract-creation transactions; contracts
+  created by other contracts are not counted)
+- Top contracts by gas
+
+The chain is read in a single streaming pass; only the current bucket is
+held in memory.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAnalyzeStats,
+	}
+
+	cmd.Flags().Uint64Var(&statsFrom, "from", 0, "First block")
+	cmd.Flags().Uint64Var(&statsTo, "to", 0, "Last block (0 = chain head)")
+	cmd.Flags().StringVar(&statsBucket, "bucket", "day", "Bucket size (day, week)")
+	cmd.Flags().IntVar(&statsTop, "top", 5, "Top contracts listed per bucket")
+	cmd.Flags().IntVar(&statsWorkers, "workers", runtime.NumCPU(), "Number of parallel decoders")
+	cmd.Flags().StringVar(&statsNamespace, "namespace", "", "Subnet-EVM key namespace (hex, empty for none)")
+	cmd.Flags().StringVar(&statsFormat, "format", "text", "Output format (text, csv, json)")
+	cmd.Flags().StringVarP(&statsOutput, "output", "o", "", "Output file for csv/json")
+
+	return cmd
+}
+
+func runAnalyzeStats(cmd *cobra.Command, args []string) error {
+	dbPath := args[0]
+
+	bucket, err := analysis.ParseBucketSize(statsBucket)
+	if err != nil {
+		return err
+	}
+	if statsTop < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+	switch statsFormat {
+	case "text":
+	case "csv", "json":
+		if statsOutput == "" {
+			return fmt.Errorf("--output is required for %s format", statsFormat)
+		}
+	default:
+		return fmt.Errorf("unknown format: %s", statsFormat)
+	}
+
+	fmt.Printf("=== Chain Statistics for %s ===\n", dbPath)
+
+	db, r, err := openChainDB(dbPath, statsNamespace, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	to := statsTo
+	if to == 0 {
+		head, err := r.HeadNumber()
+		if err != nil {
+			return fmt.Errorf("failed to find chain head: %w", err)
+		}
+		to = head
+	}
+	fmt.Printf("Block range: %d - %d, bucket: %s\n", statsFrom, to, bucket)
+
+	start := time.Now()
+	lastReport := start
+	buckets, err := analysis.BuildChainStats(r, analysis.ChainStatsConfig{
+		FromBlock: statsFrom,
+		ToBlock:   to,
+		Bucket:    bucket,
+		Top:       statsTop,
+		Workers:   statsWorkers,
+		Progress: func(number uint64) {
+			if time.Since(lastReport) < 10*time.Second {
+				return
+			}
+			lastReport = time.Now()
+			rate := float64(number-statsFrom+1) / time.Since(start).Seconds()
+			fmt.Printf("  block %d (%.0f blocks/s)\n", number, rate)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute statistics: %w", err)
+	}
+	fmt.Printf("Processed %d blocks into %d buckets in %s\n", to-statsFrom+1, len(buckets), time.Since(start).Round(time.Second))
+
+	switch statsFormat {
+	case "csv":
+		if err := analysis.ExportStatsToCSV(buckets, statsOutput); err != nil {
+			return fmt.Errorf("failed to export CSV: %w", err)
+		}
+		fmt.Printf("\nStatistics written to %s\n", statsOutput)
+	case "json":
+		if err := analysis.ExportToJSON(buckets, statsOutput); err != nil {
+			return fmt.Errorf("failed to export JSON: %w", err)
+		}
+		fmt.Printf("\nStatistics written to %s\n", statsOutput)
+	default:
+		fmt.Printf("\n%-10s %8s %8s %14s %14s %8s %9s\n", "Start", "Blocks", "Txs", "Gas", "AvgBaseFee", "Senders", "Creations")
+		for _, b := range buckets {
+			fmt.Printf("%-10s %8d %8d %14d %14s %8d %9d\n",
+				b.Start.Format("2006-01-02"), b.Blocks, b.Transactions, b.GasUsed, b.AvgBaseFee, b.UniqueSenders, b.TopLevelCreations)
+			for _, c := range b.TopContracts {
+				fmt.Printf("    %s gas=%d calls=%d\n", c.Address.Hex(), c.GasUsed, c.Calls)
+			}
+		}
+	}
+
+	return nil
+}
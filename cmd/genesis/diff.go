@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+)
+
+// Genesis diff flags
+var (
+	diffFormat   string
+	diffLimit    int
+	diffExitCode bool
+)
+
+// NewDiffCommand creates the semantic genesis diff command
+func NewDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <a.json> <b.json>",
+		Short: "Show semantic differences between two genesis files",
+		Long: `Compare two genesis files semantically rather than as text.
+
+The embedded cChainGenesis string is decoded and compared field by field.
+Reported differences:
+- Genesis fields (network ID, start time, stake duration, message, ...)
+- P/X allocations added, removed or changed, by address, with amount deltas
+- Initial stakers added, removed or changed
+- C-Chain config and fee config changes
+- C-Chain alloc balance, code, nonce and storage changes
+- Total supply delta across all chains
+
+With --exit-code the command fails when the files differ, for CI use.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runDiff,
+	}
+
+	cmd.Flags().StringVar(&diffFormat, "format", "text", "Output format (text, json)")
+	cmd.Flags().IntVar(&diffLimit, "limit", 50, "Max allocation/account lines in text output (0 = all)")
+	cmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "Fail if the files differ")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	before, err := genesis.LoadMainGenesis(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := genesis.LoadMainGenesis(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff, err := genesis.DiffGenesis(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff genesis: %w", err)
+	}
+
+	switch diffFormat {
+	case "json":
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "text":
+		fmt.Printf("=== Genesis Diff: %s -> %s ===\n", args[0], args[1])
+		if diff.Empty() {
+			fmt.Println("No semantic differences")
+			return nil
+		}
+		diff.Print(os.Stdout, diffLimit)
+	default:
+		return fmt.Errorf("unknown format: %s", diffFormat)
+	}
+
+	if diffExitCode && !diff.Empty() {
+		return fmt.Errorf("genesis files differ")
+	}
+	return nil
+}
@@ -26,13 +26,18 @@ This command creates:
 Output structure:
   configs/{network}/P/genesis.json
   configs/{network}/C/genesis.json
-  configs/{network}/X/genesis.json`,
+  configs/{network}/X/genesis.json
+
+With --spec, everything (network, allocations, stakers, C-Chain config and
+alloc, X-Chain airdrops and subnets) comes from a YAML/JSON genesis spec
+instead of the built-in network definitions.`,
 		RunE: runGenerate,
 	}
 	
 	cmd.Flags().String("network", "mainnet", "Network to generate for (mainnet/testnet)")
 	cmd.Flags().String("output", "", "Output directory (default: configs/{network})")
 	cmd.Flags().Bool("with-allocations", true, "Include account allocations")
+	cmd.Flags().String("spec", "", "Genesis spec file (YAML or JSON)")
 	
 	return cmd
 }
@@ -41,6 +46,11 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	network, _ := cmd.Flags().GetString("network")
 	outputDir, _ := cmd.Flags().GetString("output")
 	withAllocations, _ := cmd.Flags().GetBool("with-allocations")
+	specPath, _ := cmd.Flags().GetString("spec")
+
+	if specPath != "" {
+		return runGenerateFromSpec(specPath, outputDir)
+	}
 	
 	if outputDir == "" {
 		outputDir = filepath.Join("configs", network)
@@ -6,12 +6,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis/chains"
 )
 
 // generate8ChainsCmd creates the command for generating 8-chain genesis
@@ -22,7 +23,12 @@ func generate8ChainsCmd() *cobra.Command {
 		stakingDuration time.Duration
 		aiAgentCount    int
 		bridgeThreshold int
+		keysPath        string
 		mpcParticipants int
+		mpcMode         string
+		mpcEndpoints    []string
+		mpcShareDir     string
+		mpcPasswords    string
 		zkCircuitCount  int
 		cpuAffinity     bool
 	)
@@ -42,6 +48,17 @@ This command creates genesis files for:
 - Q-Chain (Quantum): Quantum-safe cryptography
 - Z-Chain (ZK): Zero-knowledge proof circuits
 
+The B, M, Q and Z chains are built from the key material in --keys (MPC
+signer public keys, bridge contracts, treasury, post-quantum validator keys
+and the ZK trusted setup); every chain genesis is validated before it is
+written.
+
+With --mpc-participants the B/M-Chain signer key is generated instead, by a
+simulated DKG or a trusted dealer for --bridge-threshold of the participants.
+Each participant's share is written to its own file encrypted with its
+password from --mpc-passwords, and the aggregate and participant public keys
+go into the genesis.
+
 The generated genesis files will be placed in the output directory
 with the proper structure expected by luxd.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,39 +94,45 @@ with the proper structure expected by luxd.`,
 				return fmt.Errorf("failed to generate X-Chain genesis: %w", err)
 			}
 			
-			// Generate custom chain genesis files
-			customChains := map[string]func(string, map[string]interface{}) error{
-				"A": func(path string, params map[string]interface{}) error {
-					return generateAChainGenesis(path, params["aiAgentCount"].(int))
-				},
-				"B": func(path string, params map[string]interface{}) error {
-					return generateBChainGenesis(path, params["bridgeThreshold"].(int), params["mpcParticipants"].(int))
-				},
-				"M": func(path string, params map[string]interface{}) error {
-					return generateMChainGenesis(path, params["mpcParticipants"].(int), params["bridgeThreshold"].(int))
-				},
-				"Q": func(path string, params map[string]interface{}) error {
-					return generateQChainGenesis(path)
-				},
-				"Z": func(path string, params map[string]interface{}) error {
-					return generateZChainGenesis(path, params["zkCircuitCount"].(int))
-				},
+			// Generate custom chain genesis files from the real key material
+			keys, err := chains.LoadKeyMaterial(keysPath)
+			if err != nil {
+				return err
 			}
-			
-			params := map[string]interface{}{
-				"aiAgentCount":    aiAgentCount,
-				"bridgeThreshold": bridgeThreshold,
-				"mpcParticipants": mpcParticipants,
-				"zkCircuitCount":  zkCircuitCount,
+			if mpcParticipants > 0 {
+				if mpcShareDir == "" {
+					mpcShareDir = filepath.Join(outputPath, "mpc-shares")
+				}
+				if err := generateMPCKeys(keys, mpcKeygenOptions{
+					mode:          mpcMode,
+					threshold:     bridgeThreshold,
+					participants:  mpcParticipants,
+					endpoints:     mpcEndpoints,
+					shareDir:      mpcShareDir,
+					passwordsFile: mpcPasswords,
+				}); err != nil {
+					return err
+				}
+			}
+			if len(keys.Signers) == 0 {
+				return fmt.Errorf("no MPC signers: list them in %s or generate them with --mpc-participants", keysPath)
+			}
+			customChains := map[chains.Type]chains.Genesis{
+				chains.AChain: buildAChainGenesis(aiAgentCount),
+				chains.BChain: buildBChainGenesis(keys, bridgeThreshold),
+				chains.MChain: buildMChainGenesis(keys, bridgeThreshold),
+				chains.QChain: buildQChainGenesis(keys),
+				chains.ZChain: buildZChainGenesis(keys, zkCircuitCount),
 			}
 			
-			for chain, genFunc := range customChains {
-				chainPath := filepath.Join(outputPath, chain)
+			for _, chain := range chains.Types {
+				chainPath := filepath.Join(outputPath, string(chain))
 				if err := os.MkdirAll(chainPath, 0755); err != nil {
 					return fmt.Errorf("failed to create %s-Chain directory: %w", chain, err)
 				}
 				
-				if err := genFunc(chainPath, params); err != nil {
+				fmt.Printf("📝 Generating %s-Chain genesis...\n", chain)
+				if err := chains.Save(chain, customChains[chain], filepath.Join(chainPath, "genesis.json")); err != nil {
 					return fmt.Errorf("failed to generate %s-Chain genesis: %w", chain, err)
 				}
 			}
@@ -140,10 +163,16 @@ with the proper structure expected by luxd.`,
 	cmd.Flags().Uint64Var(&initialStake, "stake", 2000, "Initial stake per validator in LUX")
 	cmd.Flags().DurationVar(&stakingDuration, "staking-duration", 365*24*time.Hour, "Staking duration")
 	cmd.Flags().IntVar(&aiAgentCount, "ai-agents", 10, "Number of AI agents for A-Chain")
-	cmd.Flags().IntVar(&bridgeThreshold, "bridge-threshold", 5, "Threshold for bridge operations")
-	cmd.Flags().IntVar(&mpcParticipants, "mpc-participants", 8, "Number of MPC participants")
+	cmd.Flags().IntVar(&bridgeThreshold, "bridge-threshold", 5, "Threshold for bridge and MPC signing (at most the number of signers)")
+	cmd.Flags().StringVar(&keysPath, "keys", "", "Key material file for the B, M, Q and Z chains (JSON)")
+	cmd.Flags().IntVar(&mpcParticipants, "mpc-participants", 0, "Generate a new MPC signer key for this many participants (0 = use the signers in --keys)")
+	cmd.Flags().StringVar(&mpcMode, "mpc-mode", "dkg", "MPC key generation: dkg (simulated distributed key generation) or dealer (trusted dealer)")
+	cmd.Flags().StringSliceVar(&mpcEndpoints, "mpc-endpoints", nil, "Endpoint of each MPC participant, in order (host:port)")
+	cmd.Flags().StringVar(&mpcShareDir, "mpc-share-dir", "", "Directory for the encrypted key shares (default: <output>/mpc-shares)")
+	cmd.Flags().StringVar(&mpcPasswords, "mpc-passwords", "", "File with one share password per participant, or a single shared one")
 	cmd.Flags().IntVar(&zkCircuitCount, "zk-circuits", 5, "Number of ZK circuits")
 	cmd.Flags().BoolVar(&cpuAffinity, "cpu-affinity", false, "Generate CPU affinity configuration")
+	cmd.MarkFlagRequired("keys")
 	
 	return cmd
 }
@@ -313,295 +342,189 @@ func generate8ChainsXChainGenesis(outputPath, network string) error {
 	return os.WriteFile(genesisFile, data, 0644)
 }
 
-// Custom chain genesis generators
+// Custom chain genesis builders
 
-func generateAChainGenesis(outputPath string, agentCount int) error {
-	fmt.Println("📝 Generating A-Chain (AI) genesis...")
-	
-	agents := make([]map[string]interface{}, agentCount)
-	for i := 0; i < agentCount; i++ {
-		agents[i] = map[string]interface{}{
-			"id": fmt.Sprintf("agent-%03d", i),
-			"name": fmt.Sprintf("AI Agent %d", i),
-			"modelType": "llm-7b",
-			"gpuProvider": fmt.Sprintf("gpu-pool-%d", i%3),
-			"reputation": 100,
-			"stake": 1000000000000, // 1000 LUX in nLUX
+func buildAChainGenesis(agentCount int) *chains.AChainGenesis {
+	providers := []chains.GPUProvider{
+		{ID: "gpu-pool-0", Capacity: 1000, Type: "A100", Location: "us-east-1"},
+		{ID: "gpu-pool-1", Capacity: 500, Type: "H100", Location: "us-west-2"},
+		{ID: "gpu-pool-2", Capacity: 750, Type: "RTX4090", Location: "eu-central-1"},
+	}
+	
+	agents := make([]chains.Agent, agentCount)
+	for i := range agents {
+		agents[i] = chains.Agent{
+			ID:          fmt.Sprintf("agent-%03d", i),
+			Name:        fmt.Sprintf("AI Agent %d", i),
+			ModelType:   "llm-7b",
+			GPUProvider: providers[i%len(providers)].ID,
+			Reputation:  100,
+			Stake:       1000000000000, // 1000 LUX in nLUX
 		}
 	}
 	
-	genesis := map[string]interface{}{
-		"config": map[string]interface{}{
-			"blockTime": 2000, // 2 seconds
-			"minGasPrice": 1000000000,
-			"maxGasLimit": 15000000,
-			"targetGasUsage": 10000000,
-			"minAgentStake": 1000000000000,
-			"taskTimeout": 300,
-			"reputationDecay": 0.99,
+	return &chains.AChainGenesis{
+		Config: chains.AChainConfig{
+			BlockTime:       2000, // 2 seconds
+			MinGasPrice:     1000000000,
+			MaxGasLimit:     15000000,
+			TargetGasUsage:  10000000,
+			MinAgentStake:   1000000000000,
+			TaskTimeout:     300,
+			ReputationDecay: 0.99,
 		},
-		"agents": agents,
-		"gpuProviders": []map[string]interface{}{
-			{
-				"id": "gpu-pool-0",
-				"capacity": 1000,
-				"type": "A100",
-				"location": "us-east-1",
-			},
-			{
-				"id": "gpu-pool-1",
-				"capacity": 500,
-				"type": "H100",
-				"location": "us-west-2",
-			},
-			{
-				"id": "gpu-pool-2",
-				"capacity": 750,
-				"type": "RTX4090",
-				"location": "eu-central-1",
-			},
-		},
-		"modelRegistry": []map[string]interface{}{
-			{
-				"id": "llm-7b",
-				"name": "LLM 7B Base",
-				"parameters": 7000000000,
-				"gpuMemory": 16,
-			},
+		Agents:       agents,
+		GPUProviders: providers,
+		ModelRegistry: []chains.Model{
+			{ID: "llm-7b", Name: "LLM 7B Base", Parameters: 7000000000, GPUMemory: 16},
 		},
 	}
-	
-	data, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return err
-	}
-	
-	genesisFile := filepath.Join(outputPath, "genesis.json")
-	return os.WriteFile(genesisFile, data, 0644)
 }
 
-func generateBChainGenesis(outputPath string, threshold, mpcNodes int) error {
-	fmt.Println("📝 Generating B-Chain (Bridge) genesis...")
-	
-	nodes := make([]map[string]interface{}, mpcNodes)
-	for i := 0; i < mpcNodes; i++ {
-		nodes[i] = map[string]interface{}{
-			"id": fmt.Sprintf("mpc-node-%d", i),
-			"index": i,
-			"publicKey": fmt.Sprintf("0x%064x", i),
-			"stake": 1000000000000,
-			"reputation": 100,
-			"endpoint": fmt.Sprintf("mpc-node-%d:9651", i),
+func buildBChainGenesis(keys *chains.KeyMaterial, threshold int) *chains.BChainGenesis {
+	nodes := make([]chains.MPCNode, len(keys.Signers))
+	for i, signer := range keys.Signers {
+		nodes[i] = chains.MPCNode{
+			ID:         signer.ID,
+			Index:      i,
+			PublicKey:  signer.PublicKey,
+			Stake:      1000000000000,
+			Reputation: 100,
+			Endpoint:   signer.Endpoint,
 		}
 	}
 	
-	genesis := map[string]interface{}{
-		"config": map[string]interface{}{
-			"blockTime": 3000,
-			"minSignatures": threshold,
-			"bridgeTimeout": 600,
-			"maxBridgeAmount": 1000000000000000,
-			"bridgeFee": 0.001,
-		},
-		"bridges": []map[string]interface{}{
-			{
-				"id": "eth-mainnet",
-				"targetChain": "ethereum",
-				"chainId": 1,
-				"threshold": threshold,
-				"address": "0x0000000000000000000000000000000000000000",
-				"status": "active",
-			},
-			{
-				"id": "bsc-mainnet",
-				"targetChain": "bsc",
-				"chainId": 56,
-				"threshold": threshold,
-				"address": "0x0000000000000000000000000000000000000000",
-				"status": "active",
-			},
-		},
-		"mpcNodes": nodes,
-		"treasury": map[string]interface{}{
-			"address": "0x0000000000000000000000000000000000000001",
-			"balance": 10000000000000,
-		},
+	bridges := make([]chains.Bridge, len(keys.Bridges))
+	for i, b := range keys.Bridges {
+		bridges[i] = chains.Bridge{
+			ID:          b.ID,
+			TargetChain: b.TargetChain,
+			ChainID:     b.ChainID,
+			Threshold:   threshold,
+			Address:     b.Address,
+			Status:      "active",
+		}
 	}
 	
-	data, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return err
+	return &chains.BChainGenesis{
+		Config: chains.BChainConfig{
+			BlockTime:       3000,
+			MinSignatures:   threshold,
+			BridgeTimeout:   600,
+			MaxBridgeAmount: 1000000000000000,
+			BridgeFee:       0.001,
+		},
+		Bridges:      bridges,
+		MPCNodes:     nodes,
+		ThresholdKey: keys.ThresholdKey,
+		Treasury: chains.Treasury{
+			Address: keys.Treasury,
+			Balance: 10000000000000,
+		},
 	}
-	
-	genesisFile := filepath.Join(outputPath, "genesis.json")
-	return os.WriteFile(genesisFile, data, 0644)
 }
 
-func generateMChainGenesis(outputPath string, participants, threshold int) error {
-	fmt.Println("📝 Generating M-Chain (MPC) genesis...")
-	
-	nodes := make([]map[string]interface{}, participants)
-	for i := 0; i < participants; i++ {
-		nodes[i] = map[string]interface{}{
-			"id": fmt.Sprintf("mpc-%03d", i),
-			"publicKey": fmt.Sprintf("0x%064x", i),
-			"stake": 1000000000000,
+func buildMChainGenesis(keys *chains.KeyMaterial, threshold int) *chains.MChainGenesis {
+	nodes := make([]chains.MPCParticipant, len(keys.Signers))
+	for i, signer := range keys.Signers {
+		nodes[i] = chains.MPCParticipant{
+			ID:        signer.ID,
+			PublicKey: signer.PublicKey,
+			Stake:     1000000000000,
 		}
 	}
 	
-	genesis := map[string]interface{}{
-		"config": map[string]interface{}{
-			"blockTime": 2000,
-			"mpcThreshold": threshold,
-			"sessionLength": 100,
-			"keyGenTimeout": 60,
-			"signTimeout": 30,
-			"minParticipants": threshold,
-			"maxParticipants": participants,
-			"slashingPenalty": 100000000000,
-			"sessionReward": 10000000000,
+	return &chains.MChainGenesis{
+		Config: chains.MChainConfig{
+			BlockTime:       2000,
+			MPCThreshold:    threshold,
+			SessionLength:   100,
+			KeyGenTimeout:   60,
+			SignTimeout:     30,
+			MinParticipants: threshold,
+			MaxParticipants: len(nodes),
+			SlashingPenalty: 100000000000,
+			SessionReward:   10000000000,
 		},
-		"initialNodes": nodes,
-		"protocols": []map[string]interface{}{
-			{
-				"id": "gg20",
-				"name": "Gennaro-Goldfeder 2020",
-				"threshold": threshold,
-				"supported": true,
-			},
-			{
-				"id": "frost",
-				"name": "FROST",
-				"threshold": threshold,
-				"supported": true,
-			},
+		InitialNodes: nodes,
+		ThresholdKey: keys.ThresholdKey,
+		Protocols: []chains.Protocol{
+			{ID: "gg20", Name: "Gennaro-Goldfeder 2020", Threshold: threshold, Supported: true},
+			{ID: "frost", Name: "FROST", Threshold: threshold, Supported: true},
 		},
-		"sessions": []interface{}{},
-	}
-	
-	data, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return err
+		Sessions: []json.RawMessage{},
 	}
-	
-	genesisFile := filepath.Join(outputPath, "genesis.json")
-	return os.WriteFile(genesisFile, data, 0644)
 }
 
-func generateQChainGenesis(outputPath string) error {
-	fmt.Println("📝 Generating Q-Chain (Quantum) genesis...")
-	
-	genesis := map[string]interface{}{
-		"config": map[string]interface{}{
-			"blockTime": 2000,
-			"signatureAlgo": "sphincs+",
-			"hashFunction": "sha3-256",
-			"securityLevel": 256,
-			"publicKeySize": 64,
-			"signatureSize": 49856,
-			"migrationStart": time.Now().Add(365 * 24 * time.Hour).Unix(),
+func buildQChainGenesis(keys *chains.KeyMaterial) *chains.QChainGenesis {
+	validators := keys.QuantumValidators
+	if validators == nil {
+		validators = []chains.QuantumValidator{}
+	}
+	
+	return &chains.QChainGenesis{
+		Config: chains.QChainConfig{
+			BlockTime:      2000,
+			SignatureAlgo:  "sphincs+",
+			HashFunction:   "sha3-256",
+			SecurityLevel:  256,
+			PublicKeySize:  64,
+			SignatureSize:  49856,
+			MigrationStart: time.Now().Add(365 * 24 * time.Hour).Unix(),
 		},
-		"validators": []interface{}{},
-		"quantumAlgorithms": []map[string]interface{}{
-			{
-				"id": "sphincs+",
-				"name": "SPHINCS+",
-				"type": "signature",
-				"securityLevel": 256,
-				"status": "active",
-			},
-			{
-				"id": "dilithium",
-				"name": "CRYSTALS-Dilithium",
-				"type": "signature", 
-				"securityLevel": 256,
-				"status": "experimental",
-			},
-			{
-				"id": "kyber",
-				"name": "CRYSTALS-Kyber",
-				"type": "kem",
-				"securityLevel": 256,
-				"status": "experimental",
-			},
+		Validators: validators,
+		QuantumAlgorithms: []chains.QuantumAlgorithm{
+			{ID: "sphincs+", Name: "SPHINCS+", Type: "signature", SecurityLevel: 256, Status: "active"},
+			{ID: "dilithium", Name: "CRYSTALS-Dilithium", Type: "signature", SecurityLevel: 256, Status: "experimental"},
+			{ID: "kyber", Name: "CRYSTALS-Kyber", Type: "kem", SecurityLevel: 256, Status: "experimental"},
 		},
-		"migrationPlan": map[string]interface{}{
-			"phase1Start": time.Now().Add(180 * 24 * time.Hour).Unix(),
-			"phase2Start": time.Now().Add(365 * 24 * time.Hour).Unix(),
-			"mandatory": time.Now().Add(730 * 24 * time.Hour).Unix(),
+		MigrationPlan: chains.MigrationPlan{
+			Phase1Start: time.Now().Add(180 * 24 * time.Hour).Unix(),
+			Phase2Start: time.Now().Add(365 * 24 * time.Hour).Unix(),
+			Mandatory:   time.Now().Add(730 * 24 * time.Hour).Unix(),
 		},
 	}
-	
-	data, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return err
-	}
-	
-	genesisFile := filepath.Join(outputPath, "genesis.json")
-	return os.WriteFile(genesisFile, data, 0644)
 }
 
-func generateZChainGenesis(outputPath string, circuitCount int) error {
-	fmt.Println("📝 Generating Z-Chain (ZK) genesis...")
-	
-	circuits := make([]map[string]interface{}, circuitCount)
+func buildZChainGenesis(keys *chains.KeyMaterial, circuitCount int) *chains.ZChainGenesis {
 	circuitTypes := []string{"transfer", "mint", "burn", "swap", "stake"}
 	
-	for i := 0; i < circuitCount; i++ {
-		circuits[i] = map[string]interface{}{
-			"id": fmt.Sprintf("circuit-%03d", i),
-			"name": fmt.Sprintf("ZK %s Circuit", circuitTypes[i%len(circuitTypes)]),
-			"type": circuitTypes[i%len(circuitTypes)],
-			"proofSystem": "plonk",
-			"constraintSize": 100000 + i*10000,
-			"setupComplete": true,
-			"srsHash": fmt.Sprintf("0x%064x", i),
+	// PLONK's setup is universal: every circuit uses the ceremony's SRS
+	circuits := make([]chains.Circuit, circuitCount)
+	for i := range circuits {
+		circuitType := circuitTypes[i%len(circuitTypes)]
+		circuits[i] = chains.Circuit{
+			ID:             fmt.Sprintf("circuit-%03d", i),
+			Name:           fmt.Sprintf("ZK %s Circuit", circuitType),
+			Type:           circuitType,
+			ProofSystem:    "plonk",
+			ConstraintSize: uint64(100000 + i*10000),
+			SetupComplete:  true,
+			SRSHash:        keys.TrustedSetup.SRSHash,
 		}
 	}
 	
-	genesis := map[string]interface{}{
-		"config": map[string]interface{}{
-			"blockTime": 4000,
-			"proofSystem": "plonk",
-			"curveType": "bn254",
-			"maxProofSize": 2048,
-			"maxConstraints": 1000000,
-			"proofGenTimeout": 30,
-			"proofVerifyTime": 10,
-			"recursionDepth": 3,
-			"batchSize": 100,
-		},
-		"circuits": circuits,
-		"trustedSetup": map[string]interface{}{
-			"ceremonyDate": time.Now().Unix(),
-			"participants": 100,
-			"srsHash": "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-			"verified": true,
+	return &chains.ZChainGenesis{
+		Config: chains.ZChainConfig{
+			BlockTime:       4000,
+			ProofSystem:     "plonk",
+			CurveType:       "bn254",
+			MaxProofSize:    2048,
+			MaxConstraints:  1000000,
+			ProofGenTimeout: 30,
+			ProofVerifyTime: 10,
+			RecursionDepth:  3,
+			BatchSize:       100,
 		},
-		"verifierRegistry": []map[string]interface{}{
-			{
-				"id": "plonk-verifier",
-				"version": "1.0.0",
-				"gasUsage": 300000,
-			},
+		Circuits:     circuits,
+		TrustedSetup: keys.TrustedSetup,
+		VerifierRegistry: []chains.Verifier{
+			{ID: "plonk-verifier", Version: "1.0.0", GasUsage: 300000},
 		},
-		"privacyPools": []map[string]interface{}{
-			{
-				"id": "default-pool",
-				"minDeposit": 1000000000,
-				"maxDeposit": 1000000000000000,
-				"participants": 0,
-			},
+		PrivacyPools: []chains.PrivacyPool{
+			{ID: "default-pool", MinDeposit: 1000000000, MaxDeposit: 1000000000000000},
 		},
 	}
-	
-	data, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return err
-	}
-	
-	genesisFile := filepath.Join(outputPath, "genesis.json")
-	return os.WriteFile(genesisFile, data, 0644)
 }
 
 func generateCPUAffinityConfig(outputPath string) error {
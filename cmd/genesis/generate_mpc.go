@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/genesis/pkg/genesis/chains"
+	"github.com/luxfi/genesis/pkg/genesis/mpc"
+)
+
+// mpcKeygenOptions configures the B/M-Chain signer key generation
+type mpcKeygenOptions struct {
+	mode          string
+	threshold     int
+	participants  int
+	endpoints     []string
+	shareDir      string
+	passwordsFile string
+}
+
+// generateMPCKeys runs a trusted-dealer or simulated DKG key generation,
+// writes each participant's encrypted share and replaces the signers and
+// threshold key of the key material with the result
+func generateMPCKeys(keys *chains.KeyMaterial, opts mpcKeygenOptions) error {
+	mode, err := mpc.ParseMode(opts.mode)
+	if err != nil {
+		return err
+	}
+	if len(opts.endpoints) != opts.participants {
+		return fmt.Errorf("need %d MPC endpoints, got %d", opts.participants, len(opts.endpoints))
+	}
+	passwords, err := readSharePasswords(opts.passwordsFile, opts.participants)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔑 Generating %d-of-%d MPC key (%s)...\n", opts.threshold, opts.participants, mode)
+	ks, err := mpc.Generate(mode, opts.threshold, opts.participants, nil)
+	if err != nil {
+		return fmt.Errorf("failed to generate MPC key: %w", err)
+	}
+
+	ids := make([]string, opts.participants)
+	keys.Signers = make([]chains.SignerKey, opts.participants)
+	for i := range ks.Shares {
+		ids[i] = fmt.Sprintf("mpc-node-%d", i)
+		keys.Signers[i] = chains.SignerKey{
+			ID:        ids[i],
+			PublicKey: mpc.EncodePublicKey(ks.Shares[i].PublicKey()),
+			Endpoint:  strings.TrimSpace(opts.endpoints[i]),
+		}
+	}
+	keys.ThresholdKey = &chains.ThresholdKey{
+		Scheme:       "secp256k1",
+		PublicKey:    mpc.EncodePublicKey(ks.PublicKey),
+		Address:      mpc.Address(ks.PublicKey),
+		Threshold:    ks.Threshold,
+		Participants: ks.Participants,
+	}
+
+	paths, err := ks.WriteShareFiles(opts.shareDir, ids, passwords)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   Aggregate key: %s (%s)\n", keys.ThresholdKey.PublicKey, keys.ThresholdKey.Address)
+	fmt.Printf("   Encrypted shares: %d in %s\n", len(paths), opts.shareDir)
+	return nil
+}
+
+// readSharePasswords reads one password per line: either one per
+// participant, in order, or a single password used for every share
+func readSharePasswords(path string, participants int) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("--mpc-passwords is required to encrypt the key shares")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password file: %w", err)
+	}
+	defer file.Close()
+
+	var passwords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			passwords = append(passwords, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password file: %w", err)
+	}
+
+	switch len(passwords) {
+	case participants:
+		return passwords, nil
+	case 1:
+		shared := make([]string, participants)
+		for i := range shared {
+			shared[i] = passwords[0]
+		}
+		return shared, nil
+	}
+	return nil, fmt.Errorf("password file has %d passwords, expected 1 or %d", len(passwords), participants)
+}
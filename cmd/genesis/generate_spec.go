@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/genesis/pkg/genesis/allocation"
+)
+
+// runGenerateFromSpec writes the P, C and X-Chain genesis files and any
+// subnet configs for a genesis spec
+func runGenerateFromSpec(specPath, outputDir string) error {
+	spec, err := genesis.LoadSpec(specPath)
+	if err != nil {
+		return err
+	}
+
+	builder, err := genesis.FromSpec(spec)
+	if err != nil {
+		return fmt.Errorf("failed to apply spec: %w", err)
+	}
+	network := builder.Network()
+
+	if outputDir == "" {
+		outputDir = filepath.Join("configs", strings.ToLower(strings.ReplaceAll(network.Name, " ", "-")))
+	}
+
+	fmt.Printf("🔧 Generating genesis files from %s\n", specPath)
+	fmt.Printf("   Network: %s (ID %d, HRP %s, chain ID %d)\n", network.Name, network.ID, network.HRP, network.ChainID)
+	fmt.Printf("   Output directory: %s\n", outputDir)
+
+	for _, chain := range []string{"P", "C", "X"} {
+		if err := os.MkdirAll(filepath.Join(outputDir, chain), 0755); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", chain, err)
+		}
+	}
+
+	mainGenesis, err := builder.Build()
+	if err != nil {
+		return fmt.Errorf("failed to build genesis: %w", err)
+	}
+	if err := builder.SaveToFile(mainGenesis, filepath.Join(outputDir, "P", "genesis.json")); err != nil {
+		return fmt.Errorf("failed to write P-Chain genesis: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "C", "genesis.json"), []byte(mainGenesis.CChainGenesis), 0644); err != nil {
+		return fmt.Errorf("failed to write C-Chain genesis: %w", err)
+	}
+	if upgrades := builder.CChainUpgrades(); !upgrades.Empty() {
+		if err := genesis.SaveJSON(upgrades, filepath.Join(outputDir, "C", "upgrade.json")); err != nil {
+			return fmt.Errorf("failed to write C-Chain upgrades: %w", err)
+		}
+	}
+
+	xGenesis, err := builder.BuildXChain()
+	if err != nil {
+		return fmt.Errorf("failed to build X-Chain genesis: %w", err)
+	}
+	data, err := json.MarshalIndent(xGenesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "X", "genesis.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write X-Chain genesis: %w", err)
+	}
+
+	for _, subnet := range builder.Subnets() {
+		if err := writeSubnet(outputDir, subnet); err != nil {
+			return fmt.Errorf("failed to write subnet %s: %w", subnet.Name, err)
+		}
+	}
+
+	fmt.Printf("   Allocations: %d (%s)\n", builder.GetAllocationCount(), allocation.FormatLUXAmount(builder.GetTotalSupply()))
+	fmt.Printf("   Stakers:     %d\n", len(mainGenesis.InitialStakers))
+	fmt.Printf("   Subnets:     %d\n", len(builder.Subnets()))
+
+	if intended, ok := spec.IntendedSupply(); ok {
+		report, err := builder.ReconcileSupply(intended)
+		if err != nil {
+			return fmt.Errorf("failed to reconcile supply: %w", err)
+		}
+		fmt.Println()
+		report.Print(os.Stdout)
+		if !report.Balanced() {
+			return fmt.Errorf("genesis supply %s does not match totalSupply %s", report.Total, report.Intended)
+		}
+	}
+
+	fmt.Println("✅ Genesis generation complete!")
+	fmt.Printf("   Files created in: %s\n", outputDir)
+
+	return nil
+}
+
+// writeSubnet writes a subnet's config and, if the spec names one, its genesis
+func writeSubnet(outputDir string, subnet genesis.SubnetSpec) error {
+	dir := filepath.Join(outputDir, "subnets", subnet.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	vmID := subnet.VMID
+	if vmID == "" {
+		vmID = "evm"
+	}
+	subnetConfig := &genesis.SubnetConfig{
+		SubnetID:    fmt.Sprintf("%s-subnet", subnet.Name),
+		ChainID:     subnet.ChainID,
+		VMID:        vmID,
+		GenesisFile: "genesis.json",
+	}
+	if err := genesis.SaveSubnetConfig(subnetConfig, filepath.Join(dir, "config.json")); err != nil {
+		return err
+	}
+
+	if subnet.Genesis == "" {
+		return nil
+	}
+	data, err := os.ReadFile(subnet.Genesis)
+	if err != nil {
+		return fmt.Errorf("failed to read subnet genesis: %w", err)
+	}
+	return genesis.SaveSubnetGenesis(data, filepath.Join(dir, "genesis.json"))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+)
+
+// Genesis ID flags
+var (
+	idsSpec   string
+	idsExpect string
+	idsWrite  string
+	idsFormat string
+)
+
+// NewIDsCommand creates the genesis ID computation command
+func NewIDsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ids [genesis.json]",
+		Short: "Compute genesis and blockchain IDs",
+		Long: `Compute the IDs a node derives from a genesis: the genesis ID and P-Chain
+genesis block ID, the LUX asset ID, the P/X/C blockchain IDs and the C-Chain
+genesis block hash. The node's own genesis parsing code is used, so the
+result matches what luxd computes at startup.
+
+Pass a genesis.json, or --spec to build one from a genesis spec first.
+
+With --expect, the command fails if any ID differs from the given file
+(written earlier with --write), so CI catches unintended ID drift.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runIDs,
+	}
+
+	cmd.Flags().StringVar(&idsSpec, "spec", "", "Build the genesis from a spec file instead")
+	cmd.Flags().StringVar(&idsExpect, "expect", "", "Fail unless the IDs match this file")
+	cmd.Flags().StringVar(&idsWrite, "write", "", "Write the IDs to this file")
+	cmd.Flags().StringVar(&idsFormat, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runIDs(cmd *cobra.Command, args []string) error {
+	if (len(args) == 1) == (idsSpec != "") {
+		return fmt.Errorf("pass either a genesis file or --spec")
+	}
+
+	var chainIDs *genesis.ChainIDs
+	if idsSpec != "" {
+		spec, err := genesis.LoadSpec(idsSpec)
+		if err != nil {
+			return err
+		}
+		builder, err := genesis.FromSpec(spec)
+		if err != nil {
+			return fmt.Errorf("failed to apply spec: %w", err)
+		}
+		chainIDs, err = builder.ComputeIDs()
+		if err != nil {
+			return fmt.Errorf("failed to compute IDs: %w", err)
+		}
+	} else {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read genesis: %w", err)
+		}
+		chainIDs, err = genesis.ComputeIDs(data)
+		if err != nil {
+			return fmt.Errorf("failed to compute IDs: %w", err)
+		}
+	}
+
+	switch idsFormat {
+	case "json":
+		data, err := json.MarshalIndent(chainIDs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "text":
+		fmt.Println("=== Genesis IDs ===")
+		fmt.Printf("Network ID:           %d\n", chainIDs.NetworkID)
+		fmt.Printf("Genesis ID:           %s\n", chainIDs.GenesisID)
+		fmt.Printf("Genesis block ID:     %s\n", chainIDs.GenesisBlockID)
+		fmt.Printf("LUX asset ID:         %s\n", chainIDs.LUXAssetID)
+		fmt.Printf("P-Chain ID:           %s\n", chainIDs.PChainID)
+		fmt.Printf("X-Chain ID:           %s\n", chainIDs.XChainID)
+		fmt.Printf("C-Chain ID:           %s\n", chainIDs.CChainID)
+		fmt.Printf("C-Chain genesis hash: %s\n", chainIDs.CChainGenesisHash.Hex())
+	default:
+		return fmt.Errorf("unknown format: %s", idsFormat)
+	}
+
+	if idsWrite != "" {
+		if err := genesis.SaveChainIDs(chainIDs, idsWrite); err != nil {
+			return fmt.Errorf("failed to write IDs: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "IDs written to %s\n", idsWrite)
+	}
+
+	if idsExpect == "" {
+		return nil
+	}
+	expected, err := genesis.LoadChainIDs(idsExpect)
+	if err != nil {
+		return err
+	}
+	mismatches := chainIDs.Compare(expected)
+	if len(mismatches) == 0 {
+		fmt.Fprintf(os.Stderr, "✅ All IDs match %s\n", idsExpect)
+		return nil
+	}
+	for _, m := range mismatches {
+		fmt.Fprintf(os.Stderr, "❌ %s changed\n   expected: %s\n   actual:   %s\n", m.Name, m.Expected, m.Actual)
+	}
+	return fmt.Errorf("%d IDs differ from %s", len(mismatches), idsExpect)
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/analysis"
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+// Index flags
+var (
+	indexFrom      uint64
+	indexTo        int64
+	indexWorkers   int
+	indexWindow    uint64
+	indexNamespace string
+	indexRestart   bool
+)
+
+// NewIndexCommand creates the index command with all subcommands
+func NewIndexCommand() *cobra.Command {
+	indexCmd := &cobra.Command{
+		Use:   "index",
+		Short: "Build secondary indexes in offline chain databases",
+		Long: `Build secondary indexes directly in an offline chain database.
+
+Migrated databases frequently lack the indexes luxd builds while syncing.
+
+Available indexes:
+- tx: Transaction lookup entries (needed by eth_getTransactionByHash)
+- logs: Address to block index over receipt logs (used by analyze account and the scanners)`,
+	}
+
+	// Add subcommands
+	indexCmd.AddCommand(
+		newIndexTxCmd(),
+		newIndexLogsCmd(),
+	)
+
+	return indexCmd
+}
+
+// newIndexTxCmd creates the tx-lookup index command
+func newIndexTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx <database-path>",
+		Short: "Write transaction lookup entries",
+		Long: `Walk canonical block bodies and write the 'l' + txHash -> block number
+entries geth uses to serve eth_getTransactionByHash.
+
+The command is resumable: the range of blocks indexed so far is recorded in
+the database after every window of blocks. A re-run indexes the blocks of
+--from..--to outside that range, extending it downwards and upwards. The
+range must stay contiguous, so a run that would leave unindexed blocks
+between it and the recorded range is refused; --restart discards the
+recorded range.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runIndexTx,
+	}
+
+	addIndexFlags(cmd)
+
+	return cmd
+}
+
+// newIndexLogsCmd creates the log address index command
+func newIndexLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs <database-path>",
+		Short: "Build an address to block index over receipt logs",
+		Long: `Walk canonical receipts and record, for every contract that emitted a
+log and every sender and recipient of a Transfer log, the blocks it appears
+in.
+
+The index is queried by 'analyze account' and the local-database scanners to
+skip blocks that cannot contain matching logs. They only rely on it within
+the recorded range and check header blooms elsewhere. Like 'index tx' it is
+resumable.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runIndexLogs,
+	}
+
+	addIndexFlags(cmd)
+
+	return cmd
+}
+
+func addIndexFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&indexFrom, "from", 0, "First block to index")
+	cmd.Flags().Int64Var(&indexTo, "to", -1, "Last block to index (-1 = chain head)")
+	cmd.Flags().IntVar(&indexWorkers, "workers", 8, "Number of parallel workers")
+	cmd.Flags().Uint64Var(&indexWindow, "window", 10000, "Blocks per checkpoint window")
+	cmd.Flags().StringVar(&indexNamespace, "namespace", "", "Subnet-EVM key namespace (hex, empty for none)")
+	cmd.Flags().BoolVar(&indexRestart, "restart", false, "Ignore saved progress and start from --from")
+}
+
+// Command implementations
+
+func runIndexTx(cmd *cobra.Command, args []string) error {
+	fmt.Printf("=== Indexing Transactions in %s ===\n", args[0])
+
+	return runIndexer(args[0], rawdb.TxIndexRangeKey, indexTxLookups)
+}
+
+func runIndexLogs(cmd *cobra.Command, args []string) error {
+	fmt.Printf("=== Indexing Logs in %s ===\n", args[0])
+
+	return runIndexer(args[0], rawdb.LogIndexRangeKey, indexLogs)
+}
+
+// indexFunc adds the index entries for one block to batch and returns how many it wrote
+type indexFunc func(r *rawdb.Reader, batch *pebble.Batch, number uint64) (int, error)
+
+// indexTxLookups writes the tx-lookup entries of a block
+func indexTxLookups(r *rawdb.Reader, batch *pebble.Batch, number uint64) (int, error) {
+	hash, err := r.ReadCanonicalHash(number)
+	if err != nil {
+		return 0, fmt.Errorf("missing canonical hash for block %d: %w", number, err)
+	}
+	body, err := r.ReadBody(number, hash)
+	if err != nil {
+		return 0, err
+	}
+
+	value := rawdb.TxLookupValue(number)
+	for _, tx := range body.Transactions {
+		if err := batch.Set(r.Key(rawdb.TxLookupKey(tx.Hash())), value, nil); err != nil {
+			return 0, err
+		}
+	}
+	return len(body.Transactions), nil
+}
+
+// indexLogs writes the log index entries of a block, one per address a log
+// was emitted by or transferred tokens from or to
+func indexLogs(r *rawdb.Reader, batch *pebble.Batch, number uint64) (int, error) {
+	hash, err := r.ReadCanonicalHash(number)
+	if err != nil {
+		return 0, fmt.Errorf("missing canonical hash for block %d: %w", number, err)
+	}
+	receipts, err := r.ReadReceipts(number, hash, nil)
+	if err != nil {
+		if errors.Is(err, rawdb.ErrNotFound) {
+			return 0, nil // Blocks without transactions may have no receipts entry
+		}
+		return 0, err
+	}
+
+	counts := make(map[common.Address]uint32)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			for _, addr := range logAddresses(l) {
+				counts[addr]++
+			}
+		}
+	}
+
+	for addr, count := range counts {
+		value := make([]byte, 4)
+		binary.BigEndian.PutUint32(value, count)
+		if err := batch.Set(r.Key(rawdb.LogIndexKey(addr, number)), value, nil); err != nil {
+			return 0, err
+		}
+	}
+	return len(counts), nil
+}
+
+// logAddresses returns the addresses a log is indexed under: its emitter and,
+// for a Transfer, the sender and recipient
+func logAddresses(l *types.Log) []common.Address {
+	addrs := []common.Address{l.Address}
+	if len(l.Topics) < 3 || l.Topics[0] != analysis.TransferTopic {
+		return addrs
+	}
+	for _, topic := range l.Topics[1:3] {
+		addr := common.BytesToAddress(topic.Bytes())
+		if !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// runIndexer indexes the blocks of [--from, --to] that the range saved under
+// rangeKey does not cover yet
+func runIndexer(dbPath string, rangeKey []byte, index indexFunc) error {
+	db, r, err := openChainDB(dbPath, indexNamespace, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	to := uint64(indexTo)
+	if indexTo < 0 {
+		head, err := r.HeadNumber()
+		if err != nil {
+			return fmt.Errorf("failed to find chain head: %w", err)
+		}
+		to = head
+	}
+
+	var saved rawdb.IndexRange
+	if !indexRestart {
+		data, err := r.Get(rangeKey)
+		if err != nil && !errors.Is(err, rawdb.ErrNotFound) {
+			return fmt.Errorf("failed to read progress: %w", err)
+		}
+		if err == nil {
+			var ok bool
+			if saved, ok = rawdb.DecodeIndexRange(data); !ok {
+				return fmt.Errorf("invalid saved progress %x; re-run with --restart", data)
+			}
+		}
+	}
+
+	plan, err := planIndexRun(saved, indexFrom, to)
+	if err != nil {
+		return err
+	}
+	if plan.Covered.Next > plan.Covered.Start {
+		fmt.Printf("Already indexed: blocks %d - %d\n", plan.Covered.Start, plan.Covered.Next-1)
+	}
+	if plan.Below == nil && plan.Above == nil {
+		fmt.Printf("Nothing to do: blocks %d - %d are already indexed\n", indexFrom, to)
+		return nil
+	}
+
+	workers := indexWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	window := indexWindow
+	if window == 0 {
+		window = 10000
+	}
+	fmt.Printf("Workers: %d, window: %d blocks\n", workers, window)
+
+	start := time.Now()
+	covered, total, err := buildIndex(db, r, rangeKey, plan, workers, window, index)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nDone: %d entries written in %s\n", total, time.Since(start).Round(time.Second))
+	fmt.Printf("Indexed: blocks %d - %d\n", covered.Start, covered.Next-1)
+	return nil
+}
+
+// blockSpan is an inclusive block range
+type blockSpan struct {
+	From, To uint64
+}
+
+// indexPlan is the work of one indexer run. The range covered by the index
+// only ever grows and stays contiguous: Above is indexed from the bottom up,
+// then Below from the top down, and every window extends Covered.
+type indexPlan struct {
+	Covered rawdb.IndexRange // Saved before the run
+	Below   *blockSpan       // Ends at Covered.Start-1
+	Above   *blockSpan       // Starts at Covered.Next
+}
+
+// planIndexRun plans indexing [from, to] on top of the saved range. A run
+// that would leave unindexed blocks between the saved range and its own is
+// refused, since the range could then no longer describe what is indexed.
+func planIndexRun(saved rawdb.IndexRange, from, to uint64) (indexPlan, error) {
+	if from > to {
+		return indexPlan{}, fmt.Errorf("--from %d is above --to %d", from, to)
+	}
+	if saved.Next == saved.Start {
+		return indexPlan{
+			Covered: rawdb.IndexRange{Start: from, Next: from},
+			Above:   &blockSpan{From: from, To: to},
+		}, nil
+	}
+
+	if from > saved.Next {
+		return indexPlan{}, fmt.Errorf("blocks %d - %d are indexed, so starting at %d would leave blocks %d - %d out; use --from %d, or --restart to index from scratch",
+			saved.Start, saved.Next-1, from, saved.Next, from-1, saved.Next)
+	}
+	if to+1 < saved.Start {
+		return indexPlan{}, fmt.Errorf("blocks %d - %d are indexed, so stopping at %d would leave blocks %d - %d out; use --to %d, or --restart to index from scratch",
+			saved.Start, saved.Next-1, to, to+1, saved.Start-1, saved.Start-1)
+	}
+
+	plan := indexPlan{Covered: saved}
+	if from < saved.Start {
+		plan.Below = &blockSpan{From: from, To: saved.Start - 1}
+	}
+	if to >= saved.Next {
+		plan.Above = &blockSpan{From: saved.Next, To: to}
+	}
+	return plan, nil
+}
+
+// buildIndex carries out plan in checkpoint windows. Each window is split
+// across the workers, and the covered range is only extended and saved under
+// rangeKey once every block in the window has been committed, so an
+// interrupted run never leaves a gap. It returns the covered range and the
+// number of entries written.
+func buildIndex(db *pebble.DB, r *rawdb.Reader, rangeKey []byte, plan indexPlan, workers int, window uint64, index indexFunc) (rawdb.IndexRange, int, error) {
+	covered := plan.Covered
+	start := time.Now()
+	total := 0
+	done := uint64(0)
+
+	indexSpan := func(windowStart, windowEnd uint64) error {
+		written, err := indexWindowParallel(db, r, windowStart, windowEnd, workers, index)
+		if err != nil {
+			return err
+		}
+		total += written
+
+		covered.Start = min(covered.Start, windowStart)
+		covered.Next = max(covered.Next, windowEnd+1)
+		if err := db.Set(r.Key(rangeKey), covered.Encode(), pebble.Sync); err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+
+		done += windowEnd - windowStart + 1
+		rate := float64(done) / time.Since(start).Seconds()
+		fmt.Printf("Indexed blocks %d - %d (%d entries, %.0f blocks/s)\n", windowStart, windowEnd, written, rate)
+		return nil
+	}
+
+	if span := plan.Above; span != nil {
+		fmt.Printf("Block range: %d - %d\n", span.From, span.To)
+		for windowStart := span.From; ; {
+			windowEnd := span.To
+			if span.To-windowStart >= window {
+				windowEnd = windowStart + window - 1
+			}
+			if err := indexSpan(windowStart, windowEnd); err != nil {
+				return covered, total, err
+			}
+			if windowEnd == span.To {
+				break
+			}
+			windowStart = windowEnd + 1
+		}
+	}
+
+	if span := plan.Below; span != nil {
+		fmt.Printf("Block range: %d - %d (downwards)\n", span.From, span.To)
+		for windowEnd := span.To; ; {
+			windowStart := span.From
+			if windowEnd-span.From >= window {
+				windowStart = windowEnd - window + 1
+			}
+			if err := indexSpan(windowStart, windowEnd); err != nil {
+				return covered, total, err
+			}
+			if windowStart == span.From {
+				break
+			}
+			windowEnd = windowStart - 1
+		}
+	}
+	return covered, total, nil
+}
+
+// indexWindowParallel indexes [start, end] using interleaved block assignment
+// so slow and fast regions are spread evenly across workers.
+func indexWindowParallel(db *pebble.DB, r *rawdb.Reader, start, end uint64, workers int, index indexFunc) (int, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+		errs  []error
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(offset uint64) {
+			defer wg.Done()
+
+			batch := db.NewBatch()
+			defer batch.Close()
+
+			written := 0
+			for number := start + offset; number <= end; number += uint64(workers) {
+				n, err := index(r, batch, number)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					return
+				}
+				written += n
+			}
+			if err := batch.Commit(pebble.NoSync); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to commit batch: %w", err))
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			total += written
+			mu.Unlock()
+		}(uint64(w))
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return total, errs[0]
+	}
+	return total, nil
+}
+
+// Helper functions
+
+// openChainDB opens a pebble chain database and wraps it in a schema reader.
+// namespaceHex is the optional Subnet-EVM key namespace.
+func openChainDB(dbPath, namespaceHex string, readOnly bool) (*pebble.DB, *rawdb.Reader, error) {
+	namespace, err := parseNamespace(namespaceHex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := pebble.Open(dbPath, &pebble.Options{ReadOnly: readOnly})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return db, rawdb.NewReader(db, namespace), nil
+}
+
+// parseNamespace decodes a --namespace flag value
+func parseNamespace(namespaceHex string) ([]byte, error) {
+	namespace, err := hex.DecodeString(strings.TrimPrefix(namespaceHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	return namespace, nil
+}
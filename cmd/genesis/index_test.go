@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/analysis"
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+func TestPlanIndexRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		saved    rawdb.IndexRange
+		from, to uint64
+		below    *blockSpan
+		above    *blockSpan
+		wantErr  string
+	}{
+		{name: "first run", from: 0, to: 99, above: &blockSpan{0, 99}},
+		{name: "block 0 only", from: 0, to: 0, above: &blockSpan{0, 0}},
+		{name: "resume", saved: rawdb.IndexRange{Start: 0, Next: 50}, from: 0, to: 99, above: &blockSpan{50, 99}},
+		{
+			name:  "default run after --from 1000 --to 2000",
+			saved: rawdb.IndexRange{Start: 1000, Next: 2001}, from: 0, to: 3000,
+			below: &blockSpan{0, 999}, above: &blockSpan{2001, 3000},
+		},
+		{name: "adjacent above", saved: rawdb.IndexRange{Start: 1000, Next: 2001}, from: 2001, to: 3000, above: &blockSpan{2001, 3000}},
+		{name: "adjacent below", saved: rawdb.IndexRange{Start: 1000, Next: 2001}, from: 0, to: 999, below: &blockSpan{0, 999}},
+		{name: "smaller --to", saved: rawdb.IndexRange{Start: 0, Next: 2001}, from: 0, to: 500},
+		{name: "gap above", saved: rawdb.IndexRange{Start: 0, Next: 2001}, from: 5000, to: 6000, wantErr: "blocks 2001 - 4999"},
+		{name: "gap below", saved: rawdb.IndexRange{Start: 1000, Next: 2001}, from: 0, to: 500, wantErr: "blocks 501 - 999"},
+		{name: "from above to", from: 10, to: 5, wantErr: "--from 10 is above --to 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planIndexRun(tt.saved, tt.from, tt.to)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.below, plan.Below)
+			assert.Equal(t, tt.above, plan.Above)
+			if tt.saved.Next > tt.saved.Start {
+				assert.Equal(t, tt.saved, plan.Covered)
+			}
+		})
+	}
+}
+
+// openIndexTestDB opens an empty pebble database
+func openIndexTestDB(t *testing.T) (*pebble.DB, *rawdb.Reader) {
+	db, err := pebble.Open(t.TempDir(), &pebble.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, rawdb.NewReader(db, nil)
+}
+
+// savedRange returns the range saved under key, empty when there is none
+func savedRange(t *testing.T, r *rawdb.Reader, key []byte) rawdb.IndexRange {
+	data, err := r.Get(key)
+	if errors.Is(err, rawdb.ErrNotFound) {
+		return rawdb.IndexRange{}
+	}
+	require.NoError(t, err)
+	saved, ok := rawdb.DecodeIndexRange(data)
+	require.True(t, ok)
+	return saved
+}
+
+func TestBuildIndexResume(t *testing.T) {
+	db, r := openIndexTestDB(t)
+	key := []byte("test-index-range")
+
+	var (
+		mu     sync.Mutex
+		seen   = make(map[uint64]int)
+		failAt = uint64(25)
+	)
+	index := func(r *rawdb.Reader, batch *pebble.Batch, number uint64) (int, error) {
+		if number == failAt {
+			return 0, errors.New("corrupt block")
+		}
+		mu.Lock()
+		seen[number]++
+		mu.Unlock()
+		return 1, nil
+	}
+	run := func(from, to, window uint64) error {
+		plan, err := planIndexRun(savedRange(t, r, key), from, to)
+		if err != nil {
+			return err
+		}
+		covered, _, err := buildIndex(db, r, key, plan, 3, window, index)
+		if err == nil {
+			assert.Equal(t, covered, savedRange(t, r, key))
+		}
+		return err
+	}
+
+	// The window 10-19 is saved before the window 20-29 fails
+	require.ErrorContains(t, run(10, 39, 10), "corrupt block")
+	assert.Equal(t, rawdb.IndexRange{Start: 10, Next: 20}, savedRange(t, r, key))
+
+	// A rerun continues above the saved range and fills in below it from the
+	// top down, so a failure there keeps the windows already done
+	failAt = 3
+	clear(seen)
+	require.ErrorContains(t, run(0, 39, 4), "corrupt block")
+	assert.Equal(t, rawdb.IndexRange{Start: 6, Next: 40}, savedRange(t, r, key))
+	for number := uint64(10); number < 20; number++ {
+		assert.Zero(t, seen[number], "block %d indexed again", number)
+	}
+	for number := uint64(20); number < 40; number++ {
+		assert.Equal(t, 1, seen[number], "block %d", number)
+	}
+
+	failAt = math.MaxUint64
+	clear(seen)
+	require.NoError(t, run(0, 39, 4))
+	assert.Equal(t, rawdb.IndexRange{Start: 0, Next: 40}, savedRange(t, r, key))
+	assert.Len(t, seen, 6)
+
+	// Runs that would leave a gap, or end below the range, change nothing
+	require.ErrorContains(t, run(50, 60, 4), "blocks 40 - 49")
+	require.NoError(t, run(0, 5, 4))
+	assert.Equal(t, rawdb.IndexRange{Start: 0, Next: 40}, savedRange(t, r, key))
+}
+
+// writeIndexTestChain writes canonical blocks 0 to head straight into db, with
+// one transaction and receipt per log, and returns the block of every
+// transaction
+func writeIndexTestChain(t *testing.T, db *pebble.DB, head uint64, logs map[uint64][]*types.Log) map[common.Hash]uint64 {
+	put := func(key []byte, value interface{}) {
+		data, ok := value.([]byte)
+		if !ok {
+			var err error
+			data, err = rlp.EncodeToBytes(value)
+			require.NoError(t, err)
+		}
+		require.NoError(t, db.Set(key, data, nil))
+	}
+
+	txBlocks := make(map[common.Hash]uint64)
+	for number := uint64(0); number <= head; number++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: new(big.Int)}
+		hash := header.Hash()
+
+		body := &types.Body{}
+		var receipts []*types.ReceiptForStorage
+		for i, l := range logs[number] {
+			tx := types.NewTx(&types.LegacyTx{Nonce: number*10 + uint64(i), To: &l.Address})
+			body.Transactions = append(body.Transactions, tx)
+			txBlocks[tx.Hash()] = number
+			receipts = append(receipts, &types.ReceiptForStorage{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: 21000 * uint64(i+1),
+				Logs:              []*types.Log{l},
+			})
+		}
+
+		put(rawdb.CanonicalHashKey(number), hash.Bytes())
+		put(rawdb.HeaderKey(number, hash), header)
+		put(rawdb.BlockBodyKey(number, hash), body)
+		if len(receipts) > 0 {
+			put(rawdb.BlockReceiptsKey(number, hash), receipts)
+		}
+	}
+	return txBlocks
+}
+
+func TestIndexTxAndLogs(t *testing.T) {
+	db, r := openIndexTestDB(t)
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	transfer := []common.Hash{analysis.TransferTopic, common.BytesToHash(alice.Bytes()), common.BytesToHash(token.Bytes())}
+	txBlocks := writeIndexTestChain(t, db, 6, map[uint64][]*types.Log{
+		1: {{Address: token}},
+		3: {{Address: token}, {Address: other}, {Address: token}},
+		6: {{Address: other, Topics: transfer}},
+	})
+
+	plan, err := planIndexRun(rawdb.IndexRange{}, 0, 6)
+	require.NoError(t, err)
+	_, written, err := buildIndex(db, r, rawdb.TxIndexRangeKey, plan, 2, 3, indexTxLookups)
+	require.NoError(t, err)
+	assert.Equal(t, 5, written)
+	for hash, number := range txBlocks {
+		indexed, err := r.ReadTxLookup(hash)
+		require.NoError(t, err)
+		assert.Equal(t, number, indexed)
+	}
+
+	_, written, err = buildIndex(db, r, rawdb.LogIndexRangeKey, plan, 2, 3, indexLogs)
+	require.NoError(t, err)
+	assert.Equal(t, 6, written)
+	assert.Equal(t, rawdb.IndexRange{Start: 0, Next: 7}, savedRange(t, r, rawdb.LogIndexRangeKey))
+
+	counts := func(addr common.Address) map[uint64]uint32 {
+		counts := make(map[uint64]uint32)
+		err := r.ScanKeys(rawdb.LogIndexAddressPrefix(addr), nil, func(key, value []byte) bool {
+			number, _ := rawdb.DecodeBlockNumber(key[len(key)-8:])
+			counts[number] = uint32(new(big.Int).SetBytes(value).Uint64())
+			return true
+		})
+		require.NoError(t, err)
+		return counts
+	}
+	// The Transfer in block 6 is indexed under its emitter and both parties
+	assert.Equal(t, map[uint64]uint32{1: 1, 3: 2, 6: 1}, counts(token))
+	assert.Equal(t, map[uint64]uint32{3: 1, 6: 1}, counts(other))
+	assert.Equal(t, map[uint64]uint32{6: 1}, counts(alice))
+}
@@ -31,7 +31,8 @@ Available inspections:
 - headers: Inspect block headers
 - snowman: Inspect Snowman consensus DB
 - prefixes: Scan database prefixes
-- tip: Find chain tip`,
+- tip: Find chain tip
+- tui: Interactive browser`,
 	}
 
 	// Add subcommands
@@ -42,6 +43,7 @@ Available inspections:
 		newInspectSnowmanCmd(),
 		newInspectPrefixesCmd(),
 		newInspectTipCmd(),
+		newInspectTuiCmd(),
 	)
 
 	return inspectCmd
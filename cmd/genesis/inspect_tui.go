@@ -0,0 +1,449 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/geth/common"
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+// TUI flags
+var (
+	tuiNamespace string
+	tuiPageSize  int
+	tuiBookmarks string
+)
+
+// newInspectTuiCmd creates the interactive browser command
+func newInspectTuiCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui <database-path>",
+		Short: "Interactively browse a chain database",
+		Long: `Open a keyboard-driven browser over a chain database.
+
+The browser lists key categories, pages through keys, decodes values
+(headers, bodies, receipts, trie nodes, pointers) and follows the links
+between them: parent hash, state root -> account -> storage, tx lookups.
+Keys can be bookmarked; bookmarks are kept in --bookmarks if given.
+
+The database is opened read-only. Type 'help' inside the browser for the
+list of commands.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runInspectTui,
+	}
+
+	cmd.Flags().StringVar(&tuiNamespace, "namespace", "", "Subnet-EVM key namespace (hex, empty for none)")
+	cmd.Flags().IntVar(&tuiPageSize, "page-size", 20, "Keys per page")
+	cmd.Flags().StringVar(&tuiBookmarks, "bookmarks", "", "File to load and save bookmarks (JSON)")
+
+	return cmd
+}
+
+func runInspectTui(cmd *cobra.Command, args []string) error {
+	db, r, err := openChainDB(args[0], tuiNamespace, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	b := &browser{
+		r:         r,
+		out:       os.Stdout,
+		pageSize:  tuiPageSize,
+		bookmarks: make(map[string][]byte),
+	}
+	if b.pageSize < 1 {
+		b.pageSize = 20
+	}
+	if tuiBookmarks != "" {
+		if err := b.loadBookmarks(tuiBookmarks); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("=== Browsing %s (read-only) ===\n", args[0])
+	fmt.Println("Type 'help' for commands")
+
+	return b.run(os.Stdin)
+}
+
+// browser holds the state of an interactive session
+type browser struct {
+	r        *rawdb.Reader
+	out      io.Writer
+	pageSize int
+
+	// Listing state
+	kind       rawdb.KeyKind
+	page       [][]byte
+	pageStarts [][]byte // Start key of every page visited, for 'p'
+	nextStart  []byte
+
+	// Open key state
+	current []byte
+	desc    *rawdb.Description
+	history [][]byte
+	showHex bool
+
+	bookmarks map[string][]byte
+}
+
+func (b *browser) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(b.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(b.out)
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		quit, err := b.dispatch(fields[0], fields[1:])
+		if err != nil {
+			fmt.Fprintf(b.out, "error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func (b *browser) dispatch(command string, args []string) (bool, error) {
+	switch command {
+	case "q", "quit", "exit":
+		return true, nil
+	case "h", "help", "?":
+		b.printHelp()
+	case "k", "kinds":
+		b.printKinds()
+	case "l", "list":
+		if len(args) != 1 {
+			return false, errors.New("usage: l <kind|#>")
+		}
+		kind, err := parseKind(args[0])
+		if err != nil {
+			return false, err
+		}
+		b.kind = kind
+		b.pageStarts = [][]byte{nil}
+		return false, b.listPage(nil)
+	case "n", "next":
+		if b.nextStart == nil {
+			return false, errors.New("no next page")
+		}
+		b.pageStarts = append(b.pageStarts, b.nextStart)
+		return false, b.listPage(b.nextStart)
+	case "p", "prev":
+		if len(b.pageStarts) < 2 {
+			return false, errors.New("no previous page")
+		}
+		b.pageStarts = b.pageStarts[:len(b.pageStarts)-1]
+		return false, b.listPage(b.pageStarts[len(b.pageStarts)-1])
+	case "o", "open":
+		i, err := b.index(args, len(b.page))
+		if err != nil {
+			return false, err
+		}
+		return false, b.open(b.page[i], true)
+	case "f", "follow":
+		if b.desc == nil {
+			return false, errors.New("no key open")
+		}
+		i, err := b.index(args, len(b.desc.Links))
+		if err != nil {
+			return false, err
+		}
+		return false, b.open(b.desc.Links[i].Key, true)
+	case "b", "back":
+		if len(b.history) == 0 {
+			return false, errors.New("history is empty")
+		}
+		prev := b.history[len(b.history)-1]
+		b.history = b.history[:len(b.history)-1]
+		return false, b.open(prev, false)
+	case "g", "goto":
+		if len(args) != 1 {
+			return false, errors.New("usage: g <bookmark|hex-key>")
+		}
+		if key, ok := b.bookmarks[args[0]]; ok {
+			return false, b.open(key, true)
+		}
+		key, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+		if err != nil {
+			return false, fmt.Errorf("invalid hex key: %w", err)
+		}
+		return false, b.open(key, true)
+	case "block":
+		if len(args) != 1 {
+			return false, errors.New("usage: block <number>")
+		}
+		number, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid block number: %w", err)
+		}
+		hash, err := b.r.ReadCanonicalHash(number)
+		if err != nil {
+			return false, fmt.Errorf("no canonical block %d: %w", number, err)
+		}
+		return false, b.open(rawdb.HeaderKey(number, hash), true)
+	case "tx":
+		if len(args) != 1 {
+			return false, errors.New("usage: tx <hash>")
+		}
+		return false, b.open(rawdb.TxLookupKey(common.HexToHash(args[0])), true)
+	case "m", "mark":
+		if b.current == nil {
+			return false, errors.New("no key open")
+		}
+		name := fmt.Sprintf("%s-%d", b.desc.Kind, len(b.bookmarks)+1)
+		if len(args) > 0 {
+			name = args[0]
+		}
+		b.bookmarks[name] = b.current
+		fmt.Fprintf(b.out, "Bookmarked %s\n", name)
+		if tuiBookmarks != "" {
+			return false, b.saveBookmarks(tuiBookmarks)
+		}
+	case "marks":
+		b.printBookmarks()
+	case "unmark":
+		if len(args) != 1 {
+			return false, errors.New("usage: unmark <name>")
+		}
+		delete(b.bookmarks, args[0])
+		if tuiBookmarks != "" {
+			return false, b.saveBookmarks(tuiBookmarks)
+		}
+	case "x", "hex":
+		b.showHex = !b.showHex
+		if b.showHex {
+			fmt.Fprintln(b.out, "Hex dump on")
+		} else {
+			fmt.Fprintln(b.out, "Hex dump off")
+		}
+		if b.current != nil {
+			return false, b.open(b.current, false)
+		}
+	default:
+		return false, fmt.Errorf("unknown command %q (type 'help')", command)
+	}
+	return false, nil
+}
+
+// listPage prints one page of keys of the selected kind starting at start
+func (b *browser) listPage(start []byte) error {
+	b.page = nil
+	b.nextStart = nil
+
+	if b.kind == rawdb.KindPointer {
+		for _, key := range rawdb.PointerKeys {
+			if ok, _ := b.r.Has(key); ok {
+				b.page = append(b.page, key)
+			}
+		}
+	} else {
+		err := b.r.ScanKeys(b.kind.Prefix(), start, func(key, value []byte) bool {
+			if rawdb.Classify(key) != b.kind {
+				return true
+			}
+			if len(b.page) == b.pageSize {
+				b.nextStart = append([]byte{}, key...)
+				return false
+			}
+			b.page = append(b.page, append([]byte{}, key...))
+			return true
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan keys: %w", err)
+		}
+	}
+
+	fmt.Fprintf(b.out, "\n--- %s (page %d) ---\n", b.kind, len(b.pageStarts))
+	if len(b.page) == 0 {
+		fmt.Fprintln(b.out, "  (no keys)")
+	}
+	for i, key := range b.page {
+		fmt.Fprintf(b.out, "  [%d] %s\n", i, formatKey(key))
+	}
+	if b.nextStart != nil {
+		fmt.Fprintln(b.out, "  ... 'n' for more")
+	}
+	return nil
+}
+
+// open reads, decodes and prints a key
+func (b *browser) open(key []byte, record bool) error {
+	value, err := b.r.Get(key)
+	if err != nil {
+		if errors.Is(err, rawdb.ErrNotFound) {
+			return fmt.Errorf("key %x not found", key)
+		}
+		return err
+	}
+	if record && b.current != nil {
+		b.history = append(b.history, b.current)
+	}
+	b.current = key
+	b.desc = rawdb.Describe(key, value)
+
+	fmt.Fprintf(b.out, "\n--- %s (%d bytes) ---\n", b.desc.Kind, len(value))
+	fmt.Fprintf(b.out, "Key: %x\n", key)
+	for _, f := range b.desc.Fields {
+		fmt.Fprintf(b.out, "  %s: %s\n", f.Name, f.Value)
+	}
+	if b.desc.Err != nil {
+		fmt.Fprintf(b.out, "  decode error: %v\n", b.desc.Err)
+	}
+	if b.showHex || b.desc.Err != nil {
+		fmt.Fprintf(b.out, "Value:\n%s", hex.Dump(value))
+	}
+
+	if len(b.desc.Links) > 0 {
+		fmt.Fprintln(b.out, "Links:")
+		for i, l := range b.desc.Links {
+			missing := ""
+			if ok, _ := b.r.Has(l.Key); !ok {
+				missing = " (missing)"
+			}
+			fmt.Fprintf(b.out, "  [%d] %s -> %x%s\n", i, l.Label, l.Key, missing)
+		}
+	}
+	return nil
+}
+
+func (b *browser) index(args []string, n int) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected an item number")
+	}
+	i, err := strconv.Atoi(args[0])
+	if err != nil || i < 0 || i >= n {
+		return 0, fmt.Errorf("item must be between 0 and %d", n-1)
+	}
+	return i, nil
+}
+
+func (b *browser) printHelp() {
+	fmt.Fprint(b.out, `Commands:
+  k                 list key categories
+  l <kind|#>        list keys of a category
+  n, p              next / previous page
+  o <#>             open key # from the current page
+  f <#>             follow link # of the open key
+  b                 go back to the previously open key
+  g <name|hex>      open a bookmark or a raw key (without namespace)
+  block <number>    open the canonical header at a height
+  tx <hash>         open the lookup entry of a transaction
+  m [name]          bookmark the open key
+  marks             list bookmarks
+  unmark <name>     remove a bookmark
+  x                 toggle hex dump of values
+  q                 quit
+`)
+}
+
+func (b *browser) printKinds() {
+	for i, kind := range rawdb.Kinds {
+		prefix := "(scan)"
+		if p := kind.Prefix(); p != nil {
+			prefix = fmt.Sprintf("prefix %q", p)
+		}
+		fmt.Fprintf(b.out, "  [%d] %-20s %s\n", i, kind, prefix)
+	}
+}
+
+func (b *browser) printBookmarks() {
+	if len(b.bookmarks) == 0 {
+		fmt.Fprintln(b.out, "  (no bookmarks)")
+		return
+	}
+	names := make([]string, 0, len(b.bookmarks))
+	for name := range b.bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := b.bookmarks[name]
+		fmt.Fprintf(b.out, "  %-20s %-18s %x\n", name, rawdb.Classify(key), key)
+	}
+}
+
+func (b *browser) loadBookmarks(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	var stored map[string]string
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse bookmarks: %w", err)
+	}
+	for name, keyHex := range stored {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return fmt.Errorf("invalid bookmark %s: %w", name, err)
+		}
+		b.bookmarks[name] = key
+	}
+	return nil
+}
+
+func (b *browser) saveBookmarks(path string) error {
+	stored := make(map[string]string, len(b.bookmarks))
+	for name, key := range b.bookmarks {
+		stored[name] = hex.EncodeToString(key)
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save bookmarks: %w", err)
+	}
+	return nil
+}
+
+// Helper functions
+
+// parseKind accepts a kind name or its number in rawdb.Kinds
+func parseKind(s string) (rawdb.KeyKind, error) {
+	if i, err := strconv.Atoi(s); err == nil {
+		if i < 0 || i >= len(rawdb.Kinds) {
+			return "", fmt.Errorf("kind number must be between 0 and %d", len(rawdb.Kinds)-1)
+		}
+		return rawdb.Kinds[i], nil
+	}
+	for _, kind := range rawdb.Kinds {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown kind %q (type 'k' for the list)", s)
+}
+
+// formatKey renders a key with its block number where the schema has one
+func formatKey(key []byte) string {
+	switch rawdb.Classify(key) {
+	case rawdb.KindHeader, rawdb.KindBody, rawdb.KindReceipts, rawdb.KindCanonicalHash, rawdb.KindHeaderTD:
+		return fmt.Sprintf("#%-10d %x", decodeBlockNumber(key[1:9]), key)
+	case rawdb.KindPointer:
+		return string(key)
+	}
+	return fmt.Sprintf("%x", key)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+	"github.com/luxfi/genesis/pkg/genesis/validator"
+)
+
+// Keystore flags
+var (
+	keysPasswordFile    string
+	keysNewPasswordFile string
+	keysKDF             string
+	keysNewKDF          string
+	keysOutput          string
+)
+
+// NewKeysCommand creates the encrypted keystore command
+func NewKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage encrypted validator keystores",
+		Long: `Manage encrypted validator keystores.
+
+A keystore directory holds validator.json (public) and one keystore file per
+private key: signer.keystore.json (BLS), staker.keystore.json (staking TLS key
+and certificate) and, for keys derived from a mnemonic, reward.keystore.json
+(secp256k1). Keys are sealed with AES-256-GCM under a password hashed with
+scrypt or argon2id.
+
+Passwords are read from files (first line) so they stay out of the shell
+history.`,
+	}
+
+	cmd.AddCommand(keysExportCmd(), keysImportCmd(), keysChangePasswordCmd())
+	return cmd
+}
+
+func keysExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <key-dir>",
+		Short: "Encrypt plaintext validator keys into a keystore directory",
+		Long: `Encrypt the plaintext keys of one validator - a directory written by
+validators generate --plaintext (bls.key, staking/staker.*) or a luxd staking
+directory (signer.key, staker.*) - into a keystore directory.
+
+The plaintext files are left in place; remove them once the keystore is
+backed up.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runKeysExport,
+	}
+	cmd.Flags().StringVar(&keysOutput, "output", "", "Keystore directory to write")
+	cmd.Flags().StringVar(&keysPasswordFile, "password-file", "", "File with the keystore password")
+	cmd.Flags().StringVar(&keysKDF, "kdf", "scrypt", "Password KDF (scrypt, argon2id)")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagRequired("password-file")
+	return cmd
+}
+
+func keysImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <keystore-dir>",
+		Short: "Decrypt a keystore directory into luxd staking files",
+		Long: `Decrypt a keystore directory and write luxd-ready staker.key, staker.crt and
+signer.key to --output. Run it at deploy time, on the validator host.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runKeysImport,
+	}
+	cmd.Flags().StringVar(&keysOutput, "output", "", "luxd staking directory to write")
+	cmd.Flags().StringVar(&keysPasswordFile, "password-file", "", "File with the keystore password")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagRequired("password-file")
+	return cmd
+}
+
+func keysChangePasswordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "change-password <keystore-file|keystore-dir>",
+		Short: "Re-encrypt keystores under a new password",
+		Long: `Re-encrypt a keystore file, or every keystore in a directory, under a new
+password with a fresh salt. With --kdf the keystores also switch KDF.
+
+All keystores are decrypted before any is rewritten, so a wrong password
+leaves the directory unchanged.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runKeysChangePassword,
+	}
+	cmd.Flags().StringVar(&keysPasswordFile, "password-file", "", "File with the current password")
+	cmd.Flags().StringVar(&keysNewPasswordFile, "new-password-file", "", "File with the new password")
+	cmd.Flags().StringVar(&keysNewKDF, "kdf", "", "Password KDF of the new encryption (default: keep)")
+	cmd.MarkFlagRequired("password-file")
+	cmd.MarkFlagRequired("new-password-file")
+	return cmd
+}
+
+func runKeysExport(cmd *cobra.Command, args []string) error {
+	password, err := readPasswordFile(keysPasswordFile)
+	if err != nil {
+		return err
+	}
+	kdf, err := keystore.ParseKDF(keysKDF)
+	if err != nil {
+		return err
+	}
+
+	keys, err := validator.LoadPlaintextKeys(args[0])
+	if err != nil {
+		return err
+	}
+	if err := validator.SaveEncryptedKeys(keys, keysOutput, password, kdf); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Encrypted keys of %s to %s\n", keys.NodeID, keysOutput)
+	fmt.Printf("⚠️  The plaintext keys in %s are still on disk\n", args[0])
+	return nil
+}
+
+func runKeysImport(cmd *cobra.Command, args []string) error {
+	password, err := readPasswordFile(keysPasswordFile)
+	if err != nil {
+		return err
+	}
+	nodeID, err := validator.WriteStakingFiles(args[0], password, keysOutput)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote luxd staking files for %s to %s\n", nodeID, keysOutput)
+	return nil
+}
+
+func runKeysChangePassword(cmd *cobra.Command, args []string) error {
+	oldPassword, err := readPasswordFile(keysPasswordFile)
+	if err != nil {
+		return err
+	}
+	newPassword, err := readPasswordFile(keysNewPasswordFile)
+	if err != nil {
+		return err
+	}
+	var kdf keystore.KDF
+	if keysNewKDF != "" {
+		if kdf, err = keystore.ParseKDF(keysNewKDF); err != nil {
+			return err
+		}
+	}
+
+	paths, err := keystore.Find(args[0])
+	if err != nil {
+		return err
+	}
+	keys := make([]*keystore.Key, len(paths))
+	for i, path := range paths {
+		if keys[i], err = keystore.Load(path); err != nil {
+			return err
+		}
+		newKDF := kdf
+		if newKDF == "" {
+			newKDF = keys[i].Crypto.KDF
+		}
+		if err := keys[i].ChangePassword(oldPassword, newPassword, newKDF); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	for i, path := range paths {
+		if err := keys[i].Save(path); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Re-encrypted %s\n", path)
+	}
+	return nil
+}
+
+// readPasswordFile returns the first line of a password file
+func readPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	password, _, _ := strings.Cut(string(data), "\n")
+	password = strings.TrimSuffix(password, "\r")
+	if password == "" {
+		return "", fmt.Errorf("password file %s is empty", path)
+	}
+	return password, nil
+}
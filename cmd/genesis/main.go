@@ -26,6 +26,12 @@ import (
 
 	// Import internal packages
 	"github.com/luxfi/genesis/cmd/namespace/pkg/namespace"
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+	"github.com/luxfi/genesis/pkg/genesis/config"
+	"github.com/luxfi/genesis/pkg/genesis/importer"
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+	"github.com/luxfi/genesis/pkg/genesis/validator"
 )
 
 var (
@@ -135,7 +141,7 @@ Use 'genesis --help' to see all available commands.`,
 	validatorsCmd := &cobra.Command{
 		Use:   "validators",
 		Short: "Manage validators",
-		Long:  `Add, remove, list, and generate validators`,
+		Long:  `Add, remove, list, generate and import validators`,
 	}
 	addValidatorSubcommands(validatorsCmd)
 
@@ -183,6 +189,7 @@ Use 'genesis --help' to see all available commands.`,
 		Short: "Validate genesis configuration",
 		RunE:  runValidate,
 	}
+	validateCmd.AddCommand(validateChainCmd())
 
 	// Tools command
 	toolsCmd := &cobra.Command{
@@ -295,6 +302,27 @@ This is useful for migrating subnet data to C-Chain format while preserving all
 	// Create new inspect module
 	inspectCmd := NewInspectCommand()
 
+	// Offline index builders
+	indexCmd := NewIndexCommand()
+
+	// Genesis and blockchain ID computation
+	idsCmd := NewIDsCommand()
+
+	// Semantic genesis diff
+	diffCmd := NewDiffCommand()
+
+	// Allocation schedules
+	allocationsCmd := NewAllocationsCommand()
+
+	// Circulating supply projection
+	supplyCmd := NewSupplyCommand()
+
+	// Encrypted validator keystores
+	keysCmd := NewKeysCommand()
+
+	// Validator registry checks
+	registryCmd := NewRegistryCommand()
+
 	// Build command structure
 	rootCmd.AddCommand(
 		generateCmd,
@@ -303,6 +331,13 @@ This is useful for migrating subnet data to C-Chain format while preserving all
 		importCmd,
 		analyzeCmd,
 		inspectCmd,
+		indexCmd,
+		idsCmd,
+		diffCmd,
+		allocationsCmd,
+		supplyCmd,
+		keysCmd,
+		registryCmd,
 		scanCmd,
 		migrateCmd,
 		processCmd,
@@ -355,7 +390,8 @@ func addValidatorSubcommands(validatorsCmd *cobra.Command) {
 	addCmd.Flags().String("eth-address", "", "Ethereum address")
 	addCmd.Flags().String("public-key", "", "BLS public key")
 	addCmd.Flags().String("proof-of-possession", "", "BLS proof of possession")
-	addCmd.Flags().String("weight", "100000000000000", "Validator weight")
+	addCmd.Flags().String("weight", "2M", "Validator stake in LUX (e.g. 2M), stored in nLUX")
+	addCmd.Flags().Bool("force", false, "Add the validator even if registry checks fail")
 	addCmd.MarkFlagRequired("node-id")
 	addCmd.MarkFlagRequired("eth-address")
 
@@ -372,15 +408,27 @@ func addValidatorSubcommands(validatorsCmd *cobra.Command) {
 	generateCmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate new validators",
-		RunE:  runGenerateValidators,
+		Long: `Derive validators from a BIP-39 mnemonic. For each offset N:
+
+- BLS key and proof of possession: EIP-2333 path m/12381/9000/N/0/0
+- staking TLS certificate and NodeID: HKDF from the seed, lux/staking-tls/v1/N
+- reward key: BIP-44 path m/44'/60'/0'/0/N (P-Chain and C-Chain address)
+
+The same mnemonic and offset always give the same keys. With --save-keys-dir
+the private keys are written as encrypted keystores under --password-file;
+use keys import to produce luxd staking files at deploy time.`,
+		RunE: runGenerateValidators,
 	}
 	generateCmd.Flags().String("mnemonic", "", "BIP39 mnemonic phrase")
+	generateCmd.Flags().Bool("new-mnemonic", false, "Generate a new 24-word mnemonic")
 	generateCmd.Flags().String("offsets", "0,1,2,3,4,5,6,7,8,9,10", "Comma-separated list of HD wallet offsets")
-	generateCmd.Flags().String("save-keys", "", "Save validator keys to file")
-	generateCmd.Flags().String("save-keys-dir", "configs/keys", "Directory to save individual validator key files")
-	generateCmd.MarkFlagRequired("mnemonic")
+	generateCmd.Flags().String("save-keys", "", "Save validator public keys to file")
+	generateCmd.Flags().String("save-keys-dir", "", "Directory to save encrypted keystores, one subdirectory per validator")
+	generateCmd.Flags().String("password-file", "", "File with the keystore password for --save-keys-dir")
+	generateCmd.Flags().String("kdf", "scrypt", "Keystore password KDF (scrypt, argon2id)")
+	generateCmd.Flags().Bool("plaintext", false, "Write private keys unencrypted (--save-keys-dir and --save-keys)")
 
-	validatorsCmd.AddCommand(listCmd, addCmd, removeCmd, generateCmd)
+	validatorsCmd.AddCommand(listCmd, addCmd, removeCmd, generateCmd, validatorsImportCmd(), validatorsVerifyManifestCmd(), validatorsPoPCmd(), validatorsReportCmd())
 }
 
 func addExtractSubcommands(extractCmd *cobra.Command) {
@@ -453,13 +501,24 @@ func addImportSubcommands(importCmd *cobra.Command) {
 
 	// Import allocations from CSV/JSON
 	allocationsCmd := &cobra.Command{
-		Use:   "allocations [file]",
-		Short: "Import allocations from CSV or JSON",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runImportAllocations,
-	}
-	allocationsCmd.Flags().String("format", "auto", "File format (csv, json, auto)")
-	allocationsCmd.Flags().Bool("merge", false, "Merge with existing allocations")
+		Use:   "allocations [file...]",
+		Short: "Import allocations from CSV, JSON, NDJSON or scanner exports",
+		Long: `Import allocations from one or more files into a single list.
+
+Each allocation records the file and line it came from. Rows with a bad
+address or amount are rejected with a reason rather than dropped; addresses
+that appear more than once are merged with --on-duplicate.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runImportAllocations,
+	}
+	allocationsCmd.Flags().String("format", "auto", "File format (csv, json, ndjson, scanner, auto)")
+	allocationsCmd.Flags().Bool("merge", false, "Merge with the allocations already in --output")
+	allocationsCmd.Flags().String("on-duplicate", "sum", "Duplicate address policy (sum, max, error)")
+	allocationsCmd.Flags().String("address-column", "", "Address column or field (default: detected)")
+	allocationsCmd.Flags().String("amount-column", "", "Amount column or field (default: detected)")
+	allocationsCmd.Flags().String("unit", "", "Amount unit (lux, nlux, wei; default: from the column name, else wei)")
+	allocationsCmd.Flags().String("rejected", "", "Write rejected rows to this CSV and continue")
+	allocationsCmd.Flags().String("output", "", "Write the imported allocations (wei, with sources) to this JSON file")
 
 	// Import chain data from existing database
 	chainDataCmd := &cobra.Command{
@@ -824,71 +883,129 @@ func runImportCChain(cmd *cobra.Command, args []string) error {
 }
 
 func runImportAllocations(cmd *cobra.Command, args []string) error {
-	file := args[0]
-	format, _ := cmd.Flags().GetString("format")
+	formatName, _ := cmd.Flags().GetString("format")
 	merge, _ := cmd.Flags().GetBool("merge")
+	onDuplicate, _ := cmd.Flags().GetString("on-duplicate")
+	addressColumn, _ := cmd.Flags().GetString("address-column")
+	amountColumn, _ := cmd.Flags().GetString("amount-column")
+	unitName, _ := cmd.Flags().GetString("unit")
+	rejectedPath, _ := cmd.Flags().GetString("rejected")
+	outputPath, _ := cmd.Flags().GetString("output")
 
-	fmt.Printf("Importing allocations from %s\n", file)
-	fmt.Printf("Format: %s, Merge: %v\n", format, merge)
-
-	// Detect format if auto
-	if format == "auto" {
-		if strings.HasSuffix(file, ".csv") {
-			format = "csv"
-		} else if strings.HasSuffix(file, ".json") {
-			format = "json"
-		} else {
-			return fmt.Errorf("cannot auto-detect format for file: %s", file)
-		}
+	format, err := importer.ParseFormat(formatName)
+	if err != nil {
+		return err
 	}
-
-	// Read file
-	data, err := ioutil.ReadFile(file)
+	if onDuplicate == "" {
+		onDuplicate = string(importer.MergeSum)
+	}
+	policy, err := importer.ParseMergePolicy(onDuplicate)
+	if err != nil {
+		return err
+	}
+	unit, err := importer.ParseUnit(unitName)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return err
+	}
+	if merge && outputPath == "" {
+		return fmt.Errorf("--merge requires --output")
 	}
 
-	allocations := make(map[string]*big.Int)
+	fmt.Printf("Importing allocations from %s\n", strings.Join(args, ", "))
+	fmt.Printf("Format: %s, Duplicates: %s, Merge: %v\n", format, policy, merge)
 
-	switch format {
-	case "csv":
-		// Parse CSV format
-		// Expected format: address,balance
-		lines := strings.Split(string(data), "\n")
-		for i, line := range lines {
-			if i == 0 && strings.Contains(line, "address") {
-				continue // Skip header
+	im := importer.New(importer.Options{
+		Format:       format,
+		Columns:      importer.Columns{Address: addressColumn, Amount: amountColumn},
+		Unit:         unit,
+		Denomination: amount.Wei,
+		Merge:        policy,
+	})
+
+	// Merging imports the previous output first so its addresses come first
+	if merge {
+		if _, err := os.Stat(outputPath); err == nil {
+			fmt.Printf("Merging with existing allocations in %s\n", outputPath)
+			existing := importer.New(importer.Options{Format: importer.FormatJSON, Denomination: amount.Wei})
+			if err := existing.ImportFile(outputPath); err != nil {
+				return err
+			}
+			if err := im.Merge(existing.Result()); err != nil {
+				return err
 			}
+		}
+	}
+	for _, file := range args {
+		if err := im.ImportFile(file); err != nil {
+			return err
+		}
+	}
+	result := im.Result()
 
-			parts := strings.Split(line, ",")
-			if len(parts) >= 2 {
-				addr := strings.TrimSpace(parts[0])
-				balanceStr := strings.TrimSpace(parts[1])
+	fmt.Printf("Imported %d allocations (%d duplicate rows merged), total %s\n",
+		len(result.Allocations), result.Duplicates, result.Total)
 
-				balance := new(big.Int)
-				balance.SetString(balanceStr, 10)
-				allocations[addr] = balance
+	if len(result.Rejected) > 0 {
+		fmt.Printf("⚠️  Rejected %d rows:\n", len(result.Rejected))
+		for i, rej := range result.Rejected {
+			if i == 10 {
+				fmt.Printf("  ... and %d more\n", len(result.Rejected)-i)
+				break
 			}
+			fmt.Printf("  %s: %s\n", rej.Source, rej.Reason)
 		}
-
-	case "json":
-		// Parse JSON format
-		if err := json.Unmarshal(data, &allocations); err != nil {
-			return fmt.Errorf("failed to parse JSON: %w", err)
+		if rejectedPath == "" {
+			return fmt.Errorf("%w (use --rejected to write a report and continue)", result.Err())
 		}
+		if err := result.WriteRejected(rejectedPath); err != nil {
+			return err
+		}
+		fmt.Printf("Rejected rows written to %s\n", rejectedPath)
+	}
 
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
+	if outputPath != "" {
+		if err := writeImportedAllocations(outputPath, result); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Allocations written to %s\n", outputPath)
 	}
 
-	fmt.Printf("Imported %d allocations\n", len(allocations))
+	return nil
+}
 
-	// If merge is true, we would merge with existing allocations
-	if merge {
-		fmt.Println("Merging with existing allocations...")
-		// Load existing allocations and merge
+// importedAllocation is an allocation as written by import allocations; the
+// file can be fed back to the importer
+type importedAllocation struct {
+	Address string   `json:"address"`
+	Amount  string   `json:"amount"` // wei
+	LUX     string   `json:"lux"`
+	Sources []string `json:"sources"`
+}
+
+// writeImportedAllocations writes the imported allocations with their
+// provenance
+func writeImportedAllocations(path string, result *importer.Result) error {
+	out := make([]importedAllocation, 0, len(result.Allocations))
+	for _, alloc := range result.Allocations {
+		sources := make([]string, len(alloc.Sources))
+		for i, src := range alloc.Sources {
+			sources[i] = src.String()
+		}
+		out = append(out, importedAllocation{
+			Address: alloc.Address,
+			Amount:  alloc.Amount.Value().String(),
+			LUX:     strings.TrimSuffix(alloc.Amount.String(), " LUX"),
+			Sources: sources,
+		})
 	}
 
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal allocations: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write allocations: %w", err)
+	}
 	return nil
 }
 
@@ -896,6 +1013,7 @@ func runImportAllocations(cmd *cobra.Command, args []string) error {
 type ValidatorInfo struct {
 	NodeID            string `json:"nodeId"`
 	ETHAddress        string `json:"ethAddress"`
+	RewardAddress     string `json:"rewardAddress,omitempty"`
 	PublicKey         string `json:"publicKey,omitempty"`
 	ProofOfPossession string `json:"proofOfPossession,omitempty"`
 	Weight            uint64 `json:"weight"`
@@ -935,8 +1053,13 @@ func runAddValidator(cmd *cobra.Command, args []string) error {
 	publicKey, _ := cmd.Flags().GetString("public-key")
 	proofOfPossession, _ := cmd.Flags().GetString("proof-of-possession")
 	weightStr, _ := cmd.Flags().GetString("weight")
+	force, _ := cmd.Flags().GetBool("force")
 
-	weight, err := parseAmount(weightStr)
+	parsed, err := amount.Parse(weightStr, amount.NLUX)
+	if err != nil {
+		return fmt.Errorf("invalid weight: %w", err)
+	}
+	weight, err := parsed.Uint64()
 	if err != nil {
 		return fmt.Errorf("invalid weight: %w", err)
 	}
@@ -952,12 +1075,33 @@ func runAddValidator(cmd *cobra.Command, args []string) error {
 		ETHAddress:        ethAddress,
 		PublicKey:         publicKey,
 		ProofOfPossession: proofOfPossession,
-		Weight:            weight.Uint64(),
+		Weight:            weight,
 		DelegationFee:     20000,
 	}
 
 	validators = append(validators, newValidator)
 
+	// Refuse entries the registry check would flag
+	network, err := config.GetNetwork(cfg.Network)
+	if err != nil {
+		return err
+	}
+	var rejected []genesis.RegistryIssue
+	for _, issue := range genesis.CheckRegistry(registryEntries(validators), network.MinValidatorStake) {
+		if issue.Index == len(validators)-1 {
+			rejected = append(rejected, issue)
+		}
+	}
+	if len(rejected) > 0 {
+		for _, issue := range rejected {
+			fmt.Printf("❌ %s\n", issue.Message)
+		}
+		if !force {
+			return fmt.Errorf("validator %s failed registry checks (use --force to add it anyway)", nodeID)
+		}
+		fmt.Println("⚠️  Adding anyway (--force)")
+	}
+
 	if err := saveValidators(validators, cfg.ValidatorsFile); err != nil {
 		return err
 	}
@@ -1012,34 +1156,118 @@ func runRemoveValidator(cmd *cobra.Command, args []string) error {
 }
 
 func runGenerateValidators(cmd *cobra.Command, args []string) error {
-	_, _ = cmd.Flags().GetString("mnemonic") // In production, would use this for BIP39
+	mnemonic, _ := cmd.Flags().GetString("mnemonic")
+	newMnemonic, _ := cmd.Flags().GetBool("new-mnemonic")
 	offsetsStr, _ := cmd.Flags().GetString("offsets")
+	saveKeysFile, _ := cmd.Flags().GetString("save-keys")
+	saveKeysDir, _ := cmd.Flags().GetString("save-keys-dir")
+	passwordFile, _ := cmd.Flags().GetString("password-file")
+	kdfName, _ := cmd.Flags().GetString("kdf")
+	plaintext, _ := cmd.Flags().GetBool("plaintext")
+
+	var password string
+	kdf, err := keystore.ParseKDF(kdfName)
+	if err != nil {
+		return err
+	}
+	switch {
+	case saveKeysDir == "" || plaintext:
+	case passwordFile == "":
+		return fmt.Errorf("--password-file is required to encrypt the keys in --save-keys-dir (or pass --plaintext)")
+	default:
+		if password, err = readPasswordFile(passwordFile); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case mnemonic != "" && newMnemonic:
+		return fmt.Errorf("use either --mnemonic or --new-mnemonic")
+	case newMnemonic:
+		mnemonic, err = validator.NewMnemonic(256)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("⚠️  New mnemonic - write it down and keep it offline, it is the only backup of these keys:\n\n%s\n\n", mnemonic)
+	case mnemonic == "":
+		return fmt.Errorf("--mnemonic or --new-mnemonic is required")
+	}
+	if err := validator.ValidateMnemonic(mnemonic); err != nil {
+		return err
+	}
 
 	offsetStrs := strings.Split(offsetsStr, ",")
 	offsets := make([]int, len(offsetStrs))
 	for i, s := range offsetStrs {
 		n := 0
-		if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d", &n); err != nil {
+		if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d", &n); err != nil || n < 0 {
 			return fmt.Errorf("invalid offset: %s", s)
 		}
 		offsets[i] = n
 	}
 
+	network, err := config.GetNetwork(cfg.Network)
+	if err != nil {
+		return err
+	}
+	keygen := validator.NewKeyGenerator("")
+	keygen.SetHRP(network.HRP)
+
 	fmt.Printf("Generating %d validators...\n", len(offsets))
 	validators := make([]*ValidatorInfo, 0, len(offsets))
+	allKeys := make([]*validator.ValidatorKeys, 0, len(offsets))
 
 	for _, offset := range offsets {
-		// Simple mock validator generation - in production this would use BIP39/BIP44
+		keys, err := keygen.GenerateFromSeedWithTLS(mnemonic, offset)
+		if err != nil {
+			return fmt.Errorf("failed to generate validator %d: %w", offset, err)
+		}
 		v := &ValidatorInfo{
-			NodeID:            fmt.Sprintf("NodeID-Validator%d", offset),
-			ETHAddress:        fmt.Sprintf("0x%040d", offset),
-			PublicKey:         fmt.Sprintf("0xpubkey%d", offset),
-			ProofOfPossession: fmt.Sprintf("0xpop%d", offset),
-			Weight:            100000000000000, // 100T
-			DelegationFee:     20000,           // 2%
+			NodeID:            keys.NodeID,
+			ETHAddress:        keys.ETHAddress,
+			RewardAddress:     keys.RewardAddress,
+			PublicKey:         keys.PublicKey,
+			ProofOfPossession: keys.ProofOfPossession,
+			Weight:            network.MinValidatorStake,
+			DelegationFee:     20000, // 2%
 		}
 		validators = append(validators, v)
-		fmt.Printf("Generated validator %d: %s\n", offset, v.NodeID)
+		public := *keys.ValidatorKeys
+		if !plaintext {
+			public.PrivateKey = ""
+		}
+		allKeys = append(allKeys, &public)
+
+		if saveKeysDir != "" {
+			dir := filepath.Join(saveKeysDir, fmt.Sprintf("validator-%d", offset))
+			if plaintext {
+				if err := validator.SaveKeys(keys.ValidatorKeys, dir); err != nil {
+					return err
+				}
+				if err := validator.SaveStakingFiles(keys.TLSKeyBytes, keys.TLSCertBytes, dir); err != nil {
+					return err
+				}
+			} else if err := validator.SaveEncryptedKeys(keys, dir, password, kdf); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Generated validator %d: %s (reward %s)\n", offset, v.NodeID, v.RewardAddress)
+	}
+
+	if saveKeysFile != "" {
+		data, err := json.MarshalIndent(allKeys, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal keys: %w", err)
+		}
+		if err := os.WriteFile(saveKeysFile, data, 0600); err != nil {
+			return fmt.Errorf("failed to write keys: %w", err)
+		}
+		fmt.Printf("Saved keys to %s\n", saveKeysFile)
+	}
+	if saveKeysDir != "" && plaintext {
+		fmt.Printf("⚠️  Saved unencrypted key and staking files to %s\n", saveKeysDir)
+	} else if saveKeysDir != "" {
+		fmt.Printf("Saved encrypted keystores to %s\n", saveKeysDir)
 	}
 
 	if cfg.ValidatorsFile == "" {
@@ -1156,51 +1384,6 @@ func saveValidators(validators []*ValidatorInfo, path string) error {
 	return ioutil.WriteFile(path, data, 0644)
 }
 
-func parseAmount(s string) (*big.Int, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return nil, fmt.Errorf("empty amount")
-	}
-
-	multiplier := big.NewInt(1)
-
-	// Check for suffix
-	lastChar := s[len(s)-1]
-	switch lastChar {
-	case 'T', 't':
-		multiplier = new(big.Int).Exp(big.NewInt(10), big.NewInt(12), nil)
-		s = s[:len(s)-1]
-	case 'B', 'b':
-		multiplier = new(big.Int).Exp(big.NewInt(10), big.NewInt(9), nil)
-		s = s[:len(s)-1]
-	case 'M', 'm':
-		multiplier = new(big.Int).Exp(big.NewInt(10), big.NewInt(6), nil)
-		s = s[:len(s)-1]
-	case 'K', 'k':
-		multiplier = new(big.Int).Exp(big.NewInt(10), big.NewInt(3), nil)
-		s = s[:len(s)-1]
-	}
-
-	// Parse the number
-	f, ok := new(big.Float).SetString(s)
-	if !ok {
-		return nil, fmt.Errorf("invalid number: %s", s)
-	}
-
-	// Convert to big.Int with proper precision handling
-	fWithMultiplier := new(big.Float).SetInt(multiplier)
-	f.Mul(f, fWithMultiplier)
-
-	i := new(big.Int)
-	f.Int(i)
-
-	// Convert to wei (multiply by 10^18)
-	wei := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
-	i.Mul(i, wei)
-
-	return i, nil
-}
-
 // runReadGenesis implements the read command
 func runReadGenesis(cmd *cobra.Command, args []string) error {
 	srcPath := args[0]
@@ -2342,13 +2525,6 @@ func runTransferChaindata(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 // Subnet migration pipeline implementations
 func runAddEvmPrefix(cmd *cobra.Command, args []string) error {
 	srcPath := args[0]
@@ -21,76 +21,6 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
-func TestParseAmount(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected *big.Int
-		wantErr  bool
-	}{
-		{
-			name:     "simple number",
-			input:    "1000",
-			expected: new(big.Int).Mul(big.NewInt(1000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "with K suffix",
-			input:    "1K",
-			expected: new(big.Int).Mul(big.NewInt(1000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "with M suffix",
-			input:    "1M",
-			expected: new(big.Int).Mul(big.NewInt(1000000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "with B suffix",
-			input:    "1B",
-			expected: new(big.Int).Mul(big.NewInt(1000000000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "with T suffix",
-			input:    "2T",
-			expected: new(big.Int).Mul(big.NewInt(2000000000000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "decimal number",
-			input:    "1.5M",
-			expected: new(big.Int).Mul(big.NewInt(1500000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
-			wantErr:  false,
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: nil,
-			wantErr:  true,
-		},
-		{
-			name:     "invalid format",
-			input:    "abc",
-			expected: nil,
-			wantErr:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseAmount(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, 0, tt.expected.Cmp(result), "expected %s, got %s", tt.expected.String(), result.String())
-			}
-		})
-	}
-}
-
 func TestValidatorOperations(t *testing.T) {
 	// Create temporary directory for test files
 	tmpDir, err := ioutil.TempDir("", "genesis-test-*")
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/genesis/pkg/genesis/config"
+)
+
+// Registry flags
+var (
+	registryGenesis string
+	registryFormat  string
+	registryNoStake bool
+)
+
+// NewRegistryCommand creates the validator registry command
+func NewRegistryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Check the validator registry",
+		Long: `Check the validator registry - the JSON file edited by validators
+list/add/remove (--validators, default configs/<network>-validators.json).
+
+Weights in the registry are nLUX, the unit of the network's minimum
+validator stake.`,
+	}
+
+	cmd.AddCommand(registryCheckCmd(), registryDriftCmd())
+	return cmd
+}
+
+func registryCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify every registry entry",
+		Long: `Verify every registry entry:
+- NodeID format
+- BLS proof of possession against the public key
+- Duplicate NodeIDs and BLS public keys
+- Weight against the network's minimum validator stake
+
+With --genesis the registry is also compared with the genesis initial
+stakers, as registry drift does. The command fails if any check fails.`,
+		RunE: runRegistryCheck,
+	}
+	cmd.Flags().StringVar(&cfg.ValidatorsFile, "validators", "", "Path to validators JSON file")
+	cmd.Flags().StringVar(&registryGenesis, "genesis", "", "Also compare with the initial stakers of this genesis")
+	cmd.Flags().BoolVar(&registryNoStake, "skip-stake", false, "Skip the minimum stake check")
+	cmd.Flags().StringVar(&registryFormat, "format", "text", "Output format (text, json)")
+	return cmd
+}
+
+func registryDriftCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift <genesis.json>",
+		Short: "Report drift between the registry and a genesis",
+		Long: `Compare the registry with the initial stakers of an existing genesis and
+report validators missing on either side and stakers whose BLS signer,
+delegation fee or reward address differ. Entries without a reward address
+are compared through the P-Chain form of their ETH address, the address the
+genesis builder uses.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRegistryDrift,
+	}
+	cmd.Flags().StringVar(&cfg.ValidatorsFile, "validators", "", "Path to validators JSON file")
+	cmd.Flags().StringVar(&registryFormat, "format", "text", "Output format (text, json)")
+	return cmd
+}
+
+func runRegistryCheck(cmd *cobra.Command, args []string) error {
+	validators, network, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	minStake := network.MinValidatorStake
+	if registryNoStake {
+		minStake = 0
+	}
+	issues := genesis.CheckRegistry(validators, minStake)
+
+	var drift []genesis.RegistryIssue
+	if registryGenesis != "" {
+		g, err := genesis.LoadMainGenesis(registryGenesis)
+		if err != nil {
+			return err
+		}
+		drift = genesis.CompareRegistry(validators, g, network.HRP)
+	}
+
+	switch registryFormat {
+	case "json":
+		if err := printJSON(map[string]interface{}{"issues": issues, "drift": drift}); err != nil {
+			return err
+		}
+	case "text":
+		fmt.Printf("=== Registry Check: %s (%s) ===\n", cfg.ValidatorsFile, network.Name)
+		fmt.Printf("Validators: %d\n", len(validators))
+		fmt.Printf("Min stake:  %d nLUX\n\n", network.MinValidatorStake)
+		printRegistryIssues(issues, "All entries pass")
+		if registryGenesis != "" {
+			fmt.Printf("\n=== Drift vs %s ===\n", registryGenesis)
+			printRegistryIssues(drift, "Registry matches the genesis initial stakers")
+		}
+	default:
+		return fmt.Errorf("unknown format: %s", registryFormat)
+	}
+
+	if n := len(issues) + len(drift); n > 0 {
+		return fmt.Errorf("registry check found %d issues", n)
+	}
+	return nil
+}
+
+func runRegistryDrift(cmd *cobra.Command, args []string) error {
+	validators, network, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	g, err := genesis.LoadMainGenesis(args[0])
+	if err != nil {
+		return err
+	}
+	drift := genesis.CompareRegistry(validators, g, network.HRP)
+
+	switch registryFormat {
+	case "json":
+		if err := printJSON(drift); err != nil {
+			return err
+		}
+	case "text":
+		fmt.Printf("=== Registry Drift: %s vs %s ===\n", cfg.ValidatorsFile, args[0])
+		fmt.Printf("Registry validators: %d\n", len(validators))
+		fmt.Printf("Genesis stakers:     %d\n\n", len(g.InitialStakers))
+		printRegistryIssues(drift, "Registry matches the genesis initial stakers")
+	default:
+		return fmt.Errorf("unknown format: %s", registryFormat)
+	}
+
+	if len(drift) > 0 {
+		return fmt.Errorf("registry differs from the genesis in %d places", len(drift))
+	}
+	return nil
+}
+
+// loadRegistry reads the validators file and the network it belongs to
+func loadRegistry() ([]genesis.ValidatorInfo, *config.NetworkConfig, error) {
+	network, err := config.GetNetwork(cfg.Network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.ValidatorsFile == "" {
+		cfg.ValidatorsFile = fmt.Sprintf("configs/%s-validators.json", cfg.Network)
+	}
+	validators, err := loadValidators(cfg.ValidatorsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load validators: %w", err)
+	}
+	return registryEntries(validators), network, nil
+}
+
+// registryEntries converts validators file entries for the registry checks
+func registryEntries(validators []*ValidatorInfo) []genesis.ValidatorInfo {
+	entries := make([]genesis.ValidatorInfo, len(validators))
+	for i, v := range validators {
+		entries[i] = genesis.ValidatorInfo{
+			NodeID:            v.NodeID,
+			ETHAddress:        v.ETHAddress,
+			RewardAddress:     v.RewardAddress,
+			PublicKey:         v.PublicKey,
+			ProofOfPossession: v.ProofOfPossession,
+			Weight:            v.Weight,
+			DelegationFee:     uint32(v.DelegationFee),
+		}
+	}
+	return entries
+}
+
+// printRegistryIssues prints one line per issue, or ok if there are none
+func printRegistryIssues(issues []genesis.RegistryIssue, ok string) {
+	if len(issues) == 0 {
+		fmt.Printf("✅ %s\n", ok)
+		return
+	}
+	for _, issue := range issues {
+		fmt.Printf("❌ [%s] %s\n", issue.Kind, issue)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+)
+
+// Supply projection flags
+var (
+	supplyFormat string
+	supplyAt     string
+	supplyStrict bool
+)
+
+// NewSupplyCommand creates the circulating supply projection command
+func NewSupplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply <genesis.json>",
+		Short: "Project circulating, locked and staked supply of a built genesis",
+		Long: `Project how the supply of a built genesis unlocks over time.
+
+The series starts at the genesis start time and has one row per change:
+- P/X initial amounts and the C-Chain alloc circulate from the start
+- unlock schedule entries are locked until their locktime
+- the unlock schedules of initialStakedFunds addresses are split across the
+  initial stakers and staked until each staker's end time, then locked until
+  their locktime
+
+With --at the supply on a single date is printed instead.
+
+Warnings are reported for locktimes before the start time, staked locktimes
+beyond the initial stake duration, and staked funds that cannot be matched to
+an allocation or staker. With --strict any warning fails the command.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSupply,
+	}
+
+	cmd.Flags().StringVar(&supplyFormat, "format", "text", "Output format (text, csv, json)")
+	cmd.Flags().StringVar(&supplyAt, "at", "", "Report the supply at this date (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&supplyStrict, "strict", false, "Fail if the schedules have warnings")
+
+	return cmd
+}
+
+func runSupply(cmd *cobra.Command, args []string) error {
+	g, err := genesis.LoadMainGenesis(args[0])
+	if err != nil {
+		return err
+	}
+	projection, err := genesis.ProjectSupply(g)
+	if err != nil {
+		return fmt.Errorf("failed to project supply: %w", err)
+	}
+
+	if supplyAt != "" {
+		at, err := parseSupplyDate(supplyAt)
+		if err != nil {
+			return err
+		}
+		point := projection.At(uint64(at.Unix()))
+		switch supplyFormat {
+		case "json":
+			if err := printJSON(point); err != nil {
+				return err
+			}
+		case "text", "csv":
+			projection.PrintPoint(os.Stdout, point)
+		default:
+			return fmt.Errorf("unknown format: %s", supplyFormat)
+		}
+	} else {
+		switch supplyFormat {
+		case "json":
+			if err := printJSON(projection); err != nil {
+				return err
+			}
+		case "csv":
+			if err := projection.WriteCSV(os.Stdout); err != nil {
+				return err
+			}
+			projection.PrintWarnings(os.Stderr)
+		case "text":
+			projection.Print(os.Stdout)
+		default:
+			return fmt.Errorf("unknown format: %s", supplyFormat)
+		}
+	}
+
+	if supplyStrict && len(projection.Warnings) > 0 {
+		return fmt.Errorf("%d schedule warnings", len(projection.Warnings))
+	}
+	return nil
+}
+
+// parseSupplyDate parses an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseSupplyDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected RFC 3339 or YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
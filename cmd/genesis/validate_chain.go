@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis/chains"
+)
+
+// validateChainCmd creates the command validating an A/B/M/Q/Z chain genesis
+func validateChainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "chain <type> <genesis.json>",
+		Short: "Validate an A, B, M, Q or Z chain genesis file",
+		Long: `Validate a custom chain genesis against its typed schema.
+
+The type is the chain alias (A, B, M, Q, Z) or its name (ai, bridge, mpc,
+quantum, zk). Unknown fields are rejected, and the chain's rules are
+checked, for example:
+- B/M: thresholds between 1 and the number of signers, non-zero unique
+  signer keys, non-zero bridge and treasury addresses
+- A: agents reference registered GPU providers and models
+- Q: the signature algorithm is active, migration phases are in order
+- Z: circuits carry a real SRS hash and fit maxConstraints`,
+		Args: cobra.ExactArgs(2),
+		RunE: runValidateChain,
+	}
+}
+
+func runValidateChain(cmd *cobra.Command, args []string) error {
+	chainType, err := chains.ParseType(args[0])
+	if err != nil {
+		return err
+	}
+
+	g, err := chains.Load(chainType, args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := g.Validate(); err != nil {
+		var verr *chains.ValidationError
+		if errors.As(err, &verr) {
+			fmt.Printf("✗ Invalid %s-Chain genesis: %s\n", chainType, args[1])
+			for _, problem := range verr.Problems {
+				fmt.Printf("  - %s\n", problem)
+			}
+			return fmt.Errorf("%d problems found", len(verr.Problems))
+		}
+		return err
+	}
+
+	fmt.Printf("✓ Valid %s-Chain genesis: %s\n", chainType, args[1])
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+	"github.com/luxfi/genesis/pkg/genesis/config"
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+	"github.com/luxfi/genesis/pkg/genesis/validator"
+)
+
+// Validator import flags
+var (
+	importBatch         bool
+	importETHAddress    string
+	importWeight        string
+	importDelegationFee uint32
+	importManifest      string
+	importSignKey       string
+	importPasswordFile  string
+	importDryRun        bool
+)
+
+func validatorsImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <staking-dir>",
+		Short: "Import node staking directories into the validator registry",
+		Long: `Import a luxd staking directory (staker.crt, staker.key, signer.key) into
+the validator registry. The NodeID, BLS public key and proof of possession
+are derived from the files and the staking key is checked against the
+certificate. Private keys are never copied.
+
+With --batch the argument is a directory of operator submissions, one
+staking directory per subdirectory. A submission may include operator.json
+with name, ethAddress, rewardAddress, weight (nLUX) and delegationFee, which
+override the flag defaults.
+
+Each submission is checked like registry check (NodeID, proof of possession,
+duplicates, minimum stake); rejected submissions are not added. The outcome
+is written to --manifest and, with --sign-key, signed off with a secp256k1
+keystore (e.g. a reward.keystore.json). Check it with validators
+verify-manifest.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportValidators,
+	}
+	cmd.Flags().StringVar(&cfg.ValidatorsFile, "validators", "", "Path to validators JSON file")
+	cmd.Flags().BoolVar(&importBatch, "batch", false, "Import every submission in a directory of staking directories")
+	cmd.Flags().StringVar(&importETHAddress, "eth-address", "", "Reward ETH address (default for submissions without operator.json)")
+	cmd.Flags().StringVar(&importWeight, "weight", "", "Validator stake in LUX (default: the network minimum)")
+	cmd.Flags().Uint32Var(&importDelegationFee, "delegation-fee", 20000, "Delegation fee (20000 = 2%)")
+	cmd.Flags().StringVar(&importManifest, "manifest", "validators-import-manifest.json", "Manifest file to write")
+	cmd.Flags().StringVar(&importSignKey, "sign-key", "", "secp256k1 keystore to sign off the manifest")
+	cmd.Flags().StringVar(&importPasswordFile, "password-file", "", "File with the --sign-key password")
+	cmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Check and write the manifest without changing the registry")
+	return cmd
+}
+
+func validatorsVerifyManifestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-manifest <manifest.json>",
+		Short: "Verify the sign-off of an import manifest",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runVerifyManifest,
+	}
+}
+
+func runImportValidators(cmd *cobra.Command, args []string) error {
+	network, err := config.GetNetwork(cfg.Network)
+	if err != nil {
+		return err
+	}
+	weight := network.MinValidatorStake
+	if importWeight != "" {
+		parsed, err := amount.Parse(importWeight, amount.NLUX)
+		if err != nil {
+			return fmt.Errorf("invalid weight: %w", err)
+		}
+		if weight, err = parsed.Uint64(); err != nil {
+			return fmt.Errorf("invalid weight: %w", err)
+		}
+	}
+
+	// Load the sign-off key first so a bad password fails before any change
+	var signKey *secp256k1.PrivateKey
+	if importSignKey != "" {
+		if signKey, err = loadSigningKey(importSignKey, importPasswordFile); err != nil {
+			return err
+		}
+	}
+
+	if cfg.ValidatorsFile == "" {
+		cfg.ValidatorsFile = fmt.Sprintf("configs/%s-validators.json", cfg.Network)
+	}
+	validators, err := loadValidators(cfg.ValidatorsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load validators: %w", err)
+	}
+	existing := len(validators)
+
+	dirs := []string{args[0]}
+	if importBatch {
+		if dirs, err = validator.FindSubmissions(args[0]); err != nil {
+			return err
+		}
+	}
+
+	manifest := &validator.Manifest{
+		Version:   validator.ManifestVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Network:   network.Name,
+		Registry:  cfg.ValidatorsFile,
+	}
+	// entryOf maps registry indexes of candidates to manifest entries
+	entryOf := make(map[int]int)
+	for _, dir := range dirs {
+		entry := validator.ManifestEntry{Name: filepath.Base(dir), Source: dir}
+		s, err := validator.ImportStakingDir(dir)
+		if err != nil {
+			entry.Status = validator.StatusRejected
+			entry.Issues = []string{err.Error()}
+			manifest.Entries = append(manifest.Entries, entry)
+			continue
+		}
+
+		v := &ValidatorInfo{
+			NodeID:            s.Keys.NodeID,
+			ETHAddress:        importETHAddress,
+			PublicKey:         s.Keys.PublicKey,
+			ProofOfPossession: s.Keys.ProofOfPossession,
+			Weight:            weight,
+			DelegationFee:     uint64(importDelegationFee),
+		}
+		if op := s.Operator; op != nil {
+			if op.Name != "" {
+				entry.Name = op.Name
+			}
+			if op.ETHAddress != "" {
+				v.ETHAddress = op.ETHAddress
+			}
+			v.RewardAddress = op.RewardAddress
+			if op.Weight != 0 {
+				v.Weight = op.Weight
+			}
+			if op.DelegationFee != 0 {
+				v.DelegationFee = uint64(op.DelegationFee)
+			}
+		}
+		entry.NodeID = v.NodeID
+		entry.PublicKey = v.PublicKey
+		entry.ProofOfPossession = v.ProofOfPossession
+		entry.CertificateSHA256 = s.CertificateSHA256
+		entry.ETHAddress = v.ETHAddress
+		entry.Weight = v.Weight
+		entry.Status = validator.StatusAccepted
+		if v.ETHAddress == "" {
+			entry.Status = validator.StatusRejected
+			entry.Issues = []string{"no ethAddress (set it in operator.json or with --eth-address)"}
+		} else {
+			entryOf[len(validators)] = len(manifest.Entries)
+			validators = append(validators, v)
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	// Check the candidates against each other and the existing registry
+	for _, issue := range genesis.CheckRegistry(registryEntries(validators), network.MinValidatorStake) {
+		if i, ok := entryOf[issue.Index]; ok {
+			manifest.Entries[i].Status = validator.StatusRejected
+			manifest.Entries[i].Issues = append(manifest.Entries[i].Issues, issue.Message)
+		}
+	}
+	accepted := append([]*ValidatorInfo(nil), validators[:existing]...)
+	for i, v := range validators[existing:] {
+		if manifest.Entries[entryOf[existing+i]].Status == validator.StatusAccepted {
+			accepted = append(accepted, v)
+		}
+	}
+
+	fmt.Printf("=== Validator Import: %s ===\n", args[0])
+	for _, e := range manifest.Entries {
+		if e.Status == validator.StatusAccepted {
+			fmt.Printf("✅ %s: %s\n", e.Name, e.NodeID)
+			continue
+		}
+		fmt.Printf("❌ %s: rejected\n", e.Name)
+		for _, issue := range e.Issues {
+			fmt.Printf("   - %s\n", issue)
+		}
+	}
+
+	if signKey != nil {
+		if err := manifest.Sign(signKey); err != nil {
+			return err
+		}
+	}
+	if err := manifest.Save(importManifest); err != nil {
+		return err
+	}
+
+	switch {
+	case importDryRun:
+		fmt.Printf("\nDry run: %s not changed\n", cfg.ValidatorsFile)
+	case manifest.Accepted() > 0:
+		if err := saveValidators(accepted, cfg.ValidatorsFile); err != nil {
+			return err
+		}
+		fmt.Printf("\nAdded %d validators to %s (total %d)\n", manifest.Accepted(), cfg.ValidatorsFile, len(accepted))
+	}
+	if signKey != nil {
+		fmt.Printf("Manifest: %s (signed off by %s)\n", importManifest, manifest.SignedOffBy)
+	} else {
+		fmt.Printf("Manifest: %s\n", importManifest)
+		fmt.Println("⚠️  Manifest not signed off (use --sign-key)")
+	}
+
+	if rejected := len(manifest.Entries) - manifest.Accepted(); rejected > 0 {
+		return fmt.Errorf("%d of %d submissions rejected", rejected, len(manifest.Entries))
+	}
+	return nil
+}
+
+func runVerifyManifest(cmd *cobra.Command, args []string) error {
+	manifest, err := validator.LoadManifest(args[0])
+	if err != nil {
+		return err
+	}
+	if err := manifest.Verify(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ %s signed off by %s\n", args[0], manifest.SignedOffBy)
+	fmt.Printf("   %d of %d submissions accepted into %s (%s, %s)\n",
+		manifest.Accepted(), len(manifest.Entries), manifest.Registry, manifest.Network, manifest.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+// loadSigningKey decrypts a secp256k1 keystore
+func loadSigningKey(path, passwordFile string) (*secp256k1.PrivateKey, error) {
+	if passwordFile == "" {
+		return nil, fmt.Errorf("--password-file is required with --sign-key")
+	}
+	password, err := readPasswordFile(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	k, err := keystore.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if k.Type != keystore.TypeSecp256k1 {
+		return nil, fmt.Errorf("%s holds a %s key, need secp256k1", path, k.Type)
+	}
+	secret, err := k.Decrypt(password)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.PrivKeyFromBytes(secret), nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+)
+
+// Validator report flags
+var (
+	reportGenesis         string
+	reportMaxAddressShare float64
+	reportLimit           int
+	reportFormat          string
+	reportStrict          bool
+)
+
+func validatorsReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report how stake weight is distributed",
+		Long: `Report how stake weight is distributed over the validator set: total
+weight, each validator's share, the Nakamoto coefficients and the weight
+behind each reward address.
+
+The Nakamoto coefficient is the smallest number of validators whose
+combined weight is more than 33% (enough to halt consensus) or 50% of the
+total. It is also given per reward address, since validators paying the
+same address are usually run by one operator.
+
+The registry (--validators) is used by default. With --genesis the initial
+stakers of that genesis are reported instead; their weight is the
+InitialStakedFunds allocations split evenly across them.`,
+		RunE: runValidatorsReport,
+	}
+	cmd.Flags().StringVar(&cfg.ValidatorsFile, "validators", "", "Path to validators JSON file")
+	cmd.Flags().StringVar(&reportGenesis, "genesis", "", "Report the initial stakers of this genesis instead of the registry")
+	cmd.Flags().Float64Var(&reportMaxAddressShare, "max-address-share", 33, "Warn when one reward address controls more than this percent of the weight")
+	cmd.Flags().IntVar(&reportLimit, "limit", 0, "Show only the largest N validators and addresses (0 = all)")
+	cmd.Flags().StringVar(&reportFormat, "format", "text", "Output format (text, json)")
+	cmd.Flags().BoolVar(&reportStrict, "strict", false, "Fail if there are warnings")
+	return cmd
+}
+
+func runValidatorsReport(cmd *cobra.Command, args []string) error {
+	var (
+		entries []genesis.StakeEntry
+		source  string
+	)
+	if reportGenesis != "" {
+		g, err := genesis.LoadMainGenesis(reportGenesis)
+		if err != nil {
+			return err
+		}
+		entries = genesis.GenesisStake(g)
+		source = reportGenesis
+	} else {
+		validators, network, err := loadRegistry()
+		if err != nil {
+			return err
+		}
+		if entries, err = genesis.RegistryStake(validators, network.HRP); err != nil {
+			return err
+		}
+		source = cfg.ValidatorsFile
+	}
+
+	report := genesis.WeightDistribution(entries, reportMaxAddressShare)
+
+	switch reportFormat {
+	case "json":
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	case "text":
+		printDistributionReport(report, source)
+	default:
+		return fmt.Errorf("unknown format: %s", reportFormat)
+	}
+
+	if reportStrict && len(report.Warnings) > 0 {
+		return fmt.Errorf("weight report has %d warnings", len(report.Warnings))
+	}
+	return nil
+}
+
+// printDistributionReport prints the report as tables, largest first
+func printDistributionReport(r *genesis.DistributionReport, source string) {
+	fmt.Printf("=== Validator Weight Report: %s ===\n", source)
+	fmt.Printf("Validators:       %d\n", r.Validators)
+	fmt.Printf("Reward addresses: %d\n", len(r.RewardAddresses))
+	fmt.Printf("Total weight:     %s\n", r.TotalWeight)
+	fmt.Printf("Nakamoto (33%%):   %d validators, %d reward addresses\n", r.Nakamoto33, r.AddressNakamoto33)
+	fmt.Printf("Nakamoto (50%%):   %d validators, %d reward addresses\n", r.Nakamoto50, r.AddressNakamoto50)
+
+	fmt.Printf("\n=== Validators ===\n")
+	fmt.Printf("%-42s %-50s %28s %8s\n", "NodeID", "Reward Address", "Weight", "Share")
+	for i, v := range r.Shares {
+		if reportLimit > 0 && i == reportLimit {
+			fmt.Printf("... %d more\n", len(r.Shares)-i)
+			break
+		}
+		fmt.Printf("%-42s %-50s %28s %7.2f%%\n", v.NodeID, v.RewardAddress, v.Weight, v.Share)
+	}
+
+	fmt.Printf("\n=== Reward Addresses ===\n")
+	fmt.Printf("%-50s %10s %28s %8s\n", "Address", "Validators", "Weight", "Share")
+	for i, a := range r.RewardAddresses {
+		if reportLimit > 0 && i == reportLimit {
+			fmt.Printf("... %d more\n", len(r.RewardAddresses)-i)
+			break
+		}
+		fmt.Printf("%-50s %10d %28s %7.2f%%\n", a.Address, a.Validators, a.Weight, a.Share)
+	}
+
+	fmt.Println()
+	if len(r.Warnings) == 0 {
+		fmt.Printf("✅ No reward address controls more than %.2f%% of the weight\n", r.MaxAddressShare)
+		return
+	}
+	for _, w := range r.Warnings {
+		fmt.Printf("⚠️  %s\n", w)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luxfi/genesis/pkg/genesis/validator"
+)
+
+// Validator signer flags
+var (
+	popSignerURL string
+	popKeyID     string
+	popTokenFile string
+	popSignerKey string
+	popNodeID    string
+)
+
+func validatorsPoPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pop",
+		Short: "Get a BLS public key and proof of possession from a signer",
+		Long: `Get a BLS public key and proof of possession from a signer and check the
+proof before using it.
+
+With --signer-url the key stays in an external signer service (JSON over
+HTTP, see validator.RemoteSigner), so no BLS private key is needed on the
+genesis build host. --signer-key reads a local signer.key instead.
+
+With --node-id the registry entry of that validator is updated.`,
+		RunE: runValidatorPoP,
+	}
+	cmd.Flags().StringVar(&popSignerURL, "signer-url", "", "Remote signer service URL")
+	cmd.Flags().StringVar(&popKeyID, "key-id", "", "Key ID at the remote signer")
+	cmd.Flags().StringVar(&popTokenFile, "token-file", "", "File with the remote signer bearer token")
+	cmd.Flags().StringVar(&popSignerKey, "signer-key", "", "Local signer.key (raw 32 byte BLS key)")
+	cmd.Flags().StringVar(&popNodeID, "node-id", "", "Update this validator in the registry")
+	cmd.Flags().StringVar(&cfg.ValidatorsFile, "validators", "", "Path to validators JSON file")
+	return cmd
+}
+
+func runValidatorPoP(cmd *cobra.Command, args []string) error {
+	var signer validator.Signer
+	switch {
+	case popSignerURL != "" && popSignerKey != "":
+		return fmt.Errorf("use either --signer-url or --signer-key")
+	case popSignerURL != "":
+		if popKeyID == "" {
+			return fmt.Errorf("--key-id is required with --signer-url")
+		}
+		var token string
+		if popTokenFile != "" {
+			var err error
+			if token, err = readPasswordFile(popTokenFile); err != nil {
+				return err
+			}
+		}
+		signer = validator.NewRemoteSigner(popSignerURL, popKeyID, token)
+	case popSignerKey != "":
+		key, err := os.ReadFile(popSignerKey)
+		if err != nil {
+			return fmt.Errorf("failed to read signer key: %w", err)
+		}
+		if signer, err = validator.NewLocalSigner(key); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--signer-url or --signer-key is required")
+	}
+
+	publicKey, pop, err := validator.ProofOfPossession(cmd.Context(), signer)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Public key:          %s\n", publicKey)
+	fmt.Printf("Proof of possession: %s\n", pop)
+
+	if popNodeID == "" {
+		return nil
+	}
+	if cfg.ValidatorsFile == "" {
+		cfg.ValidatorsFile = fmt.Sprintf("configs/%s-validators.json", cfg.Network)
+	}
+	validators, err := loadValidators(cfg.ValidatorsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load validators: %w", err)
+	}
+	for _, v := range validators {
+		if v.NodeID == popNodeID {
+			v.PublicKey = publicKey
+			v.ProofOfPossession = pop
+			if err := saveValidators(validators, cfg.ValidatorsFile); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Updated %s in %s\n", popNodeID, cfg.ValidatorsFile)
+			return nil
+		}
+	}
+	return fmt.Errorf("validator %s not found in %s", popNodeID, cfg.ValidatorsFile)
+}
@@ -0,0 +1,359 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+// TransferTopic is the topic of the ERC-20 and ERC-721 Transfer event
+var TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Account event kinds
+const (
+	EventTxSent        = "tx-sent"
+	EventTxReceived    = "tx-received"
+	EventContract      = "contract-created"
+	EventERC20         = "erc20-transfer"
+	EventERC721        = "erc721-transfer"
+	EventBalance       = "balance-change"
+	EventNonce         = "nonce-change"
+	EventStorage       = "storage-change"
+	EventCodeChange    = "code-change"
+	EventStateMissing  = "state-unavailable"
+	EventStateRestored = "state-available"
+)
+
+// AccountEvent is one entry in an account's history
+type AccountEvent struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	Timestamp   uint64 `json:"timestamp"`
+	Kind        string `json:"kind"`
+	TxHash      string `json:"txHash,omitempty"`
+	From        string `json:"from,omitempty"`
+	To          string `json:"to,omitempty"`
+	Token       string `json:"token,omitempty"`
+	Value       string `json:"value,omitempty"` // Wei, token units or token ID
+	Status      string `json:"status,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// AccountState is the account as read from a historical state root
+type AccountState struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	Balance     string      `json:"balance"`
+	Nonce       uint64      `json:"nonce"`
+	StorageRoot common.Hash `json:"storageRoot"`
+	CodeHash    common.Hash `json:"codeHash"`
+}
+
+// AccountHistory is the reconstructed history of an address
+type AccountHistory struct {
+	Address   common.Address `json:"address"`
+	FromBlock uint64         `json:"fromBlock"`
+	ToBlock   uint64         `json:"toBlock"`
+
+	// Blocks whose state root was present; balance, nonce and storage changes
+	// are only detected between consecutive blocks with state
+	StateBlocks uint64        `json:"stateBlocks"`
+	FirstState  *AccountState `json:"firstState,omitempty"`
+	LastState   *AccountState `json:"lastState,omitempty"`
+
+	TxSent         int `json:"txSent"`
+	TxReceived     int `json:"txReceived"`
+	TokenTransfers int `json:"tokenTransfers"`
+	StateChanges   int `json:"stateChanges"`
+
+	Events []AccountEvent `json:"events"`
+}
+
+// AccountHistoryConfig configures an account history reconstruction
+type AccountHistoryConfig struct {
+	Address   common.Address
+	FromBlock uint64
+	ToBlock   uint64
+	State     bool // Diff the account against historical state roots where present
+
+	// Progress, if set, is called every ProgressInterval blocks
+	Progress         func(number uint64, h *AccountHistory)
+	ProgressInterval uint64
+}
+
+// BuildAccountHistory walks canonical blocks in [FromBlock, ToBlock] and
+// collects every transaction sent or received by the address, every ERC-20
+// and ERC-721 Transfer involving it and, where historical state is present,
+// every block in which its balance, nonce, code or storage changed.
+//
+// Receipts are read for the blocks the `index logs` address index lists for
+// the address within the range it covers, and for blocks whose header bloom
+// may match it elsewhere.
+func BuildAccountHistory(r *rawdb.Reader, config AccountHistoryConfig) (*AccountHistory, error) {
+	h := &AccountHistory{
+		Address:   config.Address,
+		FromBlock: config.FromBlock,
+		ToBlock:   config.ToBlock,
+	}
+	addrTopic := common.BytesToHash(config.Address.Bytes())
+	indexed, logBlocks, err := indexedLogBlocks(r, config.Address, config.FromBlock, config.ToBlock)
+	if err != nil {
+		return h, err
+	}
+
+	var (
+		prevState    *types.StateAccount
+		prevRoot     common.Hash
+		stateKnown   bool
+		stateMissing bool
+	)
+
+	for number := config.FromBlock; number <= config.ToBlock; number++ {
+		hash, err := r.ReadCanonicalHash(number)
+		if err != nil {
+			return h, fmt.Errorf("missing canonical hash for block %d: %w", number, err)
+		}
+		header, err := r.ReadHeader(number, hash)
+		if err != nil {
+			return h, err
+		}
+		body, err := r.ReadBody(number, hash)
+		if err != nil {
+			return h, err
+		}
+
+		txEvents := make(map[int]int) // Tx index -> event index, to attach receipt status
+		for i, tx := range body.Transactions {
+			event, ok := txEvent(tx, config.Address)
+			if !ok {
+				continue
+			}
+			event.BlockNumber = number
+			event.Timestamp = header.Time
+			txEvents[i] = len(h.Events)
+			h.Events = append(h.Events, event)
+			switch event.Kind {
+			case EventTxSent:
+				h.TxSent++
+			case EventTxReceived:
+				h.TxReceived++
+			}
+		}
+
+		hasLogs := header.Bloom.Test(config.Address.Bytes()) || header.Bloom.Test(addrTopic.Bytes())
+		if indexed.Contains(number) {
+			hasLogs = logBlocks[number]
+		}
+		if len(txEvents) > 0 || hasLogs {
+			receipts, err := r.ReadReceipts(number, hash, body)
+			if err != nil && !errors.Is(err, rawdb.ErrNotFound) {
+				return h, err
+			}
+			for i, receipt := range receipts {
+				if idx, ok := txEvents[i]; ok {
+					h.Events[idx].Status = receiptStatus(receipt.Status)
+				}
+				for _, l := range receipt.Logs {
+					if event, ok := transferEvent(l, addrTopic); ok {
+						event.BlockNumber = number
+						event.Timestamp = header.Time
+						h.Events = append(h.Events, event)
+						h.TokenTransfers++
+					}
+				}
+			}
+		}
+
+		if config.State && header.Root != prevRoot {
+			prevRoot = header.Root
+			account, err := r.ReadAccount(header.Root, config.Address)
+			switch {
+			case errors.Is(err, rawdb.ErrMissingTrieNode):
+				if stateKnown && !stateMissing {
+					h.Events = append(h.Events, AccountEvent{BlockNumber: number, Timestamp: header.Time, Kind: EventStateMissing})
+				}
+				stateMissing = true
+			case err != nil:
+				return h, err
+			default:
+				if stateMissing {
+					h.Events = append(h.Events, AccountEvent{BlockNumber: number, Timestamp: header.Time, Kind: EventStateRestored})
+				}
+				if stateKnown {
+					h.StateChanges += diffAccount(h, number, header.Time, prevState, account)
+				}
+				prevState = account
+				stateKnown = true
+				stateMissing = false
+
+				snapshot := accountState(number, account)
+				if h.FirstState == nil {
+					h.FirstState = snapshot
+				}
+				h.LastState = snapshot
+			}
+		}
+		if config.State && !stateMissing && stateKnown {
+			h.StateBlocks++
+		}
+
+		if config.Progress != nil && config.ProgressInterval > 0 && number%config.ProgressInterval == 0 {
+			config.Progress(number, h)
+		}
+		if number == config.ToBlock {
+			break // Avoid overflow when ToBlock is the maximum uint64
+		}
+	}
+	return h, nil
+}
+
+// txEvent returns the history entry for tx if it involves addr
+func txEvent(tx *types.Transaction, addr common.Address) (AccountEvent, bool) {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return AccountEvent{}, false
+	}
+
+	event := AccountEvent{
+		TxHash: tx.Hash().Hex(),
+		From:   sender.Hex(),
+		Value:  tx.Value().String(),
+	}
+	switch {
+	case tx.To() == nil:
+		created := crypto.CreateAddress(sender, tx.Nonce())
+		event.To = created.Hex()
+		if created == addr {
+			event.Kind = EventContract
+			return event, true
+		}
+		event.Kind = EventTxSent
+		event.Detail = "contract creation"
+	case *tx.To() == addr:
+		event.To = tx.To().Hex()
+		event.Kind = EventTxReceived
+		if sender == addr {
+			event.Detail = "self"
+		}
+		return event, true
+	default:
+		event.To = tx.To().Hex()
+		event.Kind = EventTxSent
+	}
+	return event, sender == addr
+}
+
+// indexedLogBlocks returns the range recorded by `index logs` and the blocks
+// in [from, to] it lists for addr. The range is empty when there is no index.
+func indexedLogBlocks(r *rawdb.Reader, addr common.Address, from, to uint64) (rawdb.IndexRange, map[uint64]bool, error) {
+	data, err := r.Get(rawdb.LogIndexRangeKey)
+	if errors.Is(err, rawdb.ErrNotFound) {
+		return rawdb.IndexRange{}, nil, nil
+	}
+	if err != nil {
+		return rawdb.IndexRange{}, nil, fmt.Errorf("failed to read log index range: %w", err)
+	}
+	covered, ok := rawdb.DecodeIndexRange(data)
+	if !ok || covered.Next <= from || covered.Start > to {
+		return rawdb.IndexRange{}, nil, nil
+	}
+	last := min(to, covered.Next-1)
+
+	blocks := make(map[uint64]bool)
+	prefix := rawdb.LogIndexAddressPrefix(addr)
+	start := rawdb.LogIndexKey(addr, max(from, covered.Start))
+	err = r.ScanKeys(prefix, start, func(key, _ []byte) bool {
+		number, _ := rawdb.DecodeBlockNumber(key[len(prefix):])
+		if number > last {
+			return false
+		}
+		blocks[number] = true
+		return true
+	})
+	if err != nil {
+		return rawdb.IndexRange{}, nil, fmt.Errorf("failed to read log index: %w", err)
+	}
+	return covered, blocks, nil
+}
+
+// transferEvent decodes an ERC-20 or ERC-721 Transfer log involving the
+// address whose left-padded form is addrTopic
+func transferEvent(l *types.Log, addrTopic common.Hash) (AccountEvent, bool) {
+	if len(l.Topics) < 3 || l.Topics[0] != TransferTopic {
+		return AccountEvent{}, false
+	}
+	if l.Topics[1] != addrTopic && l.Topics[2] != addrTopic {
+		return AccountEvent{}, false
+	}
+
+	event := AccountEvent{
+		TxHash: l.TxHash.Hex(),
+		From:   common.BytesToAddress(l.Topics[1].Bytes()).Hex(),
+		To:     common.BytesToAddress(l.Topics[2].Bytes()).Hex(),
+		Token:  l.Address.Hex(),
+	}
+	switch {
+	case len(l.Topics) == 4:
+		event.Kind = EventERC721
+		event.Value = l.Topics[3].Big().String()
+	case len(l.Topics) == 3 && len(l.Data) == 32:
+		event.Kind = EventERC20
+		event.Value = new(big.Int).SetBytes(l.Data).String()
+	default:
+		return AccountEvent{}, false
+	}
+	return event, true
+}
+
+// diffAccount appends an event for every field that differs between two
+// consecutive states and returns how many were appended
+func diffAccount(h *AccountHistory, number, timestamp uint64, prev, next *types.StateAccount) int {
+	before, after := accountState(number, prev), accountState(number, next)
+	changes := 0
+	add := func(kind, detail string) {
+		h.Events = append(h.Events, AccountEvent{BlockNumber: number, Timestamp: timestamp, Kind: kind, Detail: detail})
+		changes++
+	}
+
+	if before.Balance != after.Balance {
+		add(EventBalance, fmt.Sprintf("%s -> %s", before.Balance, after.Balance))
+	}
+	if before.Nonce != after.Nonce {
+		add(EventNonce, fmt.Sprintf("%d -> %d", before.Nonce, after.Nonce))
+	}
+	if before.CodeHash != after.CodeHash {
+		add(EventCodeChange, fmt.Sprintf("%s -> %s", before.CodeHash.Hex(), after.CodeHash.Hex()))
+	}
+	if before.StorageRoot != after.StorageRoot {
+		add(EventStorage, fmt.Sprintf("%s -> %s", before.StorageRoot.Hex(), after.StorageRoot.Hex()))
+	}
+	return changes
+}
+
+// accountState snapshots an account; a nil account is the empty account
+func accountState(number uint64, account *types.StateAccount) *AccountState {
+	state := &AccountState{
+		BlockNumber: number,
+		Balance:     "0",
+		StorageRoot: types.EmptyRootHash,
+		CodeHash:    types.EmptyCodeHash,
+	}
+	if account != nil {
+		state.Balance = account.Balance.ToBig().String()
+		state.Nonce = account.Nonce
+		state.StorageRoot = account.Root
+		state.CodeHash = common.BytesToHash(account.CodeHash)
+	}
+	return state
+}
+
+func receiptStatus(status uint64) string {
+	if status == types.ReceiptStatusSuccessful {
+		return "success"
+	}
+	return "failed"
+}
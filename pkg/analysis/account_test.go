@@ -0,0 +1,132 @@
+package analysis
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+func TestTransferEvent(t *testing.T) {
+	account := common.HexToAddress("0x9011E888251AB053B7bD1cdB598Db4f9DEd94714")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	token := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	accountTopic := common.BytesToHash(account.Bytes())
+	otherTopic := common.BytesToHash(other.Bytes())
+
+	t.Run("erc20 incoming", func(t *testing.T) {
+		l := &types.Log{
+			Address: token,
+			Topics:  []common.Hash{TransferTopic, otherTopic, accountTopic},
+			Data:    common.BigToHash(big.NewInt(1500)).Bytes(),
+		}
+		event, ok := transferEvent(l, accountTopic)
+		require.True(t, ok)
+		assert.Equal(t, EventERC20, event.Kind)
+		assert.Equal(t, other.Hex(), event.From)
+		assert.Equal(t, account.Hex(), event.To)
+		assert.Equal(t, token.Hex(), event.Token)
+		assert.Equal(t, "1500", event.Value)
+	})
+
+	t.Run("erc721 outgoing", func(t *testing.T) {
+		l := &types.Log{
+			Address: token,
+			Topics:  []common.Hash{TransferTopic, accountTopic, otherTopic, common.BigToHash(big.NewInt(7))},
+		}
+		event, ok := transferEvent(l, accountTopic)
+		require.True(t, ok)
+		assert.Equal(t, EventERC721, event.Kind)
+		assert.Equal(t, "7", event.Value)
+	})
+
+	t.Run("unrelated transfer", func(t *testing.T) {
+		l := &types.Log{
+			Address: token,
+			Topics:  []common.Hash{TransferTopic, otherTopic, otherTopic},
+			Data:    common.BigToHash(big.NewInt(1)).Bytes(),
+		}
+		_, ok := transferEvent(l, accountTopic)
+		assert.False(t, ok)
+	})
+
+	t.Run("other event", func(t *testing.T) {
+		l := &types.Log{
+			Address: token,
+			Topics:  []common.Hash{common.HexToHash("0x01"), accountTopic, otherTopic},
+		}
+		_, ok := transferEvent(l, accountTopic)
+		assert.False(t, ok)
+	})
+}
+
+func TestDiffAccount(t *testing.T) {
+	h := &AccountHistory{}
+	prev := &types.StateAccount{
+		Nonce:    1,
+		Balance:  uint256.NewInt(100),
+		Root:     types.EmptyRootHash,
+		CodeHash: types.EmptyCodeHash.Bytes(),
+	}
+	next := &types.StateAccount{
+		Nonce:    2,
+		Balance:  uint256.NewInt(40),
+		Root:     types.EmptyRootHash,
+		CodeHash: types.EmptyCodeHash.Bytes(),
+	}
+
+	changes := diffAccount(h, 10, 0, prev, next)
+	assert.Equal(t, 2, changes)
+	require.Len(t, h.Events, 2)
+	assert.Equal(t, EventBalance, h.Events[0].Kind)
+	assert.Equal(t, "100 -> 40", h.Events[0].Detail)
+	assert.Equal(t, EventNonce, h.Events[1].Kind)
+
+	// An account appearing for the first time changes its balance from zero
+	h = &AccountHistory{}
+	assert.Equal(t, 2, diffAccount(h, 11, 0, nil, next))
+}
+
+func TestIndexedLogBlocks(t *testing.T) {
+	db, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	require.NoError(t, err)
+	defer db.Close()
+	r := rawdb.NewReader(db, nil)
+
+	account := common.HexToAddress("0x9011E888251AB053B7bD1cdB598Db4f9DEd94714")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	// Without an index every block falls back to the bloom
+	covered, blocks, err := indexedLogBlocks(r, account, 0, 100)
+	require.NoError(t, err)
+	assert.False(t, covered.Contains(50))
+	assert.Empty(t, blocks)
+
+	require.NoError(t, db.Set(rawdb.LogIndexRangeKey, rawdb.IndexRange{Start: 10, Next: 60}.Encode(), nil))
+	for _, number := range []uint64{5, 10, 30, 59, 60} {
+		require.NoError(t, db.Set(rawdb.LogIndexKey(account, number), []byte{0, 0, 0, 1}, nil))
+	}
+	require.NoError(t, db.Set(rawdb.LogIndexKey(other, 20), []byte{0, 0, 0, 1}, nil))
+
+	// Entries outside the recorded range or the requested one are ignored
+	covered, blocks, err = indexedLogBlocks(r, account, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, rawdb.IndexRange{Start: 10, Next: 60}, covered)
+	assert.Equal(t, map[uint64]bool{10: true, 30: true, 59: true}, blocks)
+
+	_, blocks, err = indexedLogBlocks(r, account, 20, 40)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint64]bool{30: true}, blocks)
+
+	covered, _, err = indexedLogBlocks(r, account, 60, 100)
+	require.NoError(t, err)
+	assert.False(t, covered.Contains(60))
+}
@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportAccountHistoryToCSV writes one row per account event
+func ExportAccountHistoryToCSV(h *AccountHistory, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"BlockNumber", "Timestamp", "Kind", "TxHash", "From", "To",
+		"Token", "Value", "Status", "Detail",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range h.Events {
+		record := []string{
+			strconv.FormatUint(e.BlockNumber, 10),
+			time.Unix(int64(e.Timestamp), 0).UTC().Format("2006-01-02 15:04:05"),
+			e.Kind,
+			e.TxHash,
+			e.From,
+			e.To,
+			e.Token,
+			e.Value,
+			e.Status,
+			e.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportStatsToCSV writes one row per statistics bucket. Top contracts are
+// packed into a single column as address:gas pairs separated by semicolons.
+func ExportStatsToCSV(buckets []*StatsBucket, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"BucketStart", "FirstBlock", "LastBlock", "Blocks", "Transactions",
+		"GasUsed", "AvgBaseFee", "UniqueSenders", "TopLevelCreations", "TopContracts",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, b := range buckets {
+		top := make([]string, len(b.TopContracts))
+		for i, c := range b.TopContracts {
+			top[i] = fmt.Sprintf("%s:%d", c.Address.Hex(), c.GasUsed)
+		}
+		record := []string{
+			b.Start.Format("2006-01-02"),
+			strconv.FormatUint(b.FirstBlock, 10),
+			strconv.FormatUint(b.LastBlock, 10),
+			strconv.Itoa(b.Blocks),
+			strconv.Itoa(b.Transactions),
+			strconv.FormatUint(b.GasUsed, 10),
+			b.AvgBaseFee,
+			strconv.Itoa(b.UniqueSenders),
+			strconv.Itoa(b.TopLevelCreations),
+			strings.Join(top, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportToJSON writes data as indented JSON
+func ExportToJSON(data interface{}, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
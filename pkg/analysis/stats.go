@@ -0,0 +1,306 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+// BucketSize is the width of a statistics bucket
+type BucketSize string
+
+// Supported bucket sizes
+const (
+	BucketDay  BucketSize = "day"
+	BucketWeek BucketSize = "week"
+)
+
+// ParseBucketSize parses a --bucket value
+func ParseBucketSize(s string) (BucketSize, error) {
+	switch BucketSize(s) {
+	case BucketDay, BucketWeek:
+		return BucketSize(s), nil
+	}
+	return "", fmt.Errorf("unknown bucket size %q (use day or week)", s)
+}
+
+// Start returns the UTC start of the bucket containing t. Weeks start on Monday.
+func (b BucketSize) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if b == BucketWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// ContractGas is the gas spent calling a contract and the number of
+// transactions calling it
+type ContractGas struct {
+	Address common.Address `json:"address"`
+	GasUsed uint64         `json:"gasUsed"`
+	Calls   int            `json:"calls"`
+}
+
+// StatsBucket holds the activity of one day or week
+type StatsBucket struct {
+	Start             time.Time     `json:"start"`
+	FirstBlock        uint64        `json:"firstBlock"`
+	LastBlock         uint64        `json:"lastBlock"`
+	Blocks            int           `json:"blocks"`
+	Transactions      int           `json:"transactions"`
+	GasUsed           uint64        `json:"gasUsed"`
+	AvgBaseFee        string        `json:"avgBaseFee"` // Wei, over blocks that have a base fee
+	UniqueSenders     int           `json:"uniqueSenders"`
+	TopLevelCreations int           `json:"topLevelCreations"` // Successful creation txs, not creates by contracts
+	TopContracts      []ContractGas `json:"topContracts"`
+}
+
+// BlockSummary is the per-block input to the statistics aggregator
+type BlockSummary struct {
+	Number            uint64
+	Time              uint64
+	GasUsed           uint64
+	BaseFee           *big.Int
+	Transactions      int
+	Senders           []common.Address
+	TopLevelCreations int
+	ContractGas       map[common.Address]uint64 // Receipt gas of calls with calldata, by target
+	ContractCalls     map[common.Address]int    // Number of those calls, by target
+}
+
+// StatsAggregator folds block summaries, in block order, into buckets. Only
+// the current bucket's sender set and contract table are held in memory.
+type StatsAggregator struct {
+	size BucketSize
+	top  int
+
+	buckets    []*StatsBucket
+	current    *StatsBucket
+	baseFeeSum *big.Int
+	baseFeeN   int64
+	senders    map[common.Address]struct{}
+	contracts  map[common.Address]*ContractGas
+}
+
+// NewStatsAggregator creates an aggregator keeping the top contracts by gas.
+// A negative top keeps none.
+func NewStatsAggregator(size BucketSize, top int) *StatsAggregator {
+	return &StatsAggregator{size: size, top: max(top, 0)}
+}
+
+// Add folds one block into the current bucket, starting a new bucket when the
+// block's timestamp falls past it
+func (a *StatsAggregator) Add(b *BlockSummary) {
+	start := a.size.Start(time.Unix(int64(b.Time), 0))
+	if a.current == nil || !start.Equal(a.current.Start) {
+		a.flush()
+		a.current = &StatsBucket{Start: start, FirstBlock: b.Number}
+		a.baseFeeSum = new(big.Int)
+		a.baseFeeN = 0
+		a.senders = make(map[common.Address]struct{})
+		a.contracts = make(map[common.Address]*ContractGas)
+	}
+
+	c := a.current
+	c.LastBlock = b.Number
+	c.Blocks++
+	c.Transactions += b.Transactions
+	c.GasUsed += b.GasUsed
+	c.TopLevelCreations += b.TopLevelCreations
+	if b.BaseFee != nil {
+		a.baseFeeSum.Add(a.baseFeeSum, b.BaseFee)
+		a.baseFeeN++
+	}
+	for _, sender := range b.Senders {
+		a.senders[sender] = struct{}{}
+	}
+	for addr, gas := range b.ContractGas {
+		entry, ok := a.contracts[addr]
+		if !ok {
+			entry = &ContractGas{Address: addr}
+			a.contracts[addr] = entry
+		}
+		entry.GasUsed += gas
+		entry.Calls += b.ContractCalls[addr]
+	}
+}
+
+// Finish closes the last bucket and returns every bucket in order
+func (a *StatsAggregator) Finish() []*StatsBucket {
+	a.flush()
+	return a.buckets
+}
+
+func (a *StatsAggregator) flush() {
+	if a.current == nil {
+		return
+	}
+	c := a.current
+	c.UniqueSenders = len(a.senders)
+	c.AvgBaseFee = "0"
+	if a.baseFeeN > 0 {
+		c.AvgBaseFee = new(big.Int).Div(a.baseFeeSum, big.NewInt(a.baseFeeN)).String()
+	}
+
+	top := make([]ContractGas, 0, len(a.contracts))
+	for _, entry := range a.contracts {
+		top = append(top, *entry)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].GasUsed != top[j].GasUsed {
+			return top[i].GasUsed > top[j].GasUsed
+		}
+		return top[i].Address.Hex() < top[j].Address.Hex()
+	})
+	if len(top) > a.top {
+		top = top[:a.top]
+	}
+	c.TopContracts = top
+
+	a.buckets = append(a.buckets, c)
+	a.current = nil
+}
+
+// ChainStatsConfig configures a statistics pass
+type ChainStatsConfig struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Bucket    BucketSize
+	Top       int // Contracts listed per bucket
+	Workers   int // Blocks decoded in parallel
+
+	// Progress, if set, is called after every batch of blocks
+	Progress func(number uint64)
+}
+
+// statsBatchSize is the number of blocks decoded in parallel before they are
+// folded, in order, into the aggregator
+const statsBatchSize = 4096
+
+// BuildChainStats computes per-bucket statistics over [FromBlock, ToBlock] in
+// a single pass. Blocks are decoded (including sender recovery) by a worker
+// pool one batch at a time and aggregated in block order.
+func BuildChainStats(r *rawdb.Reader, config ChainStatsConfig) ([]*StatsBucket, error) {
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	agg := NewStatsAggregator(config.Bucket, config.Top)
+
+	summaries := make([]*BlockSummary, statsBatchSize)
+	for batchStart := config.FromBlock; batchStart <= config.ToBlock; batchStart += statsBatchSize {
+		batchEnd := batchStart + statsBatchSize - 1
+		if batchEnd > config.ToBlock || batchEnd < batchStart {
+			batchEnd = config.ToBlock
+		}
+		n := int(batchEnd - batchStart + 1)
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+		)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(offset int) {
+				defer wg.Done()
+				for i := offset; i < n; i += workers {
+					summary, err := SummarizeBlock(r, batchStart+uint64(i))
+					if err != nil {
+						mu.Lock()
+						if firstErr == nil {
+							firstErr = err
+						}
+						mu.Unlock()
+						return
+					}
+					summaries[i] = summary
+				}
+			}(w)
+		}
+		wg.Wait()
+		if firstErr != nil {
+			return nil, firstErr
+		}
+
+		for i := 0; i < n; i++ {
+			agg.Add(summaries[i])
+			summaries[i] = nil
+		}
+		if config.Progress != nil {
+			config.Progress(batchEnd)
+		}
+		if batchEnd == config.ToBlock {
+			break
+		}
+	}
+	return agg.Finish(), nil
+}
+
+// SummarizeBlock reads one canonical block and its receipts
+func SummarizeBlock(r *rawdb.Reader, number uint64) (*BlockSummary, error) {
+	hash, err := r.ReadCanonicalHash(number)
+	if err != nil {
+		return nil, fmt.Errorf("missing canonical hash for block %d: %w", number, err)
+	}
+	header, err := r.ReadHeader(number, hash)
+	if err != nil {
+		return nil, err
+	}
+	summary := &BlockSummary{
+		Number:  number,
+		Time:    header.Time,
+		GasUsed: header.GasUsed,
+		BaseFee: header.BaseFee,
+	}
+	if header.TxHash == types.EmptyTxsHash {
+		return summary, nil
+	}
+
+	body, err := r.ReadBody(number, hash)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := r.ReadReceipts(number, hash, body)
+	if err != nil && !errors.Is(err, rawdb.ErrNotFound) {
+		return nil, err
+	}
+
+	summary.Transactions = len(body.Transactions)
+	summary.Senders = make([]common.Address, 0, len(body.Transactions))
+	summary.ContractGas = make(map[common.Address]uint64)
+	summary.ContractCalls = make(map[common.Address]int)
+	for i, tx := range body.Transactions {
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover sender of %s: %w", tx.Hash().Hex(), err)
+		}
+		summary.Senders = append(summary.Senders, sender)
+
+		var receipt *types.Receipt
+		if i < len(receipts) {
+			receipt = receipts[i]
+		}
+		if tx.To() == nil {
+			if receipt == nil || receipt.Status == types.ReceiptStatusSuccessful {
+				summary.TopLevelCreations++
+			}
+			continue
+		}
+		if receipt != nil && len(tx.Data()) > 0 {
+			summary.ContractGas[*tx.To()] += receipt.GasUsed
+			summary.ContractCalls[*tx.To()]++
+		}
+	}
+	return summary, nil
+}
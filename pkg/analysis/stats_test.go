@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketStart(t *testing.T) {
+	// Wednesday 2024-01-10 15:04:05 UTC
+	ts := time.Date(2024, 1, 10, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), BucketDay.Start(ts))
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), BucketWeek.Start(ts))
+
+	// Sunday belongs to the week starting the previous Monday
+	sunday := time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), BucketWeek.Start(sunday))
+
+	_, err := ParseBucketSize("month")
+	assert.Error(t, err)
+}
+
+func TestStatsAggregator(t *testing.T) {
+	day1 := uint64(time.Date(2024, 1, 10, 1, 0, 0, 0, time.UTC).Unix())
+	day2 := uint64(time.Date(2024, 1, 11, 1, 0, 0, 0, time.UTC).Unix())
+	alice := common.HexToAddress("0x01")
+	bob := common.HexToAddress("0x02")
+	dex := common.HexToAddress("0xd0")
+	token := common.HexToAddress("0xd1")
+
+	agg := NewStatsAggregator(BucketDay, 1)
+	agg.Add(&BlockSummary{
+		Number: 1, Time: day1, GasUsed: 100, BaseFee: big.NewInt(10),
+		Transactions: 2, Senders: []common.Address{alice, bob},
+		ContractGas:   map[common.Address]uint64{dex: 60, token: 40},
+		ContractCalls: map[common.Address]int{dex: 2, token: 1},
+	})
+	agg.Add(&BlockSummary{
+		Number: 2, Time: day1 + 60, GasUsed: 50, BaseFee: big.NewInt(20),
+		Transactions: 1, Senders: []common.Address{alice}, TopLevelCreations: 1,
+	})
+	agg.Add(&BlockSummary{Number: 3, Time: day2})
+
+	buckets := agg.Finish()
+	require.Len(t, buckets, 2)
+
+	first := buckets[0]
+	assert.Equal(t, uint64(1), first.FirstBlock)
+	assert.Equal(t, uint64(2), first.LastBlock)
+	assert.Equal(t, 2, first.Blocks)
+	assert.Equal(t, 3, first.Transactions)
+	assert.Equal(t, uint64(150), first.GasUsed)
+	assert.Equal(t, "15", first.AvgBaseFee)
+	assert.Equal(t, 2, first.UniqueSenders)
+	assert.Equal(t, 1, first.TopLevelCreations)
+	require.Len(t, first.TopContracts, 1)
+	assert.Equal(t, dex, first.TopContracts[0].Address)
+	assert.Equal(t, 2, first.TopContracts[0].Calls)
+
+	second := buckets[1]
+	assert.Equal(t, 1, second.Blocks)
+	assert.Equal(t, "0", second.AvgBaseFee)
+	assert.Empty(t, second.TopContracts)
+
+	// A negative top lists no contracts instead of panicking
+	agg = NewStatsAggregator(BucketDay, -1)
+	agg.Add(&BlockSummary{Number: 1, Time: day1, ContractGas: map[common.Address]uint64{dex: 1}})
+	buckets = agg.Finish()
+	require.Len(t, buckets, 1)
+	assert.Empty(t, buckets[0].TopContracts)
+}
@@ -8,11 +8,13 @@ import (
 	"math/big"
 	"strings"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/accounts/abi/bind"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/ethclient"
+
+	"github.com/luxfi/genesis/pkg/scanner"
 )
 
 // ERC721 ABI events and methods
@@ -148,67 +150,55 @@ func (s *NFTScanner) Scan() (*NFTScanResult, error) {
 	
 	log.Printf("Scanning NFT collection %s from block %d to %d", name, fromBlock, s.config.ToBlock)
 	
-	for startBlock := fromBlock; startBlock <= s.config.ToBlock; startBlock += chunkSize {
-		endBlock := startBlock + chunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-		
-		// Create filter query
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{contractAddr},
-			Topics:    [][]common.Hash{{transferEventSig}},
+	query := scanner.LogQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{transferEventSig}},
+	}
+
+	// Process Transfer events
+	logScanner := scanner.NewLogScanner(&scanner.RPCSource{Client: s.client}, s.config.Store, scanner.LogScanConfig{
+		ChunkSize:         chunkSize,
+		RequestsPerSecond: s.config.RequestsPerSecond,
+	})
+	err = logScanner.Scan(ctx, query, fromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		var transferEvent struct {
+			From    common.Address
+			To      common.Address
+			TokenId *big.Int
 		}
-		
-		// Get logs
-		logs, err := s.client.FilterLogs(ctx, query)
+
+		err := s.contractABI.UnpackIntoInterface(&transferEvent, "Transfer", vLog.Data)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
-		}
-		
-		// Process Transfer events
-		for _, vLog := range logs {
-			var transferEvent struct {
-				From    common.Address
-				To      common.Address
-				TokenId *big.Int
-			}
-			
-			err := s.contractABI.UnpackIntoInterface(&transferEvent, "Transfer", vLog.Data)
-			if err != nil {
-				// Try to parse indexed topics
-				if len(vLog.Topics) >= 4 {
-					transferEvent.From = common.HexToAddress(vLog.Topics[1].Hex())
-					transferEvent.To = common.HexToAddress(vLog.Topics[2].Hex())
-					transferEvent.TokenId = new(big.Int).SetBytes(vLog.Topics[3].Bytes())
-				} else {
-					log.Printf("Warning: failed to unpack Transfer event: %v", err)
-					continue
-				}
-			}
-			
-			tokenID := transferEvent.TokenId.String()
-			
-			// Update NFT ownership (latest transfer is current owner)
-			if transferEvent.To != common.HexToAddress("0x0") {
-				// Not a burn
-				nftMap[tokenID] = transferEvent.To.Hex()
+			// Try to parse indexed topics
+			if len(vLog.Topics) >= 4 {
+				transferEvent.From = common.HexToAddress(vLog.Topics[1].Hex())
+				transferEvent.To = common.HexToAddress(vLog.Topics[2].Hex())
+				transferEvent.TokenId = new(big.Int).SetBytes(vLog.Topics[3].Bytes())
 			} else {
-				// Burned NFT
-				delete(nftMap, tokenID)
+				log.Printf("Warning: failed to unpack Transfer event: %v", err)
+				return nil
 			}
 		}
-		
-		totalScanned += len(logs)
-		
-		// Progress indicator
-		if (endBlock-fromBlock)%100000 == 0 {
-			log.Printf("Scanned up to block %d/%d (found %d NFTs)", endBlock, s.config.ToBlock, len(nftMap))
+
+		tokenID := transferEvent.TokenId.String()
+
+		// Update NFT ownership (latest transfer is current owner)
+		if transferEvent.To != common.HexToAddress("0x0") {
+			// Not a burn
+			nftMap[tokenID] = transferEvent.To.Hex()
+		} else {
+			// Burned NFT
+			delete(nftMap, tokenID)
 		}
+
+		totalScanned++
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	log.Printf("Scanned %d transfers up to block %d (found %d NFTs)", totalScanned, s.config.ToBlock, len(nftMap))
 	
 	// Count NFTs per owner
 	for _, owner := range nftMap {
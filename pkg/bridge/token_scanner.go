@@ -10,11 +10,13 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/accounts/abi/bind"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/ethclient"
+
+	"github.com/luxfi/genesis/pkg/scanner"
 )
 
 // ERC20 ABI events and methods
@@ -157,68 +159,56 @@ func (s *TokenScanner) Scan() (*TokenScanResult, error) {
 	
 	log.Printf("Scanning token %s (%s) from block %d to %d", name, symbol, fromBlock, s.config.ToBlock)
 	
-	for startBlock := fromBlock; startBlock <= s.config.ToBlock; startBlock += chunkSize {
-		endBlock := startBlock + chunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-		
-		// Create filter query
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{contractAddr},
-			Topics:    [][]common.Hash{{transferEventSig}},
+	query := scanner.LogQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{transferEventSig}},
+	}
+
+	// Process Transfer events
+	logScanner := scanner.NewLogScanner(&scanner.RPCSource{Client: s.client}, nil, scanner.LogScanConfig{
+		ChunkSize:         chunkSize,
+		RequestsPerSecond: s.config.RequestsPerSecond,
+	})
+	err = logScanner.Scan(ctx, query, fromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		var from, to common.Address
+		var value *big.Int
+
+		// Parse indexed topics (from and to)
+		if len(vLog.Topics) >= 3 {
+			from = common.HexToAddress(vLog.Topics[1].Hex())
+			to = common.HexToAddress(vLog.Topics[2].Hex())
 		}
-		
-		// Get logs
-		logs, err := s.client.FilterLogs(ctx, query)
-		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
+
+		// Parse data (value)
+		if len(vLog.Data) >= 32 {
+			value = new(big.Int).SetBytes(vLog.Data)
+		} else {
+			return nil
 		}
-		
-		// Process Transfer events
-		for _, vLog := range logs {
-			var from, to common.Address
-			var value *big.Int
-			
-			// Parse indexed topics (from and to)
-			if len(vLog.Topics) >= 3 {
-				from = common.HexToAddress(vLog.Topics[1].Hex())
-				to = common.HexToAddress(vLog.Topics[2].Hex())
-			}
-			
-			// Parse data (value)
-			if len(vLog.Data) >= 32 {
-				value = new(big.Int).SetBytes(vLog.Data)
-			} else {
-				continue
-			}
-			
-			// Update balances
-			if from != common.HexToAddress("0x0") {
-				if balances[from] == nil {
-					balances[from] = new(big.Int)
-				}
-				balances[from].Sub(balances[from], value)
-			}
-			
-			if to != common.HexToAddress("0x0") {
-				if balances[to] == nil {
-					balances[to] = new(big.Int)
-				}
-				balances[to].Add(balances[to], value)
+
+		// Update balances
+		if from != common.HexToAddress("0x0") {
+			if balances[from] == nil {
+				balances[from] = new(big.Int)
 			}
+			balances[from].Sub(balances[from], value)
 		}
-		
-		totalScanned += len(logs)
-		
-		// Progress indicator
-		if (endBlock-fromBlock)%100000 == 0 {
-			log.Printf("Scanned up to block %d/%d (processed %d transfers)", endBlock, s.config.ToBlock, totalScanned)
+
+		if to != common.HexToAddress("0x0") {
+			if balances[to] == nil {
+				balances[to] = new(big.Int)
+			}
+			balances[to].Add(balances[to], value)
 		}
+
+		totalScanned++
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	log.Printf("Scanned %d transfers up to block %d", totalScanned, s.config.ToBlock)
 	
 	// Clean up zero or negative balances
 	holders := make([]TokenHolder, 0)
@@ -1,5 +1,7 @@
 package bridge
 
+import "github.com/luxfi/genesis/pkg/scanner"
+
 // Chain represents a supported blockchain
 type Chain struct {
 	Name       string
@@ -30,6 +32,8 @@ type NFTScannerConfig struct {
 	IncludeMetadata bool
 	CrossReference  string
 	ValidatorNFT    bool    // For NFTs that grant validator status
+	Store           *scanner.LogStore // Optional: keeps fetched logs so scans resume
+	RequestsPerSecond float64         // Optional: caps log requests
 }
 
 // NFTScanResult contains NFT scan results
@@ -71,6 +75,7 @@ type TokenScannerConfig struct {
 	MinBalance      string
 	IncludeZero     bool
 	CrossReference  string
+	RequestsPerSecond float64 // Optional: caps log requests
 }
 
 // TokenScanResult contains token scan results
@@ -4,17 +4,18 @@ import (
 	"context"
 	"encoding/csv"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/ethclient"
+
+	"github.com/luxfi/genesis/pkg/scanner"
 )
 
 const (
@@ -90,77 +91,61 @@ func ScanZooEggPurchases(client *ethclient.Client, zooTokenAddress string, fromB
 	// Get Transfer event signature
 	transferEventSig := contractABI.Events["Transfer"].ID
 	
-	// Scan in chunks
-	chunkSize := uint64(5000)
-	for startBlock := fromBlock; startBlock <= toBlock; startBlock += chunkSize {
-		endBlock := startBlock + chunkSize - 1
-		if endBlock > toBlock {
-			endBlock = toBlock
+	// Filter for transfers TO the purchase address
+	query := scanner.LogQuery{
+		Addresses: []common.Address{tokenAddr},
+		Topics: [][]common.Hash{
+			{transferEventSig},
+			nil, // from (any)
+			{common.BytesToHash(purchaseAddr.Bytes())}, // to (purchase address)
+		},
+	}
+
+	// Process transfers
+	logScanner := scanner.NewLogScanner(&scanner.RPCSource{Client: client}, nil, scanner.LogScanConfig{})
+	err = logScanner.Scan(ctx, query, fromBlock, toBlock, func(vLog types.Log) error {
+		var from, to common.Address
+		var value *big.Int
+
+		// Parse indexed topics
+		if len(vLog.Topics) >= 3 {
+			from = common.HexToAddress(vLog.Topics[1].Hex())
+			to = common.HexToAddress(vLog.Topics[2].Hex())
 		}
-		
-		// Filter for transfers TO the purchase address
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{tokenAddr},
-			Topics: [][]common.Hash{
-				{transferEventSig},
-				nil, // from (any)
-				{common.BytesToHash(purchaseAddr.Bytes())}, // to (purchase address)
-			},
+
+		// Parse value from data
+		if len(vLog.Data) >= 32 {
+			value = new(big.Int).SetBytes(vLog.Data)
+		} else {
+			return nil
 		}
-		
-		logs, err := client.FilterLogs(ctx, query)
+
+		// Get block details for timestamp
+		blockTime, err := logScanner.BlockTime(ctx, vLog.BlockNumber)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
+			return err
 		}
-		
-		// Process transfers
-		for _, vLog := range logs {
-			var from, to common.Address
-			var value *big.Int
-			
-			// Parse indexed topics
-			if len(vLog.Topics) >= 3 {
-				from = common.HexToAddress(vLog.Topics[1].Hex())
-				to = common.HexToAddress(vLog.Topics[2].Hex())
-			}
-			
-			// Parse value from data
-			if len(vLog.Data) >= 32 {
-				value = new(big.Int).SetBytes(vLog.Data)
-			} else {
-				continue
-			}
-			
-			// Get block details for timestamp
-			block, err := client.BlockByNumber(ctx, big.NewInt(int64(vLog.BlockNumber)))
-			if err != nil {
-				log.Printf("Warning: failed to get block %d: %v", vLog.BlockNumber, err)
-			}
-			
-			// Calculate expected eggs
-			zooAmount := new(big.Int).Set(value)
-			zooPerEgg := new(big.Int).Mul(big.NewInt(ZooPerEggNFT), big.NewInt(1e18)) // Assuming 18 decimals
-			expectedEggs := new(big.Int).Div(zooAmount, zooPerEgg).Int64()
-			
-			purchase := ZooEggPurchase{
-				TxHash:       vLog.TxHash.Hex(),
-				BlockNumber:  vLog.BlockNumber,
-				Timestamp:    time.Unix(int64(block.Time()), 0),
-				From:         from.Hex(),
-				To:           to.Hex(),
-				Amount:       value.String(),
-				ExpectedEggs: int(expectedEggs),
-			}
-			
-			purchases = append(purchases, purchase)
-		}
-		
-		if (endBlock-startBlock)%50000 == 0 {
-			log.Printf("Scanned purchases up to block %d/%d", endBlock, toBlock)
+
+		// Calculate expected eggs
+		zooAmount := new(big.Int).Set(value)
+		zooPerEgg := new(big.Int).Mul(big.NewInt(ZooPerEggNFT), big.NewInt(1e18)) // Assuming 18 decimals
+		expectedEggs := new(big.Int).Div(zooAmount, zooPerEgg).Int64()
+
+		purchase := ZooEggPurchase{
+			TxHash:       vLog.TxHash.Hex(),
+			BlockNumber:  vLog.BlockNumber,
+			Timestamp:    time.Unix(int64(blockTime), 0),
+			From:         from.Hex(),
+			To:           to.Hex(),
+			Amount:       value.String(),
+			ExpectedEggs: int(expectedEggs),
 		}
+
+		purchases = append(purchases, purchase)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	return purchases, nil
@@ -183,69 +168,53 @@ func ScanZooBurns(client *ethclient.Client, zooTokenAddress string, fromBlock, t
 	// Get Transfer event signature
 	transferEventSig := contractABI.Events["Transfer"].ID
 	
-	// Scan in chunks
-	chunkSize := uint64(5000)
-	for startBlock := fromBlock; startBlock <= toBlock; startBlock += chunkSize {
-		endBlock := startBlock + chunkSize - 1
-		if endBlock > toBlock {
-			endBlock = toBlock
+	// Filter for transfers TO the dead address
+	query := scanner.LogQuery{
+		Addresses: []common.Address{tokenAddr},
+		Topics: [][]common.Hash{
+			{transferEventSig},
+			nil, // from (any)
+			{common.BytesToHash(deadAddr.Bytes())}, // to (dead address)
+		},
+	}
+
+	// Process burns
+	logScanner := scanner.NewLogScanner(&scanner.RPCSource{Client: client}, nil, scanner.LogScanConfig{})
+	err = logScanner.Scan(ctx, query, fromBlock, toBlock, func(vLog types.Log) error {
+		var from common.Address
+		var value *big.Int
+
+		// Parse indexed topics
+		if len(vLog.Topics) >= 3 {
+			from = common.HexToAddress(vLog.Topics[1].Hex())
 		}
-		
-		// Filter for transfers TO the dead address
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{tokenAddr},
-			Topics: [][]common.Hash{
-				{transferEventSig},
-				nil, // from (any)
-				{common.BytesToHash(deadAddr.Bytes())}, // to (dead address)
-			},
+
+		// Parse value
+		if len(vLog.Data) >= 32 {
+			value = new(big.Int).SetBytes(vLog.Data)
+		} else {
+			return nil
 		}
-		
-		logs, err := client.FilterLogs(ctx, query)
+
+		// Get block details
+		blockTime, err := logScanner.BlockTime(ctx, vLog.BlockNumber)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
+			return err
 		}
-		
-		// Process burns
-		for _, vLog := range logs {
-			var from common.Address
-			var value *big.Int
-			
-			// Parse indexed topics
-			if len(vLog.Topics) >= 3 {
-				from = common.HexToAddress(vLog.Topics[1].Hex())
-			}
-			
-			// Parse value
-			if len(vLog.Data) >= 32 {
-				value = new(big.Int).SetBytes(vLog.Data)
-			} else {
-				continue
-			}
-			
-			// Get block details
-			block, err := client.BlockByNumber(ctx, big.NewInt(int64(vLog.BlockNumber)))
-			if err != nil {
-				log.Printf("Warning: failed to get block %d: %v", vLog.BlockNumber, err)
-			}
-			
-			burn := ZooBurn{
-				TxHash:      vLog.TxHash.Hex(),
-				BlockNumber: vLog.BlockNumber,
-				Timestamp:   time.Unix(int64(block.Time()), 0),
-				From:        from.Hex(),
-				Amount:      value.String(),
-			}
-			
-			burns = append(burns, burn)
-		}
-		
-		if (endBlock-startBlock)%50000 == 0 {
-			log.Printf("Scanned burns up to block %d/%d", endBlock, toBlock)
+
+		burn := ZooBurn{
+			TxHash:      vLog.TxHash.Hex(),
+			BlockNumber: vLog.BlockNumber,
+			Timestamp:   time.Unix(int64(blockTime), 0),
+			From:        from.Hex(),
+			Amount:      value.String(),
 		}
+
+		burns = append(burns, burn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	return burns, nil
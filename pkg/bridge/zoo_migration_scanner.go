@@ -11,10 +11,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/ethclient"
+
+	"github.com/luxfi/genesis/pkg/scanner"
 )
 
 const (
@@ -41,6 +43,7 @@ type ZooMigrationConfig struct {
 	IncludeBurns    bool
 	IncludeEggNFTs  bool
 	OutputPath      string
+	RequestsPerSecond float64 // Optional: caps log requests
 }
 
 // ZooHolder represents a Zoo token holder including burn amounts
@@ -128,70 +131,59 @@ func (s *ZooMigrationScanner) ScanZooMigration() (*ZooMigrationResult, error) {
 	
 	chunkSize := uint64(5000) // Smaller chunks for BSC
 	
-	for startBlock := fromBlock; startBlock <= s.config.ToBlock; startBlock += chunkSize {
-		endBlock := startBlock + chunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-		
-		// Query for all Transfer events
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{contractAddr},
-			Topics:    [][]common.Hash{{transferEventSig}},
+	// Query for all Transfer events
+	query := scanner.LogQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{transferEventSig}},
+	}
+
+	// Process transfers
+	logScanner := scanner.NewLogScanner(&scanner.RPCSource{Client: s.client}, nil, scanner.LogScanConfig{
+		ChunkSize:         chunkSize,
+		RequestsPerSecond: s.config.RequestsPerSecond,
+	})
+	err = logScanner.Scan(ctx, query, fromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		var from, to common.Address
+		var value *big.Int
+
+		if len(vLog.Topics) >= 3 {
+			from = common.HexToAddress(vLog.Topics[1].Hex())
+			to = common.HexToAddress(vLog.Topics[2].Hex())
 		}
-		
-		logs, err := s.client.FilterLogs(ctx, query)
-		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
+
+		if len(vLog.Data) >= 32 {
+			value = new(big.Int).SetBytes(vLog.Data)
+		} else {
+			return nil
 		}
-		
-		// Process transfers
-		for _, vLog := range logs {
-			var from, to common.Address
-			var value *big.Int
-			
-			if len(vLog.Topics) >= 3 {
-				from = common.HexToAddress(vLog.Topics[1].Hex())
-				to = common.HexToAddress(vLog.Topics[2].Hex())
-			}
-			
-			if len(vLog.Data) >= 32 {
-				value = new(big.Int).SetBytes(vLog.Data)
-			} else {
-				continue
+
+		// Update balances
+		if from != common.HexToAddress("0x0") {
+			if balances[from] == nil {
+				balances[from] = new(big.Int)
 			}
-			
-			// Update balances
-			if from != common.HexToAddress("0x0") {
-				if balances[from] == nil {
-					balances[from] = new(big.Int)
-				}
-				balances[from].Sub(balances[from], value)
+			balances[from].Sub(balances[from], value)
+		}
+
+		if to != common.HexToAddress("0x0") {
+			if balances[to] == nil {
+				balances[to] = new(big.Int)
 			}
-			
-			if to != common.HexToAddress("0x0") {
-				if balances[to] == nil {
-					balances[to] = new(big.Int)
-				}
-				balances[to].Add(balances[to], value)
-				
-				// Track burns to dead address
-				if to == deadAddr && from != common.HexToAddress("0x0") {
-					if burns[from] == nil {
-						burns[from] = new(big.Int)
-					}
-					burns[from].Add(burns[from], value)
-					totalBurned.Add(totalBurned, value)
+			balances[to].Add(balances[to], value)
+
+			// Track burns to dead address
+			if to == deadAddr && from != common.HexToAddress("0x0") {
+				if burns[from] == nil {
+					burns[from] = new(big.Int)
 				}
+				burns[from].Add(burns[from], value)
+				totalBurned.Add(totalBurned, value)
 			}
 		}
-		
-		if (endBlock-startBlock)%50000 == 0 {
-			log.Printf("Scanned up to block %d/%d", endBlock, s.config.ToBlock)
-		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	// Get EGG NFT holders if requested
@@ -302,6 +294,7 @@ func (s *ZooMigrationScanner) scanEggNFTHolders() (map[common.Address]int, error
 		ProjectName:     "egg",
 		FromBlock:       s.config.FromBlock,
 		ToBlock:         s.config.ToBlock,
+		RequestsPerSecond: s.config.RequestsPerSecond,
 	}
 	
 	nftScanner, err := NewNFTScanner(nftConfig)
@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/luxfi/geth/common"
+	gethrawdb "github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/geth/trie"
+)
+
+// Contract is one account with code
+type Contract struct {
+	Address        *common.Address `json:"address,omitempty"` // Nil when the address preimage is unknown
+	AddressHash    common.Hash     `json:"addressHash"`
+	CodeHash       common.Hash     `json:"codeHash"`
+	CodeSize       int             `json:"codeSize"`
+	Balance        string          `json:"balance"`
+	Standards      []string        `json:"standards,omitempty"`
+	Implementation *common.Address `json:"implementation,omitempty"` // Proxy target
+	Name           string          `json:"name,omitempty"`
+	Symbol         string          `json:"symbol,omitempty"`
+	Decimals       *uint8          `json:"decimals,omitempty"`
+}
+
+// Code is one unique bytecode and the accounts sharing it
+type Code struct {
+	Hash      common.Hash `json:"hash"`
+	Size      int         `json:"size"`
+	Standards []string    `json:"standards,omitempty"`
+	Accounts  int         `json:"accounts"`
+}
+
+// Catalog is the inventory of contracts in one state
+type Catalog struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	Root        common.Hash `json:"root"`
+	Accounts    int         `json:"accounts"`
+	Unresolved  int         `json:"unresolved"` // Contracts whose address could not be recovered
+	Contracts   []*Contract `json:"contracts"`
+	Codes       []*Code     `json:"codes"`
+}
+
+// Options configures a catalogue build
+type Options struct {
+	// Candidates maps trie hashes to addresses for accounts without a stored
+	// preimage, see Source.CandidateAddresses
+	Candidates map[common.Hash]common.Address
+
+	// Metadata enables in-process name/symbol/decimals calls
+	Metadata bool
+
+	// Progress, if set, is called every 100k accounts
+	Progress func(accounts, contracts int)
+}
+
+// Build enumerates every account with code in the state of header, dedupes
+// bytecode by hash and classifies each contract. Proxy contracts are also
+// classified by their implementation's code.
+func Build(s *Source, header *types.Header, opts Options) (*Catalog, error) {
+	c := &Catalog{
+		BlockNumber: header.Number.Uint64(),
+		Root:        header.Root,
+	}
+
+	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), s.triedb)
+	if err != nil {
+		return nil, fmt.Errorf("state %s not available: %w", header.Root.Hex(), err)
+	}
+	statedb, err := state.New(header.Root, s.state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state: %w", err)
+	}
+	caller := newCaller(s, header, statedb)
+
+	nodeIt, err := tr.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[common.Hash]*Code)
+
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		c.Accounts++
+		if opts.Progress != nil && c.Accounts%100000 == 0 {
+			opts.Progress(c.Accounts, len(c.Contracts))
+		}
+
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			return nil, fmt.Errorf("failed to decode account %x: %w", it.Key, err)
+		}
+		codeHash := common.BytesToHash(account.CodeHash)
+		if codeHash == types.EmptyCodeHash {
+			continue
+		}
+
+		code, ok := codes[codeHash]
+		if !ok {
+			bytecode := gethrawdb.ReadCode(s.db, codeHash)
+			code = &Code{
+				Hash:      codeHash,
+				Size:      len(bytecode),
+				Standards: DetectStandards(bytecode),
+			}
+			codes[codeHash] = code
+		}
+		code.Accounts++
+
+		contract := &Contract{
+			AddressHash: common.BytesToHash(it.Key),
+			CodeHash:    codeHash,
+			CodeSize:    code.Size,
+			Balance:     account.Balance.ToBig().String(),
+			Standards:   code.Standards,
+		}
+		if preimage := tr.GetKey(it.Key); len(preimage) == common.AddressLength {
+			addr := common.BytesToAddress(preimage)
+			contract.Address = &addr
+		} else if addr, ok := opts.Candidates[contract.AddressHash]; ok {
+			contract.Address = &addr
+		}
+
+		if contract.Address == nil {
+			c.Unresolved++
+		} else {
+			classifyContract(contract, statedb, caller, opts.Metadata)
+		}
+		c.Contracts = append(c.Contracts, contract)
+	}
+	if it.Err != nil {
+		return nil, fmt.Errorf("failed to iterate state: %w", it.Err)
+	}
+
+	sort.Slice(c.Contracts, func(i, j int) bool {
+		a, b := c.Contracts[i], c.Contracts[j]
+		if (a.Address == nil) != (b.Address == nil) {
+			return a.Address != nil
+		}
+		if a.Address != nil {
+			return bytes.Compare(a.Address.Bytes(), b.Address.Bytes()) < 0
+		}
+		return bytes.Compare(a.AddressHash.Bytes(), b.AddressHash.Bytes()) < 0
+	})
+	for _, code := range codes {
+		c.Codes = append(c.Codes, code)
+	}
+	sort.Slice(c.Codes, func(i, j int) bool {
+		if c.Codes[i].Accounts != c.Codes[j].Accounts {
+			return c.Codes[i].Accounts > c.Codes[j].Accounts
+		}
+		return bytes.Compare(c.Codes[i].Hash.Bytes(), c.Codes[j].Hash.Bytes()) < 0
+	})
+	return c, nil
+}
+
+// classifyContract resolves proxies and reads token metadata for a contract
+// with a known address
+func classifyContract(contract *Contract, statedb *state.StateDB, caller *caller, metadata bool) {
+	addr := *contract.Address
+
+	var impl common.Address
+	if target, ok := MinimalProxyTarget(statedb.GetCode(addr)); ok {
+		impl = target
+	} else if slot := statedb.GetState(addr, ImplementationSlot); slot != (common.Hash{}) {
+		impl = common.BytesToAddress(slot.Bytes())
+		contract.Standards = mergeStandards(contract.Standards, []string{StandardEIP1967})
+	} else if slot := statedb.GetState(addr, BeaconSlot); slot != (common.Hash{}) {
+		contract.Standards = mergeStandards(contract.Standards, []string{StandardBeaconProxy})
+		if ret := caller.call(common.BytesToAddress(slot.Bytes()), selImplementation); len(ret) == 32 {
+			impl = common.BytesToAddress(ret)
+		}
+	}
+	if impl != (common.Address{}) {
+		contract.Implementation = &impl
+		contract.Standards = mergeStandards(contract.Standards, DetectStandards(statedb.GetCode(impl)))
+	}
+
+	if !metadata {
+		return
+	}
+	if hasStandard(contract.Standards, StandardERC20) || hasStandard(contract.Standards, StandardERC721) || hasStandard(contract.Standards, StandardERC1155) {
+		contract.Name = decodeString(caller.call(addr, selName))
+		contract.Symbol = decodeString(caller.call(addr, selSymbol))
+	}
+	if hasStandard(contract.Standards, StandardERC20) {
+		contract.Decimals = decodeUint8(caller.call(addr, selDecimals))
+	}
+}
+
+// Tokens returns the contracts implementing the given standard, with a known address
+func (c *Catalog) Tokens(standard string) []*Contract {
+	var tokens []*Contract
+	for _, contract := range c.Contracts {
+		if contract.Address != nil && hasStandard(contract.Standards, standard) {
+			tokens = append(tokens, contract)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+// dispatcher builds a minimal runtime code comparing against each selector
+// the way solc's dispatcher does (PUSH4 <sel> EQ)
+func dispatcher(sels ...Selector) []byte {
+	code := []byte{0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c} // PUSH1 0 CALLDATALOAD PUSH1 0xe0 SHR
+	for _, sel := range sels {
+		code = append(code, 0x80, opPush4)
+		code = append(code, sel[:]...)
+		code = append(code, 0x14) // EQ
+	}
+	return code
+}
+
+func TestSelectors(t *testing.T) {
+	code := dispatcher(selTransfer, selBalanceOf)
+	sels := Selectors(code)
+	assert.Contains(t, sels, selTransfer)
+	assert.Contains(t, sels, selBalanceOf)
+
+	// PUSH4 bytes hidden inside a PUSH32 immediate must not be picked up
+	hidden := append([]byte{opPush32}, make([]byte, 32)...)
+	hidden[1] = opPush4
+	copy(hidden[2:6], selOwnerOf[:])
+	assert.NotContains(t, Selectors(hidden), selOwnerOf)
+}
+
+func TestDetectStandards(t *testing.T) {
+	erc20 := dispatcher(selTotalSupply, selBalanceOf, selTransfer, selTransferFrom, selApprove, selAllowance, selName, selSymbol, selDecimals)
+	assert.Equal(t, []string{StandardERC20}, DetectStandards(erc20))
+
+	erc721 := dispatcher(selBalanceOf, selOwnerOf, selTransferFrom, selApprove, selSafeTransferFrom, selSafeTransferData, selSetApprovalForAll, selSupportsInterface)
+	assert.Equal(t, []string{StandardERC721, StandardERC165}, DetectStandards(erc721))
+
+	erc1155 := dispatcher(sel1155Transfer, sel1155BatchTransfer, selBalanceOfBatch, selSetApprovalForAll, selSupportsInterface)
+	assert.Equal(t, []string{StandardERC1155, StandardERC165}, DetectStandards(erc1155))
+
+	assert.Empty(t, DetectStandards(dispatcher(selName)))
+}
+
+func TestMinimalProxyTarget(t *testing.T) {
+	impl := common.HexToAddress("0xbebebebebebebebebebebebebebebebebebebebe")
+	code := append(append(append([]byte{}, minimalProxyPrefix...), impl.Bytes()...), minimalProxySuffix...)
+
+	target, ok := MinimalProxyTarget(code)
+	require.True(t, ok)
+	assert.Equal(t, impl, target)
+	assert.Contains(t, DetectStandards(code), StandardEIP1167)
+
+	_, ok = MinimalProxyTarget(code[:len(code)-1])
+	assert.False(t, ok)
+}
+
+func TestDecodeString(t *testing.T) {
+	// ABI-encoded "Lux"
+	abiString := common.FromHex("0x" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"4c75780000000000000000000000000000000000000000000000000000000000")
+	assert.Equal(t, "Lux", decodeString(abiString))
+
+	// bytes32 "MKR"
+	bytes32 := common.RightPadBytes([]byte("MKR"), 32)
+	assert.Equal(t, "MKR", decodeString(bytes32))
+
+	// Out of range offset
+	bad := make([]byte, 64)
+	bad[31] = 0xff
+	assert.Equal(t, "", decodeString(bad))
+
+	assert.Equal(t, "", decodeString(nil))
+}
+
+func TestDecodeUint8(t *testing.T) {
+	ret := common.LeftPadBytes([]byte{18}, 32)
+	d := decodeUint8(ret)
+	require.NotNil(t, d)
+	assert.Equal(t, uint8(18), *d)
+
+	assert.Nil(t, decodeUint8(common.LeftPadBytes([]byte{1, 0}, 32)))
+	assert.Nil(t, decodeUint8(nil))
+}
+
+func TestProjectConfigFindBySymbol(t *testing.T) {
+	nft := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	c := &Catalog{Contracts: []*Contract{
+		{Address: &nft, Standards: []string{StandardERC721, StandardERC1155}, Symbol: "EGG"},
+		{Address: &other, Standards: []string{StandardERC20}, Symbol: "ZOO"},
+		{Address: &other, Standards: []string{StandardERC1155}, Symbol: "DUP"},
+		{Address: &nft, Standards: []string{StandardERC721}, Symbol: "DUP"},
+	}}
+	base := scanner.ProjectConfig{TokenContracts: map[string]string{"lux": "0xold"}}
+
+	// A contract listed as both ERC-721 and ERC-1155 is not ambiguous
+	config, err := c.ProjectConfig(base, "lux", "zoo", "EGG")
+	require.NoError(t, err)
+	assert.Equal(t, other.Hex(), config.TokenContracts["lux"])
+	assert.Equal(t, nft.Hex(), config.NFTContracts["lux"])
+	assert.Equal(t, "0xold", base.TokenContracts["lux"])
+
+	_, err = c.ProjectConfig(base, "lux", "", "DUP")
+	assert.ErrorContains(t, err, "ambiguous")
+
+	_, err = c.ProjectConfig(base, "lux", "MISSING", "")
+	assert.Error(t, err)
+}
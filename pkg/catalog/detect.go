@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Standards reported in the catalogue
+const (
+	StandardERC20       = "erc20"
+	StandardERC721      = "erc721"
+	StandardERC1155     = "erc1155"
+	StandardERC165      = "erc165"
+	StandardEIP1967     = "eip1967-proxy"
+	StandardEIP1167     = "eip1167-proxy"
+	StandardBeaconProxy = "eip1967-beacon-proxy"
+)
+
+// Selector is a 4-byte function selector
+type Selector [4]byte
+
+// Function selectors used for standard detection
+var (
+	selTotalSupply       = Selector{0x18, 0x16, 0x0d, 0xdd} // totalSupply()
+	selBalanceOf         = Selector{0x70, 0xa0, 0x82, 0x31} // balanceOf(address)
+	selTransfer          = Selector{0xa9, 0x05, 0x9c, 0xbb} // transfer(address,uint256)
+	selTransferFrom      = Selector{0x23, 0xb8, 0x72, 0xdd} // transferFrom(address,address,uint256)
+	selApprove           = Selector{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+	selAllowance         = Selector{0xdd, 0x62, 0xed, 0x3e} // allowance(address,address)
+	selOwnerOf           = Selector{0x63, 0x52, 0x21, 0x1e} // ownerOf(uint256)
+	selSafeTransferFrom  = Selector{0x42, 0x84, 0x2e, 0x0e} // safeTransferFrom(address,address,uint256)
+	selSafeTransferData  = Selector{0xb8, 0x8d, 0x4f, 0xde} // safeTransferFrom(address,address,uint256,bytes)
+	selSetApprovalForAll = Selector{0xa2, 0x2c, 0xb4, 0x65} // setApprovalForAll(address,bool)
+	sel1155Transfer      = Selector{0xf2, 0x42, 0x43, 0x2a} // safeTransferFrom(address,address,uint256,uint256,bytes)
+	sel1155BatchTransfer = Selector{0x2e, 0xb2, 0xc2, 0xd6} // safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)
+	selBalanceOfBatch    = Selector{0x4e, 0x12, 0x73, 0xf4} // balanceOfBatch(address[],uint256[])
+	selSupportsInterface = Selector{0x01, 0xff, 0xc9, 0xa7} // supportsInterface(bytes4)
+	selName              = Selector{0x06, 0xfd, 0xde, 0x03} // name()
+	selSymbol            = Selector{0x95, 0xd8, 0x9b, 0x41} // symbol()
+	selDecimals          = Selector{0x31, 0x3c, 0xe5, 0x67} // decimals()
+	selImplementation    = Selector{0x5c, 0x60, 0xda, 0x1b} // implementation()
+)
+
+// EIP-1967 storage slots
+var (
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	BeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// EIP-1167 minimal proxy runtime code around the 20-byte implementation address
+var (
+	minimalProxyPrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	minimalProxySuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+const (
+	opPush1  = 0x60
+	opPush4  = 0x63
+	opPush32 = 0x7f
+)
+
+// Selectors extracts the PUSH4 immediates of runtime code. Solidity and Vyper
+// dispatchers compare the call selector against PUSH4 constants, so the set
+// approximates the functions the contract implements.
+func Selectors(code []byte) map[Selector]struct{} {
+	selectors := make(map[Selector]struct{})
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		if op < opPush1 || op > opPush32 {
+			continue
+		}
+		size := int(op-opPush1) + 1
+		if op == opPush4 && pc+4 < len(code) {
+			var sel Selector
+			copy(sel[:], code[pc+1:pc+5])
+			selectors[sel] = struct{}{}
+		}
+		pc += size // Skip the immediate so data is never read as opcodes
+	}
+	return selectors
+}
+
+// DetectStandards returns the token and proxy standards the code appears to
+// implement, judged by its function selectors and bytecode shape
+func DetectStandards(code []byte) []string {
+	var standards []string
+	if _, ok := MinimalProxyTarget(code); ok {
+		standards = append(standards, StandardEIP1167)
+	}
+
+	sels := Selectors(code)
+	has := func(want ...Selector) bool {
+		for _, s := range want {
+			if _, ok := sels[s]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	if has(sel1155Transfer, selBalanceOfBatch) || has(sel1155BatchTransfer, selBalanceOfBatch) {
+		standards = append(standards, StandardERC1155)
+	}
+	if has(selOwnerOf, selBalanceOf) && (has(selSafeTransferFrom) || has(selSafeTransferData) || has(selSetApprovalForAll)) {
+		standards = append(standards, StandardERC721)
+	} else if has(selTotalSupply, selBalanceOf, selTransfer) && (has(selTransferFrom) || has(selApprove) || has(selAllowance)) {
+		// ERC-721 shares balanceOf/transferFrom/approve, so only report ERC-20 when ownerOf is absent
+		standards = append(standards, StandardERC20)
+	}
+	if has(selSupportsInterface) {
+		standards = append(standards, StandardERC165)
+	}
+	return standards
+}
+
+// MinimalProxyTarget returns the implementation of an EIP-1167 clone
+func MinimalProxyTarget(code []byte) (common.Address, bool) {
+	size := len(minimalProxyPrefix) + common.AddressLength + len(minimalProxySuffix)
+	if len(code) != size || !bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+common.AddressLength]), true
+}
+
+// mergeStandards returns the sorted union of standard lists
+func mergeStandards(lists ...[]string) []string {
+	set := make(map[string]struct{})
+	for _, list := range lists {
+		for _, s := range list {
+			set[s] = struct{}{}
+		}
+	}
+	merged := make([]string, 0, len(set))
+	for s := range set {
+		merged = append(merged, s)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func hasStandard(standards []string, want string) bool {
+	for _, s := range standards {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
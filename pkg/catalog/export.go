@@ -0,0 +1,73 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ExportToJSON writes the full catalogue as indented JSON
+func (c *Catalog) ExportToJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// ExportToCSV writes one row per contract
+func (c *Catalog) ExportToCSV(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"Address", "AddressHash", "CodeHash", "CodeSize", "Balance",
+		"Standards", "Implementation", "Name", "Symbol", "Decimals",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, contract := range c.Contracts {
+		address, implementation, decimals := "", "", ""
+		if contract.Address != nil {
+			address = contract.Address.Hex()
+		}
+		if contract.Implementation != nil {
+			implementation = contract.Implementation.Hex()
+		}
+		if contract.Decimals != nil {
+			decimals = strconv.Itoa(int(*contract.Decimals))
+		}
+		record := []string{
+			address,
+			contract.AddressHash.Hex(),
+			contract.CodeHash.Hex(),
+			strconv.Itoa(contract.CodeSize),
+			contract.Balance,
+			strings.Join(contract.Standards, ";"),
+			implementation,
+			contract.Name,
+			contract.Symbol,
+			decimals,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
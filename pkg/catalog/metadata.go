@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+)
+
+// callGas bounds each metadata call
+const callGas = 1_000_000
+
+// caller executes read-only calls against a state with an in-process EVM
+type caller struct {
+	source   *Source
+	blockCtx vm.BlockContext
+	statedb  *state.StateDB
+}
+
+func newCaller(s *Source, header *types.Header, statedb *state.StateDB) *caller {
+	return &caller{
+		source: s,
+		blockCtx: vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetHash:     func(uint64) common.Hash { return common.Hash{} },
+			Coinbase:    header.Coinbase,
+			GasLimit:    header.GasLimit,
+			BlockNumber: new(big.Int).Set(header.Number),
+			Time:        header.Time,
+			Difficulty:  new(big.Int),
+			BaseFee:     new(big.Int),
+			Random:      &common.Hash{},
+		},
+		statedb: statedb,
+	}
+}
+
+// call runs a static call with a bare selector and returns the output, or
+// nil if the call reverted or failed
+func (c *caller) call(addr common.Address, sel Selector) []byte {
+	evm := vm.NewEVM(c.blockCtx, c.statedb, c.source.config, vm.Config{NoBaseFee: true})
+	ret, _, err := evm.StaticCall(common.Address{}, addr, sel[:], callGas)
+	if err != nil {
+		return nil
+	}
+	return ret
+}
+
+// decodeString decodes an ABI string return value. Early tokens (e.g. MKR)
+// return bytes32 instead, which is accepted as well.
+func decodeString(ret []byte) string {
+	var raw []byte
+	switch {
+	case len(ret) >= 64:
+		offset := new(big.Int).SetBytes(ret[:32])
+		if !offset.IsUint64() || offset.Uint64() > uint64(len(ret))-32 {
+			return ""
+		}
+		start := offset.Uint64()
+		length := new(big.Int).SetBytes(ret[start : start+32])
+		if !length.IsUint64() || length.Uint64() > uint64(len(ret))-start-32 {
+			return ""
+		}
+		raw = ret[start+32 : start+32+length.Uint64()]
+	case len(ret) == 32:
+		raw = bytes.TrimRight(ret, "\x00")
+	default:
+		return ""
+	}
+
+	if !utf8.Valid(raw) {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, string(raw))
+}
+
+// decodeUint8 decodes an ABI uint8 return value
+func decodeUint8(ret []byte) *uint8 {
+	if len(ret) != 32 {
+		return nil
+	}
+	value := new(big.Int).SetBytes(ret)
+	if !value.IsUint64() || value.Uint64() > 255 {
+		return nil
+	}
+	d := uint8(value.Uint64())
+	return &d
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+// ProjectConfig returns a copy of base whose token and NFT contracts on chain
+// come from the catalogue instead of the hand-maintained maps. The contracts
+// are picked by symbol (case-insensitive); an empty symbol leaves that entry
+// as it is in base.
+func (c *Catalog) ProjectConfig(base scanner.ProjectConfig, chain, tokenSymbol, nftSymbol string) (scanner.ProjectConfig, error) {
+	config := scanner.ProjectConfig{
+		TokenContracts:  copyMap(base.TokenContracts),
+		NFTContracts:    copyMap(base.NFTContracts),
+		StakingPowers:   base.StakingPowers,
+		TypeIdentifiers: base.TypeIdentifiers,
+	}
+
+	if tokenSymbol != "" {
+		addr, err := c.findBySymbol(tokenSymbol, StandardERC20)
+		if err != nil {
+			return config, err
+		}
+		config.TokenContracts[chain] = addr
+	}
+	if nftSymbol != "" {
+		addr, err := c.findBySymbol(nftSymbol, StandardERC721, StandardERC1155)
+		if err != nil {
+			return config, err
+		}
+		config.NFTContracts[chain] = addr
+	}
+	return config, nil
+}
+
+// findBySymbol returns the address of the single contract with the symbol
+// that implements one of the standards. A contract implementing several of
+// them counts once.
+func (c *Catalog) findBySymbol(symbol string, standards ...string) (string, error) {
+	var matches []string
+	seen := make(map[string]bool)
+	for _, standard := range standards {
+		for _, contract := range c.Tokens(standard) {
+			addr := contract.Address.Hex()
+			if strings.EqualFold(contract.Symbol, symbol) && !seen[addr] {
+				seen[addr] = true
+				matches = append(matches, addr)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s contract with symbol %s in catalogue", strings.Join(standards, "/"), symbol)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("symbol %s is ambiguous: %s", symbol, strings.Join(matches, ", "))
+}
+
+func copyMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
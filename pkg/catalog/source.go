@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+	gethrawdb "github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/params"
+	"github.com/luxfi/geth/triedb"
+
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+// Source is a read-only chain database opened through geth's state stack,
+// so the catalogue can iterate the state trie and execute calls against it
+type Source struct {
+	chain  *scanner.PebbleSource
+	db     ethdb.Database
+	triedb *triedb.Database
+	state  state.Database
+	config *params.ChainConfig
+}
+
+// Open opens a pebble chain database read-only. namespace is the optional
+// Subnet-EVM key prefix. It is opened as the scanner's PebbleSource, so the
+// ancient store and hash- or path-scheme state are read the same way.
+func Open(dbPath string, namespace []byte) (*Source, error) {
+	chain, err := scanner.NewPebbleSource(dbPath, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{
+		chain:  chain,
+		db:     chain.Database(),
+		triedb: chain.TrieDB(),
+		state:  chain.StateDatabase(),
+		config: chain.ChainConfig(),
+	}, nil
+}
+
+// Close releases the database
+func (s *Source) Close() error {
+	return s.chain.Close()
+}
+
+// Header returns the canonical header at number, or the head header when
+// number is nil
+func (s *Source) Header(number *uint64) (*types.Header, error) {
+	var n *big.Int
+	if number != nil {
+		n = new(big.Int).SetUint64(*number)
+	}
+	return s.chain.HeaderByNumber(context.Background(), n)
+}
+
+// CandidateAddresses collects addresses seen in blocks [0, to]: transaction
+// recipients, created contracts and log emitters. They are keyed by the hash
+// used in the state trie, to resolve accounts whose preimage was not stored.
+func (s *Source) CandidateAddresses(to uint64, progress func(number uint64)) (map[common.Hash]common.Address, error) {
+	candidates := make(map[common.Hash]common.Address)
+	add := func(addr common.Address) {
+		candidates[crypto.Keccak256Hash(addr.Bytes())] = addr
+	}
+
+	for number := uint64(0); number <= to; number++ {
+		hash := gethrawdb.ReadCanonicalHash(s.db, number)
+		if hash == (common.Hash{}) {
+			return nil, fmt.Errorf("missing canonical hash for block %d", number)
+		}
+		body := gethrawdb.ReadBody(s.db, hash, number)
+		if body == nil || len(body.Transactions) == 0 {
+			continue
+		}
+		for _, tx := range body.Transactions {
+			if tx.To() != nil {
+				add(*tx.To())
+				continue
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err == nil {
+				add(crypto.CreateAddress(sender, tx.Nonce()))
+			}
+		}
+		for _, receipt := range gethrawdb.ReadRawReceipts(s.db, hash, number) {
+			for _, l := range receipt.Logs {
+				add(l.Address)
+			}
+		}
+
+		if progress != nil && number%100000 == 0 {
+			progress(number)
+		}
+	}
+	return candidates, nil
+}
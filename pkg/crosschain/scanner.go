@@ -4,25 +4,28 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"time"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/crypto"
+
+	"github.com/luxfi/genesis/pkg/scanner"
 )
 
 // EventScanner scans blockchain events
 type EventScanner struct {
-	client    *Client
-	batchSize int64
+	client *Client
+	logs   *scanner.LogScanner
 }
 
 // NewEventScanner creates a new event scanner
 func NewEventScanner(client *Client) *EventScanner {
 	return &EventScanner{
-		client:    client,
-		batchSize: 5000, // Scan 5000 blocks at a time
+		client: client,
+		logs: scanner.NewLogScanner(&scanner.RPCSource{Client: client.client}, nil, scanner.LogScanConfig{
+			ChunkSize:         5000, // Scan 5000 blocks at a time
+			RequestsPerSecond: 10,
+		}),
 	}
 }
 
@@ -37,73 +40,48 @@ func (s *EventScanner) ScanBurnEvents(ctx context.Context, tokenAddr common.Addr
 	
 	burns := []BurnEvent{}
 	
-	// Process in batches
-	current := new(big.Int).Set(fromBlock)
-	for current.Cmp(toBlock) <= 0 {
-		end := new(big.Int).Add(current, big.NewInt(s.batchSize))
-		if end.Cmp(toBlock) > 0 {
-			end = toBlock
-		}
-		
-		// Create filter query
-		query := ethereum.FilterQuery{
-			FromBlock: current,
-			ToBlock:   end,
-			Addresses: []common.Address{tokenAddr},
-			Topics: [][]common.Hash{
-				{transferSig},         // Transfer event
-				nil,                   // from (any)
-				{zeroAddrTopic},       // to (0x0)
-			},
+	query := scanner.LogQuery{
+		Addresses: []common.Address{tokenAddr},
+		Topics: [][]common.Hash{
+			{transferSig},   // Transfer event
+			nil,             // from (any)
+			{zeroAddrTopic}, // to (0x0)
+		},
+	}
+
+	// Process logs
+	err := s.logs.Scan(ctx, query, fromBlock.Uint64(), toBlock.Uint64(), func(log types.Log) error {
+		if len(log.Topics) < 3 {
+			return nil
 		}
-		
-		// Get logs
-		logs, err := s.client.client.FilterLogs(ctx, query)
+
+		// Extract from address
+		from := common.BytesToAddress(log.Topics[1].Bytes()[12:])
+
+		// Extract amount
+		amount := new(big.Int).SetBytes(log.Data)
+
+		// Get block timestamp
+		blockTime, err := s.logs.BlockTime(ctx, log.BlockNumber)
 		if err != nil {
-			// Try smaller batch on error
-			if s.batchSize > 100 {
-				s.batchSize = s.batchSize / 2
-				continue
-			}
-			return nil, fmt.Errorf("failed to get logs: %w", err)
+			return err
 		}
-		
-		// Process logs
-		for _, log := range logs {
-			if len(log.Topics) < 3 {
-				continue
-			}
-			
-			// Extract from address
-			from := common.BytesToAddress(log.Topics[1].Bytes()[12:])
-			
-			// Extract amount
-			amount := new(big.Int).SetBytes(log.Data)
-			
-			// Get block timestamp
-			block, err := s.client.client.BlockByNumber(ctx, big.NewInt(int64(log.BlockNumber)))
-			if err != nil {
-				continue
-			}
-			
-			burns = append(burns, BurnEvent{
-				From:            from,
-				Amount:          amount,
-				BlockNumber:     log.BlockNumber,
-				TransactionHash: log.TxHash,
-				Timestamp:       block.Time(),
-			})
-		}
-		
-		// Progress
-		fmt.Printf("Scanned blocks %s to %s, found %d burns\n", current, end, len(burns))
-		
-		// Next batch
-		current = new(big.Int).Add(end, big.NewInt(1))
-		
-		// Rate limit
-		time.Sleep(100 * time.Millisecond)
+
+		burns = append(burns, BurnEvent{
+			From:            from,
+			Amount:          amount,
+			BlockNumber:     log.BlockNumber,
+			TransactionHash: log.TxHash,
+			Timestamp:       blockTime,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
 	}
+
+	// Progress
+	fmt.Printf("Scanned blocks %s to %s, found %d burns\n", fromBlock, toBlock, len(burns))
 	
 	return burns, nil
 }
@@ -115,52 +93,27 @@ func (s *EventScanner) ScanTransferEvents(ctx context.Context, tokenAddr common.
 	
 	transfers := []TransferEvent{}
 	
-	// Process in batches
-	current := new(big.Int).Set(fromBlock)
-	for current.Cmp(toBlock) <= 0 {
-		end := new(big.Int).Add(current, big.NewInt(s.batchSize))
-		if end.Cmp(toBlock) > 0 {
-			end = toBlock
-		}
-		
-		// Create filter query
-		query := ethereum.FilterQuery{
-			FromBlock: current,
-			ToBlock:   end,
-			Addresses: []common.Address{tokenAddr},
-			Topics: [][]common.Hash{
-				{transferSig}, // Transfer event
-			},
-		}
-		
-		// Get logs
-		logs, err := s.client.client.FilterLogs(ctx, query)
-		if err != nil {
-			// Try smaller batch on error
-			if s.batchSize > 100 {
-				s.batchSize = s.batchSize / 2
-				continue
-			}
-			return nil, fmt.Errorf("failed to get logs: %w", err)
-		}
-		
-		// Process logs
-		for _, log := range logs {
-			transfer := s.parseTransferLog(log)
-			if transfer != nil {
-				transfers = append(transfers, *transfer)
-			}
+	query := scanner.LogQuery{
+		Addresses: []common.Address{tokenAddr},
+		Topics: [][]common.Hash{
+			{transferSig}, // Transfer event
+		},
+	}
+
+	// Process logs
+	err := s.logs.Scan(ctx, query, fromBlock.Uint64(), toBlock.Uint64(), func(log types.Log) error {
+		transfer := s.parseTransferLog(log)
+		if transfer != nil {
+			transfers = append(transfers, *transfer)
 		}
-		
-		// Progress
-		fmt.Printf("Scanned blocks %s to %s, found %d transfers\n", current, end, len(transfers))
-		
-		// Next batch
-		current = new(big.Int).Add(end, big.NewInt(1))
-		
-		// Rate limit
-		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
 	}
+
+	// Progress
+	fmt.Printf("Scanned blocks %s to %s, found %d transfers\n", fromBlock, toBlock, len(transfers))
 	
 	return transfers, nil
 }
@@ -3,9 +3,9 @@ package allocation
 import (
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
-	"unicode"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
 )
 
 // Builder helps construct allocations with various vesting schedules
@@ -61,6 +61,27 @@ func (b *Builder) CreateVestedAllocation(ethAddr string, config *UnlockScheduleC
 	}, nil
 }
 
+// CreateScheduledAllocation creates an allocation whose unlocks follow a
+// schedule template
+func (b *Builder) CreateScheduledAllocation(ethAddr string, config *ScheduleConfig) (*Allocation, error) {
+	schedule, err := config.Expand()
+	if err != nil {
+		return nil, err
+	}
+
+	luxAddr, err := b.converter.ETHToLux(ethAddr, "X")
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert address: %w", err)
+	}
+
+	return &Allocation{
+		ETHAddr:        ethAddr,
+		LuxAddr:        luxAddr,
+		InitialAmount:  big.NewInt(0),
+		UnlockSchedule: schedule,
+	}, nil
+}
+
 // CreateLinearVestingSchedule creates a linear unlock schedule
 func (b *Builder) createUnlockSchedule(config *UnlockScheduleConfig) []LockedAmount {
 	if config.Periods <= 0 {
@@ -116,89 +137,17 @@ var (
 )
 
 // ParseLUXAmount parses a LUX amount string with support for suffixes (T, B, M, K)
+// into nLUX (9 decimals). Digits beyond nLUX precision are an error.
 // Examples: "2T" = 2 trillion, "1.5B" = 1.5 billion, "1000000" = 1 million
 func ParseLUXAmount(amountStr string) (*big.Int, error) {
-	amountStr = strings.TrimSpace(amountStr)
-	if amountStr == "" {
-		return nil, fmt.Errorf("empty amount")
-	}
-	
-	// Check for suffix
-	suffix := ""
-	numStr := amountStr
-	
-	lastChar := amountStr[len(amountStr)-1]
-	if !unicode.IsDigit(rune(lastChar)) {
-		suffix = strings.ToUpper(string(lastChar))
-		numStr = amountStr[:len(amountStr)-1]
-	}
-	
-	// Parse the numeric part (supports decimals)
-	parts := strings.Split(numStr, ".")
-	
-	// Parse integer part
-	intPart := new(big.Int)
-	if parts[0] != "" {
-		if _, ok := intPart.SetString(parts[0], 10); !ok {
-			return nil, fmt.Errorf("invalid number: %s", parts[0])
-		}
-		// Check for negative
-		if intPart.Sign() < 0 {
-			return nil, fmt.Errorf("negative amounts not allowed")
-		}
-	}
-	
-	// Handle decimal part if present
-	decimalPlaces := 0
-	if len(parts) > 1 {
-		if len(parts) > 2 {
-			return nil, fmt.Errorf("multiple decimal points")
-		}
-		decimalPlaces = len(parts[1])
-		
-		// Append decimal digits to integer
-		if _, ok := intPart.SetString(parts[0]+parts[1], 10); !ok {
-			return nil, fmt.Errorf("invalid decimal number")
-		}
-	}
-	
-	// Apply multiplier based on suffix
-	multiplier := new(big.Int).Set(OneLUX) // Base unit with 9 decimals
-	
-	switch suffix {
-	case "T": // Trillion
-		multiplier.Mul(multiplier, big.NewInt(1_000_000_000_000))
-	case "B": // Billion
-		multiplier.Mul(multiplier, big.NewInt(1_000_000_000))
-	case "M": // Million
-		multiplier.Mul(multiplier, big.NewInt(1_000_000))
-	case "K": // Thousand
-		multiplier.Mul(multiplier, big.NewInt(1_000))
-	case "":
-		// No suffix, treat as LUX units
-		multiplier.Set(OneLUX)
-	default:
-		return nil, fmt.Errorf("unknown suffix: %s", suffix)
-	}
-	
-	// Adjust for decimal places
-	for i := 0; i < decimalPlaces; i++ {
-		multiplier.Div(multiplier, big.NewInt(10))
+	parsed, err := amount.Parse(amountStr, amount.NLUX)
+	if err != nil {
+		return nil, err
 	}
-	
-	// Calculate final amount
-	return intPart.Mul(intPart, multiplier), nil
+	return parsed.Value(), nil
 }
 
-// FormatLUXAmount formats a big.Int amount to human-readable LUX (dividing by 10^9)
-func FormatLUXAmount(amount *big.Int) string {
-	lux := new(big.Int).Div(amount, OneLUX)
-	remainder := new(big.Int).Mod(amount, OneLUX)
-	
-	if remainder.Sign() == 0 {
-		return fmt.Sprintf("%s LUX", lux.String())
-	}
-	
-	// Show up to 9 decimal places
-	return fmt.Sprintf("%s.%09d LUX", lux.String(), remainder.Int64())
-}
\ No newline at end of file
+// FormatLUXAmount formats an nLUX amount as exact human-readable LUX
+func FormatLUXAmount(nlux *big.Int) string {
+	return amount.New(nlux, amount.NLUX).String()
+}
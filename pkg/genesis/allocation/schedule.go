@@ -0,0 +1,301 @@
+package allocation
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Template names a vesting schedule shape
+type Template string
+
+const (
+	// TemplateLinear unlocks equal amounts at the end of each period after
+	// the cliff; it is the schedule UnlockScheduleConfig describes
+	TemplateLinear Template = "linear"
+
+	// TemplateMonthly unlocks equal amounts on the same day of each calendar
+	// month, starting one month after a cliff of whole months. Days past the
+	// end of a shorter month fall on its last day.
+	TemplateMonthly Template = "monthly"
+
+	// TemplateBackLoaded unlocks period k after the cliff in proportion to k,
+	// so most of the amount vests towards the end
+	TemplateBackLoaded Template = "back-loaded"
+
+	// TemplateCustom unlocks given percentages on given dates
+	TemplateCustom Template = "custom"
+
+	// TemplateTGELinear unlocks a percentage at the start (token generation
+	// event) and the rest linearly, like TemplateLinear
+	TemplateTGELinear Template = "tge-linear"
+)
+
+// Templates lists the schedule templates
+var Templates = []Template{TemplateLinear, TemplateMonthly, TemplateBackLoaded, TemplateCustom, TemplateTGELinear}
+
+// ParseTemplate parses a template name; empty means linear
+func ParseTemplate(s string) (Template, error) {
+	if s == "" {
+		return TemplateLinear, nil
+	}
+	for _, t := range Templates {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown schedule template %q", s)
+}
+
+// BasisPoints is a fraction in hundredths of a percent; 10000 is 100%
+type BasisPoints uint64
+
+// Whole is 100% in basis points
+const Whole BasisPoints = 10000
+
+// ParsePercent parses a percentage such as "10", "12.5%" or "0.25%" into
+// basis points
+func ParsePercent(s string) (BasisPoints, error) {
+	str := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	whole, frac, _ := strings.Cut(str, ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("percentage %q has more than 2 decimals", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	v, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil || whole == "" {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	if BasisPoints(v) > Whole {
+		return 0, fmt.Errorf("percentage %q exceeds 100%%", s)
+	}
+	return BasisPoints(v), nil
+}
+
+// String formats basis points as a percentage
+func (bp BasisPoints) String() string {
+	s := fmt.Sprintf("%d.%02d", bp/100, bp%100)
+	return strings.TrimSuffix(strings.TrimSuffix(s, "0"), ".0") + "%"
+}
+
+// CustomUnlock is one entry of a custom schedule
+type CustomUnlock struct {
+	Time    time.Time
+	Percent BasisPoints
+}
+
+// ScheduleConfig describes a vesting schedule built from a template
+type ScheduleConfig struct {
+	Template    Template
+	TotalAmount *big.Int
+	StartDate   time.Time // TGE
+
+	// Linear, back-loaded and tge-linear: Duration is split into Periods
+	// equal periods. Monthly: Periods is the number of monthly unlocks and
+	// Duration is unused. CliffPeriods (months for monthly) pass before the
+	// first unlock.
+	Duration     time.Duration
+	Periods      int
+	CliffPeriods int
+
+	// TGEPercent is unlocked at StartDate (tge-linear)
+	TGEPercent BasisPoints
+
+	// Unlocks lists the dates and percentages of a custom schedule; the
+	// percentages must add up to 100%
+	Unlocks []CustomUnlock
+}
+
+// Validate checks the parameters the template needs
+func (c *ScheduleConfig) Validate() error {
+	if c.TotalAmount == nil || c.TotalAmount.Sign() < 0 {
+		return fmt.Errorf("schedule needs a non-negative total amount")
+	}
+	switch c.Template {
+	case TemplateLinear, TemplateBackLoaded, TemplateTGELinear:
+		if c.Periods <= 0 || c.CliffPeriods < 0 || c.CliffPeriods >= c.Periods {
+			return fmt.Errorf("%s schedule needs periods > cliff >= 0", c.Template)
+		}
+		if c.Duration <= 0 {
+			return fmt.Errorf("%s schedule needs a positive duration", c.Template)
+		}
+		if c.Template == TemplateTGELinear && c.TGEPercent > Whole {
+			return fmt.Errorf("TGE percentage %s exceeds 100%%", c.TGEPercent)
+		}
+	case TemplateMonthly:
+		if c.Periods <= 0 || c.CliffPeriods < 0 {
+			return fmt.Errorf("monthly schedule needs periods > 0 and cliff >= 0")
+		}
+	case TemplateCustom:
+		if len(c.Unlocks) == 0 {
+			return fmt.Errorf("custom schedule needs at least one unlock")
+		}
+		var sum BasisPoints
+		for i, u := range c.Unlocks {
+			if i > 0 && !u.Time.After(c.Unlocks[i-1].Time) {
+				return fmt.Errorf("custom unlock %d: %s is not after %s", i, u.Time.Format(time.RFC3339), c.Unlocks[i-1].Time.Format(time.RFC3339))
+			}
+			sum += u.Percent
+		}
+		if sum != Whole {
+			return fmt.Errorf("custom unlocks add up to %s, expected 100%%", sum)
+		}
+	default:
+		return fmt.Errorf("unknown schedule template %q", c.Template)
+	}
+	return nil
+}
+
+// Expand returns the unlocks of the schedule in time order. The amounts add
+// up to TotalAmount exactly: each is rounded down and the remainder is added
+// to the last unlock.
+func (c *ScheduleConfig) Expand() ([]LockedAmount, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		times   []time.Time
+		weights []uint64
+	)
+	periodDuration := c.Duration / time.Duration(max(c.Periods, 1))
+	periodEnd := func(i int) time.Time { return c.StartDate.Add(periodDuration * time.Duration(i+1)) }
+
+	switch c.Template {
+	case TemplateLinear:
+		for i := c.CliffPeriods; i < c.Periods; i++ {
+			times = append(times, periodEnd(i))
+			weights = append(weights, 1)
+		}
+	case TemplateBackLoaded:
+		for i := c.CliffPeriods; i < c.Periods; i++ {
+			times = append(times, periodEnd(i))
+			weights = append(weights, uint64(i-c.CliffPeriods+1))
+		}
+	case TemplateMonthly:
+		for i := 1; i <= c.Periods; i++ {
+			times = append(times, addMonths(c.StartDate, c.CliffPeriods+i))
+			weights = append(weights, 1)
+		}
+	case TemplateTGELinear:
+		if c.TGEPercent > 0 {
+			times = append(times, c.StartDate)
+			weights = append(weights, uint64(c.TGEPercent)*uint64(c.Periods-c.CliffPeriods))
+		}
+		if c.TGEPercent < Whole {
+			for i := c.CliffPeriods; i < c.Periods; i++ {
+				times = append(times, periodEnd(i))
+				weights = append(weights, uint64(Whole-c.TGEPercent))
+			}
+		}
+	case TemplateCustom:
+		for _, u := range c.Unlocks {
+			if u.Percent == 0 {
+				continue
+			}
+			times = append(times, u.Time)
+			weights = append(weights, uint64(u.Percent))
+		}
+	}
+
+	return split(c.TotalAmount, times, weights), nil
+}
+
+// addMonths returns t moved by n calendar months, keeping its day of month
+// but clamping it to the last day of the target month (Jan 31 + 1 is Feb 28
+// or 29, not Mar 3 as time.AddDate would normalise it)
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// split divides total among the given times in proportion to weights
+func split(total *big.Int, times []time.Time, weights []uint64) []LockedAmount {
+	var sum uint64
+	for _, w := range weights {
+		sum += w
+	}
+	schedule := make([]LockedAmount, 0, len(times))
+	remaining := new(big.Int).Set(total)
+	for i, t := range times {
+		share := new(big.Int).Mul(total, new(big.Int).SetUint64(weights[i]))
+		share.Quo(share, new(big.Int).SetUint64(sum))
+		if i == len(times)-1 {
+			share = remaining
+		}
+		remaining = new(big.Int).Sub(remaining, share)
+		schedule = append(schedule, LockedAmount{Amount: share, Locktime: uint64(t.Unix())})
+	}
+	return schedule
+}
+
+// Unlock is one row of an unlock table
+type Unlock struct {
+	Address  string
+	Locktime uint64
+	Amount   *big.Int
+}
+
+// UnlockTable lists every unlock of the allocations ordered by time, then
+// address. Initial (unlocked) amounts are listed at genesisTime.
+func UnlockTable(allocs []*Allocation, genesisTime uint64) []Unlock {
+	var table []Unlock
+	for _, alloc := range allocs {
+		if alloc.InitialAmount != nil && alloc.InitialAmount.Sign() > 0 {
+			table = append(table, Unlock{Address: alloc.ETHAddr, Locktime: genesisTime, Amount: alloc.InitialAmount})
+		}
+		for _, locked := range alloc.UnlockSchedule {
+			table = append(table, Unlock{Address: alloc.ETHAddr, Locktime: max(locked.Locktime, genesisTime), Amount: locked.Amount})
+		}
+	}
+	sort.SliceStable(table, func(i, j int) bool {
+		if table[i].Locktime != table[j].Locktime {
+			return table[i].Locktime < table[j].Locktime
+		}
+		return table[i].Address < table[j].Address
+	})
+	return table
+}
+
+// SupplyPoint is the circulating supply after the unlocks at Locktime
+type SupplyPoint struct {
+	Locktime    uint64
+	Unlocked    *big.Int // unlocked at Locktime
+	Circulating *big.Int // unlocked at or before Locktime
+	Total       *big.Int // all allocations, locked or not
+}
+
+// SupplyCurve returns the cumulative circulating supply at each distinct
+// unlock time
+func SupplyCurve(allocs []*Allocation, genesisTime uint64) []SupplyPoint {
+	table := UnlockTable(allocs, genesisTime)
+	total := new(big.Int)
+	for _, u := range table {
+		total.Add(total, u.Amount)
+	}
+
+	var curve []SupplyPoint
+	circulating := new(big.Int)
+	for _, u := range table {
+		circulating.Add(circulating, u.Amount)
+		if n := len(curve); n > 0 && curve[n-1].Locktime == u.Locktime {
+			curve[n-1].Unlocked.Add(curve[n-1].Unlocked, u.Amount)
+			curve[n-1].Circulating.Set(circulating)
+			continue
+		}
+		curve = append(curve, SupplyPoint{
+			Locktime:    u.Locktime,
+			Unlocked:    new(big.Int).Set(u.Amount),
+			Circulating: new(big.Int).Set(circulating),
+			Total:       total,
+		})
+	}
+	return curve
+}
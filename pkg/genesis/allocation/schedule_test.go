@@ -0,0 +1,150 @@
+package allocation
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tge = time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+func amounts(schedule []LockedAmount) []int64 {
+	out := make([]int64, len(schedule))
+	for i, l := range schedule {
+		out[i] = l.Amount.Int64()
+	}
+	return out
+}
+
+func TestScheduleTemplates(t *testing.T) {
+	day := 24 * time.Hour
+
+	// Linear matches UnlockScheduleConfig
+	linear := &ScheduleConfig{Template: TemplateLinear, TotalAmount: big.NewInt(1000), StartDate: tge, Duration: 4 * day, Periods: 4, CliffPeriods: 1}
+	schedule, err := linear.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{333, 333, 334}, amounts(schedule))
+	legacy := NewBuilder(&mockAddressConverter{}).createUnlockSchedule(&UnlockScheduleConfig{
+		TotalAmount: big.NewInt(1000), StartDate: tge, Duration: 4 * day, Periods: 4, CliffPeriods: 1,
+	})
+	assert.Equal(t, legacy, schedule)
+
+	// Back-loaded: weights 1, 2, 3
+	backLoaded := *linear
+	backLoaded.Template = TemplateBackLoaded
+	backLoaded.TotalAmount = big.NewInt(600)
+	schedule, err = backLoaded.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100, 200, 300}, amounts(schedule))
+
+	// Monthly after a 6 month cliff follows calendar months
+	monthly := &ScheduleConfig{Template: TemplateMonthly, TotalAmount: big.NewInt(100), StartDate: tge, Periods: 3, CliffPeriods: 6}
+	schedule, err = monthly.Expand()
+	require.NoError(t, err)
+	require.Len(t, schedule, 3)
+	assert.Equal(t, uint64(tge.AddDate(0, 7, 0).Unix()), schedule[0].Locktime)
+	assert.Equal(t, uint64(tge.AddDate(0, 9, 0).Unix()), schedule[2].Locktime)
+	assert.Equal(t, []int64{33, 33, 34}, amounts(schedule))
+
+	// 10% at TGE, the rest over 3 periods
+	tgeLinear := &ScheduleConfig{Template: TemplateTGELinear, TotalAmount: big.NewInt(1000), StartDate: tge, Duration: 3 * day, Periods: 3, TGEPercent: 1000}
+	schedule, err = tgeLinear.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(tge.Unix()), schedule[0].Locktime)
+	assert.Equal(t, []int64{100, 300, 300, 300}, amounts(schedule))
+
+	// Custom dates and percentages
+	custom := &ScheduleConfig{Template: TemplateCustom, TotalAmount: big.NewInt(1000), Unlocks: []CustomUnlock{
+		{Time: tge, Percent: 2500},
+		{Time: tge.AddDate(1, 0, 0), Percent: 7500},
+	}}
+	schedule, err = custom.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{250, 750}, amounts(schedule))
+}
+
+func TestScheduleMonthlyMonthEnd(t *testing.T) {
+	// Starting on the 31st unlocks on the last day of shorter months
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	monthly := &ScheduleConfig{Template: TemplateMonthly, TotalAmount: big.NewInt(400), StartDate: start, Periods: 4}
+	schedule, err := monthly.Expand()
+	require.NoError(t, err)
+
+	want := []time.Time{
+		time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC),
+	}
+	require.Len(t, schedule, len(want))
+	for i, w := range want {
+		assert.Equal(t, uint64(w.Unix()), schedule[i].Locktime, "unlock %d", i)
+	}
+
+	// Outside a leap year February ends on the 28th, also after a cliff
+	monthly.StartDate = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	monthly.CliffPeriods = 1
+	schedule, err = monthly.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC).Unix()), schedule[0].Locktime)
+}
+
+func TestScheduleValidate(t *testing.T) {
+	custom := &ScheduleConfig{Template: TemplateCustom, TotalAmount: big.NewInt(1), Unlocks: []CustomUnlock{
+		{Time: tge, Percent: 5000},
+		{Time: tge, Percent: 4000},
+	}}
+	err := custom.Validate()
+	assert.ErrorContains(t, err, "is not after")
+
+	custom.Unlocks[1].Time = tge.Add(time.Hour)
+	assert.ErrorContains(t, custom.Validate(), "add up to 90%")
+
+	linear := &ScheduleConfig{Template: TemplateLinear, TotalAmount: big.NewInt(1), Duration: time.Hour, Periods: 2, CliffPeriods: 2}
+	assert.Error(t, linear.Validate())
+}
+
+func TestParsePercent(t *testing.T) {
+	for in, want := range map[string]BasisPoints{"10": 1000, "12.5%": 1250, "0.25": 25, "100%": Whole} {
+		got, err := ParsePercent(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "1.234", "101", "-5", "ten"} {
+		_, err := ParsePercent(in)
+		assert.Error(t, err, in)
+	}
+	assert.Equal(t, "12.5%", BasisPoints(1250).String())
+	assert.Equal(t, "10%", BasisPoints(1000).String())
+}
+
+func TestSupplyCurve(t *testing.T) {
+	genesis := uint64(tge.Unix())
+	allocs := []*Allocation{
+		{ETHAddr: "0xb", InitialAmount: big.NewInt(100)},
+		{ETHAddr: "0xa", InitialAmount: big.NewInt(0), UnlockSchedule: []LockedAmount{
+			{Amount: big.NewInt(50), Locktime: genesis + 10},
+			{Amount: big.NewInt(50), Locktime: genesis + 20},
+		}},
+		{ETHAddr: "0xc", InitialAmount: big.NewInt(0), UnlockSchedule: []LockedAmount{
+			{Amount: big.NewInt(25), Locktime: genesis + 10},
+		}},
+	}
+
+	table := UnlockTable(allocs, genesis)
+	require.Len(t, table, 4)
+	assert.Equal(t, "0xb", table[0].Address)
+	assert.Equal(t, "0xa", table[1].Address)
+	assert.Equal(t, "0xc", table[2].Address)
+
+	curve := SupplyCurve(allocs, genesis)
+	require.Len(t, curve, 3)
+	assert.Equal(t, int64(100), curve[0].Circulating.Int64())
+	assert.Equal(t, int64(75), curve[1].Unlocked.Int64())
+	assert.Equal(t, int64(175), curve[1].Circulating.Int64())
+	assert.Equal(t, int64(225), curve[2].Circulating.Int64())
+	assert.Equal(t, int64(225), curve[2].Total.Int64())
+}
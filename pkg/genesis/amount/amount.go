@@ -0,0 +1,316 @@
+// Package amount provides exact LUX amounts with an explicit denomination.
+//
+// The P-Chain and X-Chain count LUX with 9 decimals (nLUX) while the C-Chain
+// counts it with 18 (wei). Mixing the two, or squeezing big amounts through
+// uint64 or float64, silently corrupts genesis balances; Amount keeps the
+// denomination with the value and makes every lossy step explicit.
+package amount
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrOverflow is returned when an amount does not fit the target type
+	ErrOverflow = errors.New("amount overflows uint64")
+
+	// ErrPrecisionLoss is returned when a conversion would drop digits under
+	// the RoundExact policy
+	ErrPrecisionLoss = errors.New("amount cannot be represented without rounding")
+
+	// ErrNegative is returned for negative amounts
+	ErrNegative = errors.New("negative amount")
+
+	// ErrDenomination is returned when combining amounts of different units
+	ErrDenomination = errors.New("denomination mismatch")
+)
+
+// Denomination is the number of decimals of a base unit
+type Denomination uint8
+
+const (
+	// NLUX is the P-Chain and X-Chain base unit (10^-9 LUX)
+	NLUX Denomination = 9
+
+	// Wei is the C-Chain base unit (10^-18 LUX)
+	Wei Denomination = 18
+)
+
+// String returns the unit name
+func (d Denomination) String() string {
+	switch d {
+	case NLUX:
+		return "nLUX"
+	case Wei:
+		return "wei"
+	default:
+		return fmt.Sprintf("10^-%d LUX", uint8(d))
+	}
+}
+
+// unit returns 10^d
+func (d Denomination) unit() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d)), nil)
+}
+
+// Rounding selects how conversions to a coarser denomination treat the digits
+// that cannot be represented
+type Rounding int
+
+const (
+	// RoundExact refuses to drop digits
+	RoundExact Rounding = iota
+
+	// RoundDown truncates towards zero
+	RoundDown
+
+	// RoundUp rounds away from zero
+	RoundUp
+
+	// RoundHalfEven rounds to the nearest value, ties to even
+	RoundHalfEven
+)
+
+// ParseRounding parses a rounding policy name (exact, down, up, half-even)
+func ParseRounding(s string) (Rounding, error) {
+	switch strings.ToLower(s) {
+	case "exact", "":
+		return RoundExact, nil
+	case "down":
+		return RoundDown, nil
+	case "up":
+		return RoundUp, nil
+	case "half-even":
+		return RoundHalfEven, nil
+	}
+	return RoundExact, fmt.Errorf("unknown rounding policy: %s", s)
+}
+
+// String returns the policy name
+func (r Rounding) String() string {
+	switch r {
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundHalfEven:
+		return "half-even"
+	default:
+		return "exact"
+	}
+}
+
+// Amount is a number of base units of a denomination. Parsed amounts are never
+// negative; only Sub can produce one.
+type Amount struct {
+	value *big.Int
+	denom Denomination
+}
+
+// New returns an amount of v base units. A nil v is zero.
+func New(v *big.Int, denom Denomination) Amount {
+	value := new(big.Int)
+	if v != nil {
+		value.Set(v)
+	}
+	return Amount{value: value, denom: denom}
+}
+
+// Zero returns a zero amount
+func Zero(denom Denomination) Amount {
+	return New(nil, denom)
+}
+
+// suffixes are the multipliers accepted by Parse
+var suffixes = map[byte]int{
+	'K': 3,
+	'M': 6,
+	'B': 9,
+	'T': 12,
+}
+
+// Parse parses a decimal LUX amount (e.g. "1000", "123.456", "1.5M", "2T")
+// into base units of denom. Digits beyond the denomination's precision are
+// an error rather than being dropped.
+func Parse(s string, denom Denomination) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{}, fmt.Errorf("empty amount")
+	}
+	if strings.HasPrefix(s, "-") {
+		return Amount{}, ErrNegative
+	}
+
+	exp := int(denom)
+	if last := s[len(s)-1]; last < '0' || last > '9' {
+		shift, ok := suffixes[strings.ToUpper(string(last))[0]]
+		if !ok {
+			return Amount{}, fmt.Errorf("unknown suffix: %c", last)
+		}
+		exp += shift
+		s = s[:len(s)-1]
+	}
+
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if strings.Contains(fracPart, ".") {
+		return Amount{}, fmt.Errorf("multiple decimal points")
+	}
+	if (intPart == "" && fracPart == "") || (hasPoint && fracPart == "") {
+		return Amount{}, fmt.Errorf("invalid number: %s", s)
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return Amount{}, fmt.Errorf("invalid number: %s", s)
+		}
+	}
+
+	// Dropping trailing zeros first lets "1.50" parse at 1 decimal of precision
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > exp {
+		return Amount{}, fmt.Errorf("%s has more than %d decimals: %w", s, exp, ErrPrecisionLoss)
+	}
+
+	digits := intPart + fracPart + strings.Repeat("0", exp-len(fracPart))
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid number: %s", s)
+	}
+	return Amount{value: value, denom: denom}, nil
+}
+
+// Value returns a copy of the amount in base units
+func (a Amount) Value() *big.Int {
+	if a.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.value)
+}
+
+// Denomination returns the amount's unit
+func (a Amount) Denomination() Denomination {
+	return a.denom
+}
+
+// Sign returns -1, 0 or +1
+func (a Amount) Sign() int {
+	if a.value == nil {
+		return 0
+	}
+	return a.value.Sign()
+}
+
+// Uint64 returns the amount in base units, or ErrOverflow if it does not fit
+func (a Amount) Uint64() (uint64, error) {
+	v := a.Value()
+	if v.Sign() < 0 {
+		return 0, ErrNegative
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("%s %s: %w", v, a.denom, ErrOverflow)
+	}
+	return v.Uint64(), nil
+}
+
+// Convert returns the amount in another denomination. Converting to a finer
+// unit is always exact; converting to a coarser one follows the rounding
+// policy.
+func (a Amount) Convert(to Denomination, rounding Rounding) (Amount, error) {
+	v := a.Value()
+	if to >= a.denom {
+		v.Mul(v, (to - a.denom).unit())
+		return Amount{value: v, denom: to}, nil
+	}
+
+	divisor := (a.denom - to).unit()
+	quo, rem := new(big.Int).QuoRem(v, divisor, new(big.Int))
+	if rem.Sign() != 0 {
+		switch rounding {
+		case RoundExact:
+			return Amount{}, fmt.Errorf("%s %s to %s: %w", a.value, a.denom, to, ErrPrecisionLoss)
+		case RoundDown:
+		case RoundUp:
+			quo.Add(quo, big.NewInt(1))
+		case RoundHalfEven:
+			switch new(big.Int).Lsh(rem, 1).Cmp(divisor) {
+			case 1:
+				quo.Add(quo, big.NewInt(1))
+			case 0:
+				if quo.Bit(0) == 1 {
+					quo.Add(quo, big.NewInt(1))
+				}
+			}
+		}
+	}
+	return Amount{value: quo, denom: to}, nil
+}
+
+// Dust returns what a conversion of a to the coarser denomination would lose
+// (negative if rounding added to it), in a's base units
+func (a Amount) Dust(to Denomination, rounding Rounding) (Amount, error) {
+	converted, err := a.Convert(to, rounding)
+	if err != nil {
+		return Amount{}, err
+	}
+	back, _ := converted.Convert(a.denom, RoundExact)
+	return Amount{value: new(big.Int).Sub(a.Value(), back.value), denom: a.denom}, nil
+}
+
+// Add returns a + b; both must have the same denomination
+func (a Amount) Add(b Amount) (Amount, error) {
+	if a.denom != b.denom {
+		return Amount{}, fmt.Errorf("%s + %s: %w", a.denom, b.denom, ErrDenomination)
+	}
+	return Amount{value: new(big.Int).Add(a.Value(), b.Value()), denom: a.denom}, nil
+}
+
+// Sub returns a - b, which may be negative; both must have the same
+// denomination
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if a.denom != b.denom {
+		return Amount{}, fmt.Errorf("%s - %s: %w", a.denom, b.denom, ErrDenomination)
+	}
+	return Amount{value: new(big.Int).Sub(a.Value(), b.Value()), denom: a.denom}, nil
+}
+
+// Cmp compares two amounts exactly, whatever their denominations
+func (a Amount) Cmp(b Amount) int {
+	finer := a.denom
+	if b.denom > finer {
+		finer = b.denom
+	}
+	x, _ := a.Convert(finer, RoundExact)
+	y, _ := b.Convert(finer, RoundExact)
+	return x.value.Cmp(y.value)
+}
+
+// String formats the amount as an exact LUX value, e.g. "1234.5 LUX"
+func (a Amount) String() string {
+	v := a.Value()
+	neg := v.Sign() < 0
+	v.Abs(v)
+
+	quo, rem := new(big.Int).QuoRem(v, a.denom.unit(), new(big.Int))
+	s := quo.String()
+	if rem.Sign() != 0 {
+		frac := fmt.Sprintf("%0*s", int(a.denom), rem.String())
+		s += "." + strings.TrimRight(frac, "0")
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s + " LUX"
+}
+
+// MarshalJSON encodes the amount as a decimal string of base units with its
+// denomination
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Value        string `json:"value"`
+		Denomination string `json:"denomination"`
+		LUX          string `json:"lux"`
+	}{a.Value().String(), a.denom.String(), strings.TrimSuffix(a.String(), " LUX")})
+}
@@ -0,0 +1,132 @@
+package amount
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustBig(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic(s)
+	}
+	return v
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		denom    Denomination
+		expected string
+		wantErr  error
+	}{
+		{"1000", NLUX, "1000000000000", nil},
+		{"123.456", NLUX, "123456000000", nil},
+		{"1.5B", NLUX, "1500000000000000000", nil},
+		{"1000", Wei, "1000000000000000000000", nil},
+		{"1K", Wei, "1000000000000000000000", nil},
+		{"1M", Wei, "1000000000000000000000000", nil},
+		{"1.5M", Wei, "1500000000000000000000000", nil},
+		{"1B", Wei, "1000000000000000000000000000", nil},
+		{"2T", Wei, "2000000000000000000000000000000", nil},
+		{"2t", NLUX, "2000000000000000000000", nil},
+		{"0.000000001", NLUX, "1", nil},
+		{"1.50", NLUX, "1500000000", nil},
+		{"0.0000000001", NLUX, "", ErrPrecisionLoss},
+		{"0.0000000001", Wei, "100000000", nil},
+		{"-1", NLUX, "", ErrNegative},
+		{"", NLUX, "", nil},
+		{"abc", NLUX, "", nil},
+		{"100X", NLUX, "", nil},
+		{"1.2.3", NLUX, "", nil},
+		{"1.", NLUX, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			a, err := Parse(tt.input, tt.denom)
+			if tt.expected == "" {
+				require.Error(t, err)
+				if tt.wantErr != nil {
+					assert.True(t, errors.Is(err, tt.wantErr))
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, a.Value().String())
+			assert.Equal(t, tt.denom, a.Denomination())
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	oneWeiOver := New(mustBig("1000000000000000001"), Wei) // 1 LUX + 1 wei
+	halfNLUX := New(mustBig("1000000000500000000"), Wei)   // 1 LUX + 0.5 nLUX
+	halfOdd := New(mustBig("1000000001500000000"), Wei)    // 1.000000001 LUX + 0.5 nLUX
+
+	_, err := oneWeiOver.Convert(NLUX, RoundExact)
+	assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	tests := []struct {
+		name     string
+		amount   Amount
+		rounding Rounding
+		expected uint64
+	}{
+		{"down", oneWeiOver, RoundDown, 1000000000},
+		{"up", oneWeiOver, RoundUp, 1000000001},
+		{"half-even below half", oneWeiOver, RoundHalfEven, 1000000000},
+		{"half-even tie to even", halfNLUX, RoundHalfEven, 1000000000},
+		{"half-even tie to odd rounds up", halfOdd, RoundHalfEven, 1000000002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := tt.amount.Convert(NLUX, tt.rounding)
+			require.NoError(t, err)
+			v, err := converted.Uint64()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+
+	// Scaling up is always exact
+	up, err := New(big.NewInt(7), NLUX).Convert(Wei, RoundExact)
+	require.NoError(t, err)
+	assert.Equal(t, "7000000000", up.Value().String())
+	assert.Equal(t, 0, up.Cmp(New(big.NewInt(7), NLUX)))
+
+	dust, err := oneWeiOver.Dust(NLUX, RoundDown)
+	require.NoError(t, err)
+	assert.Equal(t, "1", dust.Value().String())
+}
+
+func TestUint64Overflow(t *testing.T) {
+	_, err := New(mustBig("18446744073709551616"), NLUX).Uint64()
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+	v, err := New(mustBig("18446744073709551615"), NLUX).Uint64()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(18446744073709551615), v)
+}
+
+func TestAddAndString(t *testing.T) {
+	a, err := Parse("1234.5", NLUX)
+	require.NoError(t, err)
+	assert.Equal(t, "1234.5 LUX", a.String())
+	assert.Equal(t, "0 LUX", Zero(Wei).String())
+
+	_, err = a.Add(Zero(Wei))
+	assert.True(t, errors.Is(err, ErrDenomination))
+
+	sum, err := a.Add(New(big.NewInt(1), NLUX))
+	require.NoError(t, err)
+	assert.Equal(t, "1234.500000001 LUX", sum.String())
+
+	diff, err := Zero(NLUX).Sub(New(big.NewInt(1), NLUX))
+	require.NoError(t, err)
+	assert.Equal(t, "-0.000000001 LUX", diff.String())
+}
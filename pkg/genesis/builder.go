@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/luxfi/node/genesis"
@@ -13,9 +14,11 @@ import (
 	"github.com/luxfi/node/vms/platformvm/signer"
 	
 	"github.com/luxfi/genesis/pkg/genesis/address"
+	"github.com/luxfi/genesis/pkg/genesis/amount"
 	"github.com/luxfi/genesis/pkg/genesis/allocation"
 	"github.com/luxfi/genesis/pkg/genesis/cchain"
 	"github.com/luxfi/genesis/pkg/genesis/config"
+	"github.com/luxfi/genesis/pkg/genesis/importer"
 )
 
 // Builder orchestrates the creation of genesis configurations
@@ -27,6 +30,16 @@ type Builder struct {
 	allocations   *allocation.AllocationSet
 	stakers       []StakerConfig
 	cchainGenesis string // Stores the C-Chain genesis JSON
+
+	// rounding applies when wei amounts are converted to nLUX; dust is the
+	// wei lost (or added) by those conversions
+	rounding amount.Rounding
+	dust     *big.Int
+
+	// Set from a spec (see FromSpec)
+	message        string
+	xchainAirdrops string
+	subnets        []SubnetSpec
 }
 
 // NewBuilder creates a new genesis builder for a specific network
@@ -36,6 +49,11 @@ func NewBuilder(networkName string) (*Builder, error) {
 		return nil, err
 	}
 
+	return newBuilder(network), nil
+}
+
+// newBuilder creates a builder for a resolved network configuration
+func newBuilder(network *config.NetworkConfig) *Builder {
 	addressConv := address.NewConverter(network.HRP)
 	allocBuilder := allocation.NewBuilder(addressConv)
 	cchainBuilder := cchain.NewBuilder(network.ChainID)
@@ -47,7 +65,8 @@ func NewBuilder(networkName string) (*Builder, error) {
 		cchainBuilder: cchainBuilder,
 		allocations:   allocation.NewAllocationSet(),
 		stakers:       []StakerConfig{},
-	}, nil
+		dust:          new(big.Int),
+	}
 }
 
 // AddAllocation adds a simple allocation
@@ -59,6 +78,28 @@ func (b *Builder) AddAllocation(ethAddr string, amount *big.Int) error {
 	return b.allocations.Add(alloc)
 }
 
+// SetRounding sets how wei amounts are converted to nLUX. The default,
+// amount.RoundExact, rejects amounts that are not a whole number of nLUX.
+func (b *Builder) SetRounding(rounding amount.Rounding) {
+	b.rounding = rounding
+}
+
+// AddAllocationWei adds a simple allocation given in C-Chain units (wei),
+// converting it to nLUX with the builder's rounding policy
+func (b *Builder) AddAllocationWei(ethAddr string, wei *big.Int) error {
+	balance := amount.New(wei, amount.Wei)
+	nlux, err := balance.Convert(amount.NLUX, b.rounding)
+	if err != nil {
+		return fmt.Errorf("allocation for %s: %w", ethAddr, err)
+	}
+	dust, _ := balance.Dust(amount.NLUX, b.rounding)
+	if err := b.AddAllocation(ethAddr, nlux.Value()); err != nil {
+		return err
+	}
+	b.dust.Add(b.dust, dust.Value())
+	return nil
+}
+
 // AddVestedAllocation adds an allocation with vesting
 func (b *Builder) AddVestedAllocation(ethAddr string, config *allocation.UnlockScheduleConfig) error {
 	alloc, err := b.allocBuilder.CreateVestedAllocation(ethAddr, config)
@@ -68,6 +109,16 @@ func (b *Builder) AddVestedAllocation(ethAddr string, config *allocation.UnlockS
 	return b.allocations.Add(alloc)
 }
 
+// AddScheduledAllocation adds an allocation that vests according to a
+// schedule template
+func (b *Builder) AddScheduledAllocation(ethAddr string, config *allocation.ScheduleConfig) error {
+	alloc, err := b.allocBuilder.CreateScheduledAllocation(ethAddr, config)
+	if err != nil {
+		return err
+	}
+	return b.allocations.Add(alloc)
+}
+
 // AddStaker adds a validator to the initial staker set
 func (b *Builder) AddStaker(config StakerConfig) {
 	b.stakers = append(b.stakers, config)
@@ -91,73 +142,61 @@ func (b *Builder) ImportCChainGenesis(genesisPath string) error {
 	return nil
 }
 
-// ImportCChainAllocations imports allocations from C-Chain JSON
+// ImportCChainAllocations imports allocations from C-Chain JSON (an address
+// map or genesis alloc, amounts in wei)
 func (b *Builder) ImportCChainAllocations(allocPath string) error {
-	data, err := ioutil.ReadFile(allocPath)
-	if err != nil {
-		return fmt.Errorf("failed to read allocations: %w", err)
+	im := importer.New(importer.Options{
+		Format:       importer.FormatJSON,
+		Denomination: amount.Wei,
+		Merge:        importer.MergeError,
+	})
+	if err := im.ImportFile(allocPath); err != nil {
+		return fmt.Errorf("failed to import allocations: %w", err)
+	}
+	result := im.Result()
+	if err := result.Err(); err != nil {
+		return err
 	}
 
 	cGenesis := b.cchainBuilder.Build()
-	if err := cchain.ImportAllocations(cGenesis, data); err != nil {
-		return err
+	for _, alloc := range result.Allocations {
+		if !strings.HasPrefix(alloc.Address, "0x") {
+			return fmt.Errorf("%s: C-Chain allocations need a 0x address, got %s", alloc.Sources[0], alloc.Address)
+		}
+		cchain.AddAccountToGenesis(cGenesis, alloc.Address, alloc.Amount.Value())
 	}
 
 	// Store the updated genesis
-	genesisJSON, err := cGenesis.ToJSON()
+	genesisJSON, err := b.cchainGenesisJSON(cGenesis)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid C-Chain genesis: %w", err)
 	}
 	b.cchainGenesis = string(genesisJSON)
-	
+
 	return nil
 }
 
-// ImportCSVAllocations imports allocations from CSV file
+// ImportCSVAllocations imports allocations from a CSV file. The address and
+// amount columns are found from the header (e.g. rank,address,balance_lux);
+// any row that cannot be imported fails the import.
 func (b *Builder) ImportCSVAllocations(csvPath string) error {
-	data, err := ioutil.ReadFile(csvPath)
-	if err != nil {
-		return fmt.Errorf("failed to read CSV file: %w", err)
+	im := importer.New(importer.Options{
+		Format:       importer.FormatCSV,
+		Denomination: amount.NLUX,
+		Rounding:     b.rounding,
+		Merge:        importer.MergeError,
+	})
+	if err := im.ImportFile(csvPath); err != nil {
+		return fmt.Errorf("failed to import CSV allocations: %w", err)
 	}
-
-	lines := strings.Split(string(data), "\n")
-	
-	// Skip header lines (lines starting with #) and column headers
-	dataStartIdx := 0
-	for i, line := range lines {
-		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "rank,") {
-			dataStartIdx = i
-			break
-		}
+	result := im.Result()
+	if err := result.Err(); err != nil {
+		return err
 	}
 
-	// Process allocations
-	for i := dataStartIdx; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		parts := strings.Split(line, ",")
-		if len(parts) < 4 {
-			continue
-		}
-
-		// rank,address,balance_lux,balance_wei,balance_hex,percentage
-		address := parts[1]
-		balanceLux := parts[2] // Use LUX amount instead of wei
-
-		// Parse LUX amount (with decimals)
-		luxAmount, err := parseDecimalAmount(balanceLux)
-		if err != nil {
-			return fmt.Errorf("invalid balance for address %s: %s - %w", address, balanceLux, err)
-		}
-
-		// Convert to wei (9 decimals for Lux)
-		weiAmount := new(big.Int).Mul(luxAmount, big.NewInt(1000000000))
-
-		if err := b.AddAllocation(address, weiAmount); err != nil {
-			return fmt.Errorf("failed to add allocation for %s: %w", address, err)
+	for _, alloc := range result.Allocations {
+		if err := b.AddAllocation(alloc.Address, alloc.Amount.Value()); err != nil {
+			return fmt.Errorf("%s: failed to add allocation for %s: %w", alloc.Sources[0], alloc.Address, err)
 		}
 	}
 
@@ -174,16 +213,25 @@ func (b *Builder) Build() (*MainGenesis, error) {
 		// Convert locked amounts
 		lockedAmounts := make([]genesis.LockedAmount, len(alloc.UnlockSchedule))
 		for i, locked := range alloc.UnlockSchedule {
+			lockedAmount, err := amount.New(locked.Amount, amount.NLUX).Uint64()
+			if err != nil {
+				return nil, fmt.Errorf("locked amount %d for %s: %w", i, alloc.ETHAddr, err)
+			}
 			lockedAmounts[i] = genesis.LockedAmount{
-				Amount:   locked.Amount.Uint64(),
+				Amount:   lockedAmount,
 				Locktime: locked.Locktime,
 			}
 		}
 
+		initialAmount, err := amount.New(alloc.InitialAmount, amount.NLUX).Uint64()
+		if err != nil {
+			return nil, fmt.Errorf("initial amount for %s: %w", alloc.ETHAddr, err)
+		}
+
 		unparsedAllocs = append(unparsedAllocs, genesis.UnparsedAllocation{
 			ETHAddr:        alloc.ETHAddr,
 			LUXAddr:        alloc.LuxAddr,
-			InitialAmount:  alloc.InitialAmount.Uint64(),
+			InitialAmount:  initialAmount,
 			UnlockSchedule: lockedAmounts,
 		})
 
@@ -254,14 +302,18 @@ func (b *Builder) Build() (*MainGenesis, error) {
 
 	// Create C-Chain genesis if not imported
 	if b.cchainGenesis == "" {
-		cGenesis := b.cchainBuilder.Build()
-		genesisJSON, err := cGenesis.ToJSON()
+		genesisJSON, err := b.cchainGenesisJSON(b.cchainBuilder.Build())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create C-Chain genesis: %w", err)
 		}
 		b.cchainGenesis = string(genesisJSON)
 	}
 
+	message := b.message
+	if message == "" {
+		message = fmt.Sprintf("Lux Network Genesis - %s", b.network.Name)
+	}
+
 	return &MainGenesis{
 		NetworkID:                  uint32(b.network.ID),
 		Allocations:                unparsedAllocs,
@@ -271,10 +323,46 @@ func (b *Builder) Build() (*MainGenesis, error) {
 		InitialStakedFunds:         stakedFunds,
 		InitialStakers:             unparsedStakers,
 		CChainGenesis:              b.cchainGenesis,
-		Message:                    fmt.Sprintf("Lux Network Genesis - %s", b.network.Name),
+		Message:                    message,
 	}, nil
 }
 
+// BuildXChain creates the X-Chain genesis with the airdrops from the spec, if any
+func (b *Builder) BuildXChain() (*XChainGenesis, error) {
+	xGenesis, err := BuildXChainGenesis(b.network.Name, b.xchainAirdrops)
+	if err != nil {
+		return nil, err
+	}
+	xGenesis.NetworkID = uint32(b.network.ID)
+	xGenesis.StartTime = uint64(b.network.StartTime.Unix())
+	xGenesis.InitialStakeDuration = uint64(b.network.InitialStakeDuration.Seconds())
+	return xGenesis, nil
+}
+
+// Subnets returns the subnets declared in the spec
+func (b *Builder) Subnets() []SubnetSpec {
+	return b.subnets
+}
+
+// cchainGenesisJSON validates a C-Chain genesis made by the builder, with its
+// fee config, precompiles and scheduled upgrades, and serialises it
+func (b *Builder) cchainGenesisJSON(g *cchain.Genesis) ([]byte, error) {
+	if err := b.cchainBuilder.Validate(g); err != nil {
+		return nil, err
+	}
+	return g.ToJSON()
+}
+
+// CChainUpgrades returns the C-Chain's scheduled upgrades (upgrade.json)
+func (b *Builder) CChainUpgrades() *cchain.UpgradeConfig {
+	return b.cchainBuilder.Upgrades()
+}
+
+// Network returns the network configuration the builder targets
+func (b *Builder) Network() *config.NetworkConfig {
+	return b.network
+}
+
 // SaveToFile saves the genesis to a JSON file
 func (b *Builder) SaveToFile(genesis *MainGenesis, filepath string) error {
 	data, err := json.MarshalIndent(genesis, "", "\t")
@@ -304,34 +392,11 @@ func (b *Builder) GetAllocationCount() int {
 	return b.allocations.Count()
 }
 
-// parseDecimalAmount parses a decimal string (e.g., "1234.567890123") and returns it as a big.Int
-// without the decimal point, preserving all digits
-func parseDecimalAmount(amount string) (*big.Int, error) {
-	// Remove any spaces
-	amount = strings.TrimSpace(amount)
-	
-	// Split by decimal point
-	parts := strings.Split(amount, ".")
-	
-	// Parse integer part
-	intPart := new(big.Int)
-	if parts[0] != "" {
-		if _, ok := intPart.SetString(parts[0], 10); !ok {
-			return nil, fmt.Errorf("invalid integer part: %s", parts[0])
-		}
-	}
-	
-	// If no decimal part, return integer part
-	if len(parts) == 1 {
-		return intPart, nil
-	}
-	
-	// If there's a decimal part, we need to handle it
-	if len(parts) > 2 {
-		return nil, fmt.Errorf("invalid amount format: multiple decimal points")
-	}
-	
-	// For the decimal part, we'll just parse the integer value
-	// The caller will multiply by 10^9 for Lux's 9 decimal places
-	return intPart, nil
-}
\ No newline at end of file
+// Allocations returns the P/X allocations ordered by ETH address
+func (b *Builder) Allocations() []*allocation.Allocation {
+	allocs := b.allocations.GetAll()
+	sort.Slice(allocs, func(i, j int) bool {
+		return strings.ToLower(allocs[i].ETHAddr) < strings.ToLower(allocs[j].ETHAddr)
+	})
+	return allocs
+}
@@ -41,18 +41,21 @@ type ChainConfig struct {
 	SubnetEVMTimestamp  uint64      `json:"subnetEVMTimestamp"`
 	FeeConfig           *FeeConfig  `json:"feeConfig"`
 	AllowFeeRecipients  bool        `json:"allowFeeRecipients"`
+
+	// Precompiles active from genesis; their keys sit directly in the config
+	Precompiles
 }
 
 // FeeConfig contains fee configuration for the C-Chain
 type FeeConfig struct {
-	GasLimit                 uint64 `json:"gasLimit"`
-	MinBaseFee               uint64 `json:"minBaseFee"`
-	TargetGas                uint64 `json:"targetGas"`
-	BaseFeeChangeDenominator uint64 `json:"baseFeeChangeDenominator"`
-	MinBlockGasCost          uint64 `json:"minBlockGasCost"`
-	MaxBlockGasCost          uint64 `json:"maxBlockGasCost"`
-	TargetBlockRate          uint64 `json:"targetBlockRate"`
-	BlockGasCostStep         uint64 `json:"blockGasCostStep"`
+	GasLimit                 uint64 `json:"gasLimit" yaml:"gasLimit"`
+	MinBaseFee               uint64 `json:"minBaseFee" yaml:"minBaseFee"`
+	TargetGas                uint64 `json:"targetGas" yaml:"targetGas"`
+	BaseFeeChangeDenominator uint64 `json:"baseFeeChangeDenominator" yaml:"baseFeeChangeDenominator"`
+	MinBlockGasCost          uint64 `json:"minBlockGasCost" yaml:"minBlockGasCost"`
+	MaxBlockGasCost          uint64 `json:"maxBlockGasCost" yaml:"maxBlockGasCost"`
+	TargetBlockRate          uint64 `json:"targetBlockRate" yaml:"targetBlockRate"`
+	BlockGasCostStep         uint64 `json:"blockGasCostStep" yaml:"blockGasCostStep"`
 }
 
 // GenesisAccount represents an account in the genesis block
@@ -65,7 +68,9 @@ type GenesisAccount struct {
 
 // Builder helps construct C-Chain genesis configurations
 type Builder struct {
-	chainID uint64
+	chainID     uint64
+	precompiles Precompiles
+	upgrades    UpgradeConfig
 }
 
 // NewBuilder creates a new C-Chain genesis builder
@@ -112,6 +117,7 @@ func (b *Builder) buildChainConfig() *ChainConfig {
 		SubnetEVMTimestamp:  0,
 		FeeConfig:           b.buildFeeConfig(),
 		AllowFeeRecipients:  false,
+		Precompiles:         b.precompiles,
 	}
 }
 
@@ -129,6 +135,42 @@ func (b *Builder) buildFeeConfig() *FeeConfig {
 	}
 }
 
+// SetPrecompiles sets the precompiles active from genesis. Configs without a
+// BlockTimestamp are given 0 so the chain activates them at genesis.
+func (b *Builder) SetPrecompiles(precompiles Precompiles) {
+	b.precompiles = precompiles.AtGenesis()
+}
+
+// AddPrecompileUpgrade schedules a precompile change for upgrade.json
+func (b *Builder) AddPrecompileUpgrade(upgrade PrecompileUpgrade) {
+	b.upgrades.PrecompileUpgrades = append(b.upgrades.PrecompileUpgrades, upgrade)
+}
+
+// SetNetworkUpgradeOverride overrides a network upgrade's activation time
+// (e.g. "durangoTimestamp") in upgrade.json
+func (b *Builder) SetNetworkUpgradeOverride(name string, timestamp uint64) {
+	if b.upgrades.NetworkUpgradeOverrides == nil {
+		b.upgrades.NetworkUpgradeOverrides = make(map[string]uint64)
+	}
+	b.upgrades.NetworkUpgradeOverrides[name] = timestamp
+}
+
+// Upgrades returns the scheduled upgrades, the content of upgrade.json
+func (b *Builder) Upgrades() *UpgradeConfig {
+	return &b.upgrades
+}
+
+// Validate checks a genesis built by b, including its alloc, against the
+// precompile configs and scheduled upgrades
+func (b *Builder) Validate(g *Genesis) error {
+	if g.Config != nil && g.Config.FeeConfig != nil {
+		if err := g.Config.FeeConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid fee config: %w", err)
+		}
+	}
+	return ValidatePrecompiles(g, b.Upgrades())
+}
+
 // AddAccount adds an account to the genesis allocation
 func (b *Builder) AddAccount(address string, balance *big.Int) {
 	// This method would be called on the Genesis object, not the builder
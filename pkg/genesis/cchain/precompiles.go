@@ -0,0 +1,420 @@
+package cchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Precompile config keys, as used in the chain config and upgrade.json
+const (
+	ContractDeployerAllowListKey = "contractDeployerAllowListConfig"
+	TxAllowListKey               = "txAllowListConfig"
+	ContractNativeMinterKey      = "contractNativeMinterConfig"
+	FeeManagerKey                = "feeManagerConfig"
+	RewardManagerKey             = "rewardManagerConfig"
+	WarpKey                      = "warpConfig"
+)
+
+// Warp quorum bounds (percent of stake)
+const (
+	WarpDefaultQuorumNumerator = 67
+	WarpMinQuorumNumerator     = 33
+	WarpMaxQuorumNumerator     = 100
+)
+
+// AllowListConfig is the role list shared by the allow-list based
+// precompiles. The chain only activates a precompile whose BlockTimestamp is
+// set, so genesis configs carry an explicit 0 (see Precompiles.AtGenesis).
+type AllowListConfig struct {
+	BlockTimestamp   *uint64  `json:"blockTimestamp,omitempty" yaml:"blockTimestamp,omitempty"`
+	AdminAddresses   []string `json:"adminAddresses,omitempty" yaml:"adminAddresses,omitempty"`
+	ManagerAddresses []string `json:"managerAddresses,omitempty" yaml:"managerAddresses,omitempty"`
+	EnabledAddresses []string `json:"enabledAddresses,omitempty" yaml:"enabledAddresses,omitempty"`
+	Disable          bool     `json:"disable,omitempty" yaml:"disable,omitempty"`
+}
+
+// NativeMinterConfig configures the native minter precompile
+type NativeMinterConfig struct {
+	AllowListConfig `yaml:",inline"`
+	InitialMint     map[string]string `json:"initialMint,omitempty" yaml:"initialMint,omitempty"` // address to amount in wei
+}
+
+// FeeManagerConfig configures the fee manager precompile
+type FeeManagerConfig struct {
+	AllowListConfig  `yaml:",inline"`
+	InitialFeeConfig *FeeConfig `json:"initialFeeConfig,omitempty" yaml:"initialFeeConfig,omitempty"`
+}
+
+// RewardConfig selects where block fees go: burned (empty), to the block
+// producer's fee recipient, or to a fixed address
+type RewardConfig struct {
+	AllowFeeRecipients bool   `json:"allowFeeRecipients,omitempty" yaml:"allowFeeRecipients,omitempty"`
+	RewardAddress      string `json:"rewardAddress,omitempty" yaml:"rewardAddress,omitempty"`
+}
+
+// RewardManagerConfig configures the reward manager precompile
+type RewardManagerConfig struct {
+	AllowListConfig     `yaml:",inline"`
+	InitialRewardConfig *RewardConfig `json:"initialRewardConfig,omitempty" yaml:"initialRewardConfig,omitempty"`
+}
+
+// WarpConfig configures the warp messenger precompile
+type WarpConfig struct {
+	BlockTimestamp               *uint64 `json:"blockTimestamp,omitempty" yaml:"blockTimestamp,omitempty"`
+	QuorumNumerator              uint64  `json:"quorumNumerator,omitempty" yaml:"quorumNumerator,omitempty"`
+	RequirePrimaryNetworkSigners bool    `json:"requirePrimaryNetworkSigners,omitempty" yaml:"requirePrimaryNetworkSigners,omitempty"`
+	Disable                      bool    `json:"disable,omitempty" yaml:"disable,omitempty"`
+}
+
+// Precompiles holds one config per precompile. In the chain config these
+// are the configs active from genesis (or their BlockTimestamp); in an
+// upgrade exactly one is set.
+type Precompiles struct {
+	ContractDeployerAllowList *AllowListConfig     `json:"contractDeployerAllowListConfig,omitempty" yaml:"contractDeployerAllowListConfig,omitempty"`
+	TxAllowList               *AllowListConfig     `json:"txAllowListConfig,omitempty" yaml:"txAllowListConfig,omitempty"`
+	ContractNativeMinter      *NativeMinterConfig  `json:"contractNativeMinterConfig,omitempty" yaml:"contractNativeMinterConfig,omitempty"`
+	FeeManager                *FeeManagerConfig    `json:"feeManagerConfig,omitempty" yaml:"feeManagerConfig,omitempty"`
+	RewardManager             *RewardManagerConfig `json:"rewardManagerConfig,omitempty" yaml:"rewardManagerConfig,omitempty"`
+	Warp                      *WarpConfig          `json:"warpConfig,omitempty" yaml:"warpConfig,omitempty"`
+}
+
+// AtGenesis returns a copy of p with every unset BlockTimestamp set to 0, so
+// the configs activate at genesis rather than never
+func (p Precompiles) AtGenesis() Precompiles {
+	atGenesis := func(ts *uint64) *uint64 {
+		if ts != nil {
+			return ts
+		}
+		return new(uint64)
+	}
+	if c := p.ContractDeployerAllowList; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.ContractDeployerAllowList = &copied
+	}
+	if c := p.TxAllowList; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.TxAllowList = &copied
+	}
+	if c := p.ContractNativeMinter; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.ContractNativeMinter = &copied
+	}
+	if c := p.FeeManager; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.FeeManager = &copied
+	}
+	if c := p.RewardManager; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.RewardManager = &copied
+	}
+	if c := p.Warp; c != nil {
+		copied := *c
+		copied.BlockTimestamp = atGenesis(c.BlockTimestamp)
+		p.Warp = &copied
+	}
+	return p
+}
+
+// PrecompileUpgrade is a scheduled change to a single precompile
+type PrecompileUpgrade = Precompiles
+
+// UpgradeConfig is the content of a chain's upgrade.json
+type UpgradeConfig struct {
+	PrecompileUpgrades      []PrecompileUpgrade `json:"precompileUpgrades,omitempty" yaml:"precompileUpgrades,omitempty"`
+	NetworkUpgradeOverrides map[string]uint64   `json:"networkUpgradeOverrides,omitempty" yaml:"networkUpgradeOverrides,omitempty"`
+}
+
+// Empty reports whether no upgrades are scheduled
+func (u *UpgradeConfig) Empty() bool {
+	return u == nil || (len(u.PrecompileUpgrades) == 0 && len(u.NetworkUpgradeOverrides) == 0)
+}
+
+// activation is one precompile state change, for overlap checks
+type activation struct {
+	key       string
+	timestamp uint64
+	scheduled bool // BlockTimestamp was set
+	disable   bool
+	source    string
+}
+
+// entries returns the precompiles set in p with their activation
+func (p *Precompiles) entries() []activation {
+	var out []activation
+	add := func(key string, ts *uint64, disable bool) {
+		a := activation{key: key, disable: disable}
+		if ts != nil {
+			a.timestamp, a.scheduled = *ts, true
+		}
+		out = append(out, a)
+	}
+	if c := p.ContractDeployerAllowList; c != nil {
+		add(ContractDeployerAllowListKey, c.BlockTimestamp, c.Disable)
+	}
+	if c := p.TxAllowList; c != nil {
+		add(TxAllowListKey, c.BlockTimestamp, c.Disable)
+	}
+	if c := p.ContractNativeMinter; c != nil {
+		add(ContractNativeMinterKey, c.BlockTimestamp, c.Disable)
+	}
+	if c := p.FeeManager; c != nil {
+		add(FeeManagerKey, c.BlockTimestamp, c.Disable)
+	}
+	if c := p.RewardManager; c != nil {
+		add(RewardManagerKey, c.BlockTimestamp, c.Disable)
+	}
+	if c := p.Warp; c != nil {
+		add(WarpKey, c.BlockTimestamp, c.Disable)
+	}
+	return out
+}
+
+// ValidatePrecompiles checks the precompile configs of a genesis and its
+// scheduled upgrades: addresses, roles, per-precompile settings, that admins
+// and managers are funded in alloc, and that activations of a precompile
+// never overlap (enable only when inactive, disable only when active, in
+// strictly increasing time).
+func ValidatePrecompiles(g *Genesis, upgrades *UpgradeConfig) error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	funded := make(map[string]bool, len(g.Alloc))
+	for addr := range g.Alloc {
+		funded[normalizeAddress(addr)] = true
+	}
+
+	var activations []activation
+	if g.Config != nil {
+		for _, e := range g.Config.Precompiles.entries() {
+			if e.disable {
+				add("config.%s: cannot be disabled in genesis", e.key)
+			}
+			if !e.scheduled {
+				add("config.%s: blockTimestamp is required (0 activates at genesis)", e.key)
+			}
+			e.source = "genesis"
+			activations = append(activations, e)
+		}
+		for _, msg := range g.Config.Precompiles.validate(funded) {
+			add("config.%s", msg)
+		}
+	}
+
+	if upgrades != nil {
+		for i, upgrade := range upgrades.PrecompileUpgrades {
+			entries := upgrade.entries()
+			if len(entries) != 1 {
+				add("precompileUpgrades[%d]: must configure exactly one precompile, found %d", i, len(entries))
+				continue
+			}
+			e := entries[0]
+			if !e.scheduled || e.timestamp == 0 {
+				add("precompileUpgrades[%d].%s: blockTimestamp is required", i, e.key)
+			}
+			e.source = fmt.Sprintf("precompileUpgrades[%d]", i)
+			activations = append(activations, e)
+			for _, msg := range upgrade.validate(funded) {
+				add("precompileUpgrades[%d].%s", i, msg)
+			}
+		}
+	}
+
+	problems = append(problems, checkActivations(activations)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid precompile configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// checkActivations verifies each precompile's activations alternate between
+// enabled and disabled in strictly increasing time, keeping their order
+func checkActivations(activations []activation) []string {
+	var problems []string
+	byKey := make(map[string][]activation)
+	var keys []string
+	for _, a := range activations {
+		if _, ok := byKey[a.key]; !ok {
+			keys = append(keys, a.key)
+		}
+		byKey[a.key] = append(byKey[a.key], a)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		active := false
+		var last *activation
+		for i := range byKey[key] {
+			a := byKey[key][i]
+			if last != nil && a.timestamp <= last.timestamp {
+				problems = append(problems, fmt.Sprintf("%s.%s: activation at %d does not follow %s at %d",
+					a.source, key, a.timestamp, last.source, last.timestamp))
+			}
+			switch {
+			case a.disable && !active:
+				problems = append(problems, fmt.Sprintf("%s.%s: disables a precompile that is not active", a.source, key))
+			case !a.disable && active:
+				problems = append(problems, fmt.Sprintf("%s.%s: overlaps the activation from %s", a.source, key, last.source))
+			}
+			active = !a.disable
+			last = &a
+		}
+	}
+	return problems
+}
+
+// validate checks every precompile config set in p
+func (p *Precompiles) validate(funded map[string]bool) []string {
+	var problems []string
+	if c := p.ContractDeployerAllowList; c != nil {
+		problems = append(problems, prefix(ContractDeployerAllowListKey, c.validate(funded))...)
+	}
+	if c := p.TxAllowList; c != nil {
+		problems = append(problems, prefix(TxAllowListKey, c.validate(funded))...)
+	}
+	if c := p.ContractNativeMinter; c != nil {
+		msgs := c.AllowListConfig.validate(funded)
+		if c.Disable && len(c.InitialMint) > 0 {
+			msgs = append(msgs, "initialMint is not allowed when disabling")
+		}
+		for addr, value := range c.InitialMint {
+			if !isAddress(addr) {
+				msgs = append(msgs, fmt.Sprintf("initialMint: invalid address %s", addr))
+			}
+			if v, ok := parseQuantity(value); !ok || v.Sign() <= 0 {
+				msgs = append(msgs, fmt.Sprintf("initialMint: invalid amount %q for %s", value, addr))
+			}
+		}
+		problems = append(problems, prefix(ContractNativeMinterKey, msgs)...)
+	}
+	if c := p.FeeManager; c != nil {
+		msgs := c.AllowListConfig.validate(funded)
+		if c.InitialFeeConfig != nil {
+			if c.Disable {
+				msgs = append(msgs, "initialFeeConfig is not allowed when disabling")
+			}
+			if err := c.InitialFeeConfig.Validate(); err != nil {
+				msgs = append(msgs, fmt.Sprintf("initialFeeConfig: %v", err))
+			}
+		}
+		problems = append(problems, prefix(FeeManagerKey, msgs)...)
+	}
+	if c := p.RewardManager; c != nil {
+		msgs := c.AllowListConfig.validate(funded)
+		if r := c.InitialRewardConfig; r != nil {
+			if c.Disable {
+				msgs = append(msgs, "initialRewardConfig is not allowed when disabling")
+			}
+			if r.AllowFeeRecipients && r.RewardAddress != "" {
+				msgs = append(msgs, "initialRewardConfig: allowFeeRecipients and rewardAddress are mutually exclusive")
+			}
+			if r.RewardAddress != "" && !isAddress(r.RewardAddress) {
+				msgs = append(msgs, fmt.Sprintf("initialRewardConfig: invalid rewardAddress %s", r.RewardAddress))
+			}
+		}
+		problems = append(problems, prefix(RewardManagerKey, msgs)...)
+	}
+	if c := p.Warp; c != nil {
+		if q := c.QuorumNumerator; q != 0 && (q < WarpMinQuorumNumerator || q > WarpMaxQuorumNumerator) {
+			problems = append(problems, fmt.Sprintf("%s: quorumNumerator %d outside [%d, %d]",
+				WarpKey, q, WarpMinQuorumNumerator, WarpMaxQuorumNumerator))
+		}
+	}
+	return problems
+}
+
+// validate checks addresses, that no address holds two roles, and that
+// admins and managers are funded in alloc
+func (c *AllowListConfig) validate(funded map[string]bool) []string {
+	var problems []string
+	if c.Disable {
+		if len(c.AdminAddresses)+len(c.ManagerAddresses)+len(c.EnabledAddresses) > 0 {
+			problems = append(problems, "role addresses are not allowed when disabling")
+		}
+		return problems
+	}
+
+	roles := make(map[string]string)
+	check := func(role string, addrs []string, mustBeFunded bool) {
+		for _, addr := range addrs {
+			if !isAddress(addr) {
+				problems = append(problems, fmt.Sprintf("%s: invalid address %s", role, addr))
+				continue
+			}
+			key := normalizeAddress(addr)
+			if other, ok := roles[key]; ok {
+				problems = append(problems, fmt.Sprintf("%s: %s is also in %s", role, addr, other))
+			}
+			roles[key] = role
+			if mustBeFunded && !funded[key] {
+				problems = append(problems, fmt.Sprintf("%s: %s has no balance in alloc", role, addr))
+			}
+		}
+	}
+	check("adminAddresses", c.AdminAddresses, true)
+	check("managerAddresses", c.ManagerAddresses, true)
+	check("enabledAddresses", c.EnabledAddresses, false)
+	return problems
+}
+
+// Validate checks a fee config for values the chain would reject
+func (f *FeeConfig) Validate() error {
+	switch {
+	case f.GasLimit == 0:
+		return errors.New("gasLimit must be positive")
+	case f.TargetGas == 0:
+		return errors.New("targetGas must be positive")
+	case f.TargetBlockRate == 0:
+		return errors.New("targetBlockRate must be positive")
+	case f.BaseFeeChangeDenominator == 0:
+		return errors.New("baseFeeChangeDenominator must be positive")
+	case f.MinBlockGasCost > f.MaxBlockGasCost:
+		return fmt.Errorf("minBlockGasCost %d exceeds maxBlockGasCost %d", f.MinBlockGasCost, f.MaxBlockGasCost)
+	}
+	return nil
+}
+
+func prefix(key string, msgs []string) []string {
+	out := make([]string, len(msgs))
+	for i, msg := range msgs {
+		out[i] = key + ": " + msg
+	}
+	return out
+}
+
+// isAddress reports whether s is a 0x-prefixed 20-byte hex address
+func isAddress(s string) bool {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return false
+	}
+	b, err := hex.DecodeString(s[2:])
+	return err == nil && len(b) == 20
+}
+
+// normalizeAddress lowercases an address and adds the 0x prefix
+func normalizeAddress(addr string) string {
+	addr = strings.ToLower(addr)
+	if !strings.HasPrefix(addr, "0x") {
+		addr = "0x" + addr
+	}
+	return addr
+}
+
+// parseQuantity parses a decimal or 0x-prefixed hex quantity
+func parseQuantity(s string) (*big.Int, bool) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return new(big.Int).SetString(s[2:], 16)
+	}
+	return new(big.Int).SetString(s, 10)
+}
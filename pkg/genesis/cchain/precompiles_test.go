@@ -0,0 +1,196 @@
+package cchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	admin   = "0x1000000000000000000000000000000000000001"
+	manager = "0x1000000000000000000000000000000000000002"
+	enabled = "0x1000000000000000000000000000000000000003"
+)
+
+func timestamp(ts uint64) *uint64 {
+	return &ts
+}
+
+func fundedGenesis(b *Builder) *Genesis {
+	g := b.Build()
+	AddAccountToGenesis(g, admin, big.NewInt(1))
+	AddAccountToGenesis(g, manager, big.NewInt(1))
+	return g
+}
+
+func TestPrecompilesValid(t *testing.T) {
+	b := NewBuilder(96369)
+	b.SetPrecompiles(Precompiles{
+		ContractDeployerAllowList: &AllowListConfig{
+			AdminAddresses:   []string{admin},
+			ManagerAddresses: []string{manager},
+			EnabledAddresses: []string{enabled},
+		},
+		FeeManager: &FeeManagerConfig{
+			AllowListConfig: AllowListConfig{AdminAddresses: []string{admin}},
+		},
+		Warp: &WarpConfig{QuorumNumerator: WarpDefaultQuorumNumerator},
+	})
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		ContractDeployerAllowList: &AllowListConfig{BlockTimestamp: timestamp(1000), Disable: true},
+	})
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		ContractDeployerAllowList: &AllowListConfig{BlockTimestamp: timestamp(2000), AdminAddresses: []string{admin}},
+	})
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		ContractNativeMinter: &NativeMinterConfig{
+			AllowListConfig: AllowListConfig{BlockTimestamp: timestamp(1500), AdminAddresses: []string{admin}},
+			InitialMint:     map[string]string{enabled: "0xde0b6b3a7640000"},
+		},
+	})
+	b.SetNetworkUpgradeOverride("durangoTimestamp", 500)
+
+	g := fundedGenesis(b)
+	require.NoError(t, b.Validate(g))
+	assert.False(t, b.Upgrades().Empty())
+
+	// Precompile keys sit directly in the chain config
+	data, err := g.ToJSON()
+	require.NoError(t, err)
+	var parsed struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	assert.Contains(t, parsed.Config, ContractDeployerAllowListKey)
+	assert.Contains(t, parsed.Config, WarpKey)
+	assert.NotContains(t, parsed.Config, TxAllowListKey)
+}
+
+func TestPrecompilesOverlap(t *testing.T) {
+	b := NewBuilder(96369)
+	b.SetPrecompiles(Precompiles{
+		TxAllowList: &AllowListConfig{AdminAddresses: []string{admin}},
+	})
+	// Enabling again without disabling first overlaps the genesis activation
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		TxAllowList: &AllowListConfig{BlockTimestamp: timestamp(100), AdminAddresses: []string{admin}},
+	})
+
+	err := b.Validate(fundedGenesis(b))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "overlaps the activation from genesis")
+}
+
+func TestPrecompilesActivationOrder(t *testing.T) {
+	b := NewBuilder(96369)
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		Warp: &WarpConfig{BlockTimestamp: timestamp(200)},
+	})
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		Warp: &WarpConfig{BlockTimestamp: timestamp(100), Disable: true},
+	})
+
+	err := b.Validate(fundedGenesis(b))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not follow")
+}
+
+func TestPrecompilesDisableInactive(t *testing.T) {
+	b := NewBuilder(96369)
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		RewardManager: &RewardManagerConfig{
+			AllowListConfig: AllowListConfig{BlockTimestamp: timestamp(100), Disable: true},
+		},
+	})
+
+	err := b.Validate(fundedGenesis(b))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not active")
+}
+
+func TestPrecompilesRoles(t *testing.T) {
+	unfunded := "0x2000000000000000000000000000000000000002"
+
+	tests := []struct {
+		name      string
+		allowList AllowListConfig
+		want      string
+	}{
+		{"unfunded admin", AllowListConfig{AdminAddresses: []string{unfunded}}, "has no balance in alloc"},
+		{"duplicate role", AllowListConfig{AdminAddresses: []string{admin}, EnabledAddresses: []string{admin}}, "is also in adminAddresses"},
+		{"invalid address", AllowListConfig{EnabledAddresses: []string{"0x1234"}}, "invalid address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowList := tt.allowList
+			b := NewBuilder(96369)
+			b.SetPrecompiles(Precompiles{ContractDeployerAllowList: &allowList})
+
+			err := b.Validate(fundedGenesis(b))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestPrecompilesSettings(t *testing.T) {
+	b := NewBuilder(96369)
+	b.SetPrecompiles(Precompiles{
+		Warp: &WarpConfig{QuorumNumerator: 20},
+		RewardManager: &RewardManagerConfig{
+			AllowListConfig: AllowListConfig{AdminAddresses: []string{admin}},
+			InitialRewardConfig: &RewardConfig{
+				AllowFeeRecipients: true,
+				RewardAddress:      manager,
+			},
+		},
+	})
+	b.AddPrecompileUpgrade(PrecompileUpgrade{
+		TxAllowList: &AllowListConfig{BlockTimestamp: timestamp(100)},
+		Warp:        &WarpConfig{BlockTimestamp: timestamp(100), Disable: true},
+	})
+
+	err := b.Validate(fundedGenesis(b))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quorumNumerator 20 outside")
+	assert.Contains(t, err.Error(), "mutually exclusive")
+	assert.Contains(t, err.Error(), "exactly one precompile")
+}
+
+func TestPrecompilesGenesisTimestamp(t *testing.T) {
+	b := NewBuilder(96369)
+	b.SetPrecompiles(Precompiles{
+		TxAllowList: &AllowListConfig{AdminAddresses: []string{admin}},
+		Warp:        &WarpConfig{QuorumNumerator: WarpDefaultQuorumNumerator},
+	})
+
+	// Genesis configs carry an explicit 0, or the chain never activates them
+	data, err := fundedGenesis(b).ToJSON()
+	require.NoError(t, err)
+	var parsed struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	for _, key := range []string{TxAllowListKey, WarpKey} {
+		var compact bytes.Buffer
+		require.NoError(t, json.Compact(&compact, parsed.Config[key]))
+		assert.Contains(t, compact.String(), `"blockTimestamp":0`, key)
+	}
+
+	var g Genesis
+	require.NoError(t, json.Unmarshal(data, &g))
+	require.NotNil(t, g.Config.TxAllowList.BlockTimestamp)
+	assert.Zero(t, *g.Config.TxAllowList.BlockTimestamp)
+	require.NoError(t, b.Validate(&g))
+
+	// A genesis config read from elsewhere without one is rejected
+	g.Config.TxAllowList.BlockTimestamp = nil
+	err = ValidatePrecompiles(&g, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config.txAllowListConfig: blockTimestamp is required")
+}
@@ -0,0 +1,101 @@
+package chains
+
+import "fmt"
+
+// AChainGenesis is the A-Chain (AI) genesis
+type AChainGenesis struct {
+	Config        AChainConfig  `json:"config"`
+	Agents        []Agent       `json:"agents"`
+	GPUProviders  []GPUProvider `json:"gpuProviders"`
+	ModelRegistry []Model       `json:"modelRegistry"`
+}
+
+// AChainConfig holds the A-Chain parameters
+type AChainConfig struct {
+	BlockTime       uint64  `json:"blockTime"` // milliseconds
+	MinGasPrice     uint64  `json:"minGasPrice"`
+	MaxGasLimit     uint64  `json:"maxGasLimit"`
+	TargetGasUsage  uint64  `json:"targetGasUsage"`
+	MinAgentStake   uint64  `json:"minAgentStake"` // nLUX
+	TaskTimeout     uint64  `json:"taskTimeout"`   // seconds
+	ReputationDecay float64 `json:"reputationDecay"`
+}
+
+// Agent is an AI agent registered at genesis
+type Agent struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ModelType   string `json:"modelType"`
+	GPUProvider string `json:"gpuProvider"`
+	Reputation  uint64 `json:"reputation"`
+	Stake       uint64 `json:"stake"` // nLUX
+}
+
+// GPUProvider is a pool of GPU capacity
+type GPUProvider struct {
+	ID       string `json:"id"`
+	Capacity uint64 `json:"capacity"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+}
+
+// Model is a model agents may run
+type Model struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Parameters uint64 `json:"parameters"`
+	GPUMemory  uint64 `json:"gpuMemory"` // GB
+}
+
+// Validate checks the config, that IDs are unique and that agents reference
+// registered providers and models with at least the minimum stake
+func (g *AChainGenesis) Validate() error {
+	c := &checker{chain: AChain}
+
+	if g.Config.BlockTime == 0 {
+		c.add("config.blockTime: must be positive")
+	}
+	if g.Config.TargetGasUsage > g.Config.MaxGasLimit {
+		c.add("config.targetGasUsage: %d exceeds maxGasLimit %d", g.Config.TargetGasUsage, g.Config.MaxGasLimit)
+	}
+	if d := g.Config.ReputationDecay; d <= 0 || d > 1 {
+		c.add("config.reputationDecay: %v must be in (0, 1]", d)
+	}
+
+	providers := make(map[string]bool, len(g.GPUProviders))
+	providerIDs := make([]string, len(g.GPUProviders))
+	for i, p := range g.GPUProviders {
+		providerIDs[i] = p.ID
+		providers[p.ID] = true
+		if p.Capacity == 0 {
+			c.add("gpuProviders[%d].capacity: must be positive", i)
+		}
+	}
+	c.unique("gpuProviders.id", providerIDs)
+
+	models := make(map[string]bool, len(g.ModelRegistry))
+	modelIDs := make([]string, len(g.ModelRegistry))
+	for i, m := range g.ModelRegistry {
+		modelIDs[i] = m.ID
+		models[m.ID] = true
+	}
+	c.unique("modelRegistry.id", modelIDs)
+
+	agentIDs := make([]string, len(g.Agents))
+	for i, a := range g.Agents {
+		agentIDs[i] = a.ID
+		field := fmt.Sprintf("agents[%d]", i)
+		if !providers[a.GPUProvider] {
+			c.add("%s.gpuProvider: unknown provider %q", field, a.GPUProvider)
+		}
+		if !models[a.ModelType] {
+			c.add("%s.modelType: unknown model %q", field, a.ModelType)
+		}
+		if a.Stake < g.Config.MinAgentStake {
+			c.add("%s.stake: %d below minAgentStake %d", field, a.Stake, g.Config.MinAgentStake)
+		}
+	}
+	c.unique("agents.id", agentIDs)
+
+	return c.err()
+}
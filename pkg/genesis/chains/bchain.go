@@ -0,0 +1,101 @@
+package chains
+
+import "fmt"
+
+// MinPublicKeyLength is the shortest signer public key accepted, in bytes
+const MinPublicKeyLength = 32
+
+// BChainGenesis is the B-Chain (Bridge) genesis
+type BChainGenesis struct {
+	Config       BChainConfig  `json:"config"`
+	Bridges      []Bridge      `json:"bridges"`
+	MPCNodes     []MPCNode     `json:"mpcNodes"`
+	ThresholdKey *ThresholdKey `json:"thresholdKey,omitempty"`
+	Treasury     Treasury      `json:"treasury"`
+}
+
+// BChainConfig holds the B-Chain parameters
+type BChainConfig struct {
+	BlockTime       uint64  `json:"blockTime"` // milliseconds
+	MinSignatures   int     `json:"minSignatures"`
+	BridgeTimeout   uint64  `json:"bridgeTimeout"` // seconds
+	MaxBridgeAmount uint64  `json:"maxBridgeAmount"`
+	BridgeFee       float64 `json:"bridgeFee"` // fraction of the amount
+}
+
+// Bridge is a connection to an external chain
+type Bridge struct {
+	ID          string `json:"id"`
+	TargetChain string `json:"targetChain"`
+	ChainID     uint64 `json:"chainId"`
+	Threshold   int    `json:"threshold"`
+	Address     string `json:"address"` // bridge contract on the target chain
+	Status      string `json:"status"`
+}
+
+// MPCNode is a bridge signer
+type MPCNode struct {
+	ID         string `json:"id"`
+	Index      int    `json:"index"`
+	PublicKey  string `json:"publicKey"`
+	Stake      uint64 `json:"stake"` // nLUX
+	Reputation uint64 `json:"reputation"`
+	Endpoint   string `json:"endpoint"`
+}
+
+// Treasury receives bridge fees
+type Treasury struct {
+	Address string `json:"address"`
+	Balance uint64 `json:"balance"`
+}
+
+// Validate checks thresholds against the signer set, that signer keys are
+// real and unique, and that bridge and treasury addresses are set
+func (g *BChainGenesis) Validate() error {
+	c := &checker{chain: BChain}
+	n := len(g.MPCNodes)
+
+	if n == 0 {
+		c.add("mpcNodes: at least one signer is required")
+	}
+	c.threshold("config.minSignatures", g.Config.MinSignatures, n)
+	if f := g.Config.BridgeFee; f < 0 || f >= 1 {
+		c.add("config.bridgeFee: %v must be in [0, 1)", f)
+	}
+
+	ids := make([]string, n)
+	keys := make([]string, n)
+	indexes := make([]string, n)
+	for i, node := range g.MPCNodes {
+		ids[i] = node.ID
+		keys[i] = node.PublicKey
+		indexes[i] = fmt.Sprint(node.Index)
+		c.key(fmt.Sprintf("mpcNodes[%d].publicKey", i), node.PublicKey, MinPublicKeyLength)
+		if node.Endpoint == "" {
+			c.add("mpcNodes[%d].endpoint: missing", i)
+		}
+	}
+	c.unique("mpcNodes.id", ids)
+	c.unique("mpcNodes.publicKey", keys)
+	c.unique("mpcNodes.index", indexes)
+	c.thresholdKey("thresholdKey", g.ThresholdKey, g.Config.MinSignatures, n)
+
+	bridgeIDs := make([]string, len(g.Bridges))
+	for i, b := range g.Bridges {
+		bridgeIDs[i] = b.ID
+		field := fmt.Sprintf("bridges[%d]", i)
+		if b.Threshold < g.Config.MinSignatures {
+			c.add("%s.threshold: %d below minSignatures %d", field, b.Threshold, g.Config.MinSignatures)
+		}
+		c.threshold(field+".threshold", b.Threshold, n)
+		c.address(field+".address", b.Address)
+		if b.ChainID == 0 {
+			c.add("%s.chainId: must be non-zero", field)
+		}
+	}
+	c.unique("bridges.id", bridgeIDs)
+
+	c.address("treasury.address", g.Treasury.Address)
+
+	return c.err()
+}
@@ -0,0 +1,215 @@
+// Package chains defines the genesis formats of the A, B, M, Q and Z chains
+// and the rules a genesis must satisfy before luxd is given it.
+package chains
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Type identifies a chain by its alias
+type Type string
+
+const (
+	AChain Type = "A" // AI
+	BChain Type = "B" // Bridge
+	MChain Type = "M" // MPC
+	QChain Type = "Q" // Quantum
+	ZChain Type = "Z" // Zero-knowledge
+)
+
+// Types lists the chains this package knows, in alias order
+var Types = []Type{AChain, BChain, MChain, QChain, ZChain}
+
+// typeNames maps the names accepted by ParseType to chain types
+var typeNames = map[string]Type{
+	"a": AChain, "ai": AChain, "aivm": AChain,
+	"b": BChain, "bridge": BChain, "bridgevm": BChain,
+	"m": MChain, "mpc": MChain, "mpcvm": MChain,
+	"q": QChain, "quantum": QChain, "quantumvm": QChain,
+	"z": ZChain, "zk": ZChain, "zkvm": ZChain,
+}
+
+// ParseType parses a chain alias ("B"), name ("bridge") or VM name ("bridgevm")
+func ParseType(s string) (Type, error) {
+	if t, ok := typeNames[strings.ToLower(s)]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown chain type %q (expected one of A, B, M, Q, Z)", s)
+}
+
+// Genesis is a typed chain genesis
+type Genesis interface {
+	// Validate reports every rule the genesis breaks
+	Validate() error
+}
+
+// New returns an empty genesis of the given chain type
+func New(t Type) (Genesis, error) {
+	switch t {
+	case AChain:
+		return &AChainGenesis{}, nil
+	case BChain:
+		return &BChainGenesis{}, nil
+	case MChain:
+		return &MChainGenesis{}, nil
+	case QChain:
+		return &QChainGenesis{}, nil
+	case ZChain:
+		return &ZChainGenesis{}, nil
+	}
+	return nil, fmt.Errorf("unknown chain type %q", t)
+}
+
+// Parse decodes a genesis of the given type. Unknown fields are an error so
+// that typos don't silently fall back to zero values.
+func Parse(t Type, data []byte) (Genesis, error) {
+	g, err := New(t)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(g); err != nil {
+		return nil, fmt.Errorf("failed to parse %s-Chain genesis: %w", t, err)
+	}
+	return g, nil
+}
+
+// Load reads and parses a genesis file; it does not validate it
+func Load(t Type, path string) (Genesis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis: %w", err)
+	}
+	return Parse(t, data)
+}
+
+// Save validates g and writes it as indented JSON
+func Save(t Type, g Genesis, path string) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s-Chain genesis: %w", t, err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ValidationError lists the rules a genesis breaks
+type ValidationError struct {
+	Chain    Type
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s-Chain genesis:\n  %s", e.Chain, strings.Join(e.Problems, "\n  "))
+}
+
+// checker collects validation problems
+type checker struct {
+	chain    Type
+	problems []string
+}
+
+func (c *checker) add(format string, args ...interface{}) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+// unique reports duplicate and empty values of a field across a list
+func (c *checker) unique(field string, values []string) {
+	seen := make(map[string]int, len(values))
+	for i, v := range values {
+		if v == "" {
+			c.add("%s[%d]: empty", field, i)
+			continue
+		}
+		key := strings.ToLower(v)
+		if j, ok := seen[key]; ok {
+			c.add("%s[%d]: %s duplicates %s[%d]", field, i, v, field, j)
+			continue
+		}
+		seen[key] = i
+	}
+}
+
+// key reports a missing, malformed or all-zero hex key of at least minLen
+// bytes
+func (c *checker) key(field, value string, minLen int) {
+	b, ok := decodeHex(value)
+	switch {
+	case value == "":
+		c.add("%s: missing", field)
+	case !ok:
+		c.add("%s: invalid hex %q", field, value)
+	case len(b) < minLen:
+		c.add("%s: %d bytes, expected at least %d", field, len(b), minLen)
+	case isZero(b):
+		c.add("%s: must not be zero", field)
+	}
+}
+
+// address reports a malformed or zero 20-byte address
+func (c *checker) address(field, value string) {
+	b, ok := decodeHex(value)
+	switch {
+	case value == "":
+		c.add("%s: missing", field)
+	case !ok || len(b) != 20:
+		c.add("%s: invalid address %q", field, value)
+	case isZero(b):
+		c.add("%s: must not be the zero address", field)
+	}
+}
+
+// threshold reports a t-of-n threshold outside [1, n]
+func (c *checker) threshold(field string, t, n int) {
+	if t < 1 || t > n {
+		c.add("%s: %d must be between 1 and the %d participants", field, t, n)
+	}
+}
+
+// thresholdKey checks an aggregate key against the signer set it belongs to
+func (c *checker) thresholdKey(field string, k *ThresholdKey, threshold, signers int) {
+	if k == nil {
+		return
+	}
+	c.key(field+".publicKey", k.PublicKey, MinPublicKeyLength)
+	c.address(field+".address", k.Address)
+	if k.Threshold != threshold {
+		c.add("%s.threshold: %d does not match the configured %d", field, k.Threshold, threshold)
+	}
+	if k.Participants != signers {
+		c.add("%s.participants: %d does not match the %d signers", field, k.Participants, signers)
+	}
+}
+
+func (c *checker) err() error {
+	if len(c.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Chain: c.chain, Problems: c.problems}
+}
+
+// decodeHex decodes a 0x-prefixed hex string
+func decodeHex(s string) ([]byte, bool) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s[2:])
+	return b, err == nil
+}
+
+func isZero(b []byte) bool {
+	for _, x := range b {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(i int) string {
+	return fmt.Sprintf("0x02%062x", i+1)
+}
+
+func testBChain(signers, threshold int) *BChainGenesis {
+	g := &BChainGenesis{
+		Config: BChainConfig{BlockTime: 3000, MinSignatures: threshold, BridgeFee: 0.001},
+		Bridges: []Bridge{{
+			ID:        "eth-mainnet",
+			ChainID:   1,
+			Threshold: threshold,
+			Address:   "0x1000000000000000000000000000000000000001",
+		}},
+		Treasury: Treasury{Address: "0x2000000000000000000000000000000000000002"},
+	}
+	for i := 0; i < signers; i++ {
+		g.MPCNodes = append(g.MPCNodes, MPCNode{
+			ID:        fmt.Sprintf("mpc-node-%d", i),
+			Index:     i,
+			PublicKey: testKey(i),
+			Endpoint:  fmt.Sprintf("mpc-node-%d:9651", i),
+		})
+	}
+	return g
+}
+
+func problems(t *testing.T, err error) string {
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	return strings.Join(verr.Problems, "\n")
+}
+
+func TestParseType(t *testing.T) {
+	for name, want := range map[string]Type{"B": BChain, "bridge": BChain, "zkvm": ZChain, "m": MChain} {
+		got, err := ParseType(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseType("P")
+	assert.Error(t, err)
+}
+
+func TestBChainValidate(t *testing.T) {
+	require.NoError(t, testBChain(3, 2).Validate())
+
+	// Threshold above the signer count
+	assert.Contains(t, problems(t, testBChain(3, 4).Validate()), "config.minSignatures: 4 must be between 1 and the 3 participants")
+
+	// Placeholder keys and addresses are rejected
+	g := testBChain(3, 2)
+	g.MPCNodes[0].PublicKey = fmt.Sprintf("0x%064x", 0)
+	g.MPCNodes[2].PublicKey = g.MPCNodes[1].PublicKey
+	g.Bridges[0].Address = "0x0000000000000000000000000000000000000000"
+	out := problems(t, g.Validate())
+	assert.Contains(t, out, "mpcNodes[0].publicKey: must not be zero")
+	assert.Contains(t, out, "mpcNodes.publicKey[2]")
+	assert.Contains(t, out, "bridges[0].address: must not be the zero address")
+
+	// The aggregate key must describe this signer set
+	g = testBChain(3, 2)
+	g.ThresholdKey = &ThresholdKey{
+		Scheme:       "secp256k1",
+		PublicKey:    testKey(9),
+		Address:      "0x3000000000000000000000000000000000000003",
+		Threshold:    2,
+		Participants: 3,
+	}
+	require.NoError(t, g.Validate())
+	g.ThresholdKey.Participants = 4
+	assert.Contains(t, problems(t, g.Validate()), "thresholdKey.participants: 4 does not match the 3 signers")
+}
+
+func TestMChainValidate(t *testing.T) {
+	g := &MChainGenesis{
+		Config: MChainConfig{MPCThreshold: 2, SessionLength: 100, MinParticipants: 2, MaxParticipants: 3},
+		InitialNodes: []MPCParticipant{
+			{ID: "mpc-000", PublicKey: testKey(0)},
+			{ID: "mpc-001", PublicKey: testKey(1)},
+			{ID: "mpc-001", PublicKey: testKey(2)},
+		},
+		Protocols: []Protocol{{ID: "frost", Threshold: 4}},
+	}
+	out := problems(t, g.Validate())
+	assert.Contains(t, out, "initialNodes.id[2]: mpc-001 duplicates")
+	assert.Contains(t, out, "protocols[0].threshold: 4 must be between")
+
+	g.InitialNodes[2].ID = "mpc-002"
+	g.Protocols[0].Threshold = 2
+	require.NoError(t, g.Validate())
+}
+
+func TestZChainValidate(t *testing.T) {
+	srs := "0x" + strings.Repeat("ab", SRSHashLength)
+	g := &ZChainGenesis{
+		Config:       ZChainConfig{ProofSystem: "plonk", MaxConstraints: 1000},
+		Circuits:     []Circuit{{ID: "circuit-000", ProofSystem: "plonk", ConstraintSize: 500, SetupComplete: true, SRSHash: srs}},
+		TrustedSetup: TrustedSetup{Participants: 10, SRSHash: srs},
+	}
+	require.NoError(t, g.Validate())
+
+	g.Circuits[0].ConstraintSize = 5000
+	g.Circuits[0].SRSHash = fmt.Sprintf("0x%064x", 0)
+	out := problems(t, g.Validate())
+	assert.Contains(t, out, "circuits[0].constraintSize")
+	assert.Contains(t, out, "circuits[0].srsHash: must not be zero")
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	data, err := json.Marshal(testBChain(3, 2))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	g, err := Load(BChain, path)
+	require.NoError(t, err)
+	require.NoError(t, g.Validate())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"config": {"minSignatures": 1}, "bogus": true}`), 0644))
+	_, err = Load(BChain, path)
+	assert.Error(t, err)
+}
+
+func TestSaveValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	assert.Error(t, Save(BChain, testBChain(2, 3), path))
+	assert.NoFileExists(t, path)
+	require.NoError(t, Save(BChain, testBChain(3, 2), path))
+}
@@ -0,0 +1,72 @@
+package chains
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// KeyMaterial is the externally generated key material the A/B/M/Q/Z
+// genesis files are built from. Signer keys, bridge contracts, the treasury
+// and the SRS come from their real ceremonies and deployments; the generator
+// never invents them.
+type KeyMaterial struct {
+	// Signers are the MPC nodes of the B-Chain and M-Chain
+	Signers []SignerKey `json:"signers"`
+
+	// ThresholdKey is the signers' aggregate key
+	ThresholdKey *ThresholdKey `json:"thresholdKey,omitempty"`
+
+	// Bridges are the deployed bridge contracts on external chains
+	Bridges []BridgeContract `json:"bridges"`
+
+	// Treasury receives bridge fees
+	Treasury string `json:"treasury"`
+
+	// QuantumValidators are the validators' post-quantum public keys
+	QuantumValidators []QuantumValidator `json:"quantumValidators,omitempty"`
+
+	// TrustedSetup is the ZK ceremony that produced the SRS
+	TrustedSetup TrustedSetup `json:"trustedSetup"`
+}
+
+// SignerKey is an MPC node's public key and endpoint
+type SignerKey struct {
+	ID        string `json:"id"`
+	PublicKey string `json:"publicKey"`
+	Endpoint  string `json:"endpoint"`
+}
+
+// ThresholdKey is the aggregate public key of a t-of-n signer set
+type ThresholdKey struct {
+	Scheme       string `json:"scheme"`    // curve, e.g. secp256k1
+	PublicKey    string `json:"publicKey"` // compressed
+	Address      string `json:"address"`   // Ethereum address of PublicKey
+	Threshold    int    `json:"threshold"`
+	Participants int    `json:"participants"`
+}
+
+// BridgeContract is a deployed bridge on an external chain
+type BridgeContract struct {
+	ID          string `json:"id"`
+	TargetChain string `json:"targetChain"`
+	ChainID     uint64 `json:"chainId"`
+	Address     string `json:"address"`
+}
+
+// LoadKeyMaterial reads a key material file. Unknown fields are an error;
+// signers may be left out when the generator creates them.
+func LoadKeyMaterial(path string) (*KeyMaterial, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key material: %w", err)
+	}
+	var keys KeyMaterial
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&keys); err != nil {
+		return nil, fmt.Errorf("failed to parse key material: %w", err)
+	}
+	return &keys, nil
+}
@@ -0,0 +1,81 @@
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MChainGenesis is the M-Chain (MPC) genesis
+type MChainGenesis struct {
+	Config       MChainConfig      `json:"config"`
+	InitialNodes []MPCParticipant  `json:"initialNodes"`
+	ThresholdKey *ThresholdKey     `json:"thresholdKey,omitempty"`
+	Protocols    []Protocol        `json:"protocols"`
+	Sessions     []json.RawMessage `json:"sessions"`
+}
+
+// MChainConfig holds the M-Chain parameters
+type MChainConfig struct {
+	BlockTime       uint64 `json:"blockTime"` // milliseconds
+	MPCThreshold    int    `json:"mpcThreshold"`
+	SessionLength   uint64 `json:"sessionLength"` // blocks
+	KeyGenTimeout   uint64 `json:"keyGenTimeout"` // seconds
+	SignTimeout     uint64 `json:"signTimeout"`   // seconds
+	MinParticipants int    `json:"minParticipants"`
+	MaxParticipants int    `json:"maxParticipants"`
+	SlashingPenalty uint64 `json:"slashingPenalty"` // nLUX
+	SessionReward   uint64 `json:"sessionReward"`   // nLUX
+}
+
+// MPCParticipant is an initial M-Chain node
+type MPCParticipant struct {
+	ID        string `json:"id"`
+	PublicKey string `json:"publicKey"`
+	Stake     uint64 `json:"stake"` // nLUX
+}
+
+// Protocol is a threshold signing protocol the chain runs
+type Protocol struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Threshold int    `json:"threshold"`
+	Supported bool   `json:"supported"`
+}
+
+// Validate checks the threshold against the participant bounds and the
+// initial node set, and that node keys are real and unique
+func (g *MChainGenesis) Validate() error {
+	c := &checker{chain: MChain}
+	n := len(g.InitialNodes)
+
+	if g.Config.MinParticipants < 1 || g.Config.MinParticipants > g.Config.MaxParticipants {
+		c.add("config: participant bounds [%d, %d] are invalid", g.Config.MinParticipants, g.Config.MaxParticipants)
+	}
+	if n < g.Config.MinParticipants || n > g.Config.MaxParticipants {
+		c.add("initialNodes: %d nodes outside [%d, %d]", n, g.Config.MinParticipants, g.Config.MaxParticipants)
+	}
+	c.threshold("config.mpcThreshold", g.Config.MPCThreshold, n)
+	if g.Config.SessionLength == 0 {
+		c.add("config.sessionLength: must be positive")
+	}
+
+	ids := make([]string, n)
+	keys := make([]string, n)
+	for i, node := range g.InitialNodes {
+		ids[i] = node.ID
+		keys[i] = node.PublicKey
+		c.key(fmt.Sprintf("initialNodes[%d].publicKey", i), node.PublicKey, MinPublicKeyLength)
+	}
+	c.unique("initialNodes.id", ids)
+	c.unique("initialNodes.publicKey", keys)
+	c.thresholdKey("thresholdKey", g.ThresholdKey, g.Config.MPCThreshold, n)
+
+	protocolIDs := make([]string, len(g.Protocols))
+	for i, p := range g.Protocols {
+		protocolIDs[i] = p.ID
+		c.threshold(fmt.Sprintf("protocols[%d].threshold", i), p.Threshold, n)
+	}
+	c.unique("protocols.id", protocolIDs)
+
+	return c.err()
+}
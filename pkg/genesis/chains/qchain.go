@@ -0,0 +1,93 @@
+package chains
+
+import "fmt"
+
+// QChainGenesis is the Q-Chain (Quantum) genesis
+type QChainGenesis struct {
+	Config            QChainConfig       `json:"config"`
+	Validators        []QuantumValidator `json:"validators"`
+	QuantumAlgorithms []QuantumAlgorithm `json:"quantumAlgorithms"`
+	MigrationPlan     MigrationPlan      `json:"migrationPlan"`
+}
+
+// QChainConfig holds the Q-Chain parameters
+type QChainConfig struct {
+	BlockTime      uint64 `json:"blockTime"` // milliseconds
+	SignatureAlgo  string `json:"signatureAlgo"`
+	HashFunction   string `json:"hashFunction"`
+	SecurityLevel  int    `json:"securityLevel"`
+	PublicKeySize  int    `json:"publicKeySize"` // bytes
+	SignatureSize  int    `json:"signatureSize"` // bytes
+	MigrationStart int64  `json:"migrationStart"`
+}
+
+// QuantumValidator is a validator's post-quantum key
+type QuantumValidator struct {
+	NodeID    string `json:"nodeId"`
+	PublicKey string `json:"publicKey"`
+}
+
+// QuantumAlgorithm is a post-quantum primitive known to the chain
+type QuantumAlgorithm struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"` // signature or kem
+	SecurityLevel int    `json:"securityLevel"`
+	Status        string `json:"status"` // active or experimental
+}
+
+// MigrationPlan schedules the move to post-quantum signatures
+type MigrationPlan struct {
+	Phase1Start int64 `json:"phase1Start"`
+	Phase2Start int64 `json:"phase2Start"`
+	Mandatory   int64 `json:"mandatory"`
+}
+
+// Validate checks that the signature algorithm is an active one, that the
+// migration phases are in order and that validator keys match the key size
+func (g *QChainGenesis) Validate() error {
+	c := &checker{chain: QChain}
+
+	algorithms := make(map[string]QuantumAlgorithm, len(g.QuantumAlgorithms))
+	algorithmIDs := make([]string, len(g.QuantumAlgorithms))
+	for i, a := range g.QuantumAlgorithms {
+		algorithmIDs[i] = a.ID
+		algorithms[a.ID] = a
+		if a.Type != "signature" && a.Type != "kem" {
+			c.add("quantumAlgorithms[%d].type: unknown type %q", i, a.Type)
+		}
+	}
+	c.unique("quantumAlgorithms.id", algorithmIDs)
+
+	algo, ok := algorithms[g.Config.SignatureAlgo]
+	switch {
+	case !ok:
+		c.add("config.signatureAlgo: %q is not in quantumAlgorithms", g.Config.SignatureAlgo)
+	case algo.Type != "signature" || algo.Status != "active":
+		c.add("config.signatureAlgo: %q must be an active signature algorithm", g.Config.SignatureAlgo)
+	}
+	if g.Config.PublicKeySize <= 0 || g.Config.SignatureSize <= 0 {
+		c.add("config: publicKeySize and signatureSize must be positive")
+	}
+
+	p := g.MigrationPlan
+	if !(p.Phase1Start < p.Phase2Start && p.Phase2Start < p.Mandatory) {
+		c.add("migrationPlan: phase1Start < phase2Start < mandatory is required")
+	}
+
+	nodeIDs := make([]string, len(g.Validators))
+	keys := make([]string, len(g.Validators))
+	for i, v := range g.Validators {
+		nodeIDs[i] = v.NodeID
+		keys[i] = v.PublicKey
+		field := fmt.Sprintf("validators[%d].publicKey", i)
+		c.key(field, v.PublicKey, g.Config.PublicKeySize)
+		if b, ok := decodeHex(v.PublicKey); ok && g.Config.PublicKeySize > 0 && len(b) > g.Config.PublicKeySize {
+			c.add("%s: %d bytes, expected %d", field, len(b), g.Config.PublicKeySize)
+		}
+	}
+	c.unique("validators.nodeId", nodeIDs)
+	c.unique("validators.publicKey", keys)
+
+	return c.err()
+}
@@ -0,0 +1,109 @@
+package chains
+
+import "fmt"
+
+// SRSHashLength is the length of a structured reference string hash, in bytes
+const SRSHashLength = 32
+
+// ZChainGenesis is the Z-Chain (ZK) genesis
+type ZChainGenesis struct {
+	Config           ZChainConfig  `json:"config"`
+	Circuits         []Circuit     `json:"circuits"`
+	TrustedSetup     TrustedSetup  `json:"trustedSetup"`
+	VerifierRegistry []Verifier    `json:"verifierRegistry"`
+	PrivacyPools     []PrivacyPool `json:"privacyPools"`
+}
+
+// ZChainConfig holds the Z-Chain parameters
+type ZChainConfig struct {
+	BlockTime       uint64 `json:"blockTime"` // milliseconds
+	ProofSystem     string `json:"proofSystem"`
+	CurveType       string `json:"curveType"`
+	MaxProofSize    uint64 `json:"maxProofSize"` // bytes
+	MaxConstraints  uint64 `json:"maxConstraints"`
+	ProofGenTimeout uint64 `json:"proofGenTimeout"` // seconds
+	ProofVerifyTime uint64 `json:"proofVerifyTime"` // milliseconds
+	RecursionDepth  uint64 `json:"recursionDepth"`
+	BatchSize       uint64 `json:"batchSize"`
+}
+
+// Circuit is a proving circuit registered at genesis
+type Circuit struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	ProofSystem    string `json:"proofSystem"`
+	ConstraintSize uint64 `json:"constraintSize"`
+	SetupComplete  bool   `json:"setupComplete"`
+	SRSHash        string `json:"srsHash"`
+}
+
+// TrustedSetup records the ceremony that produced the SRS
+type TrustedSetup struct {
+	CeremonyDate int64  `json:"ceremonyDate"`
+	Participants int    `json:"participants"`
+	SRSHash      string `json:"srsHash"`
+	Verified     bool   `json:"verified"`
+}
+
+// Verifier is an on-chain proof verifier
+type Verifier struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	GasUsage uint64 `json:"gasUsage"`
+}
+
+// PrivacyPool is a shielded pool
+type PrivacyPool struct {
+	ID           string `json:"id"`
+	MinDeposit   uint64 `json:"minDeposit"`
+	MaxDeposit   uint64 `json:"maxDeposit"`
+	Participants uint64 `json:"participants"`
+}
+
+// Validate checks that circuits fit the config and carry a real SRS hash,
+// that the trusted setup is recorded and that IDs are unique
+func (g *ZChainGenesis) Validate() error {
+	c := &checker{chain: ZChain}
+
+	if g.Config.ProofSystem == "" {
+		c.add("config.proofSystem: missing")
+	}
+	c.key("trustedSetup.srsHash", g.TrustedSetup.SRSHash, SRSHashLength)
+	if g.TrustedSetup.Participants < 1 {
+		c.add("trustedSetup.participants: must be positive")
+	}
+
+	circuitIDs := make([]string, len(g.Circuits))
+	for i, circuit := range g.Circuits {
+		circuitIDs[i] = circuit.ID
+		field := fmt.Sprintf("circuits[%d]", i)
+		if circuit.ProofSystem != g.Config.ProofSystem {
+			c.add("%s.proofSystem: %q does not match config %q", field, circuit.ProofSystem, g.Config.ProofSystem)
+		}
+		if circuit.ConstraintSize == 0 || circuit.ConstraintSize > g.Config.MaxConstraints {
+			c.add("%s.constraintSize: %d outside (0, %d]", field, circuit.ConstraintSize, g.Config.MaxConstraints)
+		}
+		if circuit.SetupComplete {
+			c.key(field+".srsHash", circuit.SRSHash, SRSHashLength)
+		}
+	}
+	c.unique("circuits.id", circuitIDs)
+
+	verifierIDs := make([]string, len(g.VerifierRegistry))
+	for i, v := range g.VerifierRegistry {
+		verifierIDs[i] = v.ID
+	}
+	c.unique("verifierRegistry.id", verifierIDs)
+
+	poolIDs := make([]string, len(g.PrivacyPools))
+	for i, p := range g.PrivacyPools {
+		poolIDs[i] = p.ID
+		if p.MinDeposit > p.MaxDeposit {
+			c.add("privacyPools[%d]: minDeposit %d exceeds maxDeposit %d", i, p.MinDeposit, p.MaxDeposit)
+		}
+	}
+	c.unique("privacyPools.id", poolIDs)
+
+	return c.err()
+}
@@ -0,0 +1,542 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/geth/common"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+// Change kinds
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// FieldChange is a changed scalar setting, identified by its JSON path
+type FieldChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// AllocationChange is a P/X allocation that was added, removed or changed.
+// Amounts include locked funds.
+type AllocationChange struct {
+	Address         string        `json:"address"`
+	Kind            string        `json:"kind"`
+	Before          amount.Amount `json:"before"`
+	After           amount.Amount `json:"after"`
+	Delta           amount.Amount `json:"delta"`
+	ScheduleChanged bool          `json:"scheduleChanged,omitempty"`
+}
+
+// StakerChange is an initial staker that was added, removed or changed
+type StakerChange struct {
+	NodeID string        `json:"nodeID"`
+	Kind   string        `json:"kind"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// AccountChange is a C-Chain alloc account that was added, removed or changed
+type AccountChange struct {
+	Address      string        `json:"address"`
+	Kind         string        `json:"kind"`
+	Before       amount.Amount `json:"before"`
+	After        amount.Amount `json:"after"`
+	Delta        amount.Amount `json:"delta"`
+	CodeChanged  bool          `json:"codeChanged,omitempty"`
+	NonceChanged bool          `json:"nonceChanged,omitempty"`
+	Storage      []FieldChange `json:"storage,omitempty"` // by slot
+}
+
+// Diff is the semantic difference between two genesis files
+type Diff struct {
+	Fields       []FieldChange      `json:"fields,omitempty"`
+	Allocations  []AllocationChange `json:"allocations,omitempty"`
+	Stakers      []StakerChange     `json:"stakers,omitempty"`
+	CChainConfig []FieldChange      `json:"cChainConfig,omitempty"`
+	CChainFields []FieldChange      `json:"cChainFields,omitempty"`
+	CChainAlloc  []AccountChange    `json:"cChainAlloc,omitempty"`
+
+	PXSupplyBefore     amount.Amount `json:"pxSupplyBefore"`
+	PXSupplyAfter      amount.Amount `json:"pxSupplyAfter"`
+	CChainSupplyBefore amount.Amount `json:"cChainSupplyBefore"`
+	CChainSupplyAfter  amount.Amount `json:"cChainSupplyAfter"`
+	SupplyDelta        amount.Amount `json:"supplyDelta"` // wei, across all chains
+}
+
+// Empty reports whether the two files are semantically identical
+func (d *Diff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Allocations) == 0 && len(d.Stakers) == 0 &&
+		len(d.CChainConfig) == 0 && len(d.CChainFields) == 0 && len(d.CChainAlloc) == 0
+}
+
+// LoadMainGenesis reads a genesis.json in the MainGenesis format
+func LoadMainGenesis(path string) (*MainGenesis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis: %w", err)
+	}
+	var g MainGenesis
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis %s: %w", path, err)
+	}
+	return &g, nil
+}
+
+// DiffGenesis compares two genesis files, decoding the embedded C-Chain
+// genesis so its config and alloc are compared field by field
+func DiffGenesis(before, after *MainGenesis) (*Diff, error) {
+	d := &Diff{}
+
+	d.Fields = diffFields("", map[string]interface{}{
+		"networkID":                  before.NetworkID,
+		"startTime":                  before.StartTime,
+		"initialStakeDuration":       before.InitialStakeDuration,
+		"initialStakeDurationOffset": before.InitialStakeDurationOffset,
+		"initialStakedFunds":         before.InitialStakedFunds,
+		"message":                    before.Message,
+	}, map[string]interface{}{
+		"networkID":                  after.NetworkID,
+		"startTime":                  after.StartTime,
+		"initialStakeDuration":       after.InitialStakeDuration,
+		"initialStakeDurationOffset": after.InitialStakeDurationOffset,
+		"initialStakedFunds":         after.InitialStakedFunds,
+		"message":                    after.Message,
+	})
+
+	d.PXSupplyBefore = pxSupply(before)
+	d.PXSupplyAfter = pxSupply(after)
+	d.Allocations = diffAllocations(before, after)
+	d.Stakers = diffStakers(before, after)
+
+	cBefore, err := decodeCChainGenesis(before.CChainGenesis)
+	if err != nil {
+		return nil, fmt.Errorf("first genesis: %w", err)
+	}
+	cAfter, err := decodeCChainGenesis(after.CChainGenesis)
+	if err != nil {
+		return nil, fmt.Errorf("second genesis: %w", err)
+	}
+
+	configBefore, _ := cBefore["config"].(map[string]interface{})
+	configAfter, _ := cAfter["config"].(map[string]interface{})
+	d.CChainConfig = diffFields("config", configBefore, configAfter)
+
+	delete(cBefore, "config")
+	delete(cAfter, "config")
+	allocBefore, _ := cBefore["alloc"].(map[string]interface{})
+	allocAfter, _ := cAfter["alloc"].(map[string]interface{})
+	delete(cBefore, "alloc")
+	delete(cAfter, "alloc")
+	d.CChainFields = diffFields("", cBefore, cAfter)
+
+	d.CChainAlloc, d.CChainSupplyBefore, d.CChainSupplyAfter, err = diffAlloc(allocBefore, allocAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	pxBefore, _ := d.PXSupplyBefore.Convert(amount.Wei, amount.RoundExact)
+	pxAfter, _ := d.PXSupplyAfter.Convert(amount.Wei, amount.RoundExact)
+	totalBefore, _ := pxBefore.Add(d.CChainSupplyBefore)
+	totalAfter, _ := pxAfter.Add(d.CChainSupplyAfter)
+	d.SupplyDelta, _ = totalAfter.Sub(totalBefore)
+	return d, nil
+}
+
+// allocationTotals sums each address's unlocked and locked nLUX, and keeps
+// its unlock schedule for comparison
+func allocationTotals(g *MainGenesis) (map[string]*big.Int, map[string]string) {
+	totals := make(map[string]*big.Int)
+	schedules := make(map[string]string)
+	for _, alloc := range g.Allocations {
+		key := strings.ToLower(alloc.ETHAddr)
+		if key == "" {
+			key = alloc.LUXAddr
+		}
+		total, ok := totals[key]
+		if !ok {
+			total = new(big.Int)
+			totals[key] = total
+		}
+		total.Add(total, new(big.Int).SetUint64(alloc.InitialAmount))
+		for _, locked := range alloc.UnlockSchedule {
+			total.Add(total, new(big.Int).SetUint64(locked.Amount))
+			schedules[key] += fmt.Sprintf("%d:%d,", locked.Locktime, locked.Amount)
+		}
+	}
+	return totals, schedules
+}
+
+// pxSupply sums every P/X allocation, in nLUX
+func pxSupply(g *MainGenesis) amount.Amount {
+	total := new(big.Int)
+	totals, _ := allocationTotals(g)
+	for _, v := range totals {
+		total.Add(total, v)
+	}
+	return amount.New(total, amount.NLUX)
+}
+
+func diffAllocations(before, after *MainGenesis) []AllocationChange {
+	totalsBefore, schedulesBefore := allocationTotals(before)
+	totalsAfter, schedulesAfter := allocationTotals(after)
+
+	var changes []AllocationChange
+	for _, addr := range unionKeys(totalsBefore, totalsAfter) {
+		b, inBefore := totalsBefore[addr]
+		a, inAfter := totalsAfter[addr]
+		change := AllocationChange{
+			Address: addr,
+			Before:  amount.New(b, amount.NLUX),
+			After:   amount.New(a, amount.NLUX),
+		}
+		change.Delta, _ = change.After.Sub(change.Before)
+		switch {
+		case !inBefore:
+			change.Kind = ChangeAdded
+		case !inAfter:
+			change.Kind = ChangeRemoved
+		case b.Cmp(a) != 0 || schedulesBefore[addr] != schedulesAfter[addr]:
+			change.Kind = ChangeChanged
+			change.ScheduleChanged = schedulesBefore[addr] != schedulesAfter[addr]
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func diffStakers(before, after *MainGenesis) []StakerChange {
+	index := func(g *MainGenesis) map[string]map[string]interface{} {
+		stakers := make(map[string]map[string]interface{})
+		for _, staker := range g.InitialStakers {
+			signer := ""
+			if staker.Signer != nil {
+				data, _ := json.Marshal(staker.Signer)
+				signer = string(data)
+			}
+			stakers[staker.NodeID.String()] = map[string]interface{}{
+				"rewardAddress": staker.RewardAddress,
+				"delegationFee": staker.DelegationFee,
+				"signer":        signer,
+			}
+		}
+		return stakers
+	}
+	stakersBefore := index(before)
+	stakersAfter := index(after)
+
+	var changes []StakerChange
+	for _, nodeID := range unionKeys(stakersBefore, stakersAfter) {
+		b, inBefore := stakersBefore[nodeID]
+		a, inAfter := stakersAfter[nodeID]
+		switch {
+		case !inBefore:
+			changes = append(changes, StakerChange{NodeID: nodeID, Kind: ChangeAdded})
+		case !inAfter:
+			changes = append(changes, StakerChange{NodeID: nodeID, Kind: ChangeRemoved})
+		default:
+			if fields := diffFields("", b, a); len(fields) > 0 {
+				changes = append(changes, StakerChange{NodeID: nodeID, Kind: ChangeChanged, Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+// decodeCChainGenesis decodes the embedded C-Chain genesis string
+func decodeCChainGenesis(s string) (map[string]interface{}, error) {
+	g := make(map[string]interface{})
+	if strings.TrimSpace(s) == "" {
+		return g, nil
+	}
+	if err := json.Unmarshal([]byte(s), &g); err != nil {
+		return nil, fmt.Errorf("failed to decode cChainGenesis: %w", err)
+	}
+	return g, nil
+}
+
+// allocAccount is the comparable form of a C-Chain alloc entry
+type allocAccount struct {
+	balance *big.Int
+	code    string
+	nonce   string
+	storage map[string]string
+}
+
+func parseAllocAccount(addr string, v interface{}) (allocAccount, error) {
+	entry, _ := v.(map[string]interface{})
+	str := func(key string) string {
+		s, _ := entry[key].(string)
+		return strings.ToLower(s)
+	}
+
+	balance, ok := parseBalance(str("balance"))
+	if !ok {
+		return allocAccount{}, fmt.Errorf("invalid C-Chain balance %q for %s", entry["balance"], addr)
+	}
+	account := allocAccount{
+		balance: balance,
+		code:    strings.TrimPrefix(str("code"), "0x"),
+		nonce:   str("nonce"),
+		storage: make(map[string]string),
+	}
+	if nonce, ok := parseBalance(account.nonce); ok {
+		account.nonce = nonce.String()
+	}
+	storage, _ := entry["storage"].(map[string]interface{})
+	for slot, value := range storage {
+		s, _ := value.(string)
+		// Slots and values may be written unpadded ("0x1"); compare both as
+		// full hashes
+		account.storage[common.HexToHash(slot).Hex()] = common.HexToHash(s).Hex()
+	}
+	return account, nil
+}
+
+func diffAlloc(before, after map[string]interface{}) ([]AccountChange, amount.Amount, amount.Amount, error) {
+	// Accounts are keyed by lowercase 0x address; two keys of one alloc that
+	// only differ in spelling would otherwise hide one of the accounts
+	normalize := func(alloc map[string]interface{}) (map[string]allocAccount, *big.Int, error) {
+		accounts := make(map[string]allocAccount)
+		spelledAs := make(map[string]string)
+		total := new(big.Int)
+		for _, addr := range sortedKeys(alloc) {
+			key := strings.ToLower(addr)
+			if !strings.HasPrefix(key, "0x") {
+				key = "0x" + key
+			}
+			if other, ok := spelledAs[key]; ok {
+				return nil, nil, fmt.Errorf("%s is allocated twice, as %q and %q", key, other, addr)
+			}
+			account, err := parseAllocAccount(addr, alloc[addr])
+			if err != nil {
+				return nil, nil, err
+			}
+			accounts[key] = account
+			spelledAs[key] = addr
+			total.Add(total, account.balance)
+		}
+		return accounts, total, nil
+	}
+
+	accountsBefore, totalBefore, err := normalize(before)
+	if err != nil {
+		return nil, amount.Amount{}, amount.Amount{}, fmt.Errorf("first genesis: %w", err)
+	}
+	accountsAfter, totalAfter, err := normalize(after)
+	if err != nil {
+		return nil, amount.Amount{}, amount.Amount{}, fmt.Errorf("second genesis: %w", err)
+	}
+
+	var changes []AccountChange
+	for _, addr := range unionKeys(accountsBefore, accountsAfter) {
+		b, inBefore := accountsBefore[addr]
+		a, inAfter := accountsAfter[addr]
+		change := AccountChange{
+			Address: addr,
+			Before:  amount.New(b.balance, amount.Wei),
+			After:   amount.New(a.balance, amount.Wei),
+		}
+		change.Delta, _ = change.After.Sub(change.Before)
+		switch {
+		case !inBefore:
+			change.Kind = ChangeAdded
+		case !inAfter:
+			change.Kind = ChangeRemoved
+		default:
+			change.CodeChanged = b.code != a.code
+			change.NonceChanged = b.nonce != a.nonce
+			for _, slot := range unionKeys(b.storage, a.storage) {
+				if b.storage[slot] != a.storage[slot] {
+					change.Storage = append(change.Storage, FieldChange{Path: slot, Before: b.storage[slot], After: a.storage[slot]})
+				}
+			}
+			if change.Delta.Sign() == 0 && !change.CodeChanged && !change.NonceChanged && len(change.Storage) == 0 {
+				continue
+			}
+			change.Kind = ChangeChanged
+		}
+		changes = append(changes, change)
+	}
+	return changes, amount.New(totalBefore, amount.Wei), amount.New(totalAfter, amount.Wei), nil
+}
+
+// diffFields compares two JSON-like trees leaf by leaf
+func diffFields(prefix string, before, after map[string]interface{}) []FieldChange {
+	var changes []FieldChange
+	for _, key := range unionKeys(before, after) {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+
+		bMap, bIsMap := b.(map[string]interface{})
+		aMap, aIsMap := a.(map[string]interface{})
+		if (bIsMap || !inBefore) && (aIsMap || !inAfter) && (bIsMap || aIsMap) {
+			changes = append(changes, diffFields(path, bMap, aMap)...)
+			continue
+		}
+
+		if inBefore && inAfter && reflect.DeepEqual(normalizeJSON(b), normalizeJSON(a)) {
+			continue
+		}
+		change := FieldChange{Path: path}
+		if inBefore {
+			change.Before = formatValue(b)
+		}
+		if inAfter {
+			change.After = formatValue(a)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// normalizeJSON round-trips a value through JSON so typed Go values compare
+// equal to their decoded form
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// unionKeys returns the sorted keys of both maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Print writes the diff in the command output style. limit caps the number
+// of allocation and account lines (0 for all).
+func (d *Diff) Print(w io.Writer, limit int) {
+	printFields := func(title string, fields []FieldChange) {
+		if len(fields) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n=== %s (%d) ===\n", title, len(fields))
+		for _, f := range fields {
+			fmt.Fprintf(w, "  %s: %s -> %s\n", f.Path, orNone(f.Before), orNone(f.After))
+		}
+	}
+	capped := func(n int) int {
+		if limit > 0 && n > limit {
+			return limit
+		}
+		return n
+	}
+
+	printFields("Genesis Fields", d.Fields)
+
+	if len(d.Allocations) > 0 {
+		fmt.Fprintf(w, "\n=== P/X Allocations (%d) ===\n", len(d.Allocations))
+		for _, c := range d.Allocations[:capped(len(d.Allocations))] {
+			schedule := ""
+			if c.ScheduleChanged {
+				schedule = " (unlock schedule changed)"
+			}
+			fmt.Fprintf(w, "  %-8s %s %s -> %s (%s)%s\n", c.Kind, c.Address, c.Before, c.After, signed(c.Delta), schedule)
+		}
+		if n := len(d.Allocations) - capped(len(d.Allocations)); n > 0 {
+			fmt.Fprintf(w, "  ... %d more\n", n)
+		}
+	}
+
+	if len(d.Stakers) > 0 {
+		fmt.Fprintf(w, "\n=== Stakers (%d) ===\n", len(d.Stakers))
+		for _, c := range d.Stakers {
+			fmt.Fprintf(w, "  %-8s %s\n", c.Kind, c.NodeID)
+			for _, f := range c.Fields {
+				fmt.Fprintf(w, "           %s: %s -> %s\n", f.Path, orNone(f.Before), orNone(f.After))
+			}
+		}
+	}
+
+	printFields("C-Chain Config", d.CChainConfig)
+	printFields("C-Chain Genesis Fields", d.CChainFields)
+
+	if len(d.CChainAlloc) > 0 {
+		fmt.Fprintf(w, "\n=== C-Chain Alloc (%d) ===\n", len(d.CChainAlloc))
+		for _, c := range d.CChainAlloc[:capped(len(d.CChainAlloc))] {
+			var notes []string
+			if c.CodeChanged {
+				notes = append(notes, "code changed")
+			}
+			if c.NonceChanged {
+				notes = append(notes, "nonce changed")
+			}
+			if len(c.Storage) > 0 {
+				notes = append(notes, fmt.Sprintf("%d storage slots changed", len(c.Storage)))
+			}
+			note := ""
+			if len(notes) > 0 {
+				note = " [" + strings.Join(notes, ", ") + "]"
+			}
+			fmt.Fprintf(w, "  %-8s %s %s -> %s (%s)%s\n", c.Kind, c.Address, c.Before, c.After, signed(c.Delta), note)
+		}
+		if n := len(d.CChainAlloc) - capped(len(d.CChainAlloc)); n > 0 {
+			fmt.Fprintf(w, "  ... %d more\n", n)
+		}
+	}
+
+	fmt.Fprintln(w, "\n=== Supply ===")
+	fmt.Fprintf(w, "  P/X:     %s -> %s\n", d.PXSupplyBefore, d.PXSupplyAfter)
+	fmt.Fprintf(w, "  C-Chain: %s -> %s\n", d.CChainSupplyBefore, d.CChainSupplyAfter)
+	fmt.Fprintf(w, "  Delta:   %s\n", signed(d.SupplyDelta))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// signed formats an amount with an explicit sign
+func signed(a amount.Amount) string {
+	if a.Sign() > 0 {
+		return "+" + a.String()
+	}
+	return a.String()
+}
@@ -0,0 +1,86 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/node/genesis"
+)
+
+func TestDiffGenesis(t *testing.T) {
+	before := &MainGenesis{
+		NetworkID: 96369,
+		StartTime: 1577836800,
+		Allocations: []genesis.UnparsedAllocation{
+			{ETHAddr: "0x1111111111111111111111111111111111111111", InitialAmount: 1000},
+			{ETHAddr: "0x2222222222222222222222222222222222222222", InitialAmount: 500},
+		},
+		CChainGenesis: `{"config": {"chainId": 96369, "feeConfig": {"minBaseFee": 25000000000}}, "gasLimit": "0xe4e1c0",
+			"alloc": {
+				"0x3333333333333333333333333333333333333333": {"balance": "0x64"},
+				"0x4444444444444444444444444444444444444444": {"balance": "0x0", "code": "0x6000", "storage": {"0x01": "0x02", "0x02": "0x2"}}}}`,
+		Message: "genesis",
+	}
+	after := &MainGenesis{
+		NetworkID: 96369,
+		StartTime: 1577836800,
+		Allocations: []genesis.UnparsedAllocation{
+			{ETHAddr: "0x1111111111111111111111111111111111111111", InitialAmount: 1200},
+			{ETHAddr: "0x5555555555555555555555555555555555555555", InitialAmount: 10},
+		},
+		CChainGenesis: `{"config": {"chainId": 96369, "feeConfig": {"minBaseFee": 1000000000}}, "gasLimit": "0xe4e1c0",
+			"alloc": {
+				"0x3333333333333333333333333333333333333333": {"balance": "100"},
+				"0x4444444444444444444444444444444444444444": {"balance": "0x0", "code": "0x6001",
+					"storage": {
+						"0x0000000000000000000000000000000000000000000000000000000000000001": "0x03",
+						"0x02": "0x0000000000000000000000000000000000000000000000000000000000000002"}}}}`,
+		Message: "genesis v2",
+	}
+
+	d, err := DiffGenesis(before, after)
+	require.NoError(t, err)
+	assert.False(t, d.Empty())
+
+	require.Len(t, d.Fields, 1)
+	assert.Equal(t, FieldChange{Path: "message", Before: "genesis", After: "genesis v2"}, d.Fields[0])
+
+	require.Len(t, d.Allocations, 3)
+	assert.Equal(t, ChangeChanged, d.Allocations[0].Kind)
+	assert.Equal(t, "200", d.Allocations[0].Delta.Value().String())
+	assert.Equal(t, ChangeRemoved, d.Allocations[1].Kind)
+	assert.Equal(t, ChangeAdded, d.Allocations[2].Kind)
+
+	require.Len(t, d.CChainConfig, 1)
+	assert.Equal(t, "config.feeConfig.minBaseFee", d.CChainConfig[0].Path)
+	assert.Empty(t, d.CChainFields)
+
+	// Hex and decimal balances of the same value are equal
+	require.Len(t, d.CChainAlloc, 1)
+	assert.Equal(t, "0x4444444444444444444444444444444444444444", d.CChainAlloc[0].Address)
+	assert.True(t, d.CChainAlloc[0].CodeChanged)
+	// Unpadded and padded slot keys are the same slot, and unpadded and
+	// padded values the same value
+	require.Len(t, d.CChainAlloc[0].Storage, 1)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", d.CChainAlloc[0].Storage[0].Path)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000002", d.CChainAlloc[0].Storage[0].Before)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000003", d.CChainAlloc[0].Storage[0].After)
+
+	// 1200 + 10 - (1000 + 500) nLUX
+	assert.Equal(t, "-290000000000", d.SupplyDelta.Value().String())
+
+	same, err := DiffGenesis(before, before)
+	require.NoError(t, err)
+	assert.True(t, same.Empty())
+
+	// One account spelled twice in an alloc is an error, not a silent merge
+	duplicate := *after
+	duplicate.CChainGenesis = `{"config": {"chainId": 96369}, "alloc": {
+		"0x3333333333333333333333333333333333333333": {"balance": "0x64"},
+		"3333333333333333333333333333333333333333": {"balance": "0x1"}}}`
+	_, err = DiffGenesis(before, &duplicate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "second genesis: 0x3333333333333333333333333333333333333333 is allocated twice")
+}
@@ -0,0 +1,208 @@
+package genesis
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/luxfi/genesis/pkg/genesis/address"
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+// StakeEntry is the weight (nLUX) of one validator and who receives its
+// rewards
+type StakeEntry struct {
+	NodeID        string
+	RewardAddress string
+	Weight        uint64
+}
+
+// ValidatorShare is a validator's part of the total weight
+type ValidatorShare struct {
+	NodeID        string        `json:"nodeID"`
+	RewardAddress string        `json:"rewardAddress"`
+	Weight        amount.Amount `json:"weight"`
+	Share         float64       `json:"share"` // percent
+}
+
+// AddressShare is the weight of all validators paying one reward address
+type AddressShare struct {
+	Address    string        `json:"address"`
+	Validators int           `json:"validators"`
+	Weight     amount.Amount `json:"weight"`
+	Share      float64       `json:"share"` // percent
+}
+
+// DistributionReport describes how weight is spread over a validator set.
+//
+// The Nakamoto coefficients are the smallest number of validators, or of
+// reward addresses, whose combined weight is more than a third (enough to
+// halt consensus) or more than half of the total.
+type DistributionReport struct {
+	Validators        int              `json:"validators"`
+	TotalWeight       amount.Amount    `json:"totalWeight"`
+	Nakamoto33        int              `json:"nakamoto33"`
+	Nakamoto50        int              `json:"nakamoto50"`
+	AddressNakamoto33 int              `json:"addressNakamoto33"`
+	AddressNakamoto50 int              `json:"addressNakamoto50"`
+	Shares            []ValidatorShare `json:"shares"`          // largest first
+	RewardAddresses   []AddressShare   `json:"rewardAddresses"` // largest first
+	MaxAddressShare   float64          `json:"maxAddressShare"` // warning threshold, percent
+	Warnings          []string         `json:"warnings,omitempty"`
+}
+
+// WeightDistribution computes the distribution report of a validator set
+// and warns when one reward address holds more than maxAddressShare percent
+// of the weight
+func WeightDistribution(entries []StakeEntry, maxAddressShare float64) *DistributionReport {
+	r := &DistributionReport{
+		Validators:      len(entries),
+		MaxAddressShare: maxAddressShare,
+	}
+	total := new(big.Int)
+	for _, e := range entries {
+		total.Add(total, new(big.Int).SetUint64(e.Weight))
+	}
+	r.TotalWeight = amount.New(total, amount.NLUX)
+
+	// Group by reward address, ignoring the chain prefix and case
+	byAddress := make(map[string]int)
+	addressWeights := []*big.Int{}
+	for _, e := range entries {
+		w := new(big.Int).SetUint64(e.Weight)
+		r.Shares = append(r.Shares, ValidatorShare{
+			NodeID:        e.NodeID,
+			RewardAddress: e.RewardAddress,
+			Weight:        amount.New(w, amount.NLUX),
+			Share:         percent(w, total),
+		})
+		key := luxAddressKey(e.RewardAddress)
+		i, ok := byAddress[key]
+		if !ok {
+			i = len(r.RewardAddresses)
+			byAddress[key] = i
+			r.RewardAddresses = append(r.RewardAddresses, AddressShare{Address: e.RewardAddress})
+			addressWeights = append(addressWeights, new(big.Int))
+		}
+		r.RewardAddresses[i].Validators++
+		addressWeights[i].Add(addressWeights[i], w)
+	}
+	for i, w := range addressWeights {
+		r.RewardAddresses[i].Weight = amount.New(w, amount.NLUX)
+		r.RewardAddresses[i].Share = percent(w, total)
+	}
+
+	sort.SliceStable(r.Shares, func(i, j int) bool {
+		return r.Shares[i].Weight.Cmp(r.Shares[j].Weight) > 0
+	})
+	sort.SliceStable(r.RewardAddresses, func(i, j int) bool {
+		return r.RewardAddresses[i].Weight.Cmp(r.RewardAddresses[j].Weight) > 0
+	})
+
+	validatorWeights := make([]*big.Int, len(r.Shares))
+	for i, v := range r.Shares {
+		validatorWeights[i] = v.Weight.Value()
+	}
+	for i, a := range r.RewardAddresses {
+		addressWeights[i] = a.Weight.Value()
+	}
+	r.Nakamoto33 = NakamotoCoefficient(validatorWeights, 1, 3)
+	r.Nakamoto50 = NakamotoCoefficient(validatorWeights, 1, 2)
+	r.AddressNakamoto33 = NakamotoCoefficient(addressWeights, 1, 3)
+	r.AddressNakamoto50 = NakamotoCoefficient(addressWeights, 1, 2)
+
+	for _, a := range r.RewardAddresses {
+		if a.Share > maxAddressShare {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("reward address %s controls %.2f%% of the weight (%d validators), above %.2f%%",
+				a.Address, a.Share, a.Validators, maxAddressShare))
+		}
+	}
+	if r.Nakamoto33 == 1 {
+		r.Warnings = append(r.Warnings, "a single validator holds more than a third of the weight")
+	}
+	return r
+}
+
+// NakamotoCoefficient returns the smallest number of the given weights,
+// sorted largest first, whose sum is more than num/den of the total, or 0
+// if the total is zero
+func NakamotoCoefficient(sorted []*big.Int, num, den int64) int {
+	total := new(big.Int)
+	for _, w := range sorted {
+		total.Add(total, w)
+	}
+	if total.Sign() == 0 {
+		return 0
+	}
+	// sum/total > num/den  <=>  sum*den > total*num
+	threshold := new(big.Int).Mul(total, big.NewInt(num))
+	sum := new(big.Int)
+	for i, w := range sorted {
+		sum.Add(sum, w)
+		if new(big.Int).Mul(sum, big.NewInt(den)).Cmp(threshold) > 0 {
+			return i + 1
+		}
+	}
+	return len(sorted)
+}
+
+// RegistryStake returns the stake entries of registry validators. Entries
+// without a reward address are paid to the P-Chain form of their ETH
+// address, as Builder does.
+func RegistryStake(validators []ValidatorInfo, hrp string) ([]StakeEntry, error) {
+	conv := address.NewConverter(hrp)
+	entries := make([]StakeEntry, len(validators))
+	for i, v := range validators {
+		reward := v.RewardAddress
+		if reward == "" {
+			var err error
+			if reward, err = conv.ETHToLux(v.ETHAddress, "P"); err != nil {
+				return nil, fmt.Errorf("validator %d (%s): %w", i, v.NodeID, err)
+			}
+		}
+		entries[i] = StakeEntry{NodeID: v.NodeID, RewardAddress: reward, Weight: v.Weight}
+	}
+	return entries, nil
+}
+
+// GenesisStake returns the stake entries of the initial stakers of a
+// genesis. Their weight is the unlock schedules of the InitialStakedFunds
+// allocations split evenly across the stakers, remainder to the last, as in
+// ProjectSupply.
+func GenesisStake(g *MainGenesis) []StakeEntry {
+	entries := make([]StakeEntry, len(g.InitialStakers))
+	for i, staker := range g.InitialStakers {
+		entries[i] = StakeEntry{NodeID: staker.NodeID.String(), RewardAddress: staker.RewardAddress}
+	}
+	if len(entries) == 0 {
+		return entries
+	}
+
+	staked := make(map[string]bool, len(g.InitialStakedFunds))
+	for _, addr := range g.InitialStakedFunds {
+		staked[luxAddressKey(addr)] = true
+	}
+	n := uint64(len(entries))
+	for _, alloc := range g.Allocations {
+		if !staked[luxAddressKey(alloc.LUXAddr)] {
+			continue
+		}
+		for _, locked := range alloc.UnlockSchedule {
+			share := locked.Amount / n
+			for k := range entries {
+				entries[k].Weight += share
+			}
+			entries[n-1].Weight += locked.Amount - share*n
+		}
+	}
+	return entries
+}
+
+// percent returns part as a percentage of total
+func percent(part, total *big.Int) float64 {
+	if total.Sign() == 0 {
+		return 0
+	}
+	f, _ := new(big.Rat).SetFrac(new(big.Int).Mul(part, big.NewInt(100)), total).Float64()
+	return f
+}
@@ -0,0 +1,85 @@
+package genesis
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/genesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bigs(ws ...int64) []*big.Int {
+	out := make([]*big.Int, len(ws))
+	for i, w := range ws {
+		out[i] = big.NewInt(w)
+	}
+	return out
+}
+
+func TestNakamotoCoefficient(t *testing.T) {
+	assert.Equal(t, 0, NakamotoCoefficient(nil, 1, 3))
+	assert.Equal(t, 1, NakamotoCoefficient(bigs(40, 30, 30), 1, 3))
+	assert.Equal(t, 2, NakamotoCoefficient(bigs(40, 30, 30), 1, 2))
+	// Exactly a third is not more than a third
+	assert.Equal(t, 2, NakamotoCoefficient(bigs(1, 1, 1), 1, 3))
+	assert.Equal(t, 4, NakamotoCoefficient(bigs(10, 10, 10, 10, 10, 10, 10, 10, 10, 10), 1, 3))
+	assert.Equal(t, 6, NakamotoCoefficient(bigs(10, 10, 10, 10, 10, 10, 10, 10, 10, 10), 1, 2))
+}
+
+func TestWeightDistribution(t *testing.T) {
+	entries := []StakeEntry{
+		{NodeID: "NodeID-a", RewardAddress: "P-lux1aaa", Weight: 100},
+		{NodeID: "NodeID-b", RewardAddress: "P-lux1bbb", Weight: 300},
+		{NodeID: "NodeID-c", RewardAddress: "X-lux1aaa", Weight: 200}, // same address as a
+		{NodeID: "NodeID-d", RewardAddress: "P-lux1ddd", Weight: 400},
+	}
+	r := WeightDistribution(entries, 40)
+
+	assert.Equal(t, 4, r.Validators)
+	assert.Equal(t, "1000", r.TotalWeight.Value().String())
+	require.Len(t, r.Shares, 4)
+	assert.Equal(t, "NodeID-d", r.Shares[0].NodeID)
+	assert.InDelta(t, 40.0, r.Shares[0].Share, 1e-9)
+	assert.Equal(t, 1, r.Nakamoto33)
+	assert.Equal(t, 2, r.Nakamoto50)
+
+	require.Len(t, r.RewardAddresses, 3)
+	assert.Equal(t, "P-lux1ddd", r.RewardAddresses[0].Address)
+	assert.Equal(t, "P-lux1aaa", r.RewardAddresses[1].Address)
+	assert.Equal(t, 2, r.RewardAddresses[1].Validators)
+	assert.InDelta(t, 30.0, r.RewardAddresses[1].Share, 1e-9)
+	assert.Equal(t, 1, r.AddressNakamoto33)
+	assert.Equal(t, 2, r.AddressNakamoto50)
+
+	// 40% is not above the 40% threshold, but one validator holds a third
+	require.Len(t, r.Warnings, 1)
+	assert.Contains(t, r.Warnings[0], "more than a third")
+
+	r = WeightDistribution(entries, 25)
+	assert.Len(t, r.Warnings, 4)
+}
+
+func TestGenesisStake(t *testing.T) {
+	g := &MainGenesis{
+		InitialStakedFunds: []string{"X-lux1stake"},
+		Allocations: []genesis.UnparsedAllocation{
+			{LUXAddr: "X-lux1stake", UnlockSchedule: []genesis.LockedAmount{{Amount: 1000}, {Amount: 2}}},
+			{LUXAddr: "X-lux1other", UnlockSchedule: []genesis.LockedAmount{{Amount: 5000}}},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		g.InitialStakers = append(g.InitialStakers, genesis.UnparsedStaker{
+			NodeID:        ids.GenerateTestNodeID(),
+			RewardAddress: "P-lux1reward",
+		})
+	}
+
+	entries := GenesisStake(g)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(333), entries[0].Weight)
+	assert.Equal(t, uint64(333), entries[1].Weight)
+	assert.Equal(t, uint64(336), entries[2].Weight)
+	assert.Equal(t, g.InitialStakers[0].NodeID.String(), entries[0].NodeID)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/luxfi/genesis/pkg/genesis/allocation"
+	"github.com/luxfi/genesis/pkg/genesis/cchain"
 	"github.com/luxfi/node/genesis"
 )
 
@@ -142,6 +143,21 @@ func TestBuild(t *testing.T) {
 	assert.Len(t, genesis.InitialStakers, 1)
 }
 
+func TestBuildValidatesCChain(t *testing.T) {
+	builder, err := NewBuilder("local")
+	require.NoError(t, err)
+	builder.cchainBuilder.SetPrecompiles(cchain.Precompiles{
+		ContractDeployerAllowList: &cchain.AllowListConfig{
+			AdminAddresses: []string{"0x0987654321098765432109876543210987654321"},
+		},
+	})
+
+	// The admin holds no balance in the built C-Chain alloc
+	_, err = builder.Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no balance in alloc")
+}
+
 func TestImportCChainGenesis(t *testing.T) {
 	builder, err := NewBuilder("mainnet")
 	require.NoError(t, err)
@@ -195,6 +211,16 @@ func TestImportCSVAllocations(t *testing.T) {
 	assert.Len(t, genesis.Allocations, 2)
 }
 
+func TestLoadXChainAirdrops(t *testing.T) {
+	// The snapshot's balance_lux column has 18 decimals; the loader rounds
+	// each balance down to nLUX instead of rejecting the row
+	allocations, err := LoadXChainAirdrops("../../chaindata/lux-genesis-7777/7777-airdrop-96369-mainnet.csv")
+	require.NoError(t, err)
+	require.Len(t, allocations, 151)
+	assert.Equal(t, "0x9011e888251ab053b7bd1cdb598db4f9ded94714", allocations[0].Address)
+	assert.Equal(t, "1994739905397278564453", allocations[0].Balance.String())
+}
+
 func TestAddVestedAllocation(t *testing.T) {
 	builder, err := NewBuilder("mainnet")
 	require.NoError(t, err)
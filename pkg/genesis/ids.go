@@ -0,0 +1,159 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	evmcore "github.com/luxfi/evm/core"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/genesis"
+	"github.com/luxfi/node/utils/constants"
+	"github.com/luxfi/node/utils/hashing"
+	"github.com/luxfi/node/vms/platformvm/block"
+)
+
+// ChainIDs are the identifiers a node derives from a genesis. Any change to
+// them means nodes built from the old and new genesis will not interoperate.
+type ChainIDs struct {
+	NetworkID         uint32      `json:"networkID"`
+	GenesisID         ids.ID      `json:"genesisID"`      // hash of the genesis bytes
+	GenesisBlockID    ids.ID      `json:"genesisBlockID"` // P-Chain genesis block
+	LUXAssetID        ids.ID      `json:"luxAssetID"`
+	PChainID          ids.ID      `json:"pChainID"`
+	XChainID          ids.ID      `json:"xChainID"`
+	CChainID          ids.ID      `json:"cChainID"`
+	CChainGenesisHash common.Hash `json:"cChainGenesisHash"`
+}
+
+// ComputeIDs computes the chain IDs for a genesis.json in the node's format,
+// using the node's own parsing and encoding so the result matches what luxd
+// derives at startup
+func ComputeIDs(genesisJSON []byte) (*ChainIDs, error) {
+	var unparsed genesis.UnparsedConfig
+	if err := json.Unmarshal(genesisJSON, &unparsed); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis: %w", err)
+	}
+	config, err := unparsed.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse genesis: %w", err)
+	}
+
+	genesisBytes, luxAssetID, err := genesis.FromConfig(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode genesis: %w", err)
+	}
+
+	// The P-Chain's genesis block is a commit block whose parent is the
+	// hash of the genesis bytes (see platformvm state initialisation)
+	genesisID := ids.ID(hashing.ComputeHash256Array(genesisBytes))
+	genesisBlock, err := block.NewApricotCommitBlock(genesisID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genesis block: %w", err)
+	}
+
+	xChainTx, err := genesis.VMGenesis(genesisBytes, constants.AVMID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find X-Chain: %w", err)
+	}
+	cChainTx, err := genesis.VMGenesis(genesisBytes, constants.EVMID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find C-Chain: %w", err)
+	}
+
+	cChainHash, err := CChainGenesisHash([]byte(config.CChainGenesis))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainIDs{
+		NetworkID:         config.NetworkID,
+		GenesisID:         genesisID,
+		GenesisBlockID:    genesisBlock.ID(),
+		LUXAssetID:        luxAssetID,
+		PChainID:          constants.PlatformChainID,
+		XChainID:          xChainTx.ID(),
+		CChainID:          cChainTx.ID(),
+		CChainGenesisHash: cChainHash,
+	}, nil
+}
+
+// CChainGenesisHash returns the hash of the block a C-Chain genesis produces.
+// The genesis is read with the EVM's own genesis type, whose header carries
+// the EVM specific fields (such as ExtDataHash) upstream geth does not know.
+func CChainGenesisHash(cChainGenesis []byte) (common.Hash, error) {
+	var g evmcore.Genesis
+	if err := json.Unmarshal(cChainGenesis, &g); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to parse C-Chain genesis: %w", err)
+	}
+	return g.ToBlock().Hash(), nil
+}
+
+// ComputeIDs builds the genesis and computes its chain IDs
+func (b *Builder) ComputeIDs() (*ChainIDs, error) {
+	mainGenesis, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(mainGenesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+	}
+	return ComputeIDs(data)
+}
+
+// LoadChainIDs reads chain IDs previously written with SaveChainIDs
+func LoadChainIDs(path string) (*ChainIDs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IDs: %w", err)
+	}
+	var chainIDs ChainIDs
+	if err := json.Unmarshal(data, &chainIDs); err != nil {
+		return nil, fmt.Errorf("failed to parse IDs: %w", err)
+	}
+	return &chainIDs, nil
+}
+
+// SaveChainIDs writes chain IDs as JSON, for use as an expectation file
+func SaveChainIDs(chainIDs *ChainIDs, path string) error {
+	data, err := json.MarshalIndent(chainIDs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// IDMismatch is a single ID that differs from the expectation
+type IDMismatch struct {
+	Name     string
+	Expected string
+	Actual   string
+}
+
+// Compare returns every ID in c that differs from expected
+func (c *ChainIDs) Compare(expected *ChainIDs) []IDMismatch {
+	var mismatches []IDMismatch
+	check := func(name string, want, got fmt.Stringer) {
+		if want.String() != got.String() {
+			mismatches = append(mismatches, IDMismatch{Name: name, Expected: want.String(), Actual: got.String()})
+		}
+	}
+
+	if expected.NetworkID != c.NetworkID {
+		mismatches = append(mismatches, IDMismatch{
+			Name:     "networkID",
+			Expected: fmt.Sprint(expected.NetworkID),
+			Actual:   fmt.Sprint(c.NetworkID),
+		})
+	}
+	check("genesisID", expected.GenesisID, c.GenesisID)
+	check("genesisBlockID", expected.GenesisBlockID, c.GenesisBlockID)
+	check("luxAssetID", expected.LUXAssetID, c.LUXAssetID)
+	check("pChainID", expected.PChainID, c.PChainID)
+	check("xChainID", expected.XChainID, c.XChainID)
+	check("cChainID", expected.CChainID, c.CChainID)
+	check("cChainGenesisHash", expected.CChainGenesisHash, c.CChainGenesisHash)
+	return mismatches
+}
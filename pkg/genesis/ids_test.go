@@ -0,0 +1,95 @@
+package genesis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/genesis"
+	"github.com/luxfi/node/utils/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainIDsCompare(t *testing.T) {
+	expected := &ChainIDs{
+		NetworkID:         96369,
+		GenesisID:         ids.GenerateTestID(),
+		GenesisBlockID:    ids.GenerateTestID(),
+		LUXAssetID:        ids.GenerateTestID(),
+		XChainID:          ids.GenerateTestID(),
+		CChainID:          ids.GenerateTestID(),
+		CChainGenesisHash: common.HexToHash("0x01"),
+	}
+
+	path := filepath.Join(t.TempDir(), "ids.json")
+	require.NoError(t, SaveChainIDs(expected, path))
+	loaded, err := LoadChainIDs(path)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Compare(expected))
+
+	actual := *expected
+	actual.CChainID = ids.GenerateTestID()
+	actual.CChainGenesisHash = common.HexToHash("0x02")
+	mismatches := actual.Compare(expected)
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "cChainID", mismatches[0].Name)
+	assert.Equal(t, expected.CChainID.String(), mismatches[0].Expected)
+	assert.Equal(t, "cChainGenesisHash", mismatches[1].Name)
+}
+
+func TestCChainGenesisHash(t *testing.T) {
+	cGenesis := []byte(`{"config": {"chainId": 96369}, "gasLimit": "0xe4e1c0", "difficulty": "0x0", "alloc": {}}`)
+	first, err := CChainGenesisHash(cGenesis)
+	require.NoError(t, err)
+	assert.NotEqual(t, common.Hash{}, first)
+
+	// Changing the alloc changes the state root and so the hash
+	changed := []byte(`{"config": {"chainId": 96369}, "gasLimit": "0xe4e1c0", "difficulty": "0x0",
+		"alloc": {"0x9011E888251AB053B7bD1cdB598Db4f9DEd94714": {"balance": "0x1"}}}`)
+	second, err := CChainGenesisHash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestComputeIDsBuiltinNetworks(t *testing.T) {
+	// Going through genesis.json loses nothing: the C-Chain ID matches the
+	// one the node derives from its built-in config directly
+	for _, networkID := range []uint32{constants.MainnetID, constants.TestnetID} {
+		t.Run(constants.NetworkIDToNetworkName[networkID], func(t *testing.T) {
+			unparsed, err := genesis.GetConfig(networkID).Unparse()
+			require.NoError(t, err)
+			data, err := json.Marshal(unparsed)
+			require.NoError(t, err)
+
+			chainIDs, err := ComputeIDs(data)
+			require.NoError(t, err)
+			assert.Equal(t, networkID, chainIDs.NetworkID)
+			assert.Equal(t, constants.PlatformChainID, chainIDs.PChainID)
+
+			again, err := ComputeIDs(data)
+			require.NoError(t, err)
+			assert.Empty(t, again.Compare(chainIDs))
+
+			genesisBytes, _, err := genesis.FromConfig(genesis.GetConfig(networkID))
+			require.NoError(t, err)
+			cChainTx, err := genesis.VMGenesis(genesisBytes, constants.EVMID)
+			require.NoError(t, err)
+			assert.Equal(t, cChainTx.ID(), chainIDs.CChainID)
+		})
+	}
+}
+
+func TestCChainGenesisHashGolden(t *testing.T) {
+	// Genesis block hash of the migrated LUX mainnet (96369) chain data, as
+	// read from its database and recorded in README.md
+	data, err := os.ReadFile(filepath.Join("..", "..", "chaindata", "configs", "lux-mainnet-96369", "genesis.original.json"))
+	require.NoError(t, err)
+
+	hash, err := CChainGenesisHash(data)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x3f4fa2a0b0ce089f52bf0ae9199c75ffdd76ecafc987794050cb0d286f1ec61e"), hash)
+}
@@ -0,0 +1,394 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// addressColumns are the header names recognised as the address, in order of
+// preference
+var addressColumns = []string{"address", "addr", "account", "holder", "wallet", "owner"}
+
+// amountColumns are the header names recognised as the amount, in order of
+// preference. The scanner's holder export carries both a raw balance (token
+// units or an NFT count) and the derived staking power; the latter is what
+// genesis allocations are built from.
+var amountColumns = map[Format][]string{
+	FormatCSV:     {"balance_lux", "balance_nlux", "balance_wei", "balance", "amount", "value"},
+	FormatScanner: {"staking_power_wei", "totalburned", "balance"},
+}
+
+// scannerColumns identify a CSV written by the scan commands
+var scannerColumns = []string{"staking_power_wei", "totalburned"}
+
+// ImportFile reads allocations from a file in the configured format
+func (im *Importer) ImportFile(path string) error {
+	format := im.opts.Format
+	if format == "" || format == FormatAuto {
+		var err error
+		if format, err = DetectFormat(path); err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	switch format {
+	case FormatCSV, FormatScanner:
+		return im.importCSV(path, data, format)
+	case FormatJSON:
+		return im.importJSON(path, data)
+	case FormatNDJSON:
+		return im.importNDJSON(path, data)
+	}
+	return fmt.Errorf("unsupported format: %s", format)
+}
+
+// importCSV reads a CSV with an optional header. Lines before the header
+// (report titles, notes) are skipped; without a header the first column is
+// the address and the second the amount.
+func (im *Importer) importCSV(file string, data []byte, format Format) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	addrCol, amountCol := -1, -1
+	unit := im.opts.Unit
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			im.reject(Source{File: file, Line: perr.Line}, "", "malformed CSV: %v", perr.Err)
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		src := Source{File: file, Line: line}
+		raw := strings.Join(record, ",")
+
+		if addrCol < 0 {
+			if _, ok := normalizeAddress(record[0]); ok && len(record) > 1 {
+				// Headerless: address,amount
+				addrCol, amountCol = 0, 1
+			} else {
+				if format == FormatCSV && hasColumn(record, scannerColumns) {
+					format = FormatScanner
+				}
+				a, b, column := im.findColumns(record, format)
+				if a < 0 {
+					continue // preamble before the header
+				}
+				if b < 0 {
+					return fmt.Errorf("%s: no amount column in header (looked for %s)", src, strings.Join(amountColumns[format], ", "))
+				}
+				addrCol, amountCol = a, b
+				if unit == UnitAuto {
+					unit = unitOf(column)
+				}
+				continue
+			}
+		}
+
+		if len(record) <= addrCol || len(record) <= amountCol {
+			im.reject(src, raw, "expected at least %d columns, got %d", max(addrCol, amountCol)+1, len(record))
+			continue
+		}
+		if err := im.add(src, raw, record[addrCol], record[amountCol], unit); err != nil {
+			return err
+		}
+	}
+}
+
+// findColumns locates the address and amount columns of a header and
+// returns the amount column's name
+func (im *Importer) findColumns(header []string, format Format) (int, int, string) {
+	addrNames := addressColumns
+	if im.opts.Columns.Address != "" {
+		addrNames = []string{im.opts.Columns.Address}
+	}
+	amountNames := amountColumns[format]
+	if im.opts.Columns.Amount != "" {
+		amountNames = []string{im.opts.Columns.Amount}
+	}
+
+	addrCol, amountCol, column := -1, -1, ""
+	for _, name := range addrNames {
+		if i := columnIndex(header, name); i >= 0 {
+			addrCol = i
+			break
+		}
+	}
+	for _, name := range amountNames {
+		if i := columnIndex(header, name); i >= 0 {
+			amountCol, column = i, name
+			break
+		}
+	}
+	return addrCol, amountCol, column
+}
+
+func columnIndex(header []string, name string) int {
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasColumn(header []string, names []string) bool {
+	for _, name := range names {
+		if columnIndex(header, name) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// unitOf infers the unit of an amount column from its name
+func unitOf(column string) Unit {
+	column = strings.ToLower(column)
+	switch {
+	case strings.Contains(column, "nlux"):
+		return UnitNLUX
+	case strings.Contains(column, "lux"):
+		return UnitLUX
+	default:
+		return UnitWei
+	}
+}
+
+// importJSON reads one of:
+//
+//	{"0xabc...": "1000", ...}                      address to amount
+//	{"0xabc...": {"balance": "0x3e8"}, ...}        genesis alloc style
+//	{"alloc": {...}} or {"allocations": [...]}     either of the above, wrapped
+//	[{"address": "0xabc...", "amount": "1000"}]    list of objects
+func (im *Importer) importJSON(file string, data []byte) error {
+	p := &jsonParser{im: im, file: file, data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.UseNumber()
+	if err := p.value(true); err != nil {
+		var dup *DuplicateError
+		if errors.As(err, &dup) {
+			return err
+		}
+		return fmt.Errorf("failed to parse JSON %s: %w", file, err)
+	}
+	return nil
+}
+
+// jsonParser walks a JSON document token by token so that each entry can be
+// traced back to its line
+type jsonParser struct {
+	im   *Importer
+	file string
+	data []byte
+	dec  *json.Decoder
+}
+
+// value reads an object or array of allocations; top is true for the
+// document root, where an "alloc" or "allocations" wrapper is allowed
+func (p *jsonParser) value(top bool) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for p.dec.More() {
+			keyTok, err := p.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			line := p.line(p.dec.InputOffset())
+			if top && (key == "alloc" || key == "allocations") {
+				if err := p.value(false); err != nil {
+					return err
+				}
+				continue
+			}
+			var raw json.RawMessage
+			if err := p.dec.Decode(&raw); err != nil {
+				return err
+			}
+			if err := p.entry(line, key, raw); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for p.dec.More() {
+			line := p.line(p.skipSpace(p.dec.InputOffset()))
+			var raw json.RawMessage
+			if err := p.dec.Decode(&raw); err != nil {
+				return err
+			}
+			if err := p.im.addObject(Source{File: p.file, Line: line}, raw); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("expected an object or array of allocations")
+	}
+	_, err = p.dec.Token() // closing delimiter
+	return err
+}
+
+// entry handles "address": amount and "address": {"balance": amount}
+func (p *jsonParser) entry(line int, address string, raw json.RawMessage) error {
+	src := Source{File: p.file, Line: line}
+	text := fmt.Sprintf("%q: %s", address, compact(raw))
+
+	var fields map[string]interface{}
+	if err := unmarshal(raw, &fields); err == nil {
+		value, column, ok := lookup(fields, p.im.amountNames())
+		if !ok {
+			p.im.reject(src, text, "no amount field")
+			return nil
+		}
+		return p.im.add(src, text, address, value, p.im.unitFor(column))
+	}
+
+	var value interface{}
+	if err := unmarshal(raw, &value); err != nil {
+		p.im.reject(src, text, "invalid value: %v", err)
+		return nil
+	}
+	s, ok := scalar(value)
+	if !ok {
+		p.im.reject(src, text, "amount must be a number or string")
+		return nil
+	}
+	return p.im.add(src, text, address, s, p.im.unitFor(""))
+}
+
+// line returns the 1-based line of a byte offset
+func (p *jsonParser) line(offset int64) int {
+	return bytes.Count(p.data[:offset], []byte{'\n'}) + 1
+}
+
+// skipSpace moves offset past whitespace and separators to the next value
+func (p *jsonParser) skipSpace(offset int64) int64 {
+	for offset < int64(len(p.data)) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// importNDJSON reads one {"address": ..., "amount": ...} object per line
+func (im *Importer) importNDJSON(file string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := im.addObject(Source{File: file, Line: line}, json.RawMessage(text)); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return nil
+}
+
+// addObject imports a JSON object holding an address and an amount field
+func (im *Importer) addObject(src Source, raw json.RawMessage) error {
+	text := compact(raw)
+	var fields map[string]interface{}
+	if err := unmarshal(raw, &fields); err != nil {
+		im.reject(src, text, "invalid JSON object: %v", err)
+		return nil
+	}
+	address, _, ok := lookup(fields, im.addressNames())
+	if !ok {
+		im.reject(src, text, "no address field")
+		return nil
+	}
+	value, column, ok := lookup(fields, im.amountNames())
+	if !ok {
+		im.reject(src, text, "no amount field")
+		return nil
+	}
+	return im.add(src, text, address, value, im.unitFor(column))
+}
+
+func (im *Importer) addressNames() []string {
+	if im.opts.Columns.Address != "" {
+		return []string{im.opts.Columns.Address}
+	}
+	return addressColumns
+}
+
+func (im *Importer) amountNames() []string {
+	if im.opts.Columns.Amount != "" {
+		return []string{im.opts.Columns.Amount}
+	}
+	return amountColumns[FormatCSV]
+}
+
+// unitFor returns the configured unit, or the one implied by a field name
+func (im *Importer) unitFor(column string) Unit {
+	if im.opts.Unit != UnitAuto {
+		return im.opts.Unit
+	}
+	return unitOf(column)
+}
+
+// lookup finds the first of names among fields, case-insensitively, and
+// returns its value as a string
+func lookup(fields map[string]interface{}, names []string) (string, string, bool) {
+	for _, name := range names {
+		for k, v := range fields {
+			if strings.EqualFold(k, name) {
+				s, ok := scalar(v)
+				return s, name, ok
+			}
+		}
+	}
+	return "", "", false
+}
+
+// scalar renders a JSON string or number as text
+func scalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// unmarshal decodes keeping numbers exact
+func unmarshal(raw []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func compact(raw []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
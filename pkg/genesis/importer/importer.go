@@ -0,0 +1,329 @@
+// Package importer reads genesis allocations from CSV, JSON, NDJSON and the
+// scanner's CSV exports into one exact, de-duplicated list.
+//
+// Every allocation remembers the file and line it came from. Rows that cannot
+// be used are kept in a rejected-rows report with the reason, and addresses
+// that appear more than once are merged under an explicit policy.
+package importer
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+// Format is an input file format
+type Format string
+
+const (
+	FormatAuto    Format = "auto"
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatNDJSON  Format = "ndjson"
+	FormatScanner Format = "scanner" // CSV written by the scan commands
+)
+
+// ParseFormat parses a format name
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatCSV, FormatJSON, FormatNDJSON, FormatScanner:
+		return f, nil
+	case "jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("unsupported format: %s", s)
+}
+
+// DetectFormat picks a format from the file extension
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("cannot auto-detect format for file: %s", path)
+}
+
+// Unit is the unit amounts are written in
+type Unit string
+
+const (
+	UnitAuto Unit = ""     // from the column name, else wei
+	UnitLUX  Unit = "lux"  // decimal LUX, e.g. 1234.5
+	UnitNLUX Unit = "nlux" // integer nLUX (10^-9 LUX)
+	UnitWei  Unit = "wei"  // integer wei (10^-18 LUX), decimal or 0x hex
+)
+
+// ParseUnit parses a unit name
+func ParseUnit(s string) (Unit, error) {
+	switch u := Unit(strings.ToLower(s)); u {
+	case UnitAuto, "auto":
+		return UnitAuto, nil
+	case UnitLUX, UnitNLUX, UnitWei:
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown unit %q (expected lux, nlux or wei)", s)
+}
+
+// MergePolicy decides what happens when an address appears more than once
+type MergePolicy string
+
+const (
+	MergeSum   MergePolicy = "sum"   // add the amounts
+	MergeMax   MergePolicy = "max"   // keep the largest amount
+	MergeError MergePolicy = "error" // fail the import
+)
+
+// ParseMergePolicy parses a merge policy name
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	switch p := MergePolicy(strings.ToLower(s)); p {
+	case MergeSum, MergeMax, MergeError:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown merge policy %q (expected sum, max or error)", s)
+}
+
+// Columns names the input columns (CSV) or keys (JSON objects) holding the
+// address and the amount. Empty names are found from common header names.
+type Columns struct {
+	Address string
+	Amount  string
+}
+
+// Options configures an import
+type Options struct {
+	Format       Format
+	Columns      Columns
+	Unit         Unit
+	Denomination amount.Denomination // of the imported amounts; default wei
+	Rounding     amount.Rounding     // when converting to Denomination
+	Merge        MergePolicy         // default sum
+}
+
+// Source is where a row was read
+type Source struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (s Source) String() string {
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// Allocation is an imported address and amount with every row it came from
+type Allocation struct {
+	Address string        `json:"address"`
+	Amount  amount.Amount `json:"amount"`
+	Sources []Source      `json:"sources"`
+}
+
+// Rejected is a row that was not imported
+type Rejected struct {
+	Source
+	Reason string `json:"reason"`
+	Raw    string `json:"raw"`
+}
+
+// DuplicateError is returned under MergeError when an address repeats
+type DuplicateError struct {
+	Address string
+	First   Source
+	Again   Source
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate address %s at %s (first seen at %s)", e.Address, e.Again, e.First)
+}
+
+// Importer accumulates allocations from one or more files
+type Importer struct {
+	opts       Options
+	byAddress  map[string]*Allocation
+	order      []string
+	rejected   []Rejected
+	duplicates int
+}
+
+// New creates an importer
+func New(opts Options) *Importer {
+	if opts.Denomination == 0 {
+		opts.Denomination = amount.Wei
+	}
+	if opts.Merge == "" {
+		opts.Merge = MergeSum
+	}
+	return &Importer{
+		opts:      opts,
+		byAddress: make(map[string]*Allocation),
+	}
+}
+
+// Result is the outcome of an import
+type Result struct {
+	Allocations []Allocation  `json:"allocations"` // in order of first appearance
+	Rejected    []Rejected    `json:"rejected"`
+	Duplicates  int           `json:"duplicates"` // rows merged into an earlier address
+	Total       amount.Amount `json:"total"`
+}
+
+// Result returns the allocations imported so far
+func (im *Importer) Result() *Result {
+	r := &Result{
+		Allocations: make([]Allocation, 0, len(im.order)),
+		Rejected:    append([]Rejected(nil), im.rejected...),
+		Duplicates:  im.duplicates,
+		Total:       amount.Zero(im.opts.Denomination),
+	}
+	for _, addr := range im.order {
+		alloc := im.byAddress[addr]
+		r.Allocations = append(r.Allocations, *alloc)
+		r.Total, _ = r.Total.Add(alloc.Amount)
+	}
+	return r
+}
+
+// reject records a row that could not be imported
+func (im *Importer) reject(src Source, raw, format string, args ...interface{}) {
+	im.rejected = append(im.rejected, Rejected{Source: src, Reason: fmt.Sprintf(format, args...), Raw: raw})
+}
+
+// add validates one row and merges it into the import
+func (im *Importer) add(src Source, raw, address, value string, unit Unit) error {
+	addr, ok := normalizeAddress(address)
+	if !ok {
+		im.reject(src, raw, "invalid address %q", address)
+		return nil
+	}
+	amt, err := im.parseAmount(value, unit)
+	if err != nil {
+		im.reject(src, raw, "invalid amount %q: %v", value, err)
+		return nil
+	}
+
+	return im.merge(Allocation{Address: addr, Amount: amt, Sources: []Source{src}})
+}
+
+// Merge adds the allocations and rejected rows of an earlier import, applying
+// the merge policy to addresses both contain
+func (im *Importer) Merge(r *Result) error {
+	for _, alloc := range r.Allocations {
+		amt, err := alloc.Amount.Convert(im.opts.Denomination, im.opts.Rounding)
+		if err != nil {
+			return fmt.Errorf("%s: %w", alloc.Address, err)
+		}
+		alloc.Amount = amt
+		if err := im.merge(alloc); err != nil {
+			return err
+		}
+	}
+	im.rejected = append(im.rejected, r.Rejected...)
+	return nil
+}
+
+// merge records an allocation, combining it with an earlier one for the same
+// address under the merge policy
+func (im *Importer) merge(alloc Allocation) error {
+	existing, ok := im.byAddress[alloc.Address]
+	if !ok {
+		alloc.Sources = append([]Source(nil), alloc.Sources...)
+		im.byAddress[alloc.Address] = &alloc
+		im.order = append(im.order, alloc.Address)
+		return nil
+	}
+
+	switch im.opts.Merge {
+	case MergeError:
+		return &DuplicateError{Address: alloc.Address, First: existing.Sources[0], Again: alloc.Sources[0]}
+	case MergeMax:
+		if alloc.Amount.Cmp(existing.Amount) > 0 {
+			existing.Amount = alloc.Amount
+		}
+	default:
+		existing.Amount, _ = existing.Amount.Add(alloc.Amount)
+	}
+	existing.Sources = append(existing.Sources, alloc.Sources...)
+	im.duplicates++
+	return nil
+}
+
+// parseAmount reads a value in unit and converts it to the import's
+// denomination
+func (im *Importer) parseAmount(value string, unit Unit) (amount.Amount, error) {
+	// Thousands separators are common in hand-edited files
+	value = strings.NewReplacer(",", "", "_", "").Replace(strings.TrimSpace(value))
+
+	var parsed amount.Amount
+	switch unit {
+	case UnitLUX:
+		// Parse at full wei precision so the rounding policy, not the
+		// parser, decides what happens to digits the denomination can't hold
+		a, err := amount.Parse(value, amount.Wei)
+		if err != nil {
+			return amount.Amount{}, err
+		}
+		parsed = a
+	case UnitNLUX:
+		v, err := parseInteger(value)
+		if err != nil {
+			return amount.Amount{}, err
+		}
+		parsed = amount.New(v, amount.NLUX)
+	default:
+		v, err := parseInteger(value)
+		if err != nil {
+			return amount.Amount{}, err
+		}
+		parsed = amount.New(v, amount.Wei)
+	}
+	return parsed.Convert(im.opts.Denomination, im.opts.Rounding)
+}
+
+// parseInteger parses a non-negative decimal or 0x-prefixed hex integer
+func parseInteger(s string) (*big.Int, error) {
+	var (
+		v  *big.Int
+		ok bool
+	)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		v, ok = new(big.Int).SetString(s[2:], 16)
+	} else {
+		v, ok = new(big.Int).SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("not an integer")
+	}
+	if v.Sign() < 0 {
+		return nil, amount.ErrNegative
+	}
+	return v, nil
+}
+
+var (
+	evmAddress    = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+	bech32Address = regexp.MustCompile(`^[xpc]-[a-z]+1[02-9ac-hj-np-z]{38,}$`)
+)
+
+// normalizeAddress lowercases an EVM (0x...) or chain-prefixed bech32
+// (X-lux1...) address and checks its shape
+func normalizeAddress(address string) (string, bool) {
+	addr := strings.ToLower(strings.TrimSpace(address))
+	if len(addr) == 40 && !strings.Contains(addr, "-") {
+		addr = "0x" + addr
+	}
+	if evmAddress.MatchString(addr) {
+		return addr, true
+	}
+	if bech32Address.MatchString(addr) {
+		return strings.ToUpper(addr[:1]) + addr[1:], true
+	}
+	return "", false
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+const (
+	addrA = "0x1234567890123456789012345678901234567890"
+	addrB = "0x2345678901234567890123456789012345678901"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func importFile(t *testing.T, opts Options, path string) *Result {
+	im := New(opts)
+	require.NoError(t, im.ImportFile(path))
+	return im.Result()
+}
+
+func TestImportCSVRejectsBadRows(t *testing.T) {
+	path := writeFile(t, "airdrop.csv", `# LUX airdrop snapshot
+Snapshot taken at block 100
+rank,address,balance_lux,balance_wei
+1,`+addrA+`,"1,000.5",1000500000000000000000
+2,0xnotanaddress,10,10000000000000000000
+3,`+addrB+`,ten,0
+4
+`)
+	r := importFile(t, Options{Denomination: amount.NLUX}, path)
+
+	require.Len(t, r.Allocations, 1)
+	assert.Equal(t, addrA, r.Allocations[0].Address)
+	assert.Equal(t, "1000500000000", r.Allocations[0].Amount.Value().String())
+	assert.Equal(t, []Source{{File: path, Line: 4}}, r.Allocations[0].Sources)
+
+	require.Len(t, r.Rejected, 3)
+	assert.Equal(t, 5, r.Rejected[0].Line)
+	assert.Contains(t, r.Rejected[0].Reason, "invalid address")
+	assert.Equal(t, 6, r.Rejected[1].Line)
+	assert.Contains(t, r.Rejected[1].Reason, "invalid amount")
+	assert.Equal(t, 7, r.Rejected[2].Line)
+	assert.Contains(t, r.Rejected[2].Reason, "expected at least 3 columns")
+
+	var rerr *RejectedError
+	require.ErrorAs(t, r.Err(), &rerr)
+	assert.Contains(t, rerr.Error(), "3 rows rejected")
+}
+
+func TestImportAirdropSnapshot(t *testing.T) {
+	path := filepath.Join("..", "..", "..", "chaindata", "lux-genesis-7777", "7777-airdrop-96369-mainnet.csv")
+
+	// balance_lux carries 18 decimals, so an exact nLUX import rejects rows
+	exact := importFile(t, Options{Denomination: amount.NLUX}, path)
+	assert.NotEmpty(t, exact.Rejected)
+	assert.Contains(t, exact.Rejected[0].Reason, "without rounding")
+
+	r := importFile(t, Options{Denomination: amount.NLUX, Rounding: amount.RoundDown}, path)
+	assert.Empty(t, r.Rejected)
+	require.Len(t, r.Allocations, 151)
+	assert.Equal(t, "0x9011e888251ab053b7bd1cdb598db4f9ded94714", r.Allocations[0].Address)
+	assert.Equal(t, "1994739905397278564453", r.Allocations[0].Amount.Value().String())
+
+	wei := importFile(t, Options{}, path)
+	assert.Empty(t, wei.Rejected)
+	assert.Equal(t, "1994739905397278564453125000000", wei.Allocations[0].Amount.Value().String())
+}
+
+func TestImportMergePolicies(t *testing.T) {
+	path := writeFile(t, "alloc.csv", "address,amount\n"+addrA+",100\n"+addrB+",5\n"+addrA+",300\n")
+
+	r := importFile(t, Options{Merge: MergeSum}, path)
+	require.Len(t, r.Allocations, 2)
+	assert.Equal(t, "400", r.Allocations[0].Amount.Value().String())
+	assert.Equal(t, []Source{{path, 2}, {path, 4}}, r.Allocations[0].Sources)
+	assert.Equal(t, 1, r.Duplicates)
+	assert.Equal(t, "405", r.Total.Value().String())
+
+	r = importFile(t, Options{Merge: MergeMax}, path)
+	assert.Equal(t, "300", r.Allocations[0].Amount.Value().String())
+
+	im := New(Options{Merge: MergeError})
+	err := im.ImportFile(path)
+	var dup *DuplicateError
+	require.ErrorAs(t, err, &dup)
+	assert.Equal(t, Source{path, 2}, dup.First)
+	assert.Equal(t, Source{path, 4}, dup.Again)
+}
+
+func TestImportAcrossFiles(t *testing.T) {
+	csvPath := writeFile(t, "a.csv", addrA+",100\n")
+	ndjsonPath := writeFile(t, "b.ndjson", `{"address":"`+addrB+`","amount":"7"}
+not json
+{"Address":"`+addrA+`","balance_wei":"0x10"}
+`)
+	im := New(Options{})
+	require.NoError(t, im.ImportFile(csvPath))
+	require.NoError(t, im.ImportFile(ndjsonPath))
+	r := im.Result()
+
+	require.Len(t, r.Allocations, 2)
+	assert.Equal(t, "116", r.Allocations[0].Amount.Value().String())
+	assert.Equal(t, []Source{{csvPath, 1}, {ndjsonPath, 3}}, r.Allocations[0].Sources)
+	require.Len(t, r.Rejected, 1)
+	assert.Equal(t, Source{ndjsonPath, 2}, r.Rejected[0].Source)
+}
+
+func TestImportJSONShapes(t *testing.T) {
+	for name, content := range map[string]string{
+		"map":     "{\n  \"" + addrA + "\": 1000000000000000000000,\n  \"" + addrB + "\": \"5\"\n}",
+		"alloc":   "{\"alloc\": {\n  \"" + addrA[2:] + "\": {\"balance\": \"0x3635c9adc5dea00000\"},\n  \"" + addrB + "\": {\"balance\": \"5\"}\n}}",
+		"objects": "[\n  {\"address\": \"" + addrA + "\", \"balance_lux\": \"1000\"},\n  {\"address\": \"" + addrB + "\", \"amount\": 5}\n]",
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := importFile(t, Options{}, writeFile(t, "alloc.json", content))
+			require.Empty(t, r.Rejected)
+			require.Len(t, r.Allocations, 2)
+			assert.Equal(t, addrA, r.Allocations[0].Address)
+			assert.Equal(t, "1000000000000000000000", r.Allocations[0].Amount.Value().String())
+			assert.Equal(t, 2, r.Allocations[0].Sources[0].Line)
+			assert.Equal(t, 3, r.Allocations[1].Sources[0].Line)
+		})
+	}
+
+	// Malformed JSON fails the whole file
+	assert.Error(t, New(Options{}).ImportFile(writeFile(t, "bad.json", `{"`+addrA+`": `)))
+}
+
+func TestImportScannerExport(t *testing.T) {
+	path := writeFile(t, "holders.csv", `address,asset_type,collection_type,balance_or_count,staking_power_wei,staking_power_token
+`+addrA+`,NFT,Validator,2,2000000000000000000000000,2000000.000000
+`)
+	r := importFile(t, Options{Denomination: amount.NLUX}, path)
+	require.Len(t, r.Allocations, 1)
+	assert.Equal(t, "2000000000000000", r.Allocations[0].Amount.Value().String())
+
+	// An explicit column overrides the defaults
+	r = importFile(t, Options{Columns: Columns{Amount: "balance_or_count"}, Unit: UnitLUX}, path)
+	assert.Equal(t, "2000000000000000000", r.Allocations[0].Amount.Value().String())
+}
+
+func TestWriteRejected(t *testing.T) {
+	r := importFile(t, Options{}, writeFile(t, "alloc.csv", "address,amount\n0xbad,1\n"))
+	out := filepath.Join(t.TempDir(), "rejected.csv")
+	require.NoError(t, r.WriteRejected(out))
+
+	f, err := os.Open(out)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"file", "line", "reason", "raw"}, rows[0])
+	assert.Equal(t, "2", rows[1][1])
+	assert.Equal(t, "0xbad,1", rows[1][3])
+}
+
+func TestParseOptions(t *testing.T) {
+	f, err := ParseFormat("JSONL")
+	require.NoError(t, err)
+	assert.Equal(t, FormatNDJSON, f)
+	_, err = ParseFormat("xml")
+	assert.ErrorContains(t, err, "unsupported format")
+	_, err = DetectFormat("alloc.txt")
+	assert.ErrorContains(t, err, "cannot auto-detect format")
+	_, err = ParseMergePolicy("min")
+	assert.Error(t, err)
+}
+
+func TestMergeResult(t *testing.T) {
+	earlier := importFile(t, Options{}, writeFile(t, "out.json", `[{"address": "`+addrA+`", "amount": "10"}]`))
+
+	im := New(Options{Merge: MergeError})
+	require.NoError(t, im.Merge(earlier))
+	err := im.ImportFile(writeFile(t, "new.csv", addrA+",5\n"))
+	var dup *DuplicateError
+	require.ErrorAs(t, err, &dup)
+	assert.Equal(t, 1, dup.First.Line)
+}
@@ -0,0 +1,54 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// RejectedError reports rows that were not imported
+type RejectedError struct {
+	Rejected []Rejected
+}
+
+func (e *RejectedError) Error() string {
+	first := e.Rejected[0]
+	if len(e.Rejected) == 1 {
+		return fmt.Sprintf("rejected row at %s: %s", first.Source, first.Reason)
+	}
+	return fmt.Sprintf("%d rows rejected, first at %s: %s", len(e.Rejected), first.Source, first.Reason)
+}
+
+// Err returns a *RejectedError if any row was rejected. Callers that cannot
+// show a report use it so bad rows are never dropped silently.
+func (r *Result) Err() error {
+	if len(r.Rejected) == 0 {
+		return nil
+	}
+	return &RejectedError{Rejected: r.Rejected}
+}
+
+// WriteRejected writes the rejected rows as CSV (file, line, reason, raw)
+func (r *Result) WriteRejected(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create rejected-rows report: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"file", "line", "reason", "raw"}); err != nil {
+		return err
+	}
+	for _, rej := range r.Rejected {
+		if err := w.Write([]string{rej.File, strconv.Itoa(rej.Line), rej.Reason, rej.Raw}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write rejected-rows report: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,410 @@
+// Package keystore encrypts validator private keys at rest.
+//
+// A keystore file holds one key - a BLS signer key, a staking TLS key or a
+// secp256k1 key - sealed with AES-256-GCM under a key derived from a
+// password with scrypt or argon2id, in a JSON layout modelled on the
+// Ethereum v3 keystore. The public fields are authenticated with the
+// ciphertext so they cannot be swapped between files. Seal and Open apply
+// the same encryption to other secret files, such as MPC key shares.
+package keystore
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Version is the keystore format written by this build
+const Version = 1
+
+// Extension is the file name suffix of keystore files
+const Extension = ".keystore.json"
+
+// Type is the kind of key in a keystore
+type Type string
+
+const (
+	TypeBLS       Type = "bls"       // 32 byte BLS signer key
+	TypeTLS       Type = "tls"       // PKCS #8 staking TLS key, with its certificate
+	TypeSecp256k1 Type = "secp256k1" // 32 byte secp256k1 key
+)
+
+// KDF is the password hashing function of a keystore
+type KDF string
+
+const (
+	KDFScrypt   KDF = "scrypt"
+	KDFArgon2id KDF = "argon2id"
+)
+
+// ParseKDF parses a KDF name
+func ParseKDF(s string) (KDF, error) {
+	switch KDF(strings.ToLower(s)) {
+	case KDFScrypt, "":
+		return KDFScrypt, nil
+	case KDFArgon2id, "argon2":
+		return KDFArgon2id, nil
+	}
+	return "", fmt.Errorf("unsupported kdf: %s (use scrypt or argon2id)", s)
+}
+
+// KDF parameters for new keystores
+var (
+	ScryptN = 1 << 18
+	ScryptR = 8
+	ScryptP = 1
+
+	Argon2Time    uint32 = 3
+	Argon2Memory  uint32 = 64 * 1024 // KiB
+	Argon2Threads uint8  = 4
+)
+
+// Limits on the KDF parameters read from a file, so that a hostile
+// keystore cannot make Decrypt allocate or compute without bound
+const (
+	MaxKDFMemory  = 1 << 30 // bytes; scrypt uses 128 * n * r
+	MaxScryptP    = 16
+	MaxArgon2Time = 16
+)
+
+const (
+	cipherName = "aes-256-gcm"
+	keyLen     = 32
+)
+
+var (
+	// ErrDecrypt is returned for a wrong password or a tampered keystore
+	ErrDecrypt = errors.New("failed to decrypt keystore: wrong password or corrupted file")
+
+	// ErrEmptyPassword is returned when encrypting without a password
+	ErrEmptyPassword = errors.New("empty keystore password")
+)
+
+// Key is an encrypted key file
+type Key struct {
+	Version     int    `json:"version"`
+	ID          string `json:"id"`
+	Type        Type   `json:"type"`
+	Name        string `json:"name,omitempty"`
+	PublicKey   string `json:"publicKey,omitempty"`   // hex, BLS and secp256k1 keys
+	Certificate string `json:"certificate,omitempty"` // PEM, TLS keys
+	Crypto      Crypto `json:"crypto"`
+}
+
+// Crypto holds the KDF and cipher parameters of a keystore
+type Crypto struct {
+	KDF        KDF       `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+	Cipher     string    `json:"cipher"`
+	Nonce      string    `json:"nonce"`
+	Ciphertext string    `json:"ciphertext"`
+}
+
+// KDFParams are the parameters of either KDF
+type KDFParams struct {
+	DKLen   int    `json:"dklen"`
+	Salt    string `json:"salt"`
+	N       int    `json:"n,omitempty"`
+	R       int    `json:"r,omitempty"`
+	P       int    `json:"p,omitempty"`
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// Encrypt seals a secret under password. The public key or certificate is
+// stored alongside in the clear and checked against the secret where the
+// key type allows it.
+func Encrypt(typ Type, name string, secret []byte, public string, password string, kdf KDF) (*Key, error) {
+	k := &Key{
+		Version: Version,
+		Type:    typ,
+		Name:    name,
+	}
+	switch typ {
+	case TypeTLS:
+		k.Certificate = public
+	case TypeBLS, TypeSecp256k1:
+		k.PublicKey = public
+	default:
+		return nil, fmt.Errorf("unknown key type: %s", typ)
+	}
+	if err := k.check(secret); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to read randomness: %w", err)
+	}
+	k.ID = hex.EncodeToString(id)
+
+	if err := k.seal(secret, password, kdf); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Decrypt opens the keystore and checks the secret against the public key
+// or certificate
+func (k *Key) Decrypt(password string) ([]byte, error) {
+	secret, err := k.Crypto.Open(password, k.additionalData())
+	if err != nil {
+		return nil, err
+	}
+	if err := k.check(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// ChangePassword re-encrypts the keystore under a new password with a fresh
+// salt and nonce, optionally switching the KDF
+func (k *Key) ChangePassword(oldPassword, newPassword string, kdf KDF) error {
+	secret, err := k.Decrypt(oldPassword)
+	if err != nil {
+		return err
+	}
+	return k.seal(secret, newPassword, kdf)
+}
+
+// seal encrypts secret under password, authenticating the public fields
+func (k *Key) seal(secret []byte, password string, kdf KDF) error {
+	c, err := Seal(secret, k.additionalData(), password, kdf)
+	if err != nil {
+		return err
+	}
+	k.Crypto = c
+	return nil
+}
+
+// additionalData binds the public fields to the ciphertext
+func (k *Key) additionalData() []byte {
+	header := *k
+	header.Crypto = Crypto{}
+	data, _ := json.Marshal(header)
+	return data
+}
+
+// Seal derives a key from password with a fresh salt and encrypts secret
+// with it. aad, typically the public fields of the file the secret is
+// stored in, is authenticated with the ciphertext.
+func Seal(secret, aad []byte, password string, kdf KDF) (Crypto, error) {
+	if password == "" {
+		return Crypto{}, ErrEmptyPassword
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return Crypto{}, fmt.Errorf("failed to read randomness: %w", err)
+	}
+
+	c := Crypto{
+		KDF:       kdf,
+		KDFParams: KDFParams{DKLen: keyLen, Salt: hex.EncodeToString(salt)},
+		Cipher:    cipherName,
+	}
+	switch kdf {
+	case KDFScrypt:
+		c.KDFParams.N, c.KDFParams.R, c.KDFParams.P = ScryptN, ScryptR, ScryptP
+	case KDFArgon2id:
+		c.KDFParams.Time, c.KDFParams.Memory, c.KDFParams.Threads = Argon2Time, Argon2Memory, Argon2Threads
+	default:
+		return Crypto{}, fmt.Errorf("unsupported kdf: %s", kdf)
+	}
+
+	aead, err := c.aead(password)
+	if err != nil {
+		return Crypto{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Crypto{}, fmt.Errorf("failed to read randomness: %w", err)
+	}
+	c.Nonce = hex.EncodeToString(nonce)
+	c.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, secret, aad))
+	return c, nil
+}
+
+// Open decrypts a secret sealed with Seal. It returns ErrDecrypt for a wrong
+// password or when the ciphertext or aad were tampered with.
+func (c *Crypto) Open(password string, aad []byte) ([]byte, error) {
+	if c.Cipher != cipherName {
+		return nil, fmt.Errorf("unsupported keystore cipher: %s", c.Cipher)
+	}
+	nonce, err := hex.DecodeString(c.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(c.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	aead, err := c.aead(password)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	secret, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return secret, nil
+}
+
+// aead derives the AES-256-GCM cipher from the password. The KDF parameters
+// come from the file, so they are checked against the limits first.
+func (c *Crypto) aead(password string) (cipher.AEAD, error) {
+	p := c.KDFParams
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	if p.DKLen != keyLen {
+		return nil, fmt.Errorf("unsupported key length: %d", p.DKLen)
+	}
+
+	var key []byte
+	switch c.KDF {
+	case KDFScrypt:
+		if p.N <= 1 || p.R <= 0 || p.P <= 0 || p.P > MaxScryptP ||
+			p.R > MaxKDFMemory/128 || p.N > MaxKDFMemory/(128*p.R) {
+			return nil, fmt.Errorf("scrypt parameters n=%d r=%d p=%d outside the supported limits", p.N, p.R, p.P)
+		}
+		key, err = scrypt.Key([]byte(password), salt, p.N, p.R, p.P, keyLen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+	case KDFArgon2id:
+		if p.Time == 0 || p.Memory == 0 || p.Threads == 0 {
+			return nil, fmt.Errorf("invalid argon2id parameters")
+		}
+		if p.Time > MaxArgon2Time || uint64(p.Memory)*1024 > MaxKDFMemory {
+			return nil, fmt.Errorf("argon2id parameters time=%d memory=%d outside the supported limits", p.Time, p.Memory)
+		}
+		key = argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyLen)
+	default:
+		return nil, fmt.Errorf("unsupported kdf: %s", c.KDF)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// check verifies that a secret is well formed for the key type and matches
+// the stored public key or certificate. BLS public keys are not checked
+// here; see validator.DecryptKeystore.
+func (k *Key) check(secret []byte) error {
+	switch k.Type {
+	case TypeBLS:
+		if len(secret) != 32 {
+			return fmt.Errorf("invalid BLS key length: %d", len(secret))
+		}
+	case TypeSecp256k1:
+		if len(secret) != 32 {
+			return fmt.Errorf("invalid secp256k1 key length: %d", len(secret))
+		}
+		if k.PublicKey != "" {
+			pub := secp256k1.PrivKeyFromBytes(secret).PubKey().SerializeCompressed()
+			if "0x"+hex.EncodeToString(pub) != k.PublicKey {
+				return fmt.Errorf("secp256k1 key does not match its public key")
+			}
+		}
+	case TypeTLS:
+		key, err := x509.ParsePKCS8PrivateKey(secret)
+		if err != nil {
+			return fmt.Errorf("invalid TLS key: %w", err)
+		}
+		if k.Certificate == "" {
+			return nil
+		}
+		block, _ := pem.Decode([]byte(k.Certificate))
+		if block == nil {
+			return fmt.Errorf("invalid certificate PEM")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid certificate: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("unsupported TLS key type %T", key)
+		}
+		pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !pub.Equal(cert.PublicKey) {
+			return fmt.Errorf("TLS key does not match its certificate")
+		}
+	default:
+		return fmt.Errorf("unknown key type: %s", k.Type)
+	}
+	return nil
+}
+
+// Load reads a keystore file
+func Load(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var k Key
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore %s: %w", path, err)
+	}
+	if k.Version != Version {
+		return nil, fmt.Errorf("unsupported keystore version %d in %s", k.Version, path)
+	}
+	return &k, nil
+}
+
+// Save writes the keystore with owner-only permissions
+func (k *Key) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return nil
+}
+
+// Find returns the keystore files in dir, or dir itself if it is a file
+func Find(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{dir}, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Extension))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no keystore files in %s", dir)
+	}
+	return paths, nil
+}
@@ -0,0 +1,155 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Keep the tests fast
+	ScryptN = 1 << 10
+	Argon2Time, Argon2Memory, Argon2Threads = 1, 1024, 1
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	secret := priv.Serialize()
+	public := "0x" + hex.EncodeToString(priv.PubKey().SerializeCompressed())
+
+	for _, kdf := range []KDF{KDFScrypt, KDFArgon2id} {
+		k, err := Encrypt(TypeSecp256k1, "reward-0", secret, public, "hunter2", kdf)
+		require.NoError(t, err, kdf)
+		assert.Equal(t, kdf, k.Crypto.KDF)
+
+		path := filepath.Join(t.TempDir(), "reward"+Extension)
+		require.NoError(t, k.Save(path))
+		loaded, err := Load(path)
+		require.NoError(t, err)
+
+		got, err := loaded.Decrypt("hunter2")
+		require.NoError(t, err)
+		assert.Equal(t, secret, got)
+
+		_, err = loaded.Decrypt("wrong")
+		assert.ErrorIs(t, err, ErrDecrypt)
+
+		// The public fields are authenticated
+		loaded.Name = "reward-1"
+		_, err = loaded.Decrypt("hunter2")
+		assert.ErrorIs(t, err, ErrDecrypt)
+	}
+
+	_, err = Encrypt(TypeSecp256k1, "", secret, public, "", KDFScrypt)
+	assert.ErrorIs(t, err, ErrEmptyPassword)
+	_, err = Encrypt(TypeSecp256k1, "", secret, "0x02", "pw", KDFScrypt)
+	assert.ErrorContains(t, err, "does not match")
+	_, err = Encrypt(TypeBLS, "", secret[:31], "", "pw", KDFScrypt)
+	assert.Error(t, err)
+}
+
+func TestTLSKeystore(t *testing.T) {
+	certPEM, keyDER := testCert(t)
+	k, err := Encrypt(TypeTLS, "staker", keyDER, string(certPEM), "pw", KDFScrypt)
+	require.NoError(t, err)
+	got, err := k.Decrypt("pw")
+	require.NoError(t, err)
+	assert.Equal(t, keyDER, got)
+
+	// A key that does not belong to the certificate is rejected
+	_, otherKey := testCert(t)
+	_, err = Encrypt(TypeTLS, "staker", otherKey, string(certPEM), "pw", KDFScrypt)
+	assert.ErrorContains(t, err, "does not match its certificate")
+}
+
+func TestChangePassword(t *testing.T) {
+	secret := make([]byte, 32)
+	secret[31] = 1
+	k, err := Encrypt(TypeBLS, "signer", secret, "0xabcd", "old", KDFScrypt)
+	require.NoError(t, err)
+	salt := k.Crypto.KDFParams.Salt
+
+	assert.ErrorIs(t, k.ChangePassword("wrong", "new", KDFScrypt), ErrDecrypt)
+	require.NoError(t, k.ChangePassword("old", "new", KDFArgon2id))
+	assert.NotEqual(t, salt, k.Crypto.KDFParams.Salt)
+	assert.Equal(t, KDFArgon2id, k.Crypto.KDF)
+
+	_, err = k.Decrypt("old")
+	assert.ErrorIs(t, err, ErrDecrypt)
+	got, err := k.Decrypt("new")
+	require.NoError(t, err)
+	assert.Equal(t, secret, got)
+}
+
+func TestKDFLimits(t *testing.T) {
+	secret := make([]byte, 32)
+	secret[31] = 1
+
+	// Parameters read from a file are bounded before any key is derived
+	scryptKey, err := Encrypt(TypeBLS, "signer", secret, "", "pw", KDFScrypt)
+	require.NoError(t, err)
+	for _, params := range []struct{ n, r, p int }{
+		{1 << 30, 8, 1},
+		{1 << 10, 1 << 30, 1},
+		{1 << 10, 8, 1 << 20},
+		{0, 8, 1},
+	} {
+		k := *scryptKey
+		k.Crypto.KDFParams.N, k.Crypto.KDFParams.R, k.Crypto.KDFParams.P = params.n, params.r, params.p
+		_, err := k.Decrypt("pw")
+		assert.ErrorContains(t, err, "outside the supported limits", "%+v", params)
+	}
+
+	argonKey, err := Encrypt(TypeBLS, "signer", secret, "", "pw", KDFArgon2id)
+	require.NoError(t, err)
+	k := *argonKey
+	k.Crypto.KDFParams.Memory = 1 << 31
+	_, err = k.Decrypt("pw")
+	assert.ErrorContains(t, err, "outside the supported limits")
+	k = *argonKey
+	k.Crypto.KDFParams.Time = 1 << 20
+	_, err = k.Decrypt("pw")
+	assert.ErrorContains(t, err, "outside the supported limits")
+
+	got, err := argonKey.Decrypt("pw")
+	require.NoError(t, err)
+	assert.Equal(t, secret, got)
+}
+
+func TestParseKDF(t *testing.T) {
+	kdf, err := ParseKDF("")
+	require.NoError(t, err)
+	assert.Equal(t, KDFScrypt, kdf)
+	kdf, err = ParseKDF("Argon2id")
+	require.NoError(t, err)
+	assert.Equal(t, KDFArgon2id, kdf)
+	_, err = ParseKDF("pbkdf2")
+	assert.Error(t, err)
+}
+
+func testCert(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(1<<32, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyDER
+}
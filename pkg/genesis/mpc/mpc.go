@@ -0,0 +1,246 @@
+// Package mpc generates t-of-n secp256k1 threshold keys for the B-Chain and
+// M-Chain signer sets.
+//
+// Keys are produced either by a trusted dealer or by a simulated
+// distributed key generation (every participant deals a polynomial and the
+// shares are summed). Both use Shamir sharing with Feldman commitments, so
+// every share can be checked against the public commitments.
+package mpc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/sha3"
+)
+
+// Mode selects how the key is generated
+type Mode string
+
+const (
+	// TrustedDealer samples one polynomial; the dealer briefly knows the key
+	TrustedDealer Mode = "dealer"
+
+	// SimulatedDKG runs a Feldman DKG locally: each participant deals and
+	// verifies, and the key is the sum of every dealer's secret
+	SimulatedDKG Mode = "dkg"
+)
+
+// ParseMode parses a key generation mode name
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case TrustedDealer, SimulatedDKG:
+		return Mode(s), nil
+	}
+	return "", fmt.Errorf("unknown MPC key generation mode %q (expected dealer or dkg)", s)
+}
+
+// ErrInvalidShare is returned when a share does not match the commitments
+var ErrInvalidShare = errors.New("share does not match the commitments")
+
+// Share is one participant's secret share; Index is its x coordinate,
+// starting at 1
+type Share struct {
+	Index  uint32
+	secret secp256k1.ModNScalar
+}
+
+// PublicKey returns the share's public key, secret * G
+func (s *Share) PublicKey() *secp256k1.PublicKey {
+	return scalarBaseMult(&s.secret)
+}
+
+// KeySet is the result of a key generation: the aggregate public key, the
+// commitments to the sharing polynomial and every participant's share
+type KeySet struct {
+	Mode         Mode
+	Threshold    int
+	Participants int
+	PublicKey    *secp256k1.PublicKey
+	Commitments  []*secp256k1.PublicKey
+	Shares       []Share
+}
+
+// Generate creates a threshold-of-participants key. A nil random uses
+// crypto/rand.
+func Generate(mode Mode, threshold, participants int, random io.Reader) (*KeySet, error) {
+	if participants < 1 {
+		return nil, fmt.Errorf("need at least one participant")
+	}
+	if threshold < 1 || threshold > participants {
+		return nil, fmt.Errorf("threshold %d must be between 1 and %d participants", threshold, participants)
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	dealers := 1
+	switch mode {
+	case TrustedDealer:
+	case SimulatedDKG:
+		dealers = participants
+	default:
+		return nil, fmt.Errorf("unknown MPC key generation mode %q", mode)
+	}
+
+	shares := make([]Share, participants)
+	for i := range shares {
+		shares[i].Index = uint32(i + 1)
+	}
+	commitments := make([]secp256k1.JacobianPoint, threshold)
+
+	for d := 0; d < dealers; d++ {
+		poly, err := randomPolynomial(threshold, random)
+		if err != nil {
+			return nil, err
+		}
+		dealt := commit(poly)
+		for i := range shares {
+			value := evaluate(poly, shares[i].Index)
+			// Each participant checks what it was dealt before accepting it
+			if !verify(shares[i].Index, &value, dealt) {
+				return nil, fmt.Errorf("dealer %d: share %d: %w", d+1, shares[i].Index, ErrInvalidShare)
+			}
+			shares[i].secret.Add(&value)
+		}
+		for j := range commitments {
+			commitments[j] = add(&commitments[j], &dealt[j])
+		}
+	}
+
+	ks := &KeySet{
+		Mode:         mode,
+		Threshold:    threshold,
+		Participants: participants,
+		Commitments:  make([]*secp256k1.PublicKey, threshold),
+		Shares:       shares,
+	}
+	for j := range commitments {
+		ks.Commitments[j] = toPublicKey(&commitments[j])
+	}
+	ks.PublicKey = ks.Commitments[0]
+	return ks, nil
+}
+
+// VerifyShare checks a share against the key set's commitments
+func (ks *KeySet) VerifyShare(share *Share) error {
+	points := make([]secp256k1.JacobianPoint, len(ks.Commitments))
+	for j, c := range ks.Commitments {
+		c.AsJacobian(&points[j])
+	}
+	if !verify(share.Index, &share.secret, points) {
+		return fmt.Errorf("share %d: %w", share.Index, ErrInvalidShare)
+	}
+	return nil
+}
+
+// Address returns the Ethereum address of a public key, as used by bridge
+// contracts to check threshold signatures
+func Address(pub *secp256k1.PublicKey) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pub.SerializeUncompressed()[1:])
+	return fmt.Sprintf("0x%x", h.Sum(nil)[12:])
+}
+
+// randomPolynomial returns n random non-zero coefficients, a polynomial of
+// degree n-1
+func randomPolynomial(n int, random io.Reader) ([]secp256k1.ModNScalar, error) {
+	poly := make([]secp256k1.ModNScalar, n)
+	var buf [32]byte
+	for i := range poly {
+		for {
+			if _, err := io.ReadFull(random, buf[:]); err != nil {
+				return nil, fmt.Errorf("failed to read randomness: %w", err)
+			}
+			if overflow := poly[i].SetByteSlice(buf[:]); !overflow && !poly[i].IsZero() {
+				break
+			}
+		}
+	}
+	return poly, nil
+}
+
+// evaluate returns poly(x) by Horner's rule
+func evaluate(poly []secp256k1.ModNScalar, x uint32) secp256k1.ModNScalar {
+	var xs, result secp256k1.ModNScalar
+	xs.SetInt(x)
+	for i := len(poly) - 1; i >= 0; i-- {
+		result.Mul(&xs).Add(&poly[i])
+	}
+	return result
+}
+
+// commit returns the Feldman commitments coefficient * G
+func commit(poly []secp256k1.ModNScalar) []secp256k1.JacobianPoint {
+	points := make([]secp256k1.JacobianPoint, len(poly))
+	for i := range poly {
+		secp256k1.ScalarBaseMultNonConst(&poly[i], &points[i])
+	}
+	return points
+}
+
+// verify checks value * G == sum(commitments[j] * x^j)
+func verify(x uint32, value *secp256k1.ModNScalar, commitments []secp256k1.JacobianPoint) bool {
+	var lhs, rhs, term secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(value, &lhs)
+
+	var xs, power secp256k1.ModNScalar
+	xs.SetInt(x)
+	power.SetInt(1)
+	for j := range commitments {
+		secp256k1.ScalarMultNonConst(&power, &commitments[j], &term)
+		rhs = add(&rhs, &term)
+		power.Mul(&xs)
+	}
+
+	lhs.ToAffine()
+	rhs.ToAffine()
+	return lhs.X.Equals(&rhs.X) && lhs.Y.Equals(&rhs.Y)
+}
+
+// interpolate recovers the secret, poly(0), from threshold shares
+func interpolate(shares []Share) secp256k1.ModNScalar {
+	var secret secp256k1.ModNScalar
+	for i := range shares {
+		var num, den, xi secp256k1.ModNScalar
+		num.SetInt(1)
+		den.SetInt(1)
+		xi.SetInt(shares[i].Index)
+		for j := range shares {
+			if i == j {
+				continue
+			}
+			var xj, diff secp256k1.ModNScalar
+			xj.SetInt(shares[j].Index)
+			num.Mul(&xj)
+			diff.NegateVal(&xi).Add(&xj)
+			den.Mul(&diff)
+		}
+		term := shares[i].secret
+		term.Mul(&num).Mul(den.InverseNonConst())
+		secret.Add(&term)
+	}
+	return secret
+}
+
+// add returns p1 + p2; AddNonConst must not write to one of its inputs
+func add(p1, p2 *secp256k1.JacobianPoint) secp256k1.JacobianPoint {
+	var sum secp256k1.JacobianPoint
+	secp256k1.AddNonConst(p1, p2, &sum)
+	return sum
+}
+
+func scalarBaseMult(k *secp256k1.ModNScalar) *secp256k1.PublicKey {
+	var p secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(k, &p)
+	return toPublicKey(&p)
+}
+
+func toPublicKey(p *secp256k1.JacobianPoint) *secp256k1.PublicKey {
+	affine := *p
+	affine.ToAffine()
+	return secp256k1.NewPublicKey(&affine.X, &affine.Y)
+}
@@ -0,0 +1,109 @@
+package mpc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+)
+
+func init() {
+	// Keep scrypt cheap in tests
+	keystore.ScryptN = 1 << 10
+}
+
+func TestGenerateReconstructs(t *testing.T) {
+	for _, mode := range []Mode{TrustedDealer, SimulatedDKG} {
+		t.Run(string(mode), func(t *testing.T) {
+			ks, err := Generate(mode, 3, 5, nil)
+			require.NoError(t, err)
+			require.Len(t, ks.Shares, 5)
+			require.Len(t, ks.Commitments, 3)
+
+			for i := range ks.Shares {
+				require.NoError(t, ks.VerifyShare(&ks.Shares[i]))
+			}
+
+			// Any threshold shares recover the aggregate key
+			for _, subset := range [][]int{{0, 1, 2}, {1, 3, 4}, {0, 2, 4}} {
+				shares := make([]Share, len(subset))
+				for i, idx := range subset {
+					shares[i] = ks.Shares[idx]
+				}
+				secret := interpolate(shares)
+				assert.True(t, scalarBaseMult(&secret).IsEqual(ks.PublicKey))
+			}
+
+			// Fewer do not
+			secret := interpolate(ks.Shares[:2])
+			assert.False(t, scalarBaseMult(&secret).IsEqual(ks.PublicKey))
+		})
+	}
+}
+
+func TestGenerateRejectsBadThreshold(t *testing.T) {
+	_, err := Generate(TrustedDealer, 6, 5, nil)
+	assert.Error(t, err)
+	_, err = Generate(TrustedDealer, 0, 5, nil)
+	assert.Error(t, err)
+	_, err = Generate("shamir", 2, 3, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifyShareDetectsTampering(t *testing.T) {
+	ks, err := Generate(TrustedDealer, 2, 3, nil)
+	require.NoError(t, err)
+
+	share := ks.Shares[0]
+	share.Index = 2
+	assert.ErrorIs(t, ks.VerifyShare(&share), ErrInvalidShare)
+}
+
+func TestShareFiles(t *testing.T) {
+	ks, err := Generate(SimulatedDKG, 2, 3, nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	ids := []string{"mpc-node-0", "mpc-node-1", "mpc-node-2"}
+	paths, err := ks.WriteShareFiles(dir, ids, []string{"alpha", "bravo", "charlie"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "mpc-node-1.json"), paths[1])
+
+	f, err := LoadShareFile(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, EncodePublicKey(ks.PublicKey), f.PublicKey)
+
+	share, err := f.Decrypt("bravo")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Shares[1].Index, share.Index)
+	assert.True(t, share.PublicKey().IsEqual(ks.Shares[1].PublicKey()))
+
+	_, err = f.Decrypt("alpha")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	// The public fields are authenticated
+	f.Index = 3
+	_, err = f.Decrypt("bravo")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestLoadShareFileVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mpc-node-0.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "id": "mpc-node-0", "index": 1,
+		"crypto": {"kdf": "scrypt", "n": 262144, "r": 8, "p": 1, "salt": "00", "cipher": "aes-256-gcm"}}`), 0600))
+
+	_, err := LoadShareFile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version 1 encryption layout")
+}
+
+func TestAddress(t *testing.T) {
+	// Private key 1 is the generator, whose address is well known
+	ks := &KeySet{Shares: []Share{{Index: 1}}}
+	ks.Shares[0].secret.SetInt(1)
+	assert.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", Address(ks.Shares[0].PublicKey()))
+}
@@ -0,0 +1,169 @@
+package mpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+)
+
+// ShareFileVersion is the share file format written by this build. Version
+// 1 files kept their scrypt parameters in flat fields instead of the
+// keystore's kdfparams and are no longer read.
+const ShareFileVersion = 2
+
+// ErrDecrypt is returned for a wrong password or a tampered share file
+var ErrDecrypt = keystore.ErrDecrypt
+
+// ShareFile is a participant's key share encrypted with its password, sealed
+// the same way as a validator keystore (scrypt and AES-256-GCM, with the
+// keystore's KDF parameters and limits). The public fields are
+// authenticated with the ciphertext, so they cannot be swapped between
+// files.
+type ShareFile struct {
+	Version      int             `json:"version"`
+	ID           string          `json:"id"`
+	Index        uint32          `json:"index"`
+	Threshold    int             `json:"threshold"`
+	Participants int             `json:"participants"`
+	Mode         Mode            `json:"mode"`
+	PublicKey    string          `json:"publicKey"`   // aggregate key, compressed
+	PublicShare  string          `json:"publicShare"` // this share's public key, compressed
+	Commitments  []string        `json:"commitments"`
+	Crypto       keystore.Crypto `json:"crypto"`
+}
+
+// EncryptShare seals the share at index i of the key set under password
+func (ks *KeySet) EncryptShare(i int, id, password string) (*ShareFile, error) {
+	if password == "" {
+		return nil, fmt.Errorf("empty password for share %s", id)
+	}
+	share := &ks.Shares[i]
+
+	f := &ShareFile{
+		Version:      ShareFileVersion,
+		ID:           id,
+		Index:        share.Index,
+		Threshold:    ks.Threshold,
+		Participants: ks.Participants,
+		Mode:         ks.Mode,
+		PublicKey:    EncodePublicKey(ks.PublicKey),
+		PublicShare:  EncodePublicKey(share.PublicKey()),
+		Commitments:  make([]string, len(ks.Commitments)),
+	}
+	for j, c := range ks.Commitments {
+		f.Commitments[j] = EncodePublicKey(c)
+	}
+
+	secret := share.secret.Bytes()
+	c, err := keystore.Seal(secret[:], f.additionalData(), password, keystore.KDFScrypt)
+	if err != nil {
+		return nil, err
+	}
+	f.Crypto = c
+	return f, nil
+}
+
+// Decrypt opens the share and checks it against the file's public share and
+// commitments
+func (f *ShareFile) Decrypt(password string) (*Share, error) {
+	plain, err := f.Crypto.Open(password, f.additionalData())
+	if err != nil {
+		return nil, err
+	}
+
+	share := &Share{Index: f.Index}
+	if overflow := share.secret.SetByteSlice(plain); overflow {
+		return nil, ErrDecrypt
+	}
+	if EncodePublicKey(share.PublicKey()) != f.PublicShare {
+		return nil, fmt.Errorf("share %s does not match its public share", f.ID)
+	}
+
+	ks := &KeySet{Commitments: make([]*secp256k1.PublicKey, len(f.Commitments))}
+	for j, c := range f.Commitments {
+		if ks.Commitments[j], err = DecodePublicKey(c); err != nil {
+			return nil, fmt.Errorf("invalid commitment %d: %w", j, err)
+		}
+	}
+	if err := ks.VerifyShare(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// additionalData binds the public fields to the ciphertext
+func (f *ShareFile) additionalData() []byte {
+	header := *f
+	header.Crypto = keystore.Crypto{}
+	data, _ := json.Marshal(header)
+	return data
+}
+
+// WriteShareFiles encrypts every share to <dir>/<id>.json, share i under
+// passwords[i], and returns the paths
+func (ks *KeySet) WriteShareFiles(dir string, ids, passwords []string) ([]string, error) {
+	if len(ids) != len(ks.Shares) || len(passwords) != len(ks.Shares) {
+		return nil, fmt.Errorf("need %d ids and passwords, got %d and %d", len(ks.Shares), len(ids), len(passwords))
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create share directory: %w", err)
+	}
+
+	paths := make([]string, len(ks.Shares))
+	for i := range ks.Shares {
+		f, err := ks.EncryptShare(i, ids[i], passwords[i])
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = filepath.Join(dir, ids[i]+".json")
+		if err := os.WriteFile(paths[i], data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write share %s: %w", ids[i], err)
+		}
+	}
+	return paths, nil
+}
+
+// LoadShareFile reads an encrypted share file
+func LoadShareFile(path string) (*ShareFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read share file: %w", err)
+	}
+	var f ShareFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse share file: %w", err)
+	}
+	switch f.Version {
+	case ShareFileVersion:
+	case 1:
+		return nil, fmt.Errorf("share file %s uses the version 1 encryption layout, which is no longer supported; regenerate the shares", path)
+	default:
+		return nil, fmt.Errorf("unsupported share file version %d", f.Version)
+	}
+	return &f, nil
+}
+
+// EncodePublicKey encodes a public key compressed, 0x-prefixed
+func EncodePublicKey(pub *secp256k1.PublicKey) string {
+	return "0x" + hex.EncodeToString(pub.SerializeCompressed())
+}
+
+// DecodePublicKey parses a key written by EncodePublicKey
+func DecodePublicKey(s string) (*secp256k1.PublicKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.ParsePubKey(b)
+}
@@ -0,0 +1,322 @@
+package genesis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+// ProjectionPoint is the supply split at a point in time. All amounts are
+// wei so P/X (nLUX) and C-Chain balances add up exactly.
+type ProjectionPoint struct {
+	Time        uint64        `json:"time"`
+	Circulating amount.Amount `json:"circulating"`
+	Locked      amount.Amount `json:"locked"` // waiting for a locktime
+	Staked      amount.Amount `json:"staked"` // bonded by an initial staker
+}
+
+// SupplyProjection is the circulating, locked and staked supply of a genesis
+// from its start time until the last unlock
+type SupplyProjection struct {
+	StartTime uint64            `json:"startTime"`
+	Total     amount.Amount     `json:"total"`
+	Points    []ProjectionPoint `json:"points"` // one per change, from StartTime
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// tranche is an amount that is staked until stakedUntil, then locked until
+// lockedUntil, then circulating
+type tranche struct {
+	amount      *big.Int // wei
+	stakedUntil uint64
+	lockedUntil uint64
+}
+
+// ProjectSupply projects the supply of a built genesis over time.
+//
+// Initial amounts and the C-Chain alloc circulate from StartTime. Unlock
+// schedule entries are locked until their locktime. The unlock schedules of
+// InitialStakedFunds addresses are split evenly across the initial stakers
+// and bonded until each staker's end time (StartTime + InitialStakeDuration,
+// less InitialStakeDurationOffset per earlier staker); they circulate once
+// both the stake has ended and the locktime has passed.
+func ProjectSupply(g *MainGenesis) (*SupplyProjection, error) {
+	p := &SupplyProjection{StartTime: g.StartTime}
+	warn := func(format string, args ...interface{}) {
+		p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+	}
+	nluxToWei := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(amount.Wei-amount.NLUX)), nil)
+	wei := func(nlux uint64) *big.Int {
+		return new(big.Int).Mul(new(big.Int).SetUint64(nlux), nluxToWei)
+	}
+
+	stakeEnds := stakerEndTimes(g)
+	for i, end := range stakeEnds {
+		if end <= g.StartTime {
+			warn("initialStakers[%d]: stake ends at %s, not after the start time", i, formatUnix(end))
+		}
+	}
+	stakeEnd := g.StartTime + g.InitialStakeDuration
+
+	staked := make(map[string]bool, len(g.InitialStakedFunds))
+	for _, addr := range g.InitialStakedFunds {
+		staked[luxAddressKey(addr)] = true
+	}
+	if len(staked) > 0 && len(stakeEnds) == 0 {
+		warn("initialStakedFunds is set but there are no initial stakers; the funds are treated as locked")
+	}
+
+	var tranches []tranche
+	found := make(map[string]bool)
+	for i, alloc := range g.Allocations {
+		who := alloc.ETHAddr
+		if who == "" {
+			who = alloc.LUXAddr
+		}
+		isStaked := staked[luxAddressKey(alloc.LUXAddr)] && len(stakeEnds) > 0
+		if isStaked {
+			found[luxAddressKey(alloc.LUXAddr)] = true
+			if len(alloc.UnlockSchedule) == 0 {
+				warn("allocations[%d] (%s): in initialStakedFunds but has no unlock schedule to stake", i, who)
+			}
+		}
+
+		tranches = append(tranches, tranche{amount: wei(alloc.InitialAmount)})
+		for j, locked := range alloc.UnlockSchedule {
+			if locked.Locktime < g.StartTime {
+				warn("allocations[%d] (%s): unlockSchedule[%d] locktime %s is before the start time %s",
+					i, who, j, formatUnix(locked.Locktime), formatUnix(g.StartTime))
+			}
+			if !isStaked {
+				tranches = append(tranches, tranche{amount: wei(locked.Amount), lockedUntil: locked.Locktime})
+				continue
+			}
+			if locked.Locktime > stakeEnd {
+				warn("allocations[%d] (%s): unlockSchedule[%d] locktime %s is beyond the initial stake duration (ends %s)",
+					i, who, j, formatUnix(locked.Locktime), formatUnix(stakeEnd))
+			}
+			// Split across the stakers, remainder to the last
+			total := wei(locked.Amount)
+			share := new(big.Int).Quo(total, big.NewInt(int64(len(stakeEnds))))
+			rest := new(big.Int).Set(total)
+			for k, end := range stakeEnds {
+				part := share
+				if k == len(stakeEnds)-1 {
+					part = rest
+				}
+				rest = new(big.Int).Sub(rest, part)
+				tranches = append(tranches, tranche{amount: part, stakedUntil: end, lockedUntil: locked.Locktime})
+			}
+		}
+	}
+	for addr := range staked {
+		if !found[addr] && len(stakeEnds) > 0 {
+			warn("initialStakedFunds: %s has no allocation", addr)
+		}
+	}
+
+	cGenesis, err := decodeCChainGenesis(g.CChainGenesis)
+	if err != nil {
+		return nil, err
+	}
+	alloc, _ := cGenesis["alloc"].(map[string]interface{})
+	for addr, v := range alloc {
+		account, err := parseAllocAccount(addr, v)
+		if err != nil {
+			return nil, err
+		}
+		tranches = append(tranches, tranche{amount: account.balance})
+	}
+
+	p.project(tranches)
+	return p, nil
+}
+
+// project turns tranches into a series of points, one per time the split
+// changes
+func (p *SupplyProjection) project(tranches []tranche) {
+	const (
+		circulating = iota
+		locked
+		staked
+	)
+	deltas := make(map[uint64]*[3]big.Int)
+	move := func(t uint64, a *big.Int, from, to int) {
+		d, ok := deltas[t]
+		if !ok {
+			d = new([3]big.Int)
+			deltas[t] = d
+		}
+		if from >= 0 {
+			d[from].Sub(&d[from], a)
+		}
+		d[to].Add(&d[to], a)
+	}
+
+	total := new(big.Int)
+	for _, tr := range tranches {
+		total.Add(total, tr.amount)
+		stakedUntil := max(tr.stakedUntil, p.StartTime)
+		lockedUntil := max(tr.lockedUntil, stakedUntil)
+		switch {
+		case stakedUntil > p.StartTime:
+			move(p.StartTime, tr.amount, -1, staked)
+			if lockedUntil > stakedUntil {
+				move(stakedUntil, tr.amount, staked, locked)
+				move(lockedUntil, tr.amount, locked, circulating)
+			} else {
+				move(stakedUntil, tr.amount, staked, circulating)
+			}
+		case lockedUntil > p.StartTime:
+			move(p.StartTime, tr.amount, -1, locked)
+			move(lockedUntil, tr.amount, locked, circulating)
+		default:
+			move(p.StartTime, tr.amount, -1, circulating)
+		}
+	}
+	p.Total = amount.New(total, amount.Wei)
+
+	times := make([]uint64, 0, len(deltas))
+	for t := range deltas {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	var running [3]big.Int
+	for _, t := range times {
+		d := deltas[t]
+		for i := range running {
+			running[i].Add(&running[i], &d[i])
+		}
+		p.Points = append(p.Points, ProjectionPoint{
+			Time:        t,
+			Circulating: amount.New(&running[circulating], amount.Wei),
+			Locked:      amount.New(&running[locked], amount.Wei),
+			Staked:      amount.New(&running[staked], amount.Wei),
+		})
+	}
+}
+
+// At returns the supply split in effect at t; times before the start return
+// the split at the start
+func (p *SupplyProjection) At(t uint64) ProjectionPoint {
+	i := sort.Search(len(p.Points), func(i int) bool { return p.Points[i].Time > t })
+	if i == 0 {
+		if len(p.Points) == 0 {
+			zero := amount.Zero(amount.Wei)
+			return ProjectionPoint{Time: t, Circulating: zero, Locked: zero, Staked: zero}
+		}
+		i = 1
+	}
+	point := p.Points[i-1]
+	point.Time = t
+	return point
+}
+
+// stakerEndTimes returns when each initial staker's stake ends
+func stakerEndTimes(g *MainGenesis) []uint64 {
+	ends := make([]uint64, len(g.InitialStakers))
+	offset := uint64(0)
+	for i := range g.InitialStakers {
+		end := g.StartTime + g.InitialStakeDuration
+		if offset < end {
+			end -= offset
+		} else {
+			end = 0
+		}
+		ends[i] = end
+		offset += g.InitialStakeDurationOffset
+	}
+	return ends
+}
+
+// luxAddressKey drops the chain prefix so X-lux1... and P-lux1... match
+func luxAddressKey(addr string) string {
+	if i := strings.IndexByte(addr, '-'); i >= 0 {
+		addr = addr[i+1:]
+	}
+	return strings.ToLower(addr)
+}
+
+func formatUnix(t uint64) string {
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
+
+// WriteCSV writes the series as CSV with LUX amounts
+func (p *SupplyProjection) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "time", "circulating_lux", "locked_lux", "staked_lux", "circulating_percent"})
+	for _, point := range p.Points {
+		cw.Write([]string{
+			formatUnix(point.Time),
+			strconv.FormatUint(point.Time, 10),
+			luxString(point.Circulating),
+			luxString(point.Locked),
+			luxString(point.Staked),
+			p.percent(point.Circulating),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Print writes the series in the command output style
+func (p *SupplyProjection) Print(w io.Writer) {
+	fmt.Fprintln(w, "=== Supply Projection ===")
+	fmt.Fprintf(w, "Start:   %s\n", formatUnix(p.StartTime))
+	fmt.Fprintf(w, "Total:   %s\n\n", p.Total)
+	fmt.Fprintf(w, "%-20s %28s %28s %28s %8s\n", "Date", "Circulating", "Locked", "Staked", "Circ %")
+	fmt.Fprintln(w, strings.Repeat("-", 116))
+	for _, point := range p.Points {
+		p.printPoint(w, point)
+	}
+	p.PrintWarnings(w)
+}
+
+// PrintPoint writes a single point, e.g. the answer for one date
+func (p *SupplyProjection) PrintPoint(w io.Writer, point ProjectionPoint) {
+	fmt.Fprintf(w, "=== Supply at %s ===\n", formatUnix(point.Time))
+	fmt.Fprintf(w, "Circulating: %s (%s)\n", point.Circulating, p.percent(point.Circulating))
+	fmt.Fprintf(w, "Locked:      %s\n", point.Locked)
+	fmt.Fprintf(w, "Staked:      %s\n", point.Staked)
+	fmt.Fprintf(w, "Total:       %s\n", p.Total)
+	p.PrintWarnings(w)
+}
+
+// PrintWarnings writes the schedule problems found, if any
+func (p *SupplyProjection) PrintWarnings(w io.Writer) {
+	if len(p.Warnings) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n⚠️  %d schedule warnings:\n", len(p.Warnings))
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "  - %s\n", warning)
+	}
+}
+
+func (p *SupplyProjection) printPoint(w io.Writer, point ProjectionPoint) {
+	fmt.Fprintf(w, "%-20s %28s %28s %28s %8s\n",
+		formatUnix(point.Time), luxString(point.Circulating), luxString(point.Locked), luxString(point.Staked), p.percent(point.Circulating))
+}
+
+// percent formats a as a share of the total with two decimals
+func (p *SupplyProjection) percent(a amount.Amount) string {
+	if p.Total.Sign() == 0 {
+		return "0.00%"
+	}
+	bp := new(big.Int).Mul(a.Value(), big.NewInt(10000))
+	bp.Quo(bp, p.Total.Value())
+	return fmt.Sprintf("%d.%02d%%", bp.Int64()/100, bp.Int64()%100)
+}
+
+// luxString formats an amount as a plain LUX number
+func luxString(a amount.Amount) string {
+	return strings.TrimSuffix(a.String(), " LUX")
+}
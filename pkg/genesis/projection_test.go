@@ -0,0 +1,66 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/genesis"
+)
+
+func TestProjectSupply(t *testing.T) {
+	const start = 1735689600
+	g := &MainGenesis{
+		StartTime:                  start,
+		InitialStakeDuration:       1000,
+		InitialStakeDurationOffset: 100,
+		InitialStakedFunds:         []string{"X-lux1staked"},
+		InitialStakers: []genesis.UnparsedStaker{
+			{NodeID: ids.GenerateTestNodeID()},
+			{NodeID: ids.GenerateTestNodeID()},
+		},
+		Allocations: []genesis.UnparsedAllocation{
+			{ETHAddr: "0x1111111111111111111111111111111111111111", InitialAmount: 100},
+			{ETHAddr: "0x2222222222222222222222222222222222222222", UnlockSchedule: []genesis.LockedAmount{
+				{Amount: 10, Locktime: start - 10},
+				{Amount: 50, Locktime: start + 500},
+			}},
+			{LUXAddr: "X-lux1staked", UnlockSchedule: []genesis.LockedAmount{
+				{Amount: 60, Locktime: start + 950},
+				{Amount: 40, Locktime: start + 2000},
+			}},
+		},
+		CChainGenesis: `{"alloc": {"0x3333333333333333333333333333333333333333": {"balance": "0x3b9aca00"}}}`,
+	}
+
+	p, err := ProjectSupply(g)
+	require.NoError(t, err)
+	nlux := func(a ProjectionPoint) [3]string {
+		div := "000000000"
+		trim := func(s string) string {
+			if s == "0" {
+				return s
+			}
+			return s[:len(s)-len(div)]
+		}
+		return [3]string{trim(a.Circulating.Value().String()), trim(a.Locked.Value().String()), trim(a.Staked.Value().String())}
+	}
+
+	// Start: 100 + 10 (past locktime) + 1 (C-Chain, 1e9 wei) circulating,
+	// 50 locked, 100 staked across two stakers
+	assert.Equal(t, [3]string{"111", "50", "100"}, nlux(p.At(start)))
+	assert.Equal(t, [3]string{"161", "0", "100"}, nlux(p.At(start+500)))
+	// The second staker ends at start+900; its half of the 60 is still locked
+	// until start+950, its half of the 40 until start+2000
+	assert.Equal(t, [3]string{"161", "50", "50"}, nlux(p.At(start+900)))
+	assert.Equal(t, [3]string{"191", "20", "50"}, nlux(p.At(start+950)))
+	assert.Equal(t, [3]string{"221", "40", "0"}, nlux(p.At(start+1000)))
+	assert.Equal(t, [3]string{"261", "0", "0"}, nlux(p.At(start+5000)))
+	assert.Equal(t, "261000000000", p.Total.Value().String())
+
+	require.Len(t, p.Warnings, 2)
+	assert.Contains(t, p.Warnings[0], "before the start time")
+	assert.Contains(t, p.Warnings[1], "beyond the initial stake duration")
+}
@@ -0,0 +1,177 @@
+package genesis
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/vms/platformvm/signer"
+
+	"github.com/luxfi/genesis/pkg/genesis/address"
+)
+
+// Registry issue kinds
+const (
+	IssueInvalidNodeID   = "invalid-node-id"
+	IssueInvalidSigner   = "invalid-signer"
+	IssueDuplicateNodeID = "duplicate-node-id"
+	IssueDuplicateBLSKey = "duplicate-bls-key"
+	IssueLowStake        = "low-stake"
+	IssueNotInGenesis    = "not-in-genesis"
+	IssueNotInRegistry   = "not-in-registry"
+	IssueSignerMismatch  = "signer-mismatch"
+	IssueFeeMismatch     = "delegation-fee-mismatch"
+	IssueRewardMismatch  = "reward-address-mismatch"
+	IssueInvalidReward   = "invalid-reward-address"
+)
+
+// RegistryIssue is a problem with a registry entry, or a difference between
+// the registry and a genesis. Index is the entry's position in the registry,
+// or -1 for stakers found only in the genesis.
+type RegistryIssue struct {
+	Index   int    `json:"index"`
+	NodeID  string `json:"nodeID"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// String formats the issue for display
+func (i RegistryIssue) String() string {
+	if i.Index < 0 {
+		return fmt.Sprintf("%s: %s", i.NodeID, i.Message)
+	}
+	return fmt.Sprintf("#%d %s: %s", i.Index, i.NodeID, i.Message)
+}
+
+// CheckRegistry checks each registry entry on its own and against the
+// others: NodeID format, BLS proof of possession, duplicate NodeIDs and BLS
+// keys, and a weight (nLUX) of at least minStake. A zero minStake skips the
+// stake check.
+func CheckRegistry(validators []ValidatorInfo, minStake uint64) []RegistryIssue {
+	var issues []RegistryIssue
+	add := func(i int, v ValidatorInfo, kind, format string, args ...interface{}) {
+		issues = append(issues, RegistryIssue{Index: i, NodeID: v.NodeID, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	nodeIDs := make(map[string]int)
+	blsKeys := make(map[string]int)
+	for i, v := range validators {
+		if nodeID, err := ids.NodeIDFromString(v.NodeID); err != nil {
+			add(i, v, IssueInvalidNodeID, "invalid NodeID: %v", err)
+		} else {
+			key := nodeID.String()
+			if first, ok := nodeIDs[key]; ok {
+				add(i, v, IssueDuplicateNodeID, "NodeID already used by #%d", first)
+			} else {
+				nodeIDs[key] = i
+			}
+		}
+
+		if pop, err := parseProofOfPossession(v.PublicKey, v.ProofOfPossession); err != nil {
+			add(i, v, IssueInvalidSigner, "%v", err)
+		} else {
+			if err := pop.Verify(); err != nil {
+				add(i, v, IssueInvalidSigner, "proof of possession does not verify against the BLS public key: %v", err)
+			}
+			key := hex.EncodeToString(pop.PublicKey[:])
+			if first, ok := blsKeys[key]; ok {
+				add(i, v, IssueDuplicateBLSKey, "BLS public key already used by #%d", first)
+			} else {
+				blsKeys[key] = i
+			}
+		}
+
+		if v.Weight < minStake {
+			add(i, v, IssueLowStake, "weight %d nLUX is below the minimum validator stake of %d nLUX", v.Weight, minStake)
+		}
+	}
+	return issues
+}
+
+// CompareRegistry reports drift between a registry and the initial stakers
+// of a genesis: stakers on one side only and stakers whose signer, delegation
+// fee or reward address differ. Registry entries without a reward address are
+// compared through the P-Chain form of their ETH address, as Builder does.
+func CompareRegistry(validators []ValidatorInfo, g *MainGenesis, hrp string) []RegistryIssue {
+	stakers := make(map[string]int, len(g.InitialStakers))
+	for i, staker := range g.InitialStakers {
+		stakers[staker.NodeID.String()] = i
+	}
+
+	conv := address.NewConverter(hrp)
+	var issues []RegistryIssue
+	seen := make(map[string]bool)
+	for i, v := range validators {
+		nodeID, err := ids.NodeIDFromString(v.NodeID)
+		if err != nil {
+			continue // reported by CheckRegistry
+		}
+		key := nodeID.String()
+		seen[key] = true
+		j, ok := stakers[key]
+		if !ok {
+			issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueNotInGenesis, Message: "not an initial staker of the genesis"})
+			continue
+		}
+		staker := g.InitialStakers[j]
+
+		if staker.Signer == nil {
+			if v.PublicKey != "" {
+				issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueSignerMismatch, Message: "genesis staker has no BLS signer"})
+			}
+		} else if !strings.EqualFold(strings.TrimPrefix(v.PublicKey, "0x"), hex.EncodeToString(staker.Signer.PublicKey[:])) {
+			issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueSignerMismatch,
+				Message: fmt.Sprintf("BLS public key %s, genesis has 0x%x", v.PublicKey, staker.Signer.PublicKey)})
+		}
+
+		if v.DelegationFee != staker.DelegationFee {
+			issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueFeeMismatch,
+				Message: fmt.Sprintf("delegation fee %d, genesis has %d", v.DelegationFee, staker.DelegationFee)})
+		}
+
+		reward := v.RewardAddress
+		if reward == "" && v.ETHAddress != "" {
+			if reward, err = conv.ETHToLux(v.ETHAddress, "P"); err != nil {
+				issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueInvalidReward, Message: err.Error()})
+				continue
+			}
+		}
+		if reward != "" && !strings.EqualFold(reward, staker.RewardAddress) {
+			issues = append(issues, RegistryIssue{Index: i, NodeID: key, Kind: IssueRewardMismatch,
+				Message: fmt.Sprintf("reward address %s, genesis has %s", reward, staker.RewardAddress)})
+		}
+	}
+
+	var missing []string
+	for key := range stakers {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		issues = append(issues, RegistryIssue{Index: -1, NodeID: key, Kind: IssueNotInRegistry, Message: "initial staker of the genesis is not in the registry"})
+	}
+	return issues
+}
+
+// parseProofOfPossession decodes a hex BLS public key and proof of possession
+func parseProofOfPossession(publicKey, proof string) (*signer.ProofOfPossession, error) {
+	if publicKey == "" || proof == "" {
+		return nil, fmt.Errorf("missing BLS public key or proof of possession")
+	}
+	pk, err := hex.DecodeString(strings.TrimPrefix(publicKey, "0x"))
+	if err != nil || len(pk) != 48 {
+		return nil, fmt.Errorf("BLS public key must be 48 hex-encoded bytes")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(proof, "0x"))
+	if err != nil || len(sig) != 96 {
+		return nil, fmt.Errorf("proof of possession must be 96 hex-encoded bytes")
+	}
+	pop := &signer.ProofOfPossession{}
+	copy(pop.PublicKey[:], pk)
+	copy(pop.ProofOfPossession[:], sig)
+	return pop, nil
+}
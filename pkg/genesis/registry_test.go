@@ -0,0 +1,107 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/genesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/genesis/validator"
+)
+
+const registryMnemonic = "test test test test test test test test test test test junk"
+
+func testRegistry(t *testing.T, n int) []ValidatorInfo {
+	keygen := validator.NewKeyGenerator("")
+	validators := make([]ValidatorInfo, n)
+	for i := range validators {
+		keys, err := keygen.GenerateFromSeedWithTLS(registryMnemonic, i)
+		require.NoError(t, err)
+		validators[i] = ValidatorInfo{
+			NodeID:            keys.NodeID,
+			ETHAddress:        keys.ETHAddress,
+			RewardAddress:     keys.RewardAddress,
+			PublicKey:         keys.PublicKey,
+			ProofOfPossession: keys.ProofOfPossession,
+			Weight:            2000000000000000,
+			DelegationFee:     20000,
+		}
+	}
+	return validators
+}
+
+func issueKinds(issues []RegistryIssue) []string {
+	kinds := make([]string, len(issues))
+	for i, issue := range issues {
+		kinds[i] = issue.Kind
+	}
+	return kinds
+}
+
+func TestCheckRegistry(t *testing.T) {
+	validators := testRegistry(t, 4)
+	assert.Empty(t, CheckRegistry(validators, 2000000000000000))
+
+	validators[1].ProofOfPossession = validators[0].ProofOfPossession
+	validators[2].NodeID = validators[0].NodeID
+	validators[2].Weight = 1000000000
+	validators[3].PublicKey = validators[0].PublicKey
+	validators[3].ProofOfPossession = validators[0].ProofOfPossession
+	validators = append(validators, ValidatorInfo{NodeID: "NodeID-bogus", Weight: 2000000000000000})
+
+	issues := CheckRegistry(validators, 2000000000000000)
+	assert.Equal(t, []string{
+		IssueInvalidSigner,
+		IssueDuplicateNodeID,
+		IssueLowStake,
+		IssueDuplicateBLSKey,
+		IssueInvalidNodeID,
+		IssueInvalidSigner,
+	}, issueKinds(issues))
+	assert.Equal(t, 1, issues[0].Index)
+	assert.Contains(t, issues[1].Message, "#0")
+
+	// A zero minimum skips the stake check
+	assert.NotContains(t, issueKinds(CheckRegistry(validators, 0)), IssueLowStake)
+}
+
+func TestCompareRegistry(t *testing.T) {
+	validators := testRegistry(t, 3)
+	g := &MainGenesis{}
+	for _, v := range validators {
+		nodeID, err := ids.NodeIDFromString(v.NodeID)
+		require.NoError(t, err)
+		pop, err := parseProofOfPossession(v.PublicKey, v.ProofOfPossession)
+		require.NoError(t, err)
+		g.InitialStakers = append(g.InitialStakers, genesis.UnparsedStaker{
+			NodeID:        nodeID,
+			RewardAddress: v.RewardAddress,
+			DelegationFee: v.DelegationFee,
+			Signer:        pop,
+		})
+	}
+	assert.Empty(t, CompareRegistry(validators, g, "lux"))
+
+	// Without a reward address the ETH address is converted as Builder does,
+	// which is not the address derived from the reward key
+	noReward := append([]ValidatorInfo(nil), validators...)
+	noReward[0].RewardAddress = ""
+	assert.Equal(t, []string{IssueRewardMismatch}, issueKinds(CompareRegistry(noReward, g, "lux")))
+
+	validators[0].DelegationFee = 30000
+	validators[1].PublicKey = validators[2].PublicKey
+	extra := testRegistry(t, 4)[3]
+	validators = append(validators[:2], extra)
+
+	issues := CompareRegistry(validators, g, "lux")
+	assert.Equal(t, []string{
+		IssueFeeMismatch,
+		IssueSignerMismatch,
+		IssueNotInGenesis,
+		IssueNotInRegistry,
+	}, issueKinds(issues))
+	assert.Equal(t, -1, issues[3].Index)
+	assert.Equal(t, g.InitialStakers[2].NodeID.String(), issues[3].NodeID)
+}
@@ -0,0 +1,654 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/geth/common"
+	"gopkg.in/yaml.v3"
+
+	"github.com/luxfi/genesis/pkg/genesis/allocation"
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+	"github.com/luxfi/genesis/pkg/genesis/cchain"
+	"github.com/luxfi/genesis/pkg/genesis/config"
+	"github.com/luxfi/genesis/pkg/genesis/importer"
+)
+
+// SpecVersion is the spec file format understood by this build
+const SpecVersion = 1
+
+// Allocation kinds in a spec
+const (
+	AllocationSimple  = "simple"
+	AllocationVested  = "vested"
+	AllocationStaking = "staking"
+)
+
+// Spec is a versioned, declarative description of a network's genesis. It
+// replaces the combination of generate flags, validators.json, CSVs and the
+// compiled-in config.Networks entries.
+type Spec struct {
+	Version     int              `json:"version" yaml:"version"`
+	Network     NetworkSpec      `json:"network" yaml:"network"`
+	Allocations []AllocationSpec `json:"allocations,omitempty" yaml:"allocations,omitempty"`
+	Stakers     []StakerSpec     `json:"stakers,omitempty" yaml:"stakers,omitempty"`
+	CChain      CChainSpec       `json:"cchain,omitempty" yaml:"cchain,omitempty"`
+	XChain      XChainSpec       `json:"xchain,omitempty" yaml:"xchain,omitempty"`
+	Subnets     []SubnetSpec     `json:"subnets,omitempty" yaml:"subnets,omitempty"`
+
+	// dir is the directory of the spec file, used to resolve relative paths
+	dir string
+}
+
+// NetworkSpec describes the network. When Base names a predefined network its
+// settings are used for every field left empty.
+type NetworkSpec struct {
+	Base                 string `json:"base,omitempty" yaml:"base,omitempty"`
+	Name                 string `json:"name,omitempty" yaml:"name,omitempty"`
+	ID                   uint32 `json:"id,omitempty" yaml:"id,omitempty"`
+	HRP                  string `json:"hrp,omitempty" yaml:"hrp,omitempty"`
+	ChainID              uint64 `json:"chainId,omitempty" yaml:"chainId,omitempty"`
+	StartTime            string `json:"startTime,omitempty" yaml:"startTime,omitempty"`                       // RFC 3339
+	InitialStakeDuration string `json:"initialStakeDuration,omitempty" yaml:"initialStakeDuration,omitempty"` // e.g. 8760h
+	MinValidatorStake    string `json:"minValidatorStake,omitempty" yaml:"minValidatorStake,omitempty"`       // LUX amount, e.g. 2M
+	MinDelegatorStake    string `json:"minDelegatorStake,omitempty" yaml:"minDelegatorStake,omitempty"`       // LUX amount, e.g. 25
+	ParentNetwork        string `json:"parentNetwork,omitempty" yaml:"parentNetwork,omitempty"`
+	Message              string `json:"message,omitempty" yaml:"message,omitempty"`
+	TotalSupply          string `json:"totalSupply,omitempty" yaml:"totalSupply,omitempty"` // LUX amount across all chains, checked by ReconcileSupply
+	Rounding             string `json:"rounding,omitempty" yaml:"rounding,omitempty"`       // wei to nLUX policy: exact (default), down, up, half-even
+}
+
+// AllocationSpec is a single allocation. Amount is a LUX amount as accepted
+// by allocation.ParseLUXAmount (e.g. "1000", "1.5M", "2T"); simple allocations
+// may give AmountWei instead, e.g. a balance copied from the C-Chain, which is
+// converted to nLUX with the network's rounding policy.
+type AllocationSpec struct {
+	Address   string `json:"address" yaml:"address"`
+	Amount    string `json:"amount,omitempty" yaml:"amount,omitempty"`
+	AmountWei string `json:"amountWei,omitempty" yaml:"amountWei,omitempty"`
+	Type      string `json:"type,omitempty" yaml:"type,omitempty"` // simple (default), vested, staking
+
+	// Vested allocations; Schedule picks an allocation.Template
+	Schedule string       `json:"schedule,omitempty" yaml:"schedule,omitempty"` // linear (default), monthly, back-loaded, custom, tge-linear
+	Start    string       `json:"start,omitempty" yaml:"start,omitempty"`       // RFC 3339, defaults to the network start time
+	Duration string       `json:"duration,omitempty" yaml:"duration,omitempty"` // e.g. 17520h
+	Periods  int          `json:"periods,omitempty" yaml:"periods,omitempty"`   // monthly: number of monthly unlocks
+	Cliff    int          `json:"cliff,omitempty" yaml:"cliff,omitempty"`       // periods (monthly: months) before the first unlock
+	TGE      string       `json:"tge,omitempty" yaml:"tge,omitempty"`           // tge-linear: percentage unlocked at start, e.g. 10%
+	Unlocks  []UnlockSpec `json:"unlocks,omitempty" yaml:"unlocks,omitempty"`   // custom
+
+	// Staking allocations
+	Years int `json:"years,omitempty" yaml:"years,omitempty"`
+}
+
+// UnlockSpec is one date of a custom vesting schedule
+type UnlockSpec struct {
+	Date    string `json:"date" yaml:"date"`       // RFC 3339 or YYYY-MM-DD
+	Percent string `json:"percent" yaml:"percent"` // e.g. 25%
+}
+
+// StakerSpec is an initial validator
+type StakerSpec struct {
+	NodeID            string `json:"nodeId" yaml:"nodeId"`
+	ETHAddress        string `json:"ethAddress" yaml:"ethAddress"`
+	PublicKey         string `json:"publicKey,omitempty" yaml:"publicKey,omitempty"`
+	ProofOfPossession string `json:"proofOfPossession,omitempty" yaml:"proofOfPossession,omitempty"`
+	Weight            uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+	DelegationFee     uint32 `json:"delegationFee,omitempty" yaml:"delegationFee,omitempty"`
+}
+
+// CChainSpec configures the C-Chain genesis. Genesis imports a complete
+// genesis file; AllocFile and Alloc (address to balance in wei) are merged
+// into it, or into a generated one if Genesis is empty, and an address set by
+// more than one of them is an error. Precompiles are set in the chain config
+// and Upgrades are written to upgrade.json.
+type CChainSpec struct {
+	Genesis     string                `json:"genesis,omitempty" yaml:"genesis,omitempty"`
+	GasLimit    uint64                `json:"gasLimit,omitempty" yaml:"gasLimit,omitempty"`
+	AllocFile   string                `json:"allocFile,omitempty" yaml:"allocFile,omitempty"`
+	Alloc       map[string]string     `json:"alloc,omitempty" yaml:"alloc,omitempty"`
+	Precompiles *cchain.Precompiles   `json:"precompiles,omitempty" yaml:"precompiles,omitempty"`
+	Upgrades    *cchain.UpgradeConfig `json:"upgrades,omitempty" yaml:"upgrades,omitempty"`
+}
+
+// XChainSpec configures the X-Chain genesis
+type XChainSpec struct {
+	Airdrops string `json:"airdrops,omitempty" yaml:"airdrops,omitempty"` // CSV in the rank,address,balance_lux format
+}
+
+// SubnetSpec describes a subnet chain created with the network
+type SubnetSpec struct {
+	Name    string `json:"name" yaml:"name"`
+	ChainID string `json:"chainId" yaml:"chainId"`
+	VMID    string `json:"vmId,omitempty" yaml:"vmId,omitempty"`
+	Genesis string `json:"genesis,omitempty" yaml:"genesis,omitempty"`
+}
+
+// LoadSpec reads a spec from a .yaml/.yml or .json file and validates it
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	spec := &Spec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(spec)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(spec)
+	default:
+		return nil, fmt.Errorf("unsupported spec format: %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+
+	spec.dir = filepath.Dir(path)
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate checks the spec without touching any referenced files
+func (s *Spec) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if s.Version != SpecVersion {
+		add("unsupported spec version %d (expected %d)", s.Version, SpecVersion)
+	}
+	if _, err := s.NetworkConfig(); err != nil {
+		add("network: %v", err)
+	}
+	if _, err := amount.ParseRounding(s.Network.Rounding); err != nil {
+		add("network: %v", err)
+	}
+	if s.Network.TotalSupply != "" {
+		if _, err := amount.Parse(s.Network.TotalSupply, amount.Wei); err != nil {
+			add("network: invalid totalSupply %q: %v", s.Network.TotalSupply, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, alloc := range s.Allocations {
+		key := strings.ToLower(alloc.Address)
+		if seen[key] {
+			add("allocations[%d]: duplicate address %s", i, alloc.Address)
+		}
+		seen[key] = true
+		switch {
+		case (alloc.Amount == "") == (alloc.AmountWei == ""):
+			add("allocations[%d]: exactly one of amount and amountWei is required", i)
+		case alloc.AmountWei != "":
+			if wei, ok := parseBalance(alloc.AmountWei); !ok || wei.Sign() < 0 {
+				add("allocations[%d]: invalid amountWei %q", i, alloc.AmountWei)
+			}
+			if alloc.Type != "" && alloc.Type != AllocationSimple {
+				add("allocations[%d]: amountWei is only supported for simple allocations", i)
+			}
+		default:
+			if _, err := allocation.ParseLUXAmount(alloc.Amount); err != nil {
+				add("allocations[%d]: invalid amount %q: %v", i, alloc.Amount, err)
+			}
+		}
+		switch alloc.Type {
+		case "", AllocationSimple:
+		case AllocationVested:
+			if _, err := alloc.schedule(new(big.Int), time.Time{}); err != nil {
+				add("allocations[%d]: %v", i, err)
+			}
+		case AllocationStaking:
+			if alloc.Years <= 0 {
+				add("allocations[%d]: staking allocation needs years > 0", i)
+			}
+		default:
+			add("allocations[%d]: unknown type %q", i, alloc.Type)
+		}
+	}
+
+	nodes := make(map[string]bool)
+	for i, staker := range s.Stakers {
+		if staker.NodeID == "" || staker.ETHAddress == "" {
+			add("stakers[%d]: nodeId and ethAddress are required", i)
+		}
+		if nodes[staker.NodeID] {
+			add("stakers[%d]: duplicate node %s", i, staker.NodeID)
+		}
+		nodes[staker.NodeID] = true
+		if (staker.PublicKey == "") != (staker.ProofOfPossession == "") {
+			add("stakers[%d]: publicKey and proofOfPossession must be set together", i)
+		}
+	}
+
+	cchainAlloc := make(map[string]string)
+	for _, addr := range sortedKeys(s.CChain.Alloc) {
+		balance := s.CChain.Alloc[addr]
+		if _, ok := parseBalance(balance); !ok {
+			add("cchain.alloc: invalid balance %q for %s", balance, addr)
+		}
+		key, ok := cchainAllocKey(addr)
+		if !ok {
+			add("cchain.alloc: invalid address %s", addr)
+			continue
+		}
+		if other, ok := cchainAlloc[key]; ok {
+			add("cchain.alloc: %s and %s are the same address", other, addr)
+		}
+		cchainAlloc[key] = addr
+	}
+
+	subnets := make(map[string]bool)
+	for i, subnet := range s.Subnets {
+		if subnet.Name == "" || subnet.ChainID == "" {
+			add("subnets[%d]: name and chainId are required", i)
+		}
+		if subnets[subnet.Name] {
+			add("subnets[%d]: duplicate subnet %s", i, subnet.Name)
+		}
+		subnets[subnet.Name] = true
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid genesis spec:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// NetworkConfig resolves the network section into a config.NetworkConfig
+func (s *Spec) NetworkConfig() (*config.NetworkConfig, error) {
+	n := s.Network
+	network := &config.NetworkConfig{}
+	if n.Base != "" {
+		base, err := config.GetNetwork(n.Base)
+		if err != nil {
+			return nil, err
+		}
+		*network = *base
+	}
+
+	if n.Name != "" {
+		network.Name = n.Name
+	}
+	if n.ID != 0 {
+		network.ID = config.NetworkID(n.ID)
+	}
+	if n.HRP != "" {
+		network.HRP = n.HRP
+	}
+	if n.ChainID != 0 {
+		network.ChainID = n.ChainID
+	}
+	if n.StartTime != "" {
+		start, err := time.Parse(time.RFC3339, n.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid startTime: %w", err)
+		}
+		network.StartTime = start
+	}
+	if n.InitialStakeDuration != "" {
+		d, err := time.ParseDuration(n.InitialStakeDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid initialStakeDuration: %w", err)
+		}
+		network.InitialStakeDuration = d
+	}
+	if n.MinValidatorStake != "" {
+		amount, err := allocation.ParseLUXAmount(n.MinValidatorStake)
+		if err != nil || !amount.IsUint64() {
+			return nil, fmt.Errorf("invalid minValidatorStake: %s", n.MinValidatorStake)
+		}
+		network.MinValidatorStake = amount.Uint64()
+	}
+	if n.MinDelegatorStake != "" {
+		amount, err := allocation.ParseLUXAmount(n.MinDelegatorStake)
+		if err != nil || !amount.IsUint64() {
+			return nil, fmt.Errorf("invalid minDelegatorStake: %s", n.MinDelegatorStake)
+		}
+		network.MinDelegatorStake = amount.Uint64()
+	}
+	if n.ParentNetwork != "" {
+		network.ParentNetwork = n.ParentNetwork
+		network.IsL2 = true
+	}
+
+	switch {
+	case network.Name == "":
+		return nil, fmt.Errorf("name is required")
+	case network.ID == 0:
+		return nil, fmt.Errorf("id is required")
+	case network.HRP == "":
+		return nil, fmt.Errorf("hrp is required")
+	case network.ChainID == 0:
+		return nil, fmt.Errorf("chainId is required")
+	case network.StartTime.IsZero():
+		return nil, fmt.Errorf("startTime is required")
+	case network.InitialStakeDuration <= 0:
+		return nil, fmt.Errorf("initialStakeDuration is required")
+	}
+	return network, nil
+}
+
+// path resolves a path in the spec relative to the spec file
+func (s *Spec) path(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(s.dir, p)
+}
+
+// FromSpec creates a builder for the network described by spec with all of
+// its allocations, stakers and chain configuration applied
+func FromSpec(spec *Spec) (*Builder, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	network, err := spec.NetworkConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBuilder(network)
+	b.rounding, _ = amount.ParseRounding(spec.Network.Rounding)
+	b.message = spec.Network.Message
+	b.xchainAirdrops = spec.path(spec.XChain.Airdrops)
+	for _, subnet := range spec.Subnets {
+		subnet.Genesis = spec.path(subnet.Genesis)
+		b.subnets = append(b.subnets, subnet)
+	}
+
+	for _, alloc := range spec.Allocations {
+		if err := b.addSpecAllocation(alloc); err != nil {
+			return nil, fmt.Errorf("failed to add allocation for %s: %w", alloc.Address, err)
+		}
+	}
+
+	for _, staker := range spec.Stakers {
+		b.AddStaker(StakerConfig{
+			NodeID:            staker.NodeID,
+			ETHAddress:        staker.ETHAddress,
+			PublicKey:         staker.PublicKey,
+			ProofOfPossession: staker.ProofOfPossession,
+			Weight:            staker.Weight,
+			DelegationFee:     staker.DelegationFee,
+		})
+	}
+
+	if err := b.applyCChainSpec(spec); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// addSpecAllocation adds a validated spec allocation
+func (b *Builder) addSpecAllocation(alloc AllocationSpec) error {
+	if alloc.AmountWei != "" {
+		wei, _ := parseBalance(alloc.AmountWei)
+		return b.AddAllocationWei(alloc.Address, wei)
+	}
+
+	total, err := allocation.ParseLUXAmount(alloc.Amount)
+	if err != nil {
+		return err
+	}
+
+	switch alloc.Type {
+	case AllocationVested:
+		schedule, err := alloc.schedule(total, b.network.StartTime)
+		if err != nil {
+			return err
+		}
+		return b.AddScheduledAllocation(alloc.Address, schedule)
+	case AllocationStaking:
+		// Same as allocation.CreateStakingAllocation, but anchored at the
+		// network start so the output is reproducible
+		return b.AddVestedAllocation(alloc.Address, &allocation.UnlockScheduleConfig{
+			TotalAmount: total,
+			StartDate:   b.network.StartTime,
+			Duration:    time.Duration(alloc.Years) * 365 * 24 * time.Hour,
+			Periods:     alloc.Years,
+		})
+	}
+	return b.AddAllocation(alloc.Address, total)
+}
+
+// schedule builds the vesting schedule of a vested allocation; start is
+// used when the allocation doesn't set its own
+func (a AllocationSpec) schedule(total *big.Int, start time.Time) (*allocation.ScheduleConfig, error) {
+	template, err := allocation.ParseTemplate(a.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	config := &allocation.ScheduleConfig{
+		Template:     template,
+		TotalAmount:  total,
+		StartDate:    start,
+		Periods:      a.Periods,
+		CliffPeriods: a.Cliff,
+	}
+	if a.Start != "" {
+		if config.StartDate, err = time.Parse(time.RFC3339, a.Start); err != nil {
+			return nil, fmt.Errorf("invalid start %q", a.Start)
+		}
+	}
+	if a.Duration != "" || template != allocation.TemplateMonthly && template != allocation.TemplateCustom {
+		if config.Duration, err = time.ParseDuration(a.Duration); err != nil {
+			return nil, fmt.Errorf("invalid duration %q", a.Duration)
+		}
+	}
+	if a.TGE != "" {
+		if config.TGEPercent, err = allocation.ParsePercent(a.TGE); err != nil {
+			return nil, err
+		}
+	}
+	for _, u := range a.Unlocks {
+		date, err := parseDate(u.Date)
+		if err != nil {
+			return nil, err
+		}
+		percent, err := allocation.ParsePercent(u.Percent)
+		if err != nil {
+			return nil, err
+		}
+		config.Unlocks = append(config.Unlocks, allocation.CustomUnlock{Time: date, Percent: percent})
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseDate parses an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
+
+// IntendedSupply returns the spec's totalSupply, if it sets one
+func (s *Spec) IntendedSupply() (amount.Amount, bool) {
+	if s.Network.TotalSupply == "" {
+		return amount.Amount{}, false
+	}
+	supply, err := amount.Parse(s.Network.TotalSupply, amount.Wei)
+	return supply, err == nil
+}
+
+// applyCChainSpec builds the C-Chain genesis from the spec, if it sets anything
+func (b *Builder) applyCChainSpec(spec *Spec) error {
+	c := spec.CChain
+	if c.Precompiles != nil {
+		b.cchainBuilder.SetPrecompiles(*c.Precompiles)
+	}
+	if c.Upgrades != nil {
+		for _, upgrade := range c.Upgrades.PrecompileUpgrades {
+			b.cchainBuilder.AddPrecompileUpgrade(upgrade)
+		}
+		for name, timestamp := range c.Upgrades.NetworkUpgradeOverrides {
+			b.cchainBuilder.SetNetworkUpgradeOverride(name, timestamp)
+		}
+	}
+	if c.Genesis == "" && c.AllocFile == "" && len(c.Alloc) == 0 && c.GasLimit == 0 &&
+		c.Precompiles == nil && c.Upgrades == nil {
+		return nil
+	}
+
+	var genesisJSON []byte
+	if c.Genesis != "" {
+		data, err := os.ReadFile(spec.path(c.Genesis))
+		if err != nil {
+			return fmt.Errorf("failed to read C-Chain genesis: %w", err)
+		}
+		genesisJSON = data
+	} else {
+		data, err := b.cchainBuilder.Build().ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to create C-Chain genesis: %w", err)
+		}
+		genesisJSON = data
+	}
+
+	// Work on a generic map so fields the cchain types don't model survive
+	var genesis map[string]interface{}
+	if err := json.Unmarshal(genesisJSON, &genesis); err != nil {
+		return fmt.Errorf("invalid C-Chain genesis: %w", err)
+	}
+	// Every account is keyed by its lowercase 0x address, whichever of the
+	// genesis, the alloc file or the spec set it, so one address can't be
+	// allocated twice under different spellings
+	alloc := make(map[string]interface{})
+	allocatedBy := make(map[string]string)
+	put := func(addr string, account interface{}, source string) error {
+		key, ok := cchainAllocKey(addr)
+		if !ok {
+			return fmt.Errorf("%s: invalid C-Chain address %q", source, addr)
+		}
+		if other, ok := allocatedBy[key]; ok {
+			return fmt.Errorf("%s: %s is already allocated by %s", source, key, other)
+		}
+		alloc[key] = account
+		allocatedBy[key] = source
+		return nil
+	}
+
+	existing, _ := genesis["alloc"].(map[string]interface{})
+	for _, addr := range sortedKeys(existing) {
+		if err := put(addr, existing[addr], "C-Chain genesis"); err != nil {
+			return err
+		}
+	}
+	if c.AllocFile != "" {
+		im := importer.New(importer.Options{Denomination: amount.Wei, Merge: importer.MergeError})
+		if err := im.ImportFile(spec.path(c.AllocFile)); err != nil {
+			return fmt.Errorf("failed to import allocations: %w", err)
+		}
+		imported := im.Result()
+		if err := imported.Err(); err != nil {
+			return err
+		}
+		for _, account := range imported.Allocations {
+			balance := map[string]interface{}{"balance": fmt.Sprintf("0x%x", account.Amount.Value())}
+			if err := put(account.Address, balance, account.Sources[0].String()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, addr := range sortedKeys(c.Alloc) {
+		wei, _ := parseBalance(c.Alloc[addr])
+		if err := put(addr, map[string]interface{}{"balance": fmt.Sprintf("0x%x", wei)}, "cchain.alloc"); err != nil {
+			return err
+		}
+	}
+	genesis["alloc"] = alloc
+
+	if c.GasLimit != 0 {
+		genesis["gasLimit"] = fmt.Sprintf("0x%x", c.GasLimit)
+	}
+
+	chainConfig, _ := genesis["config"].(map[string]interface{})
+	if chainConfig == nil {
+		chainConfig = make(map[string]interface{})
+	}
+	if c.Precompiles != nil && c.Genesis != "" {
+		// An imported genesis doesn't carry the builder's precompiles yet
+		var keys map[string]interface{}
+		data, _ := json.Marshal(c.Precompiles.AtGenesis())
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return err
+		}
+		for key, value := range keys {
+			chainConfig[key] = value
+		}
+		genesis["config"] = chainConfig
+	}
+	if err := b.validateCChainPrecompiles(chainConfig, alloc); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(genesis, "", "\t")
+	if err != nil {
+		return err
+	}
+	b.SetCChainGenesis(string(data))
+	return nil
+}
+
+// cchainAllocKey returns the lowercase 0x form of a C-Chain address, with or
+// without the 0x prefix
+func cchainAllocKey(addr string) (string, bool) {
+	if !common.IsHexAddress(addr) {
+		return "", false
+	}
+	return strings.ToLower(common.HexToAddress(addr).Hex()), true
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateCChainPrecompiles checks the precompiles of a C-Chain config, and
+// the scheduled upgrades, against its alloc
+func (b *Builder) validateCChainPrecompiles(chainConfig, alloc map[string]interface{}) error {
+	data, err := json.Marshal(chainConfig)
+	if err != nil {
+		return err
+	}
+	var precompiles cchain.Precompiles
+	if err := json.Unmarshal(data, &precompiles); err != nil {
+		return fmt.Errorf("invalid C-Chain precompile config: %w", err)
+	}
+
+	g := &cchain.Genesis{
+		Config: &cchain.ChainConfig{Precompiles: precompiles},
+		Alloc:  make(map[string]cchain.GenesisAccount, len(alloc)),
+	}
+	for addr := range alloc {
+		g.Alloc[addr] = cchain.GenesisAccount{}
+	}
+	return cchain.ValidatePrecompiles(g, b.cchainBuilder.Upgrades())
+}
@@ -0,0 +1,236 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `version: 1
+network:
+  name: Spec Devnet
+  id: 4242
+  hrp: dev
+  chainId: 4242
+  startTime: "2025-01-01T00:00:00Z"
+  initialStakeDuration: 8760h
+allocations:
+  - address: "0x1234567890123456789012345678901234567890"
+    amount: "1000"
+  - address: "0x0987654321098765432109876543210987654321"
+    amount: "400"
+    type: vested
+    duration: 35040h
+    periods: 4
+    cliff: 0
+stakers:
+  - nodeId: NodeID-7VJMGZYzqTvVjS23912uzu8gCFcnquPQW
+    ethAddress: "0x1234567890123456789012345678901234567890"
+    delegationFee: 20000
+cchain:
+  alloc:
+    "0x1234567890123456789012345678901234567890": "0x1000"
+subnets:
+  - name: zoo
+    chainId: "200200"
+`
+
+func writeSpec(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestFromSpec(t *testing.T) {
+	spec, err := LoadSpec(writeSpec(t, "spec.yaml", testSpec))
+	require.NoError(t, err)
+
+	builder, err := FromSpec(spec)
+	require.NoError(t, err)
+	assert.Equal(t, "dev", builder.Network().HRP)
+
+	genesis, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4242), genesis.NetworkID)
+	assert.Equal(t, uint64(1735689600), genesis.StartTime)
+	assert.Len(t, genesis.Allocations, 2)
+	assert.Len(t, genesis.InitialStakers, 1)
+
+	// 1000 + 400 LUX with 9 decimals
+	assert.Equal(t, "1400000000000", builder.GetTotalSupply().String())
+
+	var cGenesis map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(genesis.CChainGenesis), &cGenesis))
+	alloc := cGenesis["alloc"].(map[string]interface{})
+	assert.Contains(t, alloc, "0x1234567890123456789012345678901234567890")
+
+	xGenesis, err := builder.BuildXChain()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4242), xGenesis.NetworkID)
+
+	require.Len(t, builder.Subnets(), 1)
+	assert.Equal(t, "zoo", builder.Subnets()[0].Name)
+}
+
+func TestSpecBaseNetwork(t *testing.T) {
+	spec := &Spec{
+		Version: SpecVersion,
+		Network: NetworkSpec{Base: "mainnet", Name: "Mainnet Fork", ID: 7777},
+	}
+	network, err := spec.NetworkConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "lux", network.HRP)
+	assert.Equal(t, uint64(96369), network.ChainID)
+	assert.Equal(t, uint32(7777), uint32(network.ID))
+
+	// The predefined network must not be modified
+	mainnet, err := NewBuilder("mainnet")
+	require.NoError(t, err)
+	assert.Equal(t, "Lux Mainnet", mainnet.Network().Name)
+}
+
+func TestSpecVestingSchedules(t *testing.T) {
+	const scheduleSpec = `version: 1
+network:
+  base: local
+  startTime: "2025-01-01T00:00:00Z"
+allocations:
+  - address: "0x1234567890123456789012345678901234567890"
+    amount: "1000"
+    type: vested
+    schedule: tge-linear
+    tge: 10%
+    duration: 8760h
+    periods: 3
+  - address: "0x2345678901234567890123456789012345678901"
+    amount: "100"
+    type: vested
+    schedule: custom
+    unlocks:
+      - {date: 2025-06-01, percent: 40%}
+      - {date: 2026-06-01, percent: 60%}
+`
+	spec, err := LoadSpec(writeSpec(t, "spec.yaml", scheduleSpec))
+	require.NoError(t, err)
+	builder, err := FromSpec(spec)
+	require.NoError(t, err)
+
+	allocs := builder.Allocations()
+	require.Len(t, allocs, 2)
+	require.Len(t, allocs[0].UnlockSchedule, 4)
+	assert.Equal(t, "100000000000", allocs[0].UnlockSchedule[0].Amount.String())
+	assert.Equal(t, uint64(1735689600), allocs[0].UnlockSchedule[0].Locktime)
+	require.Len(t, allocs[1].UnlockSchedule, 2)
+	assert.Equal(t, "40000000000", allocs[1].UnlockSchedule[0].Amount.String())
+
+	// Custom percentages must add up to 100%
+	bad := strings.Replace(scheduleSpec, "percent: 60%", "percent: 50%", 1)
+	_, err = LoadSpec(writeSpec(t, "bad.yaml", bad))
+	assert.ErrorContains(t, err, "add up to 90%")
+}
+
+func TestSpecValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"unknown field", `{"version": 1, "network": {"base": "local"}, "bogus": true}`},
+		{"wrong version", `{"version": 2, "network": {"base": "local"}}`},
+		{"missing network fields", `{"version": 1, "network": {"name": "x"}}`},
+		{"bad amount", `{"version": 1, "network": {"base": "local"}, "allocations": [{"address": "0x1234567890123456789012345678901234567890", "amount": "lots"}]}`},
+		{"duplicate address", `{"version": 1, "network": {"base": "local"}, "allocations": [
+			{"address": "0x1234567890123456789012345678901234567890", "amount": "1"},
+			{"address": "0x1234567890123456789012345678901234567890", "amount": "2"}]}`},
+		{"same C-Chain address twice", `{"version": 1, "network": {"base": "local"}, "cchain": {"alloc": {
+			"0x9011E888251AB053B7bD1cdB598Db4f9DEd94714": "1",
+			"9011e888251ab053b7bd1cdb598db4f9ded94714": "2"}}}`},
+		{"bad vesting", `{"version": 1, "network": {"base": "local"}, "allocations": [{"address": "0x1234567890123456789012345678901234567890", "amount": "1", "type": "vested", "periods": 2, "cliff": 2, "duration": "1h"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpec(writeSpec(t, "spec.json", tt.spec))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSpecPrecompiles(t *testing.T) {
+	const precompileSpec = `version: 1
+network:
+  base: local
+cchain:
+  alloc:
+    "0x1234567890123456789012345678901234567890": "1000000000000000000"
+  precompiles:
+    contractDeployerAllowListConfig:
+      adminAddresses: ["%s"]
+  upgrades:
+    precompileUpgrades:
+      - contractDeployerAllowListConfig:
+          blockTimestamp: 1000
+          disable: true
+`
+	spec, err := LoadSpec(writeSpec(t, "spec.yaml", fmt.Sprintf(precompileSpec, "0x1234567890123456789012345678901234567890")))
+	require.NoError(t, err)
+	builder, err := FromSpec(spec)
+	require.NoError(t, err)
+	require.Len(t, builder.CChainUpgrades().PrecompileUpgrades, 1)
+
+	genesis, err := builder.Build()
+	require.NoError(t, err)
+	var cGenesis struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(genesis.CChainGenesis), &cGenesis))
+	assert.Contains(t, cGenesis.Config, "contractDeployerAllowListConfig")
+
+	// Admins must hold a balance in the C-Chain alloc
+	spec, err = LoadSpec(writeSpec(t, "spec.yaml", fmt.Sprintf(precompileSpec, "0x0987654321098765432109876543210987654321")))
+	require.NoError(t, err)
+	_, err = FromSpec(spec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no balance in alloc")
+}
+
+func TestSpecCChainAllocKeys(t *testing.T) {
+	// The imported genesis spells its account without 0x and in mixed case
+	genesisPath := filepath.Join(t.TempDir(), "cchain.json")
+	require.NoError(t, os.WriteFile(genesisPath, []byte(`{"config": {"chainId": 4242}, "gasLimit": "0xe4e1c0", "difficulty": "0x0",
+		"alloc": {"9011E888251AB053B7bD1cdB598Db4f9DEd94714": {"balance": "0x1"}}}`), 0644))
+	const allocSpec = `version: 1
+network:
+  base: local
+cchain:
+  genesis: %s
+  alloc:
+    "%s": "2"
+`
+
+	spec, err := LoadSpec(writeSpec(t, "spec.yaml", fmt.Sprintf(allocSpec, genesisPath, "1234567890ABCDEF1234567890ABCDEF12345678")))
+	require.NoError(t, err)
+	builder, err := FromSpec(spec)
+	require.NoError(t, err)
+	genesis, err := builder.Build()
+	require.NoError(t, err)
+	var cGenesis struct {
+		Alloc map[string]interface{} `json:"alloc"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(genesis.CChainGenesis), &cGenesis))
+	assert.Len(t, cGenesis.Alloc, 2)
+	assert.Contains(t, cGenesis.Alloc, "0x9011e888251ab053b7bd1cdb598db4f9ded94714")
+	assert.Contains(t, cGenesis.Alloc, "0x1234567890abcdef1234567890abcdef12345678")
+
+	// The same account spelled differently is a collision, not a second entry
+	spec, err = LoadSpec(writeSpec(t, "spec.yaml", fmt.Sprintf(allocSpec, genesisPath, "0x9011e888251ab053b7bd1cdb598db4f9ded94714")))
+	require.NoError(t, err)
+	_, err = FromSpec(spec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already allocated by C-Chain genesis")
+}
@@ -0,0 +1,136 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+// SupplyReport reconciles the LUX a genesis creates on each chain against the
+// intended total supply. P/X amounts are nLUX and C-Chain amounts are wei;
+// everything is compared in wei so no digits are dropped.
+type SupplyReport struct {
+	Allocations    int           `json:"allocations"`
+	PXUnlocked     amount.Amount `json:"pxUnlocked"` // nLUX
+	PXLocked       amount.Amount `json:"pxLocked"`   // nLUX
+	CChainAccounts int           `json:"cChainAccounts"`
+	CChain         amount.Amount `json:"cChain"` // wei
+	Total          amount.Amount `json:"total"`  // wei
+	Intended       amount.Amount `json:"intended"`
+	Difference     amount.Amount `json:"difference"` // Total - Intended, wei
+	Dust           amount.Amount `json:"dust"`       // wei lost to rounding when converting to nLUX
+	Rounding       string        `json:"rounding"`
+}
+
+// Balanced reports whether the chains add up to the intended supply exactly
+func (r *SupplyReport) Balanced() bool {
+	return r.Difference.Sign() == 0
+}
+
+// ReconcileSupply sums the P/X allocations (unlocked and locked) and the
+// C-Chain alloc and compares the total with intended
+func (b *Builder) ReconcileSupply(intended amount.Amount) (*SupplyReport, error) {
+	report := &SupplyReport{
+		Allocations: b.allocations.Count(),
+		PXUnlocked:  amount.Zero(amount.NLUX),
+		PXLocked:    amount.Zero(amount.NLUX),
+		Dust:        amount.New(b.dust, amount.Wei),
+		Rounding:    b.rounding.String(),
+	}
+
+	for _, alloc := range b.allocations.GetAll() {
+		var err error
+		report.PXUnlocked, err = report.PXUnlocked.Add(amount.New(alloc.InitialAmount, amount.NLUX))
+		if err != nil {
+			return nil, err
+		}
+		for _, locked := range alloc.UnlockSchedule {
+			report.PXLocked, err = report.PXLocked.Add(amount.New(locked.Amount, amount.NLUX))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cChain, accounts, err := b.cchainSupply()
+	if err != nil {
+		return nil, err
+	}
+	report.CChain = cChain
+	report.CChainAccounts = accounts
+
+	px, _ := report.PXUnlocked.Add(report.PXLocked)
+	pxWei, _ := px.Convert(amount.Wei, amount.RoundExact)
+	report.Total, _ = pxWei.Add(cChain)
+
+	report.Intended, _ = intended.Convert(amount.Wei, amount.RoundExact)
+	report.Difference, _ = report.Total.Sub(report.Intended)
+	return report, nil
+}
+
+// cchainSupply sums the balances of the C-Chain genesis alloc, in wei
+func (b *Builder) cchainSupply() (amount.Amount, int, error) {
+	cGenesis := b.cchainGenesis
+	if cGenesis == "" {
+		data, err := b.cchainBuilder.Build().ToJSON()
+		if err != nil {
+			return amount.Amount{}, 0, err
+		}
+		cGenesis = string(data)
+	}
+
+	var parsed struct {
+		Alloc map[string]struct {
+			Balance string `json:"balance"`
+		} `json:"alloc"`
+	}
+	if err := json.Unmarshal([]byte(cGenesis), &parsed); err != nil {
+		return amount.Amount{}, 0, fmt.Errorf("failed to parse C-Chain genesis: %w", err)
+	}
+
+	total := new(big.Int)
+	for addr, account := range parsed.Alloc {
+		balance, ok := parseBalance(account.Balance)
+		if !ok {
+			return amount.Amount{}, 0, fmt.Errorf("invalid C-Chain balance %q for %s", account.Balance, addr)
+		}
+		total.Add(total, balance)
+	}
+	return amount.New(total, amount.Wei), len(parsed.Alloc), nil
+}
+
+// parseBalance parses a decimal or 0x-prefixed hex balance
+func parseBalance(balance string) (*big.Int, bool) {
+	if balance == "" {
+		return new(big.Int), true
+	}
+	if strings.HasPrefix(balance, "0x") || strings.HasPrefix(balance, "0X") {
+		return new(big.Int).SetString(balance[2:], 16)
+	}
+	return new(big.Int).SetString(balance, 10)
+}
+
+// Print writes the report in the command output style
+func (r *SupplyReport) Print(w io.Writer) {
+	fmt.Fprintln(w, "=== Supply Reconciliation ===")
+	fmt.Fprintf(w, "P/X allocations:   %d\n", r.Allocations)
+	fmt.Fprintf(w, "  Unlocked:        %s (%s nLUX)\n", r.PXUnlocked, r.PXUnlocked.Value())
+	fmt.Fprintf(w, "  Locked:          %s (%s nLUX)\n", r.PXLocked, r.PXLocked.Value())
+	fmt.Fprintf(w, "C-Chain accounts:  %d\n", r.CChainAccounts)
+	fmt.Fprintf(w, "  Balance:         %s (%s wei)\n", r.CChain, r.CChain.Value())
+	fmt.Fprintf(w, "Total:             %s\n", r.Total)
+	fmt.Fprintf(w, "Intended:          %s\n", r.Intended)
+	fmt.Fprintf(w, "Difference:        %s (%s wei)\n", r.Difference, r.Difference.Value())
+	if r.Dust.Sign() != 0 {
+		fmt.Fprintf(w, "Rounding dust:     %s wei (policy %s)\n", r.Dust.Value(), r.Rounding)
+	}
+	if r.Balanced() {
+		fmt.Fprintln(w, "✅ Supply reconciles")
+	} else {
+		fmt.Fprintln(w, "❌ Supply does not reconcile")
+	}
+}
@@ -0,0 +1,72 @@
+package genesis
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+)
+
+func TestBuildOverflow(t *testing.T) {
+	builder, err := NewBuilder("mainnet")
+	require.NoError(t, err)
+
+	// 2^64 nLUX does not fit the node's uint64 amounts
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 64)
+	require.NoError(t, builder.AddAllocation("0x1234567890123456789012345678901234567890", tooBig))
+
+	_, err = builder.Build()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, amount.ErrOverflow))
+}
+
+func TestAddAllocationWei(t *testing.T) {
+	builder, err := NewBuilder("mainnet")
+	require.NoError(t, err)
+
+	// 1 LUX and 1 wei is not a whole number of nLUX
+	wei, _ := new(big.Int).SetString("1000000000000000001", 10)
+	err = builder.AddAllocationWei("0x1234567890123456789012345678901234567890", wei)
+	assert.True(t, errors.Is(err, amount.ErrPrecisionLoss))
+
+	builder.SetRounding(amount.RoundDown)
+	require.NoError(t, builder.AddAllocationWei("0x1234567890123456789012345678901234567890", wei))
+	assert.Equal(t, "1000000000", builder.GetTotalSupply().String())
+
+	report, err := builder.ReconcileSupply(amount.New(wei, amount.Wei))
+	require.NoError(t, err)
+	assert.Equal(t, "1", report.Dust.Value().String())
+	assert.Equal(t, "-1", report.Difference.Value().String())
+	assert.False(t, report.Balanced())
+}
+
+func TestReconcileSupply(t *testing.T) {
+	builder, err := NewBuilder("mainnet")
+	require.NoError(t, err)
+
+	// 1000 LUX on P/X
+	require.NoError(t, builder.AddAllocation("0x1234567890123456789012345678901234567890", big.NewInt(1_000_000_000_000)))
+	// 500 LUX on the C-Chain
+	builder.SetCChainGenesis(`{"config": {"chainId": 96369}, "alloc": {
+		"0x0987654321098765432109876543210987654321": {"balance": "0x1b1ae4d6e2ef500000"}}}`)
+
+	intended, err := amount.Parse("1500", amount.NLUX)
+	require.NoError(t, err)
+	report, err := builder.ReconcileSupply(intended)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Allocations)
+	assert.Equal(t, 1, report.CChainAccounts)
+	assert.Equal(t, "500 LUX", report.CChain.String())
+	assert.Equal(t, "1500 LUX", report.Total.String())
+	assert.True(t, report.Balanced())
+
+	intended, _ = amount.Parse("1500.000000001", amount.NLUX)
+	report, err = builder.ReconcileSupply(intended)
+	require.NoError(t, err)
+	assert.False(t, report.Balanced())
+}
@@ -6,16 +6,19 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
 )
 
-// FormatLux converts wei to LUX string representation
-func FormatLux(wei *big.Int) string {
-	if wei == nil {
+// FormatLux formats an nLUX (P/X-Chain, 9 decimals) amount as LUX rounded to
+// two decimals. Use amount.Amount.String for the exact value.
+func FormatLux(nlux *big.Int) string {
+	if nlux == nil {
 		return "0"
 	}
-	lux := new(big.Float).SetInt(wei)
-	lux.Quo(lux, big.NewFloat(1e9))
-	return lux.Text('f', 2)
+	cents, _ := amount.New(nlux, amount.NLUX).Convert(2, amount.RoundHalfEven)
+	quo, rem := new(big.Int).QuoRem(cents.Value(), big.NewInt(100), new(big.Int))
+	return fmt.Sprintf("%s.%02d", quo, rem)
 }
 
 // SaveJSON saves data as formatted JSON to file
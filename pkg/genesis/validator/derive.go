@@ -0,0 +1,172 @@
+package validator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+)
+
+// Derivation paths for validator N of a mnemonic.
+//
+// The BLS key follows EIP-2333/EIP-2334 with the Lux coin type (9000); the
+// path has no hardened markers because every EIP-2333 step is hardened. The
+// reward key is the BIP-44 Ethereum path, so its C-Chain address is the one
+// wallets show for the same mnemonic and account. The TLS key is derived
+// with HKDF from the BIP-39 seed, see TLSDerivationPath.
+const (
+	BLSPathFormat    = "m/12381/9000/%d/0/0"
+	RewardPathFormat = "m/44'/60'/0'/0/%d"
+)
+
+// HardenedOffset is added to BIP-32 indexes marked with '
+const HardenedOffset uint32 = 1 << 31
+
+// BLS12-381 group order
+var blsOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+// ParseDerivationPath parses a path such as m/44'/60'/0'/0/0 into indexes,
+// with HardenedOffset added to indexes marked ' or h
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		n, err := strconv.ParseUint(strings.TrimRight(part, "'h"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: bad index %q", path, part)
+		}
+		index := uint32(n)
+		if hardened {
+			index += HardenedOffset
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// DeriveBLSKey derives a BLS secret key from a seed along an EIP-2333 path
+// and returns it as 32 big-endian bytes
+func DeriveBLSKey(seed []byte, path string) ([]byte, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("seed must be at least 32 bytes, got %d", len(seed))
+	}
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk := hkdfModR(seed)
+	for _, index := range indexes {
+		if index >= HardenedOffset {
+			return nil, fmt.Errorf("invalid EIP-2333 path %q: indexes are always hardened, drop the '", path)
+		}
+		sk = hkdfModR(lamportPublicKey(sk, index))
+	}
+	return sk.FillBytes(make([]byte, 32)), nil
+}
+
+// hkdfModR is HKDF_mod_r from EIP-2333
+func hkdfModR(ikm []byte) *big.Int {
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	okm := make([]byte, 48)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, append(append([]byte{}, ikm...), 0), salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte{0, 48}), okm); err != nil {
+			panic(err) // 48 bytes is far below the HKDF limit
+		}
+		sk.SetBytes(okm).Mod(sk, blsOrder)
+	}
+	return sk
+}
+
+// lamportPublicKey is parent_SK_to_lamport_PK from EIP-2333, returning the
+// compressed Lamport public key of a child index
+func lamportPublicKey(parent *big.Int, index uint32) []byte {
+	salt := binary.BigEndian.AppendUint32(nil, index)
+	ikm := parent.FillBytes(make([]byte, 32))
+	notIKM := make([]byte, 32)
+	for i, b := range ikm {
+		notIKM[i] = ^b
+	}
+
+	pk := sha256.New()
+	chunk := make([]byte, 32)
+	for _, secret := range [][]byte{ikm, notIKM} {
+		okm := hkdf.New(sha256.New, secret, salt, nil)
+		for i := 0; i < 255; i++ {
+			if _, err := io.ReadFull(okm, chunk); err != nil {
+				panic(err) // 255 * 32 bytes is the HKDF-SHA256 limit
+			}
+			h := sha256.Sum256(chunk)
+			pk.Write(h[:])
+		}
+	}
+	return pk.Sum(nil)
+}
+
+// DeriveSecp256k1Key derives a secp256k1 key from a seed along a BIP-32 path
+func DeriveSecp256k1Key(seed []byte, path string) (*secp256k1.PrivateKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be 16 to 64 bytes, got %d", len(seed))
+	}
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	master := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	master.Write(seed)
+	i := master.Sum(nil)
+	var key secp256k1.ModNScalar
+	if overflow := key.SetByteSlice(i[:32]); overflow || key.IsZero() {
+		return nil, fmt.Errorf("invalid master key, use another seed")
+	}
+	chainCode := i[32:]
+
+	for _, index := range indexes {
+		var data []byte
+		if index >= HardenedOffset {
+			keyBytes := key.Bytes()
+			data = append([]byte{0}, keyBytes[:]...)
+		} else {
+			data = secp256k1.NewPrivateKey(&key).PubKey().SerializeCompressed()
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		var tweak secp256k1.ModNScalar
+		if overflow := tweak.SetByteSlice(i[:32]); overflow {
+			return nil, fmt.Errorf("invalid child key at index %d of %s", index, path)
+		}
+		key.Add(&tweak)
+		if key.IsZero() {
+			return nil, fmt.Errorf("invalid child key at index %d of %s", index, path)
+		}
+		chainCode = i[32:]
+	}
+	return secp256k1.NewPrivateKey(&key), nil
+}
+
+// ethAddress returns the C-Chain (Ethereum) address of a public key
+func ethAddress(pub *secp256k1.PublicKey) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pub.SerializeUncompressed()[1:])
+	return fmt.Sprintf("0x%x", h.Sum(nil)[12:])
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestValidateMnemonic(t *testing.T) {
+	assert.NoError(t, ValidateMnemonic(testMnemonic))
+	assert.NoError(t, ValidateMnemonic("  test test test test test test\ttest test test test test junk "))
+
+	for _, m := range []string{
+		"",
+		"test test test",
+		"test test test test test test test test test test test test", // checksum
+		"test test test test test test test test test test test junkk",
+	} {
+		assert.ErrorIs(t, ValidateMnemonic(m), ErrInvalidMnemonic, m)
+	}
+
+	for _, bits := range []int{128, 256} {
+		m, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		assert.NoError(t, ValidateMnemonic(m))
+	}
+	_, err := NewMnemonic(100)
+	assert.Error(t, err)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indexes, err := ParseDerivationPath("m/44'/60'/0h/0/7")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44 + HardenedOffset, 60 + HardenedOffset, HardenedOffset, 0, 7}, indexes)
+
+	for _, path := range []string{"", "44/60", "m/x", "m/-1", "m/2147483648"} {
+		_, err := ParseDerivationPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+// EIP-2333 test case 0
+func TestDeriveBLSKey(t *testing.T) {
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+
+	master, err := DeriveBLSKey(seed, "m")
+	require.NoError(t, err)
+	assert.Equal(t, "6083874454709270928345386274498605044986640685124978867557563392430687146096", new(big.Int).SetBytes(master).String())
+
+	child, err := DeriveBLSKey(seed, "m/0")
+	require.NoError(t, err)
+	assert.Equal(t, "20397789859736650942317412262472558107875392172444076792671091975210932703118", new(big.Int).SetBytes(child).String())
+
+	_, err = DeriveBLSKey(seed, "m/0'")
+	assert.Error(t, err)
+	_, err = DeriveBLSKey(seed[:16], "m")
+	assert.Error(t, err)
+}
+
+func TestDeriveSecp256k1Key(t *testing.T) {
+	// BIP-32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	key, err := DeriveSecp256k1Key(seed, "m/0'")
+	require.NoError(t, err)
+	assert.Equal(t, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", hex.EncodeToString(key.Serialize()))
+	key, err = DeriveSecp256k1Key(seed, "m/0'/1")
+	require.NoError(t, err)
+	assert.Equal(t, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", hex.EncodeToString(key.Serialize()))
+
+	// The reward path matches the first account wallets derive
+	seed, err = SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+	key, err = DeriveSecp256k1Key(seed, fmt.Sprintf(RewardPathFormat, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", ethAddress(key.PubKey()))
+}
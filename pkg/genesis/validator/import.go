@@ -0,0 +1,130 @@
+package validator
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// OperatorInfoFile is the optional file of an operator submission with the
+// registry fields the staking files cannot provide
+const OperatorInfoFile = "operator.json"
+
+// OperatorInfo is the content of operator.json. Zero fields fall back to the
+// import defaults.
+type OperatorInfo struct {
+	Name          string `json:"name,omitempty"`
+	ETHAddress    string `json:"ethAddress,omitempty"`
+	RewardAddress string `json:"rewardAddress,omitempty"`
+	Weight        uint64 `json:"weight,omitempty"` // nLUX
+	DelegationFee uint32 `json:"delegationFee,omitempty"`
+}
+
+// Submission is the public material imported from an operator's luxd
+// staking directory
+type Submission struct {
+	Dir               string
+	Keys              *ValidatorKeys // PrivateKey is never set
+	Operator          *OperatorInfo  // nil without operator.json
+	CertificateSHA256 string
+}
+
+// ImportStakingDir reads staker.crt, staker.key and signer.key from a luxd
+// staking directory, checks that the TLS key belongs to the certificate and
+// derives the NodeID, BLS public key and proof of possession. Only public
+// material is returned; the private keys stay in dir.
+func ImportStakingDir(dir string) (*Submission, error) {
+	keys, err := LoadPlaintextKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTLSKeyPair(keys.TLSCertBytes, keys.TLSKeyBytes); err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keys.TLSCertBytes)
+	certHash := sha256.Sum256(block.Bytes)
+
+	public := *keys.ValidatorKeys
+	public.PrivateKey = ""
+	s := &Submission{
+		Dir:               dir,
+		Keys:              &public,
+		CertificateSHA256: hex.EncodeToString(certHash[:]),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, OperatorInfoFile))
+	switch {
+	case err == nil:
+		s.Operator = &OperatorInfo{}
+		if err := json.Unmarshal(data, s.Operator); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", OperatorInfoFile, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read %s: %w", OperatorInfoFile, err)
+	}
+	return s, nil
+}
+
+// FindSubmissions returns the subdirectories of dir that hold a staking
+// certificate, one per operator submission, sorted by name
+func FindSubmissions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read submissions directory: %w", err)
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		sub := filepath.Join(dir, e.Name())
+		for _, cert := range []string{StakerCertFile, filepath.Join("staking", StakerCertFile)} {
+			if _, err := os.Stat(filepath.Join(sub, cert)); err == nil {
+				dirs = append(dirs, sub)
+				break
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no staking directories in %s", dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// checkTLSKeyPair checks that a PEM staking key belongs to a PEM certificate
+func checkTLSKeyPair(certPEM, keyPEM []byte) error {
+	der, err := pkcs8Key(keyPEM)
+	if err != nil {
+		return err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse TLS key: %w", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return fmt.Errorf("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported TLS key type %T", key)
+	}
+	pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return fmt.Errorf("%s does not belong to %s", StakerKeyFile, StakerCertFile)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStakingDir writes the luxd staking files of keys to dir
+func writeStakingDir(t *testing.T, keys *ValidatorKeysWithTLS, dir string) {
+	blsKey, err := hex.DecodeString(strings.TrimPrefix(keys.PrivateKey, "0x"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, StakerKeyFile), keys.TLSKeyBytes, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, StakerCertFile), keys.TLSCertBytes, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SignerKeyFile), blsKey, 0600))
+}
+
+func TestImportStakingDir(t *testing.T) {
+	keygen := NewKeyGenerator("")
+	keys0, err := keygen.GenerateFromSeedWithTLS(testMnemonic, 0)
+	require.NoError(t, err)
+	keys1, err := keygen.GenerateFromSeedWithTLS(testMnemonic, 1)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "operator-a")
+	writeStakingDir(t, keys0, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, OperatorInfoFile),
+		[]byte(`{"name": "Operator A", "ethAddress": "0x1111111111111111111111111111111111111111", "delegationFee": 30000}`), 0644))
+
+	s, err := ImportStakingDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, keys0.NodeID, s.Keys.NodeID)
+	assert.Equal(t, keys0.PublicKey, s.Keys.PublicKey)
+	assert.Equal(t, keys0.ProofOfPossession, s.Keys.ProofOfPossession)
+	assert.Empty(t, s.Keys.PrivateKey)
+	assert.Len(t, s.CertificateSHA256, 64)
+	require.NotNil(t, s.Operator)
+	assert.Equal(t, "Operator A", s.Operator.Name)
+	assert.Equal(t, uint32(30000), s.Operator.DelegationFee)
+
+	// A staking key that does not belong to the certificate is rejected
+	bad := filepath.Join(root, "operator-b")
+	writeStakingDir(t, keys1, bad)
+	require.NoError(t, os.WriteFile(filepath.Join(bad, StakerKeyFile), keys0.TLSKeyBytes, 0600))
+	_, err = ImportStakingDir(bad)
+	assert.ErrorContains(t, err, "does not belong")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), 0700))
+	dirs, err := FindSubmissions(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir, bad}, dirs)
+
+	_, err = FindSubmissions(filepath.Join(root, "notes"))
+	assert.Error(t, err)
+}
+
+func TestManifestSignOff(t *testing.T) {
+	m := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Network:   "mainnet",
+		Registry:  "configs/mainnet-validators.json",
+		Entries: []ManifestEntry{
+			{Name: "operator-a", NodeID: "NodeID-a", Status: StatusAccepted},
+			{Name: "operator-b", Status: StatusRejected, Issues: []string{"duplicate NodeID"}},
+		},
+	}
+	assert.Equal(t, 1, m.Accepted())
+	assert.ErrorContains(t, m.Verify(), "not signed")
+
+	key, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, m.Sign(key))
+	assert.Equal(t, ethAddress(key.PubKey()), m.SignedOffBy)
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, m.Save(path))
+	loaded, err := LoadManifest(path)
+	require.NoError(t, err)
+	require.NoError(t, loaded.Verify())
+
+	// Any change to the entries breaks the sign-off
+	loaded.Entries[1].Status = StatusAccepted
+	assert.Error(t, loaded.Verify())
+
+	// So does claiming another signer
+	loaded, err = LoadManifest(path)
+	require.NoError(t, err)
+	loaded.SignedOffBy = "0x1111111111111111111111111111111111111111"
+	assert.Error(t, loaded.Verify())
+}
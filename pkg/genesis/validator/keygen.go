@@ -1,87 +1,79 @@
 package validator
 
 import (
-	"crypto/sha256"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
-	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/luxfi/ids"
 	"github.com/luxfi/node/staking"
-	"github.com/luxfi/node/utils/crypto/bls"
-	"github.com/luxfi/node/utils/crypto/bls/signer/localsigner"
-	// "github.com/luxfi/node/utils/formatting/address" // Not needed now
+	"github.com/luxfi/node/utils/formatting/address"
+	"github.com/luxfi/node/utils/hashing"
 )
 
 // KeyGenerator handles validator key generation using luxd
 type KeyGenerator struct {
 	luxdPath string
+	hrp      string // for reward addresses
 }
 
 // NewKeyGenerator creates a new key generator
 func NewKeyGenerator(luxdPath string) *KeyGenerator {
 	return &KeyGenerator{
 		luxdPath: luxdPath,
+		hrp:      "lux",
 	}
 }
 
+// SetHRP sets the human-readable part of derived reward addresses
+func (kg *KeyGenerator) SetHRP(hrp string) {
+	kg.hrp = hrp
+}
+
 // ValidatorKeys contains the generated validator key information
 type ValidatorKeys struct {
 	NodeID            string `json:"nodeID"`
 	PublicKey         string `json:"publicKey"`
 	ProofOfPossession string `json:"proofOfPossession"`
 	PrivateKey        string `json:"privateKey,omitempty"` // Only for secure storage, never in genesis
+	RewardAddress     string `json:"rewardAddress,omitempty"` // P-Chain address of the reward key
+	ETHAddress        string `json:"ethAddress,omitempty"`    // C-Chain address of the reward key
 }
 
-// GenerateFromSeed generates validator keys using BLS with deterministic TLS cert
-func (kg *KeyGenerator) GenerateFromSeedWithTLS(seedPhrase string, accountNum int) (*ValidatorKeysWithTLS, error) {
-	// Use deterministic derivation
-	seedData := fmt.Sprintf("%s-luxnode-%d", seedPhrase, accountNum)
-	
-	// Create deterministic private key using HKDF-like approach
-	h := sha256.New()
-	h.Write([]byte("lux-bls-key"))
-	h.Write([]byte(seedData))
-	seed := h.Sum(nil)
-	
-	// BLS12-381 curve order
-	curveOrder, _ := new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
-	
-	// Convert seed to big int and reduce modulo curve order
-	keyInt := new(big.Int).SetBytes(seed)
-	keyInt.Mod(keyInt, curveOrder)
-	
-	// Ensure non-zero
-	if keyInt.Sign() == 0 {
-		keyInt.SetInt64(1)
+// GenerateFromSeedWithTLS derives every key of validator accountNum from a
+// BIP-39 mnemonic: the BLS key along BLSPathFormat, the staking TLS
+// certificate and so the NodeID (see TLSDerivationPath), and the reward key
+// along RewardPathFormat. The same mnemonic and account always give the
+// same keys.
+func (kg *KeyGenerator) GenerateFromSeedWithTLS(mnemonic string, accountNum int) (*ValidatorKeysWithTLS, error) {
+	if accountNum < 0 {
+		return nil, fmt.Errorf("invalid account index: %d", accountNum)
+	}
+	seed, err := SeedFromMnemonic(mnemonic, "")
+	if err != nil {
+		return nil, err
 	}
-	
-	// Convert back to 32 bytes
-	keyBytes := make([]byte, 32)
-	keyInt.FillBytes(keyBytes)
-	
-	// Create signer from the deterministic key
-	signer, err := localsigner.FromBytes(keyBytes)
+
+	blsKey, err := DeriveBLSKey(seed, fmt.Sprintf(BLSPathFormat, accountNum))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate BLS signer: %w", err)
+		return nil, fmt.Errorf("failed to derive BLS key: %w", err)
 	}
-	
-	pk := signer.PublicKey()
-	
-	// Generate proof of possession
-	pop, err := signer.SignProofOfPossession(bls.PublicKeyToCompressedBytes(pk))
+	signer, err := NewLocalSigner(blsKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign proof of possession: %w", err)
+		return nil, err
+	}
+	publicKey, pop, err := ProofOfPossession(context.Background(), signer)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Generate TLS certificate for NodeID (deterministic based on account)
-	// For production, you'd want proper deterministic TLS generation
-	tlsCertPEM, tlsKeyPEM, err := staking.NewCertAndKeyBytes()
+	// Derive the TLS certificate so the NodeID is stable for the account
+	tlsCertPEM, tlsKeyPEM, err := NewDeterministicCertAndKeyBytes(seed, accountNum)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate TLS cert: %w", err)
 	}
@@ -98,19 +90,31 @@ func (kg *KeyGenerator) GenerateFromSeedWithTLS(seedPhrase string, accountNum in
 	}
 	
 	nodeID := ids.NodeIDFromCert(cert)
+
+	rewardKey, err := DeriveSecp256k1Key(seed, fmt.Sprintf(RewardPathFormat, accountNum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive reward key: %w", err)
+	}
+	rewardAddr, err := address.Format("P", kg.hrp, hashing.PubkeyBytesToAddress(rewardKey.PubKey().SerializeCompressed()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format reward address: %w", err)
+	}
 	
 	// Create validator keys
 	keys := &ValidatorKeys{
 		NodeID:            nodeID.String(),
-		PublicKey:         "0x" + hex.EncodeToString(bls.PublicKeyToCompressedBytes(pk)),
-		ProofOfPossession: "0x" + hex.EncodeToString(bls.SignatureToBytes(pop)),
-		PrivateKey:        "0x" + hex.EncodeToString(signer.ToBytes()),
+		PublicKey:         publicKey,
+		ProofOfPossession: pop,
+		PrivateKey:        "0x" + hex.EncodeToString(signer.Bytes()),
+		RewardAddress:     rewardAddr,
+		ETHAddress:        ethAddress(rewardKey.PubKey()),
 	}
 	
 	return &ValidatorKeysWithTLS{
-		ValidatorKeys: keys,
-		TLSKeyBytes:   tlsKeyPEM,
-		TLSCertBytes:  tlsCertPEM,
+		ValidatorKeys:    keys,
+		TLSKeyBytes:      tlsKeyPEM,
+		TLSCertBytes:     tlsCertPEM,
+		RewardPrivateKey: "0x" + hex.EncodeToString(rewardKey.Serialize()),
 	}, nil
 }
 
@@ -177,25 +181,24 @@ func SaveValidatorConfigs(validators []*ValidatorInfo, filepath string) error {
 // ValidatorKeysWithTLS contains validator keys and TLS certificate data
 type ValidatorKeysWithTLS struct {
 	*ValidatorKeys
-	TLSKeyBytes  []byte
-	TLSCertBytes []byte
+	TLSKeyBytes      []byte
+	TLSCertBytes     []byte
+	RewardPrivateKey string // hex secp256k1 key, only set for derived keys
 }
 
 // GenerateCompatibleKeys generates validator keys compatible with luxd
 // This uses the same TLS-based NodeID generation as luxd
 func (kg *KeyGenerator) GenerateCompatibleKeys() (*ValidatorKeysWithTLS, error) {
 	// Generate BLS key pair
-	signer, err := localsigner.New()
+	signer, err := GenerateLocalSigner()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate BLS signer: %w", err)
+		return nil, err
 	}
 	
-	pk := signer.PublicKey()
-	
 	// Generate proof of possession
-	pop, err := signer.SignProofOfPossession(bls.PublicKeyToCompressedBytes(pk))
+	publicKey, pop, err := ProofOfPossession(context.Background(), signer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign proof of possession: %w", err)
+		return nil, err
 	}
 	
 	// Generate TLS certificate for NodeID
@@ -225,9 +228,9 @@ func (kg *KeyGenerator) GenerateCompatibleKeys() (*ValidatorKeysWithTLS, error)
 	
 	keys := &ValidatorKeys{
 		NodeID:            nodeID.String(),
-		PublicKey:         "0x" + hex.EncodeToString(bls.PublicKeyToCompressedBytes(pk)),
-		ProofOfPossession: "0x" + hex.EncodeToString(bls.SignatureToBytes(pop)),
-		PrivateKey:        "0x" + hex.EncodeToString(signer.ToBytes()), // Include private key for secure storage
+		PublicKey:         publicKey,
+		ProofOfPossession: pop,
+		PrivateKey:        "0x" + hex.EncodeToString(signer.Bytes()), // Include private key for secure storage
 	}
 	
 	// Just use the clean NodeID without extra information
@@ -240,7 +243,8 @@ func (kg *KeyGenerator) GenerateCompatibleKeys() (*ValidatorKeysWithTLS, error)
 	}, nil
 }
 
-// SaveKeys saves validator keys and certificates to disk
+// SaveKeys saves validator keys and certificates to disk. The BLS key is
+// written in the clear; use SaveEncryptedKeys for keys kept at rest.
 func SaveKeys(keys *ValidatorKeys, outputDir string) error {
 	// Create output directory
 	if err := os.MkdirAll(outputDir, 0700); err != nil {
@@ -282,7 +286,8 @@ func SaveKeys(keys *ValidatorKeys, outputDir string) error {
 	return nil
 }
 
-// SaveStakingFiles saves the TLS key and certificate files for a validator
+// SaveStakingFiles saves the TLS key and certificate files for a validator.
+// The key is written in the clear; see WriteStakingFiles.
 func SaveStakingFiles(tlsKeyBytes, tlsCertBytes []byte, outputDir string) error {
 	// Create staking directory
 	stakingDir := filepath.Join(outputDir, "staking")
@@ -315,17 +320,15 @@ func (kg *KeyGenerator) GenerateFromPrivateKey(privateKeyHex string) (*Validator
 	}
 	
 	// Create signer from private key
-	signer, err := localsigner.FromBytes(privKeyBytes)
+	signer, err := NewLocalSigner(privKeyBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create signer from private key: %w", err)
+		return nil, err
 	}
 	
-	pk := signer.PublicKey()
-	
 	// Generate proof of possession
-	pop, err := signer.SignProofOfPossession(bls.PublicKeyToCompressedBytes(pk))
+	publicKey, pop, err := ProofOfPossession(context.Background(), signer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign proof of possession: %w", err)
+		return nil, err
 	}
 	
 	// Generate TLS certificate for NodeID
@@ -350,8 +353,8 @@ func (kg *KeyGenerator) GenerateFromPrivateKey(privateKeyHex string) (*Validator
 	// Create validator keys
 	keys := &ValidatorKeys{
 		NodeID:            nodeID.String(),
-		PublicKey:         "0x" + hex.EncodeToString(bls.PublicKeyToCompressedBytes(pk)),
-		ProofOfPossession: "0x" + hex.EncodeToString(bls.SignatureToBytes(pop)),
+		PublicKey:         publicKey,
+		ProofOfPossession: pop,
 		PrivateKey:        "0x" + privateKeyHex,
 	}
 	
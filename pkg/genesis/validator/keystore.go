@@ -0,0 +1,263 @@
+package validator
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/staking"
+
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+)
+
+// Keystore files in an encrypted validator key directory
+const (
+	SignerKeystoreFile = "signer" + keystore.Extension
+	StakerKeystoreFile = "staker" + keystore.Extension
+	RewardKeystoreFile = "reward" + keystore.Extension
+	ValidatorInfoFile  = "validator.json"
+)
+
+// Staking files luxd reads from its staking directory
+const (
+	StakerKeyFile  = "staker.key"
+	StakerCertFile = "staker.crt"
+	SignerKeyFile  = "signer.key"
+)
+
+// SaveEncryptedKeys writes the public validator.json and one keystore per
+// private key - BLS signer, staking TLS and, for derived keys, the reward
+// key - to outputDir. Nothing secret is written in the clear; luxd-ready
+// files are produced later with WriteStakingFiles.
+func SaveEncryptedKeys(keys *ValidatorKeysWithTLS, outputDir, password string, kdf keystore.KDF) error {
+	if err := saveValidatorInfo(keys.ValidatorKeys, outputDir); err != nil {
+		return err
+	}
+
+	blsKey, err := hex.DecodeString(strings.TrimPrefix(keys.PrivateKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("failed to decode BLS key: %w", err)
+	}
+	tlsKey, err := pkcs8Key(keys.TLSKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		file   string
+		typ    keystore.Type
+		secret []byte
+		public string
+	}
+	entries := []entry{
+		{SignerKeystoreFile, keystore.TypeBLS, blsKey, keys.PublicKey},
+		{StakerKeystoreFile, keystore.TypeTLS, tlsKey, string(keys.TLSCertBytes)},
+	}
+	if keys.RewardPrivateKey != "" {
+		rewardKey, err := hex.DecodeString(strings.TrimPrefix(keys.RewardPrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to decode reward key: %w", err)
+		}
+		pub := secp256k1.PrivKeyFromBytes(rewardKey).PubKey().SerializeCompressed()
+		entries = append(entries, entry{RewardKeystoreFile, keystore.TypeSecp256k1, rewardKey, "0x" + hex.EncodeToString(pub)})
+	}
+
+	for _, e := range entries {
+		k, err := keystore.Encrypt(e.typ, keys.NodeID, e.secret, e.public, password, kdf)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", e.file, err)
+		}
+		if err := k.Save(filepath.Join(outputDir, e.file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteStakingFiles decrypts the keystores in keystoreDir and writes the
+// luxd-ready staker.key, staker.crt and signer.key to stakingDir, returning
+// the NodeID. Run it at deploy time on the validator host.
+func WriteStakingFiles(keystoreDir, password, stakingDir string) (string, error) {
+	signer, err := keystore.Load(filepath.Join(keystoreDir, SignerKeystoreFile))
+	if err != nil {
+		return "", err
+	}
+	blsKey, err := DecryptKeystore(signer, password)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", SignerKeystoreFile, err)
+	}
+	staker, err := keystore.Load(filepath.Join(keystoreDir, StakerKeystoreFile))
+	if err != nil {
+		return "", err
+	}
+	tlsKey, err := staker.Decrypt(password)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", StakerKeystoreFile, err)
+	}
+
+	certPEM := []byte(staker.Certificate)
+	keys, err := keysFromMaterial(blsKey, certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: tlsKey}))
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(stakingDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create staking directory: %w", err)
+	}
+	files := []struct {
+		name string
+		data []byte
+		perm os.FileMode
+	}{
+		{StakerKeyFile, keys.TLSKeyBytes, 0600},
+		{StakerCertFile, keys.TLSCertBytes, 0644},
+		{SignerKeyFile, blsKey, 0600},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(stakingDir, f.name), f.data, f.perm); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	return keys.NodeID, nil
+}
+
+// DecryptKeystore decrypts a keystore and, for BLS keys, checks the key
+// against the stored public key
+func DecryptKeystore(k *keystore.Key, password string) ([]byte, error) {
+	secret, err := k.Decrypt(password)
+	if err != nil {
+		return nil, err
+	}
+	if k.Type == keystore.TypeBLS && k.PublicKey != "" {
+		signer, err := NewLocalSigner(secret)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := signer.PublicKey(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive BLS public key: %w", err)
+		}
+		if "0x"+hex.EncodeToString(pub) != k.PublicKey {
+			return nil, fmt.Errorf("BLS key does not match its public key")
+		}
+	}
+	return secret, nil
+}
+
+// LoadPlaintextKeys reads the plaintext keys of one validator: either a
+// directory written by SaveKeys and SaveStakingFiles (bls.key and
+// staking/staker.*) or a luxd staking directory (signer.key and staker.*)
+func LoadPlaintextKeys(dir string) (*ValidatorKeysWithTLS, error) {
+	find := func(names ...string) ([]byte, error) {
+		for _, name := range names {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err == nil {
+				return data, nil
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("none of %s found in %s", strings.Join(names, ", "), dir)
+	}
+
+	blsKey, err := find("bls.key", SignerKeyFile, filepath.Join("staking", SignerKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	certPEM, err := find(StakerCertFile, filepath.Join("staking", StakerCertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := find(StakerKeyFile, filepath.Join("staking", StakerKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return keysFromMaterial(blsKey, certPEM, keyPEM)
+}
+
+// keysFromMaterial rebuilds validator keys from a BLS key and a staking
+// certificate and key
+func keysFromMaterial(blsKey, certPEM, keyPEM []byte) (*ValidatorKeysWithTLS, error) {
+	signer, err := NewLocalSigner(blsKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, pop, err := ProofOfPossession(context.Background(), signer)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode certificate PEM")
+	}
+	cert, err := staking.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return &ValidatorKeysWithTLS{
+		ValidatorKeys: &ValidatorKeys{
+			NodeID:            ids.NodeIDFromCert(cert).String(),
+			PublicKey:         publicKey,
+			ProofOfPossession: pop,
+			PrivateKey:        "0x" + hex.EncodeToString(blsKey),
+		},
+		TLSKeyBytes:  keyPEM,
+		TLSCertBytes: certPEM,
+	}, nil
+}
+
+// pkcs8Key returns a PEM staking key as PKCS #8 DER, converting the PKCS #1
+// RSA and SEC 1 EC keys of older luxd staking directories
+func pkcs8Key(keyPEM []byte) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode TLS key PEM")
+	}
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		return block.Bytes, nil
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported TLS key PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TLS key: %w", err)
+	}
+	return x509.MarshalPKCS8PrivateKey(key)
+}
+
+// saveValidatorInfo writes the public fields of keys to validator.json
+func saveValidatorInfo(keys *ValidatorKeys, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	public := *keys
+	public.PrivateKey = ""
+	data, err := json.MarshalIndent(public, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validator info: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, ValidatorInfoFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write validator info: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/genesis/keystore"
+)
+
+func TestEncryptedKeysRoundTrip(t *testing.T) {
+	scryptN := keystore.ScryptN
+	keystore.ScryptN = 1 << 10
+	t.Cleanup(func() { keystore.ScryptN = scryptN })
+
+	keys, err := NewKeyGenerator("").GenerateFromSeedWithTLS(testMnemonic, 0)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "validator-0")
+	require.NoError(t, SaveEncryptedKeys(keys, dir, "pw", keystore.KDFScrypt))
+	for _, name := range []string{SignerKeystoreFile, StakerKeystoreFile, RewardKeystoreFile, ValidatorInfoFile} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	// No private material in the clear
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	bls := strings.TrimPrefix(keys.PrivateKey, "0x")
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), bls, e.Name())
+		assert.NotContains(t, string(data), "PRIVATE KEY", e.Name())
+	}
+
+	_, err = WriteStakingFiles(dir, "wrong", t.TempDir())
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	// Deploy time: luxd-ready files with the same NodeID
+	stakingDir := t.TempDir()
+	nodeID, err := WriteStakingFiles(dir, "pw", stakingDir)
+	require.NoError(t, err)
+	assert.Equal(t, keys.NodeID, nodeID)
+
+	loaded, err := LoadPlaintextKeys(stakingDir)
+	require.NoError(t, err)
+	assert.Equal(t, keys.NodeID, loaded.NodeID)
+	assert.Equal(t, keys.PublicKey, loaded.PublicKey)
+	assert.Equal(t, keys.TLSCertBytes, loaded.TLSCertBytes)
+}
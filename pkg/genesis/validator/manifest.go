@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// ManifestVersion is the import manifest format written by this build
+const ManifestVersion = 1
+
+// Manifest entry statuses
+const (
+	StatusAccepted = "accepted"
+	StatusRejected = "rejected"
+)
+
+// Manifest records a batch of operator submissions imported into the
+// validator registry. It is signed off with a secp256k1 key: Signature is
+// a 65 byte recoverable signature over Digest, and SignedOffBy the C-Chain
+// address of the key.
+type Manifest struct {
+	Version     int             `json:"version"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Network     string          `json:"network"`
+	Registry    string          `json:"registry"`
+	Entries     []ManifestEntry `json:"entries"`
+	SignedOffBy string          `json:"signedOffBy,omitempty"`
+	Signature   string          `json:"signature,omitempty"`
+}
+
+// ManifestEntry is one imported submission
+type ManifestEntry struct {
+	Name              string   `json:"name"`
+	Source            string   `json:"source"`
+	NodeID            string   `json:"nodeID,omitempty"`
+	PublicKey         string   `json:"publicKey,omitempty"`
+	ProofOfPossession string   `json:"proofOfPossession,omitempty"`
+	CertificateSHA256 string   `json:"certificateSHA256,omitempty"`
+	ETHAddress        string   `json:"ethAddress,omitempty"`
+	Weight            uint64   `json:"weight,omitempty"`
+	Status            string   `json:"status"`
+	Issues            []string `json:"issues,omitempty"`
+}
+
+// Accepted returns the number of accepted entries
+func (m *Manifest) Accepted() int {
+	n := 0
+	for _, e := range m.Entries {
+		if e.Status == StatusAccepted {
+			n++
+		}
+	}
+	return n
+}
+
+// Digest is the SHA-256 hash of the manifest JSON without its signature
+func (m *Manifest) Digest() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	h := sha256.Sum256(data)
+	return h[:], nil
+}
+
+// Sign signs off the manifest with key
+func (m *Manifest) Sign(key *secp256k1.PrivateKey) error {
+	m.SignedOffBy = ethAddress(key.PubKey())
+	m.Signature = ""
+	digest, err := m.Digest()
+	if err != nil {
+		return err
+	}
+	m.Signature = "0x" + hex.EncodeToString(ecdsa.SignCompact(key, digest, true))
+	return nil
+}
+
+// Verify checks that the manifest is signed by the key of SignedOffBy
+func (m *Manifest) Verify() error {
+	if m.Signature == "" {
+		return fmt.Errorf("manifest is not signed")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(m.Signature, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature: %w", err)
+	}
+	digest, err := m.Digest()
+	if err != nil {
+		return err
+	}
+	pub, _, err := ecdsa.RecoverCompact(sig, digest)
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature: %w", err)
+	}
+	if signer := ethAddress(pub); !strings.EqualFold(signer, m.SignedOffBy) {
+		return fmt.Errorf("manifest signed by %s, not %s", signer, m.SignedOffBy)
+	}
+	return nil
+}
+
+// LoadManifest reads an import manifest
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", m.Version)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
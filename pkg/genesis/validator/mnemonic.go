@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ErrInvalidMnemonic is returned for phrases that are not BIP-39 mnemonics
+var ErrInvalidMnemonic = errors.New("invalid BIP-39 mnemonic")
+
+// NewMnemonic generates a BIP-39 mnemonic with the given entropy: 128 bits
+// gives 12 words, 256 bits gives 24
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the word count, the words and the checksum of a
+// BIP-39 mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return fmt.Errorf("%w: %d words, expected 12, 15, 18, 21 or 24", ErrInvalidMnemonic, len(words))
+	}
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(word); !ok {
+			return fmt.Errorf("%w: word %d (%q) is not in the wordlist", ErrInvalidMnemonic, i+1, word)
+		}
+	}
+	if _, err := bip39.EntropyFromMnemonic(strings.Join(words, " ")); err != nil {
+		return fmt.Errorf("%w: bad checksum", ErrInvalidMnemonic)
+	}
+	return nil
+}
+
+// SeedFromMnemonic validates a mnemonic and returns its 64 byte BIP-39 seed
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return bip39.NewSeed(strings.Join(strings.Fields(mnemonic), " "), passphrase), nil
+}
@@ -0,0 +1,240 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Remote signer protocol: JSON over HTTP, one key per key ID, bytes as
+// 0x-prefixed hex.
+//
+//	GET  /v1/keys/{id}           -> {"publicKey": "0x..."}
+//	POST /v1/keys/{id}/sign      {"message": "0x..."} -> {"signature": "0x..."}
+//	POST /v1/keys/{id}/sign-pop  {"message": "0x..."} -> {"signature": "0x..."}
+//
+// Errors are non-2xx responses with {"error": "..."}. With a token, requests
+// carry "Authorization: Bearer <token>".
+const remoteSignerPrefix = "/v1/keys/"
+
+type remoteKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+type remoteSignRequest struct {
+	Message string `json:"message"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type remoteErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// RemoteSigner is a Signer whose key is held by an external signer service
+type RemoteSigner struct {
+	baseURL string
+	keyID   string
+	token   string
+	client  *http.Client
+
+	mu        sync.Mutex
+	publicKey []byte
+}
+
+var _ Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner creates a client for key keyID of the signer service at
+// baseURL. An empty token sends no Authorization header.
+func NewRemoteSigner(baseURL, keyID, token string) *RemoteSigner {
+	return &RemoteSigner{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		keyID:   keyID,
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// PublicKey returns the compressed public key, fetched once
+func (s *RemoteSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.publicKey != nil {
+		return s.publicKey, nil
+	}
+
+	var resp remoteKeyResponse
+	if err := s.call(ctx, http.MethodGet, "", nil, &resp); err != nil {
+		return nil, err
+	}
+	pk, err := decodeHex(resp.PublicKey, 48)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key from remote signer: %w", err)
+	}
+	s.publicKey = pk
+	return pk, nil
+}
+
+// Sign signs msg
+func (s *RemoteSigner) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	return s.sign(ctx, "/sign", msg)
+}
+
+// SignProofOfPossession signs msg with the proof of possession tag
+func (s *RemoteSigner) SignProofOfPossession(ctx context.Context, msg []byte) ([]byte, error) {
+	return s.sign(ctx, "/sign-pop", msg)
+}
+
+func (s *RemoteSigner) sign(ctx context.Context, path string, msg []byte) ([]byte, error) {
+	var resp remoteSignResponse
+	req := remoteSignRequest{Message: "0x" + hex.EncodeToString(msg)}
+	if err := s.call(ctx, http.MethodPost, path, req, &resp); err != nil {
+		return nil, err
+	}
+	sig, err := decodeHex(resp.Signature, 96)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	return sig, nil
+}
+
+// call sends one request to the signer service and decodes the response
+func (s *RemoteSigner) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := s.baseURL + remoteSignerPrefix + url.PathEscape(s.keyID) + path
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create remote signer request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read remote signer response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		var e remoteErrorResponse
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("remote signer: %s (%s)", e.Error, resp.Status)
+		}
+		return fmt.Errorf("remote signer: %s", resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse remote signer response: %w", err)
+	}
+	return nil
+}
+
+// NewSignerHandler serves the remote signer protocol for signers by key
+// ID. It is the reference for signer services and a local stand-in for
+// tests and development; an empty token accepts any request.
+func NewSignerHandler(signers map[string]Signer, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+remoteSignerPrefix+"{id}", func(w http.ResponseWriter, r *http.Request) {
+		s, ok := handlerSigner(w, r, signers, token)
+		if !ok {
+			return
+		}
+		pk, err := s.PublicKey(r.Context())
+		if err != nil {
+			writeSignerError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeSignerJSON(w, remoteKeyResponse{PublicKey: "0x" + hex.EncodeToString(pk)})
+	})
+	for path, pop := range map[string]bool{"/sign": false, "/sign-pop": true} {
+		mux.HandleFunc("POST "+remoteSignerPrefix+"{id}"+path, func(w http.ResponseWriter, r *http.Request) {
+			s, ok := handlerSigner(w, r, signers, token)
+			if !ok {
+				return
+			}
+			var req remoteSignRequest
+			if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+				writeSignerError(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+			msg, err := decodeHex(req.Message, -1)
+			if err != nil {
+				writeSignerError(w, http.StatusBadRequest, "invalid message")
+				return
+			}
+			sign := s.Sign
+			if pop {
+				sign = s.SignProofOfPossession
+			}
+			sig, err := sign(r.Context(), msg)
+			if err != nil {
+				writeSignerError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeSignerJSON(w, remoteSignResponse{Signature: "0x" + hex.EncodeToString(sig)})
+		})
+	}
+	return mux
+}
+
+// handlerSigner authenticates a request and looks up its signer
+func handlerSigner(w http.ResponseWriter, r *http.Request, signers map[string]Signer, token string) (Signer, bool) {
+	auth := []byte(r.Header.Get("Authorization"))
+	if token != "" && subtle.ConstantTimeCompare(auth, []byte("Bearer "+token)) != 1 {
+		writeSignerError(w, http.StatusUnauthorized, "unauthorized")
+		return nil, false
+	}
+	s, ok := signers[r.PathValue("id")]
+	if !ok {
+		writeSignerError(w, http.StatusNotFound, "unknown key")
+		return nil, false
+	}
+	return s, true
+}
+
+func writeSignerJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeSignerError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(remoteErrorResponse{Error: msg})
+}
+
+// decodeHex decodes 0x-prefixed hex of n bytes, or of any length if n < 0
+func decodeHex(s string, n int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && len(b) != n {
+		return nil, fmt.Errorf("expected %d bytes, got %d", n, len(b))
+	}
+	return b, nil
+}
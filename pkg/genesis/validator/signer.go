@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/luxfi/node/utils/crypto/bls"
+	"github.com/luxfi/node/utils/crypto/bls/signer/localsigner"
+)
+
+// Signer signs with a BLS key it holds. Keys and signatures are exchanged
+// as bytes - 48 byte compressed public keys and 96 byte signatures - so the
+// key can live in another process or on another host; see RemoteSigner.
+type Signer interface {
+	// PublicKey returns the compressed public key
+	PublicKey(ctx context.Context) ([]byte, error)
+
+	// Sign signs msg
+	Sign(ctx context.Context, msg []byte) ([]byte, error)
+
+	// SignProofOfPossession signs msg with the proof of possession
+	// domain separation tag
+	SignProofOfPossession(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// LocalSigner is a Signer over a BLS key held in memory
+type LocalSigner struct {
+	signer *localsigner.LocalSigner
+}
+
+var _ Signer = (*LocalSigner)(nil)
+
+// NewLocalSigner creates a signer from a 32 byte BLS secret key
+func NewLocalSigner(key []byte) (*LocalSigner, error) {
+	signer, err := localsigner.FromBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLS key: %w", err)
+	}
+	return &LocalSigner{signer: signer}, nil
+}
+
+// GenerateLocalSigner creates a signer with a new random BLS key
+func GenerateLocalSigner() (*LocalSigner, error) {
+	signer, err := localsigner.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate BLS signer: %w", err)
+	}
+	return &LocalSigner{signer: signer}, nil
+}
+
+// PublicKey returns the compressed public key
+func (s *LocalSigner) PublicKey(context.Context) ([]byte, error) {
+	return bls.PublicKeyToCompressedBytes(s.signer.PublicKey()), nil
+}
+
+// Sign signs msg
+func (s *LocalSigner) Sign(_ context.Context, msg []byte) ([]byte, error) {
+	sig, err := s.signer.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return bls.SignatureToBytes(sig), nil
+}
+
+// SignProofOfPossession signs msg with the proof of possession tag
+func (s *LocalSigner) SignProofOfPossession(_ context.Context, msg []byte) ([]byte, error) {
+	sig, err := s.signer.SignProofOfPossession(msg)
+	if err != nil {
+		return nil, err
+	}
+	return bls.SignatureToBytes(sig), nil
+}
+
+// Bytes returns the secret key
+func (s *LocalSigner) Bytes() []byte {
+	return s.signer.ToBytes()
+}
+
+// ProofOfPossession asks a signer for its public key and proof of
+// possession, as 0x-prefixed hex, and verifies the proof before returning
+// it, so a faulty or malicious remote signer cannot put a bad key into a
+// genesis
+func ProofOfPossession(ctx context.Context, s Signer) (publicKey, proof string, err error) {
+	pkBytes, err := s.PublicKey(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get BLS public key: %w", err)
+	}
+	pk, err := bls.PublicKeyFromCompressedBytes(pkBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid BLS public key from signer: %w", err)
+	}
+	sigBytes, err := s.SignProofOfPossession(ctx, pkBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign proof of possession: %w", err)
+	}
+	sig, err := bls.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid proof of possession from signer: %w", err)
+	}
+	if !bls.VerifyProofOfPossession(pk, sig, pkBytes) {
+		return "", "", fmt.Errorf("proof of possession from signer does not verify against its public key")
+	}
+	return "0x" + hex.EncodeToString(pkBytes), "0x" + hex.EncodeToString(sigBytes), nil
+}
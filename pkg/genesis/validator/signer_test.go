@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLocalSigner(t *testing.T, index int) *LocalSigner {
+	seed, err := SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+	key, err := DeriveBLSKey(seed, fmt.Sprintf(BLSPathFormat, index))
+	require.NoError(t, err)
+	signer, err := NewLocalSigner(key)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestRemoteSigner(t *testing.T) {
+	ctx := context.Background()
+	local := testLocalSigner(t, 0)
+	srv := httptest.NewServer(NewSignerHandler(map[string]Signer{"validator-0": local}, "secret"))
+	defer srv.Close()
+
+	// The remote key gives the same public key, PoP and signatures as the
+	// key held locally
+	remote := NewRemoteSigner(srv.URL+"/", "validator-0", "secret")
+	wantPK, wantPoP, err := ProofOfPossession(ctx, local)
+	require.NoError(t, err)
+	pk, pop, err := ProofOfPossession(ctx, remote)
+	require.NoError(t, err)
+	assert.Equal(t, wantPK, pk)
+	assert.Equal(t, wantPoP, pop)
+
+	want, err := local.Sign(ctx, []byte("genesis"))
+	require.NoError(t, err)
+	got, err := remote.Sign(ctx, []byte("genesis"))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// Keys derived from a mnemonic get the same PoP through either signer
+	keys, err := NewKeyGenerator("").GenerateFromSeedWithTLS(testMnemonic, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys.ProofOfPossession, pop)
+
+	_, err = NewRemoteSigner(srv.URL, "validator-0", "wrong").PublicKey(ctx)
+	assert.ErrorContains(t, err, "unauthorized")
+	_, err = NewRemoteSigner(srv.URL, "validator-1", "secret").PublicKey(ctx)
+	assert.ErrorContains(t, err, "unknown key")
+}
+
+// mismatchedSigner reports one key but signs with another
+type mismatchedSigner struct {
+	*LocalSigner
+	other *LocalSigner
+}
+
+func (s mismatchedSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	return s.other.PublicKey(ctx)
+}
+
+func TestProofOfPossessionVerifiesSigner(t *testing.T) {
+	bad := mismatchedSigner{LocalSigner: testLocalSigner(t, 0), other: testLocalSigner(t, 1)}
+	srv := httptest.NewServer(NewSignerHandler(map[string]Signer{"validator-0": bad}, ""))
+	defer srv.Close()
+
+	_, _, err := ProofOfPossession(context.Background(), NewRemoteSigner(srv.URL, "validator-0", ""))
+	assert.ErrorContains(t, err, "does not verify")
+}
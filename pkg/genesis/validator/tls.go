@@ -0,0 +1,181 @@
+package validator
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Staking TLS keys are derived from a seed with HKDF-SHA256:
+//
+//	salt = TLSDerivationSalt
+//	info = TLSDerivationPath + "/" + account index, e.g. "lux/staking-tls/v1/0"
+//
+// The HKDF output is read 32 bytes at a time until it is a valid P-256
+// scalar. The certificate is self-signed with a fixed validity window and a
+// serial taken from the public key, and signed with RFC 6979 nonces, so the
+// certificate bytes - and therefore the NodeID - only depend on the seed and
+// the index.
+const (
+	TLSDerivationSalt = "lux-staking-tls"
+	TLSDerivationPath = "lux/staking-tls/v1"
+)
+
+// Fixed certificate validity of derived staking certificates
+var (
+	TLSNotBefore = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	TLSNotAfter  = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// DeriveTLSKey derives the P-256 staking TLS key for an account index
+func DeriveTLSKey(seed []byte, index int) (*ecdsa.PrivateKey, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("empty seed")
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("invalid account index: %d", index)
+	}
+	info := fmt.Sprintf("%s/%d", TLSDerivationPath, index)
+	r := hkdf.New(sha256.New, seed, []byte(TLSDerivationSalt), []byte(info))
+
+	// Rejection sampling; a candidate is out of range with probability ~2^-32
+	scalar := make([]byte, 32)
+	for i := 0; i < 64; i++ {
+		if _, err := io.ReadFull(r, scalar); err != nil {
+			return nil, fmt.Errorf("failed to read HKDF output: %w", err)
+		}
+		if key, err := p256Key(scalar); err == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to derive TLS key for account %d", index)
+}
+
+// NewDeterministicCertAndKeyBytes derives the staking TLS key for an account
+// index and returns a self-signed certificate and PKCS #8 key, both PEM
+// encoded, in the format luxd expects in staker.crt and staker.key
+func NewDeterministicCertAndKeyBytes(seed []byte, index int) ([]byte, []byte, error) {
+	key, err := DeriveTLSKey(seed, index)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	serial := sha256.Sum256(pub)
+	template := &x509.Certificate{
+		SerialNumber:          new(big.Int).SetBytes(serial[:16]),
+		NotBefore:             TLSNotBefore,
+		NotAfter:              TLSNotAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	// The signer ignores the random source, so nothing is read from it
+	certDER, err := x509.CreateCertificate(nil, template, template, &key.PublicKey, rfc6979Signer{key})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// p256Key builds a P-256 key from a big-endian scalar, failing if it is zero
+// or not below the group order
+func p256Key(scalar []byte) (*ecdsa.PrivateKey, error) {
+	priv, err := ecdh.P256().NewPrivateKey(scalar)
+	if err != nil {
+		return nil, err
+	}
+	x, y := splitPoint(priv.PublicKey().Bytes())
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		D:         new(big.Int).SetBytes(scalar),
+	}, nil
+}
+
+// splitPoint splits an uncompressed P-256 point into its coordinates
+func splitPoint(point []byte) (*big.Int, *big.Int) {
+	point = point[1:] // 0x04
+	return new(big.Int).SetBytes(point[:32]), new(big.Int).SetBytes(point[32:])
+}
+
+// rfc6979Signer signs P-256 digests with deterministic nonces (RFC 6979,
+// HMAC-SHA256), unlike ecdsa.PrivateKey which always mixes in randomness
+type rfc6979Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s rfc6979Signer) Public() crypto.PublicKey {
+	return &s.key.PublicKey
+}
+
+func (s rfc6979Signer) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	n := elliptic.P256().Params().N
+	d := s.key.D
+
+	// bits2int: the order is 256 bits, so keep the leftmost 32 bytes
+	if len(digest) > 32 {
+		digest = digest[:32]
+	}
+	z := new(big.Int).SetBytes(digest)
+	h1 := make([]byte, 32)
+	new(big.Int).Mod(z, n).FillBytes(h1)
+	x := make([]byte, 32)
+	d.FillBytes(x)
+
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, p := range parts {
+			m.Write(p)
+		}
+		return m.Sum(nil)
+	}
+	v := make([]byte, 32)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, 32)
+	k = mac(k, v, []byte{0x00}, x, h1)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h1)
+	v = mac(k, v)
+
+	for {
+		v = mac(k, v)
+		// NewPrivateKey rejects nonces that are zero or not below the order
+		if nonce, err := ecdh.P256().NewPrivateKey(v); err == nil {
+			rx, _ := splitPoint(nonce.PublicKey().Bytes())
+			r := rx.Mod(rx, n)
+			kInv := new(big.Int).ModInverse(new(big.Int).SetBytes(v), n)
+			sig := new(big.Int).Mul(r, d)
+			sig.Add(sig, z)
+			sig.Mul(sig, kInv)
+			sig.Mod(sig, n)
+			if r.Sign() != 0 && sig.Sign() != 0 {
+				return asn1.Marshal(struct{ R, S *big.Int }{r, sig})
+			}
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
@@ -1,8 +1,14 @@
 package validator
 
 import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
@@ -49,19 +55,97 @@ func TestGenerateFromSeed(t *testing.T) {
 	require.NoError(t, err)
 	
 	// Verify deterministic generation - same seed/offset should produce same keys
-	// Just verify the keys are valid and match
 	assert.NotEmpty(t, keys1.NodeID)
-	assert.NotEmpty(t, keys3.NodeID)
+	assert.Equal(t, keys1.NodeID, keys3.NodeID)
+	assert.Equal(t, keys1.PublicKey, keys3.PublicKey)
 	validateKeyLengths(t, keys1)
 	validateKeyLengths(t, keys3)
 	
 	// Different offsets should produce different keys
 	assert.NotEqual(t, keys1.NodeID, keys2.NodeID)
+
+	// The reward key is the wallet account of the same index
+	assert.Equal(t, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", keys1.ETHAddress)
+	assert.Contains(t, keys1.RewardAddress, "P-lux1")
+	assert.NotEqual(t, keys1.RewardAddress, keys2.RewardAddress)
+
+	// Only BIP-39 mnemonics are accepted
+	_, err = keygen.GenerateFromSeed("not a mnemonic", 0)
+	assert.ErrorIs(t, err, ErrInvalidMnemonic)
+	_, err = keygen.GenerateFromSeed(mnemonic, -1)
+	assert.Error(t, err)
 	
 	validateKeyLengths(t, keys1)
 	validateKeyLengths(t, keys2)
 }
 
+func TestGenerateFromSeedDeterministicTLS(t *testing.T) {
+	keygen := NewKeyGenerator("")
+	mnemonic := "test test test test test test test test test test test junk"
+
+	keys1, err := keygen.GenerateFromSeedWithTLS(mnemonic, 3)
+	require.NoError(t, err)
+	keys2, err := keygen.GenerateFromSeedWithTLS(mnemonic, 3)
+	require.NoError(t, err)
+
+	// Regenerating the same account gives the same NodeID and staking files
+	assert.Equal(t, keys1.NodeID, keys2.NodeID)
+	assert.Equal(t, keys1.TLSCertBytes, keys2.TLSCertBytes)
+	assert.Equal(t, keys1.TLSKeyBytes, keys2.TLSKeyBytes)
+
+	other, err := keygen.GenerateFromSeedWithTLS(mnemonic, 4)
+	require.NoError(t, err)
+	assert.NotEqual(t, keys1.NodeID, other.NodeID)
+
+	otherSeed, err := keygen.GenerateFromSeedWithTLS("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 3)
+	require.NoError(t, err)
+	assert.NotEqual(t, keys1.NodeID, otherSeed.NodeID)
+
+	// The certificate has the fixed validity and matches the key
+	block, _ := pem.Decode(keys1.TLSCertBytes)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, TLSNotBefore, cert.NotBefore)
+	assert.Equal(t, TLSNotAfter, cert.NotAfter)
+	require.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+
+	block, _ = pem.Decode(keys1.TLSKeyBytes)
+	require.NotNil(t, block)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, key.(*ecdsa.PrivateKey).PublicKey.Equal(cert.PublicKey))
+}
+
+func TestDeriveTLSKey(t *testing.T) {
+	_, err := DeriveTLSKey(nil, 0)
+	assert.Error(t, err)
+	_, err = DeriveTLSKey([]byte("seed"), -1)
+	assert.Error(t, err)
+
+	key, err := DeriveTLSKey([]byte("seed"), 0)
+	require.NoError(t, err)
+	assert.True(t, key.Curve.IsOnCurve(key.X, key.Y))
+}
+
+// RFC 6979 appendix A.2.5, P-256 with SHA-256, message "sample"
+func TestRFC6979Signer(t *testing.T) {
+	d, _ := new(big.Int).SetString("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", 16)
+	key, err := p256Key(d.FillBytes(make([]byte, 32)))
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("sample"))
+	sig, err := rfc6979Signer{key}.Sign(nil, digest[:], nil)
+	require.NoError(t, err)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig))
+
+	var rs struct{ R, S *big.Int }
+	_, err = asn1.Unmarshal(sig, &rs)
+	require.NoError(t, err)
+	assert.Equal(t, "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716", rs.R.Text(16))
+	assert.Equal(t, "f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8", rs.S.Text(16))
+}
+
 func TestGenerateFromPrivateKey(t *testing.T) {
 	keygen := NewKeyGenerator("")
 	
@@ -10,9 +10,10 @@ import (
 type ValidatorInfo struct {
 	NodeID            string `json:"nodeID"`
 	ETHAddress        string `json:"ethAddress"`
+	RewardAddress     string `json:"rewardAddress,omitempty"`
 	PublicKey         string `json:"publicKey"`
 	ProofOfPossession string `json:"proofOfPossession"`
-	Weight            uint64 `json:"weight"`
+	Weight            uint64 `json:"weight"` // nLUX
 	DelegationFee     uint32 `json:"delegationFee"`
 }
 
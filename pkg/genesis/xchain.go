@@ -1,12 +1,11 @@
 package genesis
 
 import (
-	"encoding/csv"
 	"fmt"
-	"io"
 	"math/big"
-	"os"
-	"strings"
+
+	"github.com/luxfi/genesis/pkg/genesis/amount"
+	"github.com/luxfi/genesis/pkg/genesis/importer"
 )
 
 // XChainGenesis represents X-Chain genesis configuration
@@ -52,68 +51,29 @@ func BuildXChainGenesis(network string, airdropCSVPath string) (*XChainGenesis,
 	return genesis, nil
 }
 
-// LoadXChainAirdrops loads X-Chain allocations from CSV
+// LoadXChainAirdrops loads X-Chain allocations from CSV. Amounts are read
+// from the balance_lux column (or another recognised amount column) and
+// rounded down to nLUX, as airdrop snapshots carry 18-decimal balances; rows
+// that cannot be imported fail the load.
 func LoadXChainAirdrops(csvPath string) ([]XChainAllocation, error) {
-	file, err := os.Open(csvPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	im := importer.New(importer.Options{
+		Format:       importer.FormatCSV,
+		Denomination: amount.NLUX,
+		Rounding:     amount.RoundDown,
+	})
+	if err := im.ImportFile(csvPath); err != nil {
+		return nil, err
+	}
+	result := im.Result()
+	if err := result.Err(); err != nil {
+		return nil, err
 	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	reader.Comment = '#'  // Skip comment lines
-	reader.FieldsPerRecord = -1  // Variable number of fields
-	
-	allocations := make([]XChainAllocation, 0)
-	headerFound := false
-
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			continue  // Skip problematic lines
-		}
-
-		// Skip until we find the header
-		if !headerFound {
-			if len(record) > 0 && strings.HasPrefix(record[0], "rank") {
-				headerFound = true
-			}
-			continue
-		}
-
-		// Expected format: rank,address,balance_lux,balance_wei,balance_hex,percentage
-		if len(record) < 3 {
-			continue
-		}
-
-		// Skip empty or invalid entries
-		if record[0] == "" || record[1] == "" || record[2] == "" {
-			continue
-		}
-
-		address := strings.TrimSpace(record[1])
-		balanceLux := strings.TrimSpace(record[2])
-
-		// Remove commas from balance
-		balanceLux = strings.ReplaceAll(balanceLux, ",", "")
-
-		// Parse LUX amount
-		luxAmount := new(big.Float)
-		if _, ok := luxAmount.SetString(balanceLux); !ok {
-			continue  // Skip invalid amounts
-		}
-
-		// Convert to wei (9 decimals)
-		weiAmount := new(big.Float).Mul(luxAmount, big.NewFloat(1e9))
-		balance := new(big.Int)
-		weiAmount.Int(balance)
 
+	allocations := make([]XChainAllocation, 0, len(result.Allocations))
+	for _, alloc := range result.Allocations {
 		allocations = append(allocations, XChainAllocation{
-			Address: address,
-			Balance: balance,
+			Address: alloc.Address,
+			Balance: alloc.Amount.Value(),
 		})
 	}
 
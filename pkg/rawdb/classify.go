@@ -0,0 +1,187 @@
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Path-scheme trie, snapshot and code prefixes from the upstream schema
+var (
+	CodePrefix            = []byte("c") // codePrefix + code hash -> contract code
+	TrieNodeAccountPrefix = []byte("A") // trieNodeAccountPrefix + hexPath -> account trie node
+	TrieNodeStoragePrefix = []byte("O") // trieNodeStoragePrefix + accountHash + hexPath -> storage trie node
+	SnapshotAccountPrefix = []byte("a") // snapshotAccountPrefix + account hash -> slim account
+	SnapshotStoragePrefix = []byte("o") // snapshotStoragePrefix + account hash + storage hash -> slot value
+)
+
+// Lengths of the block-number keyed entries
+const (
+	headerKeyLength        = 1 + 8 + common.HashLength     // header, body and receipts keys
+	headerTDKeyLength      = 1 + 8 + common.HashLength + 1 // header + headerTDSuffix
+	canonicalHashKeyLength = 1 + 8 + 1                     // header + headerHashSuffix
+)
+
+// KeyKind is the schema category of a database key
+type KeyKind string
+
+// Known key kinds
+const (
+	KindHeader          KeyKind = "header"
+	KindHeaderTD        KeyKind = "header-td"
+	KindCanonicalHash   KeyKind = "canonical-hash"
+	KindHeaderNumber    KeyKind = "hash-to-number"
+	KindBody            KeyKind = "body"
+	KindReceipts        KeyKind = "receipts"
+	KindTxLookup        KeyKind = "tx-lookup"
+	KindCode            KeyKind = "code"
+	KindTrieNode        KeyKind = "trie-node"
+	KindAccountTrieNode KeyKind = "account-trie-node"
+	KindStorageTrieNode KeyKind = "storage-trie-node"
+	KindSnapAccount     KeyKind = "snapshot-account"
+	KindSnapStorage     KeyKind = "snapshot-storage"
+	KindPointer         KeyKind = "pointer"
+	KindLogIndex        KeyKind = "log-index"
+	KindOther           KeyKind = "other"
+)
+
+// Kinds lists every key kind in browsing order
+var Kinds = []KeyKind{
+	KindPointer,
+	KindCanonicalHash,
+	KindHeader,
+	KindHeaderTD,
+	KindHeaderNumber,
+	KindBody,
+	KindReceipts,
+	KindTxLookup,
+	KindCode,
+	KindTrieNode,
+	KindAccountTrieNode,
+	KindStorageTrieNode,
+	KindSnapAccount,
+	KindSnapStorage,
+	KindLogIndex,
+	KindOther,
+}
+
+// PointerKeys are the fixed single-value keys that point into the chain
+var PointerKeys = [][]byte{
+	HeadHeaderKey,
+	HeadBlockKey,
+	HeadFastBlockKey,
+	AcceptorTipKey,
+	AcceptorTipHeightKey,
+	TxIndexRangeKey,
+	LogIndexRangeKey,
+}
+
+// Prefix returns the key prefix shared by every key of the kind, or nil when
+// keys of the kind can only be found by scanning (hash-scheme trie nodes,
+// pointers and unclassified keys).
+func (k KeyKind) Prefix() []byte {
+	switch k {
+	case KindHeader, KindHeaderTD, KindCanonicalHash:
+		return HeaderPrefix
+	case KindHeaderNumber:
+		return HeaderNumberPrefix
+	case KindBody:
+		return BlockBodyPrefix
+	case KindReceipts:
+		return BlockReceiptsPrefix
+	case KindTxLookup:
+		return TxLookupPrefix
+	case KindCode:
+		return CodePrefix
+	case KindAccountTrieNode:
+		return TrieNodeAccountPrefix
+	case KindStorageTrieNode:
+		return TrieNodeStoragePrefix
+	case KindSnapAccount:
+		return SnapshotAccountPrefix
+	case KindSnapStorage:
+		return SnapshotStoragePrefix
+	case KindLogIndex:
+		return LogIndexPrefix
+	}
+	return nil
+}
+
+// Classify determines the kind of an (un-namespaced) key. Single-byte
+// prefixes are ambiguous with hash-scheme trie nodes, so key lengths are
+// checked as well.
+func Classify(key []byte) KeyKind {
+	for _, pointer := range PointerKeys {
+		if bytes.Equal(key, pointer) {
+			return KindPointer
+		}
+	}
+	if len(key) == common.HashLength {
+		return KindTrieNode
+	}
+	if bytes.HasPrefix(key, LogIndexPrefix) && len(key) == len(LogIndexPrefix)+common.AddressLength+8 {
+		return KindLogIndex
+	}
+	if len(key) == 0 {
+		return KindOther
+	}
+
+	switch key[0] {
+	case HeaderPrefix[0]:
+		switch {
+		case len(key) == headerKeyLength:
+			return KindHeader
+		case len(key) == canonicalHashKeyLength && key[9] == HeaderHashSuffix[0]:
+			return KindCanonicalHash
+		case len(key) == headerTDKeyLength && key[len(key)-1] == HeaderTDSuffix[0]:
+			return KindHeaderTD
+		}
+	case HeaderNumberPrefix[0]:
+		if len(key) == 1+common.HashLength {
+			return KindHeaderNumber
+		}
+	case BlockBodyPrefix[0]:
+		if len(key) == headerKeyLength {
+			return KindBody
+		}
+	case BlockReceiptsPrefix[0]:
+		if len(key) == headerKeyLength {
+			return KindReceipts
+		}
+	case TxLookupPrefix[0]:
+		if len(key) == 1+common.HashLength {
+			return KindTxLookup
+		}
+	case CodePrefix[0]:
+		if len(key) == 1+common.HashLength {
+			return KindCode
+		}
+	case SnapshotAccountPrefix[0]:
+		if len(key) == 1+common.HashLength {
+			return KindSnapAccount
+		}
+	case SnapshotStoragePrefix[0]:
+		if len(key) == 1+2*common.HashLength {
+			return KindSnapStorage
+		}
+	case TrieNodeAccountPrefix[0]:
+		if len(key) <= 1+2*common.HashLength && isNibbles(key[1:]) {
+			return KindAccountTrieNode
+		}
+	case TrieNodeStoragePrefix[0]:
+		if len(key) >= 1+common.HashLength && len(key) <= 1+3*common.HashLength && isNibbles(key[1+common.HashLength:]) {
+			return KindStorageTrieNode
+		}
+	}
+	return KindOther
+}
+
+// isNibbles reports whether every byte of a path-scheme trie path is a nibble
+func isNibbles(path []byte) bool {
+	for _, b := range path {
+		if b > 0x0f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	hash := common.HexToHash("0x3f9bcd4f5ed4a8c1b43b5c1e5f2b4a2c1d6e7f8091a2b3c4d5e6f708192a3b4c")
+	addr := common.HexToAddress("0x9011E888251AB053B7bD1cdB598Db4f9DEd94714")
+
+	tests := []struct {
+		name     string
+		key      []byte
+		expected KeyKind
+	}{
+		{"header", HeaderKey(42, hash), KindHeader},
+		{"canonical hash", CanonicalHashKey(42), KindCanonicalHash},
+		{"header td", append(HeaderKey(42, hash), HeaderTDSuffix...), KindHeaderTD},
+		{"hash to number", HeaderNumberKey(hash), KindHeaderNumber},
+		{"body", BlockBodyKey(42, hash), KindBody},
+		{"receipts", BlockReceiptsKey(42, hash), KindReceipts},
+		{"tx lookup", TxLookupKey(hash), KindTxLookup},
+		{"code", concat(CodePrefix, hash.Bytes()), KindCode},
+		{"hash-scheme trie node", hash.Bytes(), KindTrieNode},
+		{"account trie root", TrieNodeAccountPrefix, KindAccountTrieNode},
+		{"account trie node", concat(TrieNodeAccountPrefix, []byte{0x1, 0xf}), KindAccountTrieNode},
+		{"storage trie node", concat(TrieNodeStoragePrefix, hash.Bytes(), []byte{0x3}), KindStorageTrieNode},
+		{"snapshot account", concat(SnapshotAccountPrefix, hash.Bytes()), KindSnapAccount},
+		{"snapshot storage", concat(SnapshotStoragePrefix, hash.Bytes(), hash.Bytes()), KindSnapStorage},
+		{"last block", HeadBlockKey, KindPointer},
+		{"acceptor tip", AcceptorTipKey, KindPointer},
+		{"log index", LogIndexKey(addr, 42), KindLogIndex},
+		{"log index range", LogIndexRangeKey, KindPointer},
+		{"short header prefix", []byte("hx"), KindOther},
+		{"non-nibble path", []byte("Abc"), KindOther},
+		{"empty", nil, KindOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classify(tt.key))
+		})
+	}
+}
+
+func TestCompactToNibbles(t *testing.T) {
+	// Even extension, odd extension, even leaf, odd leaf
+	nibbles, leaf := compactToNibbles([]byte{0x00, 0x12, 0x34})
+	assert.Equal(t, []byte{1, 2, 3, 4}, nibbles)
+	assert.False(t, leaf)
+
+	nibbles, leaf = compactToNibbles([]byte{0x11, 0x23})
+	assert.Equal(t, []byte{1, 2, 3}, nibbles)
+	assert.False(t, leaf)
+
+	nibbles, leaf = compactToNibbles([]byte{0x20, 0xab})
+	assert.Equal(t, []byte{0xa, 0xb}, nibbles)
+	assert.True(t, leaf)
+
+	nibbles, leaf = compactToNibbles([]byte{0x3c})
+	assert.Equal(t, []byte{0xc}, nibbles)
+	assert.True(t, leaf)
+
+	assert.Equal(t, []byte{0x12, 0x34}, nibblesToBytes([]byte{1, 2, 3, 4}))
+	assert.Nil(t, nibblesToBytes([]byte{1, 2, 3}))
+}
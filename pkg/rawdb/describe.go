@@ -0,0 +1,414 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+)
+
+// maxListedItems caps how many transactions, receipts or logs a description lists
+const maxListedItems = 16
+
+// Field is one decoded, human readable value
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Link is a reference from a decoded value to another (un-namespaced) key
+type Link struct {
+	Label string
+	Key   []byte
+}
+
+// Description is the decoded form of a key/value pair
+type Description struct {
+	Kind   KeyKind
+	Fields []Field
+	Links  []Link
+	Err    error // Set when the value could not be decoded
+}
+
+func (d *Description) field(name string, format string, args ...interface{}) {
+	d.Fields = append(d.Fields, Field{Name: name, Value: fmt.Sprintf(format, args...)})
+}
+
+func (d *Description) link(label string, key []byte) {
+	d.Links = append(d.Links, Link{Label: label, Key: key})
+}
+
+// Describe classifies an (un-namespaced) key and decodes its value. Links
+// point at the keys a value references, such as a header's parent or a trie
+// node's children. Trie links are emitted for the scheme the node was read
+// with; header state roots link to both the hash-scheme and path-scheme root.
+func Describe(key, value []byte) *Description {
+	d := &Description{Kind: Classify(key)}
+
+	switch d.Kind {
+	case KindHeader:
+		describeHeader(d, key, value)
+	case KindHeaderTD:
+		td := new(big.Int)
+		if err := rlp.DecodeBytes(value, td); err != nil {
+			d.Err = err
+			return d
+		}
+		d.field("total difficulty", "%s", td)
+	case KindCanonicalHash:
+		number := binary.BigEndian.Uint64(key[1:9])
+		d.field("number", "%d", number)
+		if len(value) != common.HashLength {
+			d.Err = fmt.Errorf("hash has length %d", len(value))
+			return d
+		}
+		hash := common.BytesToHash(value)
+		d.field("hash", "%s", hash.Hex())
+		linkBlock(d, number, hash)
+	case KindHeaderNumber:
+		hash := common.BytesToHash(key[1:])
+		number, ok := DecodeBlockNumber(value)
+		if !ok {
+			d.Err = fmt.Errorf("block number has length %d", len(value))
+			return d
+		}
+		d.field("hash", "%s", hash.Hex())
+		d.field("number", "%d", number)
+		linkBlock(d, number, hash)
+	case KindBody:
+		describeBody(d, key, value)
+	case KindReceipts:
+		describeReceipts(d, key, value)
+	case KindTxLookup:
+		if len(value) > 8 {
+			d.Err = fmt.Errorf("lookup entry has length %d", len(value))
+			return d
+		}
+		number := new(big.Int).SetBytes(value).Uint64()
+		d.field("tx hash", "%s", common.BytesToHash(key[1:]).Hex())
+		d.field("block", "%d", number)
+		d.link("canonical hash", CanonicalHashKey(number))
+	case KindCode:
+		d.field("code hash", "%s", common.BytesToHash(key[1:]).Hex())
+		d.field("size", "%d bytes", len(value))
+	case KindTrieNode:
+		describeTrieNode(d, value, nil)
+	case KindAccountTrieNode, KindStorageTrieNode:
+		describeTrieNode(d, value, key)
+	case KindSnapAccount:
+		account, err := types.FullAccount(value)
+		if err != nil {
+			d.Err = err
+			return d
+		}
+		accountHash := key[1:]
+		d.field("account hash", "%x", accountHash)
+		describeAccount(d, account, concat(TrieNodeStoragePrefix, accountHash))
+	case KindSnapStorage:
+		d.field("account hash", "%x", key[1:1+common.HashLength])
+		d.field("slot hash", "%x", key[1+common.HashLength:])
+		describeStorageValue(d, value)
+	case KindPointer:
+		describePointer(d, value)
+	case KindLogIndex:
+		addr := common.BytesToAddress(key[len(LogIndexPrefix) : len(LogIndexPrefix)+common.AddressLength])
+		number := binary.BigEndian.Uint64(key[len(key)-8:])
+		d.field("address", "%s", addr.Hex())
+		d.field("block", "%d", number)
+		if len(value) == 4 {
+			d.field("logs", "%d", binary.BigEndian.Uint32(value))
+		}
+		d.link("canonical hash", CanonicalHashKey(number))
+	default:
+		d.field("size", "%d bytes", len(value))
+	}
+	return d
+}
+
+// linkBlock links every per-block entry for (number, hash)
+func linkBlock(d *Description, number uint64, hash common.Hash) {
+	d.link("header", HeaderKey(number, hash))
+	d.link("body", BlockBodyKey(number, hash))
+	d.link("receipts", BlockReceiptsKey(number, hash))
+}
+
+func describeHeader(d *Description, key, value []byte) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(value, header); err != nil {
+		d.Err = err
+		return
+	}
+	number := header.Number.Uint64()
+	hash := common.BytesToHash(key[9:])
+
+	d.field("number", "%d", number)
+	d.field("hash", "%s", hash.Hex())
+	d.field("parent", "%s", header.ParentHash.Hex())
+	d.field("time", "%d", header.Time)
+	d.field("coinbase", "%s", header.Coinbase.Hex())
+	d.field("state root", "%s", header.Root.Hex())
+	d.field("tx root", "%s", header.TxHash.Hex())
+	d.field("receipt root", "%s", header.ReceiptHash.Hex())
+	d.field("gas", "%d / %d", header.GasUsed, header.GasLimit)
+	if header.BaseFee != nil {
+		d.field("base fee", "%s", header.BaseFee)
+	}
+	d.field("extra", "%x", header.Extra)
+
+	if number > 0 {
+		d.link("parent header", HeaderKey(number-1, header.ParentHash))
+	}
+	d.link("body", BlockBodyKey(number, hash))
+	d.link("receipts", BlockReceiptsKey(number, hash))
+	d.link("state root (hash scheme)", header.Root.Bytes())
+	d.link("state root (path scheme)", TrieNodeAccountPrefix)
+}
+
+func describeBody(d *Description, key, value []byte) {
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(value, body); err != nil {
+		d.Err = err
+		return
+	}
+	number := binary.BigEndian.Uint64(key[1:9])
+	hash := common.BytesToHash(key[9:])
+
+	d.field("number", "%d", number)
+	d.field("transactions", "%d", len(body.Transactions))
+	d.field("uncles", "%d", len(body.Uncles))
+	for i, tx := range body.Transactions {
+		if i == maxListedItems {
+			d.field("...", "%d more", len(body.Transactions)-i)
+			break
+		}
+		to := "contract creation"
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+		d.field(fmt.Sprintf("tx %d", i), "%s -> %s value=%s", tx.Hash().Hex(), to, tx.Value())
+		d.link(fmt.Sprintf("tx %d lookup", i), TxLookupKey(tx.Hash()))
+	}
+	d.link("header", HeaderKey(number, hash))
+	d.link("receipts", BlockReceiptsKey(number, hash))
+}
+
+func describeReceipts(d *Description, key, value []byte) {
+	var receipts []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(value, &receipts); err != nil {
+		d.Err = err
+		return
+	}
+	number := binary.BigEndian.Uint64(key[1:9])
+	hash := common.BytesToHash(key[9:])
+
+	d.field("number", "%d", number)
+	d.field("receipts", "%d", len(receipts))
+	listed := 0
+	for i, r := range receipts {
+		if listed == maxListedItems {
+			d.field("...", "more receipts or logs omitted")
+			break
+		}
+		d.field(fmt.Sprintf("receipt %d", i), "status=%d cumulativeGas=%d logs=%d", r.Status, r.CumulativeGasUsed, len(r.Logs))
+		listed++
+		for j, l := range r.Logs {
+			if listed == maxListedItems {
+				break
+			}
+			topic := "-"
+			if len(l.Topics) > 0 {
+				topic = l.Topics[0].Hex()
+			}
+			d.field(fmt.Sprintf("  log %d", j), "%s topic0=%s", l.Address.Hex(), topic)
+			listed++
+		}
+	}
+	d.link("header", HeaderKey(number, hash))
+	d.link("body", BlockBodyKey(number, hash))
+}
+
+func describePointer(d *Description, value []byte) {
+	switch len(value) {
+	case common.HashLength:
+		hash := common.BytesToHash(value)
+		d.field("hash", "%s", hash.Hex())
+		d.link("block number", HeaderNumberKey(hash))
+	case 8:
+		number := binary.BigEndian.Uint64(value)
+		d.field("number", "%d", number)
+		d.link("canonical hash", CanonicalHashKey(number))
+	case 16:
+		if r, ok := DecodeIndexRange(value); ok {
+			d.field("indexed blocks", "[%d, %d)", r.Start, r.Next)
+			break
+		}
+		d.field("value", "%x", value)
+	default:
+		d.field("value", "%x", value)
+	}
+}
+
+func describeAccount(d *Description, account *types.StateAccount, storagePathRoot []byte) {
+	d.field("nonce", "%d", account.Nonce)
+	d.field("balance", "%s wei", account.Balance.ToBig())
+	d.field("storage root", "%s", account.Root.Hex())
+	d.field("code hash", "%x", account.CodeHash)
+
+	if account.Root != types.EmptyRootHash {
+		if storagePathRoot != nil {
+			d.link("storage root", storagePathRoot)
+		} else {
+			d.link("storage root", account.Root.Bytes())
+		}
+	}
+	if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+		d.link("code", concat(CodePrefix, codeHash.Bytes()))
+	}
+}
+
+func describeStorageValue(d *Description, value []byte) {
+	_, content, _, err := rlp.Split(value)
+	if err != nil {
+		d.Err = err
+		return
+	}
+	d.field("value", "%s", common.BytesToHash(content).Hex())
+}
+
+// describeTrieNode decodes a trie node. pathKey is the node's path-scheme key
+// (prefix + [owner] + nibbles), or nil for hash-scheme nodes, in which case
+// children are linked by hash.
+func describeTrieNode(d *Description, value, pathKey []byte) {
+	elems, _, err := rlp.SplitList(value)
+	if err != nil {
+		d.Err = err
+		return
+	}
+	count, err := rlp.CountValues(elems)
+	if err != nil {
+		d.Err = err
+		return
+	}
+
+	var path []byte // Nibbles from the root to this node
+	if pathKey != nil {
+		path = pathKey[trieOwnerLength(pathKey):]
+		d.field("path", "%x", path)
+	}
+
+	switch count {
+	case 17:
+		d.field("type", "full node")
+		for i := 0; i < 16; i++ {
+			var child []byte
+			if _, child, elems, err = rlp.Split(elems); err != nil {
+				d.Err = err
+				return
+			}
+			if len(child) == 0 {
+				continue
+			}
+			d.field(fmt.Sprintf("child %x", i), "%x", child)
+			if len(child) == common.HashLength {
+				d.link(fmt.Sprintf("child %x", i), childKey(pathKey, child, []byte{byte(i)}))
+			}
+		}
+		if _, val, _, err := rlp.Split(elems); err == nil && len(val) > 0 {
+			d.field("value", "%x", val)
+		}
+
+	case 2:
+		_, compact, rest, err := rlp.Split(elems)
+		if err != nil {
+			d.Err = err
+			return
+		}
+		nibbles, leaf := compactToNibbles(compact)
+		_, val, _, err := rlp.Split(rest)
+		if err != nil {
+			d.Err = err
+			return
+		}
+		d.field("key", "%x", nibbles)
+
+		if !leaf {
+			d.field("type", "extension node")
+			d.field("child", "%x", val)
+			if len(val) == common.HashLength {
+				d.link("child", childKey(pathKey, val, nibbles))
+			}
+			return
+		}
+
+		d.field("type", "leaf node")
+		isAccountTrie := pathKey == nil || pathKey[0] == TrieNodeAccountPrefix[0]
+		if isAccountTrie {
+			account := new(types.StateAccount)
+			if err := rlp.DecodeBytes(val, account); err == nil {
+				var storagePathRoot []byte
+				if pathKey != nil {
+					full := append(append([]byte{}, path...), nibbles...)
+					if hash := nibblesToBytes(full); len(hash) == common.HashLength {
+						d.field("account hash", "%x", hash)
+						storagePathRoot = concat(TrieNodeStoragePrefix, hash)
+					}
+				}
+				describeAccount(d, account, storagePathRoot)
+				return
+			}
+		}
+		describeStorageValue(d, val)
+
+	default:
+		d.Err = fmt.Errorf("unexpected trie node with %d elements", count)
+	}
+}
+
+// trieOwnerLength returns the length of the prefix and owner part of a path-scheme key
+func trieOwnerLength(pathKey []byte) int {
+	if pathKey[0] == TrieNodeStoragePrefix[0] {
+		return 1 + common.HashLength
+	}
+	return 1
+}
+
+// childKey returns the key of a child node reached by following nibbles
+func childKey(pathKey, hash, nibbles []byte) []byte {
+	if pathKey == nil {
+		return hash
+	}
+	return concat(pathKey, nibbles)
+}
+
+// compactToNibbles decodes a hex-prefix encoded trie key
+func compactToNibbles(compact []byte) ([]byte, bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+	flag := compact[0] >> 4
+	leaf := flag&2 != 0
+
+	var nibbles []byte
+	if flag&1 != 0 {
+		nibbles = append(nibbles, compact[0]&0x0f)
+	}
+	for _, b := range compact[1:] {
+		nibbles = append(nibbles, b>>4, b&0x0f)
+	}
+	return nibbles, leaf
+}
+
+// nibblesToBytes packs an even number of nibbles into bytes
+func nibblesToBytes(nibbles []byte) []byte {
+	if len(nibbles)%2 != 0 {
+		return nil
+	}
+	out := make([]byte, len(nibbles)/2)
+	for i := range out {
+		out[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	return out
+}
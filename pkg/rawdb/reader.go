@@ -0,0 +1,266 @@
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+)
+
+// ErrNotFound is returned when a requested chain item is not in the database
+var ErrNotFound = errors.New("not found")
+
+// Subnet-EVM acceptor pointers, stored under the namespace like every other key
+var (
+	AcceptorTipKey       = []byte("AcceptorTipKey")
+	AcceptorTipHeightKey = []byte("AcceptorTipHeightKey")
+)
+
+// Reader reads geth-schema chain data straight out of a pebble database.
+// Subnet-EVM databases prefix every key with a 32-byte namespace; pass it
+// as namespace (or nil for plain C-Chain / de-namespaced databases).
+type Reader struct {
+	db        *pebble.DB
+	namespace []byte
+}
+
+// NewReader creates a reader over db using the given key namespace
+func NewReader(db *pebble.DB, namespace []byte) *Reader {
+	return &Reader{
+		db:        db,
+		namespace: namespace,
+	}
+}
+
+// DB returns the underlying database
+func (r *Reader) DB() *pebble.DB {
+	return r.db
+}
+
+// Key applies the reader's namespace to a schema key
+func (r *Reader) Key(key []byte) []byte {
+	if len(r.namespace) == 0 {
+		return key
+	}
+	return concat(r.namespace, key)
+}
+
+// Get returns a copy of the value stored under the (un-namespaced) key
+func (r *Reader) Get(key []byte) ([]byte, error) {
+	value, closer, err := r.db.Get(r.Key(key))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	return bytes.Clone(value), nil
+}
+
+// Has reports whether the (un-namespaced) key exists
+func (r *Reader) Has(key []byte) (bool, error) {
+	_, err := r.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReadCanonicalHash returns the canonical block hash at a height
+func (r *Reader) ReadCanonicalHash(number uint64) (common.Hash, error) {
+	data, err := r.Get(CanonicalHashKey(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(data) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("canonical hash for block %d has length %d", number, len(data))
+	}
+	return common.BytesToHash(data), nil
+}
+
+// ReadHeaderNumber returns the block number for a block hash
+func (r *Reader) ReadHeaderNumber(hash common.Hash) (uint64, error) {
+	data, err := r.Get(HeaderNumberKey(hash))
+	if err != nil {
+		return 0, err
+	}
+	number, ok := DecodeBlockNumber(data)
+	if !ok {
+		return 0, fmt.Errorf("invalid block number for hash %s", hash.Hex())
+	}
+	return number, nil
+}
+
+// ReadHeader reads and decodes a block header
+func (r *Reader) ReadHeader(number uint64, hash common.Hash) (*types.Header, error) {
+	data, err := r.Get(HeaderKey(number, hash))
+	if err != nil {
+		return nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, fmt.Errorf("failed to decode header %d: %w", number, err)
+	}
+	return header, nil
+}
+
+// ReadBody reads and decodes a block body
+func (r *Reader) ReadBody(number uint64, hash common.Hash) (*types.Body, error) {
+	data, err := r.Get(BlockBodyKey(number, hash))
+	if err != nil {
+		return nil, err
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(data, body); err != nil {
+		return nil, fmt.Errorf("failed to decode body %d: %w", number, err)
+	}
+	return body, nil
+}
+
+// ReadReceipts reads the stored receipts of a block. When the block body is
+// supplied, the fields that are not persisted (tx hash, per-tx gas used, log
+// positions) are derived from it.
+func (r *Reader) ReadReceipts(number uint64, hash common.Hash, body *types.Body) ([]*types.Receipt, error) {
+	data, err := r.Get(BlockReceiptsKey(number, hash))
+	if err != nil {
+		return nil, err
+	}
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode receipts %d: %w", number, err)
+	}
+
+	receipts := make([]*types.Receipt, len(stored))
+	logIndex := uint(0)
+	for i, s := range stored {
+		receipt := (*types.Receipt)(s)
+		receipt.BlockHash = hash
+		receipt.BlockNumber = new(big.Int).SetUint64(number)
+		receipt.TransactionIndex = uint(i)
+
+		if i == 0 {
+			receipt.GasUsed = receipt.CumulativeGasUsed
+		} else {
+			receipt.GasUsed = receipt.CumulativeGasUsed - stored[i-1].CumulativeGasUsed
+		}
+		if body != nil && i < len(body.Transactions) {
+			receipt.TxHash = body.Transactions[i].Hash()
+		}
+
+		for _, l := range receipt.Logs {
+			l.BlockNumber = number
+			l.BlockHash = hash
+			l.TxHash = receipt.TxHash
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+		}
+		receipts[i] = receipt
+	}
+	return receipts, nil
+}
+
+// ReadTxLookup returns the block number recorded for a transaction hash
+func (r *Reader) ReadTxLookup(hash common.Hash) (uint64, error) {
+	data, err := r.Get(TxLookupKey(hash))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > 8 {
+		return 0, fmt.Errorf("invalid tx lookup entry for %s", hash.Hex())
+	}
+	return new(big.Int).SetBytes(data).Uint64(), nil
+}
+
+// HeadNumber returns the height of the chain head. It follows the LastBlock
+// and acceptor tip pointers and falls back to searching the canonical index.
+func (r *Reader) HeadNumber() (uint64, error) {
+	for _, key := range [][]byte{HeadBlockKey, AcceptorTipKey} {
+		data, err := r.Get(key)
+		if err != nil || len(data) != common.HashLength {
+			continue
+		}
+		if number, err := r.ReadHeaderNumber(common.BytesToHash(data)); err == nil {
+			return number, nil
+		}
+	}
+	if data, err := r.Get(AcceptorTipHeightKey); err == nil {
+		if number, ok := DecodeBlockNumber(data); ok {
+			return number, nil
+		}
+	}
+	return r.searchCanonicalHead()
+}
+
+// searchCanonicalHead finds the highest canonical block, assuming canonical
+// hashes are stored contiguously from genesis.
+func (r *Reader) searchCanonicalHead() (uint64, error) {
+	if _, err := r.ReadCanonicalHash(0); err != nil {
+		return 0, fmt.Errorf("no canonical blocks found: %w", err)
+	}
+
+	// Grow an upper bound, then binary search between the last hit and miss
+	lo, hi := uint64(0), uint64(1)
+	for {
+		if _, err := r.ReadCanonicalHash(hi); err != nil {
+			break
+		}
+		lo = hi
+		hi *= 2
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if _, err := r.ReadCanonicalHash(mid); err == nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// ScanKeys calls fn for every key under prefix, in order, starting at start
+// (or the beginning of the prefix when start is nil). Keys passed to fn have
+// the namespace stripped; values are only valid for the duration of the call.
+// Scanning stops when fn returns false.
+func (r *Reader) ScanKeys(prefix, start []byte, fn func(key, value []byte) bool) error {
+	lower := r.Key(prefix)
+	upper := upperBound(lower)
+	if start != nil {
+		lower = r.Key(start)
+	}
+	iter, err := r.db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		if !fn(iter.Key()[len(r.namespace):], iter.Value()) {
+			break
+		}
+	}
+	return iter.Error()
+}
+
+// upperBound returns the smallest key greater than every key with the prefix
+func upperBound(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
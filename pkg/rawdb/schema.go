@@ -0,0 +1,123 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Keys written by the offline indexers. They live alongside the geth schema
+// but use long ASCII prefixes so they can never collide with it.
+var (
+	// LogIndexPrefix + address + num (uint64 big endian) -> log count (uint32 big endian).
+	// An address is indexed for the logs it emitted and the Transfer logs it
+	// sent or received tokens in.
+	LogIndexPrefix = []byte("genesis-logidx-")
+
+	// Block ranges covered by `index tx` and `index logs`, as an IndexRange
+	TxIndexRangeKey  = []byte("genesis-index-tx-range")
+	LogIndexRangeKey = []byte("genesis-index-logs-range")
+)
+
+// IndexRange is the contiguous block range [Start, Next) an offline index
+// covers. Blocks outside it may or may not have entries.
+type IndexRange struct {
+	Start uint64
+	Next  uint64
+}
+
+// Contains reports whether block number is covered by the range
+func (r IndexRange) Contains(number uint64) bool {
+	return number >= r.Start && number < r.Next
+}
+
+// Encode encodes the range as its start and next block, uint64 big endian
+func (r IndexRange) Encode() []byte {
+	return concat(EncodeBlockNumber(r.Start), EncodeBlockNumber(r.Next))
+}
+
+// DecodeIndexRange decodes a range written by IndexRange.Encode
+func DecodeIndexRange(data []byte) (IndexRange, bool) {
+	if len(data) != 16 {
+		return IndexRange{}, false
+	}
+	r := IndexRange{Start: binary.BigEndian.Uint64(data[:8]), Next: binary.BigEndian.Uint64(data[8:])}
+	if r.Next < r.Start {
+		return IndexRange{}, false
+	}
+	return r, true
+}
+
+// EncodeBlockNumber encodes a block number as uint64 big endian
+func EncodeBlockNumber(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	return enc
+}
+
+// DecodeBlockNumber decodes a uint64 big endian block number
+func DecodeBlockNumber(data []byte) (uint64, bool) {
+	if len(data) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(data), true
+}
+
+// HeaderKey = headerPrefix + num (uint64 big endian) + hash
+func HeaderKey(number uint64, hash common.Hash) []byte {
+	return concat(HeaderPrefix, EncodeBlockNumber(number), hash.Bytes())
+}
+
+// CanonicalHashKey = headerPrefix + num (uint64 big endian) + headerHashSuffix
+func CanonicalHashKey(number uint64) []byte {
+	return concat(HeaderPrefix, EncodeBlockNumber(number), HeaderHashSuffix)
+}
+
+// HeaderNumberKey = headerNumberPrefix + hash
+func HeaderNumberKey(hash common.Hash) []byte {
+	return concat(HeaderNumberPrefix, hash.Bytes())
+}
+
+// BlockBodyKey = blockBodyPrefix + num (uint64 big endian) + hash
+func BlockBodyKey(number uint64, hash common.Hash) []byte {
+	return concat(BlockBodyPrefix, EncodeBlockNumber(number), hash.Bytes())
+}
+
+// BlockReceiptsKey = blockReceiptsPrefix + num (uint64 big endian) + hash
+func BlockReceiptsKey(number uint64, hash common.Hash) []byte {
+	return concat(BlockReceiptsPrefix, EncodeBlockNumber(number), hash.Bytes())
+}
+
+// TxLookupKey = txLookupPrefix + hash
+func TxLookupKey(hash common.Hash) []byte {
+	return concat(TxLookupPrefix, hash.Bytes())
+}
+
+// TxLookupValue encodes a tx-lookup entry the same way geth does: the block
+// number as a big-endian integer with leading zero bytes stripped.
+func TxLookupValue(number uint64) []byte {
+	return new(big.Int).SetUint64(number).Bytes()
+}
+
+// LogIndexKey = logIndexPrefix + address + num (uint64 big endian)
+func LogIndexKey(addr common.Address, number uint64) []byte {
+	return concat(LogIndexPrefix, addr.Bytes(), EncodeBlockNumber(number))
+}
+
+// LogIndexAddressPrefix returns the key prefix covering every indexed block for addr
+func LogIndexAddressPrefix(addr common.Address) []byte {
+	return concat(LogIndexPrefix, addr.Bytes())
+}
+
+func concat(parts ...[]byte) []byte {
+	size := 0
+	for _, p := range parts {
+		size += len(p)
+	}
+	key := make([]byte, 0, size)
+	for _, p := range parts {
+		key = append(key, p...)
+	}
+	return key
+}
@@ -0,0 +1,26 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexRange(t *testing.T) {
+	r := IndexRange{Start: 1000, Next: 2001}
+	decoded, ok := DecodeIndexRange(r.Encode())
+	assert.True(t, ok)
+	assert.Equal(t, r, decoded)
+
+	assert.False(t, r.Contains(999))
+	assert.True(t, r.Contains(1000))
+	assert.True(t, r.Contains(2000))
+	assert.False(t, r.Contains(2001))
+	assert.False(t, IndexRange{Start: 5, Next: 5}.Contains(5))
+
+	// The old single next-block marker and inverted ranges are rejected
+	_, ok = DecodeIndexRange(EncodeBlockNumber(2001))
+	assert.False(t, ok)
+	_, ok = DecodeIndexRange(IndexRange{Start: 10, Next: 5}.Encode())
+	assert.False(t, ok)
+}
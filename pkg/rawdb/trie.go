@@ -0,0 +1,157 @@
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/luxfi/geth/rlp"
+)
+
+// ErrMissingTrieNode is returned when a state lookup reaches a trie node that
+// is not in the database, typically because the state was pruned
+var ErrMissingTrieNode = errors.New("missing trie node")
+
+// ReadAccount looks up an account in the hash-scheme state trie with the
+// given root. It returns (nil, nil) when the account does not exist and
+// ErrMissingTrieNode when the state is not (fully) present.
+func (r *Reader) ReadAccount(root common.Hash, addr common.Address) (*types.StateAccount, error) {
+	value, err := r.trieGet(root, crypto.Keccak256(addr.Bytes()))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	account := new(types.StateAccount)
+	if err := rlp.DecodeBytes(value, account); err != nil {
+		return nil, fmt.Errorf("failed to decode account %s: %w", addr.Hex(), err)
+	}
+	return account, nil
+}
+
+// ReadStorage looks up a storage slot in the hash-scheme storage trie with
+// the given root. Missing slots read as the zero hash.
+func (r *Reader) ReadStorage(storageRoot common.Hash, slot common.Hash) (common.Hash, error) {
+	value, err := r.trieGet(storageRoot, crypto.Keccak256(slot.Bytes()))
+	if err != nil || value == nil {
+		return common.Hash{}, err
+	}
+	_, content, _, err := rlp.Split(value)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode slot %s: %w", slot.Hex(), err)
+	}
+	return common.BytesToHash(content), nil
+}
+
+// trieGet resolves a hashed key in a hash-scheme trie
+func (r *Reader) trieGet(root common.Hash, hashedKey []byte) ([]byte, error) {
+	if root == types.EmptyRootHash || root == (common.Hash{}) {
+		return nil, nil
+	}
+	node, err := r.resolveNode(root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	key := keybytesToNibbles(hashedKey)
+	for node != nil {
+		elems, _, err := rlp.SplitList(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trie node: %w", err)
+		}
+		count, err := rlp.CountValues(elems)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trie node: %w", err)
+		}
+
+		switch count {
+		case 17:
+			if len(key) == 0 {
+				return nil, nil // Values are never stored in branches of secure tries
+			}
+			for i := byte(0); i < key[0]; i++ {
+				if _, _, elems, err = rlp.Split(elems); err != nil {
+					return nil, err
+				}
+			}
+			child, err := rawElement(elems)
+			if err != nil {
+				return nil, err
+			}
+			key = key[1:]
+			if node, err = r.resolveNode(child); err != nil {
+				return nil, err
+			}
+
+		case 2:
+			_, compact, rest, err := rlp.Split(elems)
+			if err != nil {
+				return nil, err
+			}
+			nibbles, leaf := compactToNibbles(compact)
+			if !bytes.HasPrefix(key, nibbles) {
+				return nil, nil
+			}
+			if leaf {
+				if len(key) != len(nibbles) {
+					return nil, nil
+				}
+				_, value, _, err := rlp.Split(rest)
+				return value, err
+			}
+			child, err := rawElement(rest)
+			if err != nil {
+				return nil, err
+			}
+			key = key[len(nibbles):]
+			if node, err = r.resolveNode(child); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid trie node with %d elements", count)
+		}
+	}
+	return nil, nil
+}
+
+// resolveNode turns a child reference into an encoded node: embedded nodes
+// are returned as is, hashes are read from the database and empty references
+// resolve to nil.
+func (r *Reader) resolveNode(ref []byte) ([]byte, error) {
+	if len(ref) == 0 {
+		return nil, nil
+	}
+	if len(ref) != common.HashLength {
+		return ref, nil
+	}
+	node, err := r.Get(ref)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w %x", ErrMissingTrieNode, ref)
+	}
+	return node, err
+}
+
+// rawElement returns the first RLP element of elems: the content of a string
+// (a hash reference or empty) or the full encoding of a list (an embedded node)
+func rawElement(elems []byte) ([]byte, error) {
+	kind, content, rest, err := rlp.Split(elems)
+	if err != nil {
+		return nil, err
+	}
+	if kind == rlp.List {
+		return elems[:len(elems)-len(rest)], nil
+	}
+	return content, nil
+}
+
+// keybytesToNibbles expands a key into one nibble per byte
+func keybytesToNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[2*i] = b >> 4
+		nibbles[2*i+1] = b & 0x0f
+	}
+	return nibbles
+}
@@ -0,0 +1,128 @@
+package rawdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+	gethrawdb "github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/tracing"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/trie"
+	"github.com/luxfi/geth/trie/trienode"
+	"github.com/luxfi/geth/triedb"
+	"github.com/luxfi/geth/triedb/hashdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// copyToPebble copies every key of a geth database into an in-memory pebble
+// database and returns a reader over it
+func copyToPebble(t *testing.T, src ethdb.Database) (*pebble.DB, *Reader) {
+	db, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	it := src.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		require.NoError(t, db.Set(it.Key(), it.Value(), pebble.NoSync))
+	}
+	require.NoError(t, it.Error())
+	return db, NewReader(db, nil)
+}
+
+func TestReadAccountAndStorage(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+		bob   = common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+		slot  = common.HexToHash("0x01")
+		value = common.HexToHash("0xdeadbeef")
+	)
+
+	src := gethrawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(src, &triedb.Config{HashDB: hashdb.Defaults})
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	require.NoError(t, err)
+	statedb.SetBalance(alice, uint256.NewInt(1e18), tracing.BalanceChangeUnspecified)
+	statedb.SetNonce(alice, 3, tracing.NonceChangeUnspecified)
+	statedb.SetState(alice, slot, value)
+	root, err := statedb.Commit(0, false, false)
+	require.NoError(t, err)
+	require.NoError(t, tdb.Commit(root, false))
+
+	db, r := copyToPebble(t, src)
+
+	account, err := r.ReadAccount(root, alice)
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, uint64(1e18), account.Balance.Uint64())
+	assert.Equal(t, uint64(3), account.Nonce)
+	storageRoot := account.Root
+
+	stored, err := r.ReadStorage(storageRoot, slot)
+	require.NoError(t, err)
+	assert.Equal(t, value, stored)
+	stored, err = r.ReadStorage(storageRoot, common.HexToHash("0x02"))
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}, stored)
+
+	// Absent accounts and empty roots are not errors
+	account, err = r.ReadAccount(root, bob)
+	require.NoError(t, err)
+	assert.Nil(t, account)
+	account, err = r.ReadAccount(types.EmptyRootHash, alice)
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	// A pruned root or subtree is reported as missing
+	_, err = r.ReadAccount(common.HexToHash("0x1234"), alice)
+	assert.True(t, errors.Is(err, ErrMissingTrieNode))
+	require.NoError(t, db.Delete(storageRoot.Bytes(), pebble.NoSync))
+	_, err = r.ReadStorage(storageRoot, slot)
+	assert.True(t, errors.Is(err, ErrMissingTrieNode))
+}
+
+func TestTrieGetEmbeddedNodes(t *testing.T) {
+	// Keys that differ only in their last nibble put both leaves, and the
+	// branch holding them, inline in the root extension node
+	var k1, k2, k3, k4 [32]byte
+	k2[31] = 0x01
+	k3[31] = 0x02
+	k4[0] = 0x10
+
+	src := gethrawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(src, &triedb.Config{HashDB: hashdb.Defaults})
+	tr := trie.NewEmpty(tdb)
+	tr.MustUpdate(k1[:], []byte("a"))
+	tr.MustUpdate(k2[:], []byte("b"))
+	root, nodes := tr.Commit(false)
+	require.NoError(t, tdb.Update(root, types.EmptyRootHash, 0, trienode.NewWithNodeSet(nodes), nil))
+	require.NoError(t, tdb.Commit(root, false))
+
+	_, r := copyToPebble(t, src)
+
+	node, err := r.Get(root.Bytes())
+	require.NoError(t, err)
+	require.Less(t, len(node), 64, "root should embed its children")
+
+	value, err := r.trieGet(root, k1[:])
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), value)
+	value, err = r.trieGet(root, k2[:])
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), value)
+
+	// An empty branch child and a diverging extension both miss
+	value, err = r.trieGet(root, k3[:])
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = r.trieGet(root, k4[:])
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
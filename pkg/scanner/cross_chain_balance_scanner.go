@@ -7,10 +7,10 @@ import (
 	"math/big"
 	"strings"
 
+	ethereum "github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/accounts/abi/bind"
 	"github.com/luxfi/geth/common"
-	"github.com/luxfi/geth/ethclient"
 )
 
 // CrossChainBalance represents a balance on a specific chain
@@ -24,7 +24,7 @@ type CrossChainBalance struct {
 
 // CrossChainBalanceScanner scans balances across multiple chains
 type CrossChainBalanceScanner struct {
-	clients map[string]*ethclient.Client
+	sources map[string]ChainSource
 	config  *CrossChainBalanceScanConfig
 }
 
@@ -39,22 +39,25 @@ type ChainConfig struct {
 	ChainID      int64  `json:"chainId"`
 	RPC          string `json:"rpc"`
 	TokenAddress string `json:"tokenAddress"`
+
+	// Source, if set, is read instead of dialing RPC; Close closes it
+	Source ChainSource `json:"-"`
 }
 
 // NewCrossChainBalanceScanner creates a new cross-chain balance scanner
 func NewCrossChainBalanceScanner(config *CrossChainBalanceScanConfig) (*CrossChainBalanceScanner, error) {
-	clients := make(map[string]*ethclient.Client)
+	sources := make(map[string]ChainSource)
 
 	for _, chain := range config.Chains {
-		client, err := ethclient.Dial(chain.RPC)
+		source, err := sourceOrDial(chain.Source, chain.RPC)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to %s: %w", chain.Name, err)
 		}
-		clients[chain.Name] = client
+		sources[chain.Name] = source
 	}
 
 	scanner := &CrossChainBalanceScanner{
-		clients: clients,
+		sources: sources,
 		config:  config,
 	}
 
@@ -66,7 +69,7 @@ func (s *CrossChainBalanceScanner) ScanBalances(addresses []string) (map[string]
 	balanceMap := make(map[string][]CrossChainBalance)
 
 	for _, chain := range s.config.Chains {
-		client, ok := s.clients[chain.Name]
+		source, ok := s.sources[chain.Name]
 		if !ok {
 			continue
 		}
@@ -74,7 +77,7 @@ func (s *CrossChainBalanceScanner) ScanBalances(addresses []string) (map[string]
 		log.Printf("Scanning balances on %s (chain %d)", chain.Name, chain.ChainID)
 
 		for _, addr := range addresses {
-			balance, blockNum, err := s.getTokenBalance(client, addr, chain.TokenAddress)
+			balance, blockNum, err := s.getTokenBalance(source, addr, chain.TokenAddress)
 			if err != nil {
 				log.Printf("Warning: failed to get balance for %s on %s: %v", addr, chain.Name, err)
 				continue
@@ -142,13 +145,13 @@ func (s *CrossChainBalanceScanner) CompareBalances(addresses []string) ([]Balanc
 	comparisons := []BalanceComparison{}
 
 	for _, addr := range addresses {
-		sourceBalance, _, err := s.getTokenBalance(s.clients[sourceChain.Name], addr, sourceChain.TokenAddress)
+		sourceBalance, _, err := s.getTokenBalance(s.sources[sourceChain.Name], addr, sourceChain.TokenAddress)
 		if err != nil {
 			log.Printf("Warning: failed to get source balance for %s: %v", addr, err)
 			continue
 		}
 
-		targetBalance, _, err := s.getTokenBalance(s.clients[targetChain.Name], addr, targetChain.TokenAddress)
+		targetBalance, _, err := s.getTokenBalance(s.sources[targetChain.Name], addr, targetChain.TokenAddress)
 		if err != nil {
 			log.Printf("Warning: failed to get target balance for %s: %v", addr, err)
 			continue
@@ -173,39 +176,49 @@ func (s *CrossChainBalanceScanner) CompareBalances(addresses []string) ([]Balanc
 }
 
 // getTokenBalance gets the ERC20 token balance for an address
-func (s *CrossChainBalanceScanner) getTokenBalance(client *ethclient.Client, address, tokenAddress string) (*big.Int, uint64, error) {
+func (s *CrossChainBalanceScanner) getTokenBalance(source ChainSource, address, tokenAddress string) (*big.Int, uint64, error) {
 	ctx := context.Background()
 
 	// Get current block number
-	header, err := client.HeaderByNumber(ctx, nil)
+	header, err := source.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return nil, 0, err
 	}
 	blockNum := header.Number.Uint64()
 
-	// Create token instance
-	token, err := NewERC20(common.HexToAddress(tokenAddress), client)
+	// Call balanceOf at that block
+	parsed, err := abi.JSON(strings.NewReader(ERC20BalanceABI))
 	if err != nil {
 		return nil, 0, err
 	}
-
-	// Get balance
-	opts := &bind.CallOpts{Context: ctx}
-	balance, err := token.BalanceOf(opts, common.HexToAddress(address))
+	data, err := parsed.Pack("balanceOf", common.HexToAddress(address))
+	if err != nil {
+		return nil, 0, err
+	}
+	token := common.HexToAddress(tokenAddress)
+	result, err := source.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, header.Number)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	var balance *big.Int
+	if err := parsed.UnpackIntoInterface(&balance, "balanceOf", result); err != nil {
+		return nil, 0, err
+	}
+
 	return balance, blockNum, nil
 }
 
-// Close closes all client connections
+// Close closes all chain sources
 func (s *CrossChainBalanceScanner) Close() error {
-	for name, client := range s.clients {
-		client.Close()
-		delete(s.clients, name)
+	var firstErr error
+	for name, source := range s.sources {
+		if err := source.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.sources, name)
 	}
-	return nil
+	return firstErr
 }
 
 // BalanceComparison represents a balance comparison between chains
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LogErrorKind is how the log-fetch layer handles a provider error
+type LogErrorKind int
+
+const (
+	// LogErrorFatal errors end the scan
+	LogErrorFatal LogErrorKind = iota
+	// LogErrorRetry errors are transient; the request is retried after a backoff
+	LogErrorRetry
+	// LogErrorTooManyResults errors reject the size of the result; the range
+	// is split
+	LogErrorTooManyResults
+	// LogErrorRangeTooLarge errors reject the number of blocks; the range is
+	// split and later ranges stay below it
+	LogErrorRangeTooLarge
+)
+
+// Provider error messages, lowercased. Rate limits are matched first, since
+// some providers word them as exceeded limits too, and result caps before
+// range caps, since their messages may mention block ranges.
+var (
+	rateLimitErrors = []string{
+		"rate limit",
+		"rate-limit",
+		"ratelimit",
+		"too many requests",
+		"request count exceeded",
+		"capacity exceeded",
+		"compute units",
+	}
+
+	tooManyResultsErrors = []string{
+		"query returned more than", // geth, Infura, Alchemy
+		"response size exceeded",   // Alchemy
+		"response size should not", // Ankr
+		"too many results",
+		"too many logs",
+		"query timeout exceeded", // Infura: the range is too expensive
+		"limit exceeded",
+	}
+
+	rangeTooLargeErrors = []string{
+		"block range", // exceed maximum block range, block range too large/wide
+		"range too large",
+		"range is too large",
+		"range is too wide",
+		"too many blocks",
+		"is limited to", // QuickNode: eth_getLogs is limited to a 10,000 range
+	}
+
+	transientErrors = []string{
+		"timeout",
+		"timed out",
+		"deadline exceeded",
+		"unexpected eof",
+		"connection reset",
+		"connection refused",
+		"broken pipe",
+		"temporarily unavailable",
+		"bad gateway",
+		"service unavailable",
+		"gateway timeout",
+		"header not found", // the node behind a load balancer lags
+		"try again",
+	}
+)
+
+// ClassifyLogError tells how a failed log request should be handled
+func ClassifyLogError(err error) LogErrorKind {
+	if err == nil {
+		return LogErrorFatal
+	}
+	msg := strings.ToLower(err.Error())
+	if containsAny(msg, rateLimitErrors) {
+		return LogErrorRetry
+	}
+	if containsAny(msg, tooManyResultsErrors) {
+		return LogErrorTooManyResults
+	}
+	if containsAny(msg, rangeTooLargeErrors) {
+		return LogErrorRangeTooLarge
+	}
+
+	var netErr net.Error
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return LogErrorRetry
+	}
+	if containsAny(msg, transientErrors) {
+		return LogErrorRetry
+	}
+	return LogErrorFatal
+}
+
+// suggestedRangeRe matches the range providers suggest with result cap
+// errors, such as "Try with this block range [0x1e8480, 0x1e8c4f]."
+var suggestedRangeRe = regexp.MustCompile(`\[(0x[0-9a-fA-F]+),\s*(0x[0-9a-fA-F]+)\]`)
+
+// suggestedEnd returns the last block of the range suggested in err
+func suggestedEnd(err error) (uint64, bool) {
+	m := suggestedRangeRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	end, parseErr := strconv.ParseUint(m[2][2:], 16, 64)
+	if parseErr != nil {
+		return 0, false
+	}
+	return end, true
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
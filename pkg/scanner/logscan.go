@@ -0,0 +1,396 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+// Log fetch defaults
+const (
+	DefaultLogChunkSize     = 5000
+	DefaultMaxLogChunkSize  = 100000
+	DefaultSparseLogs       = 1000
+	DefaultLogRetries       = 5
+	DefaultLogRetryDelay    = time.Second
+	DefaultMaxLogRetryDelay = 30 * time.Second
+	DefaultLogConfirmations = 64
+)
+
+// LogQuery selects logs by emitting contract and topics, as in a filter
+// query: each topic position lists the accepted topics, an empty position
+// accepts any
+type LogQuery struct {
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+// LogScanConfig tunes how a LogScanner fetches logs. Zero values mean the
+// defaults.
+type LogScanConfig struct {
+	// ChunkSize is the block range of the first request. It is halved when
+	// the provider rejects a range as too large and doubled after ranges
+	// with fewer than SparseLogs logs, within [MinChunkSize, MaxChunkSize].
+	ChunkSize    uint64
+	MinChunkSize uint64
+	MaxChunkSize uint64
+	SparseLogs   int
+
+	// MaxRetries is how often a request failing with a transient error is
+	// retried (negative for never). The delay starts at RetryDelay and
+	// doubles per retry up to MaxRetryDelay, with jitter.
+	MaxRetries    int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// RequestsPerSecond caps the request rate, retries included; 0 means
+	// no limit
+	RequestsPerSecond float64
+
+	// Confirmations is how far below the chain head a block must be for its
+	// logs to be stored (negative to store up to the head). Newer blocks may
+	// still be reorganized away, so every scan fetches them again.
+	Confirmations int
+}
+
+// LogSourceOptions are the log-fetch settings shared by the scanners built
+// on a LogScanner. Scanner configs embed it and create their LogScanner with
+// its NewLogScanner.
+type LogSourceOptions struct {
+	// Source, if set, is read instead of dialing the scanner's RPC endpoint;
+	// the scanner's Close closes it
+	Source ChainSource `json:"-"`
+
+	// Store, if set, keeps fetched logs and checkpoints so an interrupted
+	// scan resumes and a rerun only fetches new blocks. The scanner's Close
+	// leaves it open. Blocks within Confirmations of the head are not stored
+	// (0 for the default, negative to store up to the head).
+	Store         *LogStore `json:"-"`
+	Confirmations int       `json:"confirmations,omitempty"`
+
+	// RequestsPerSecond caps log requests (0 for no limit). MaxRetries is
+	// how often a failed request is retried (0 for the default, negative for
+	// never).
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	MaxRetries        int     `json:"maxRetries,omitempty"`
+}
+
+// NewLogScanner creates a log scanner over source with these options.
+// chunkSize is only the first range; it adapts to the provider.
+func (o LogSourceOptions) NewLogScanner(source ChainSource, chunkSize uint64) *LogScanner {
+	return NewLogScanner(source, o.Store, LogScanConfig{
+		ChunkSize:         chunkSize,
+		RequestsPerSecond: o.RequestsPerSecond,
+		MaxRetries:        o.MaxRetries,
+		Confirmations:     o.Confirmations,
+	})
+}
+
+// LogScanner fetches the logs of a query over a block range, chunk by
+// chunk. It is the log-fetch layer shared by the scanners.
+//
+// The chunk size adapts to the provider: ranges rejected for returning too
+// many results or spanning too many blocks are split, and the size grows
+// again over sparse ranges. Transient errors (rate limits, timeouts,
+// dropped connections) are retried with backoff; other errors end the scan.
+//
+// With a LogStore every fetched chunk is stored together with the query's
+// checkpoint. A scan that fails part way then resumes after the last stored
+// chunk, and a later scan of the same query from the same block only fetches
+// the blocks added since, which makes recurring snapshots incremental. Blocks
+// within Confirmations of the head are passed on but not stored.
+type LogScanner struct {
+	source ChainSource
+	store  *LogStore
+	config LogScanConfig
+
+	mu          sync.Mutex
+	nextRequest time.Time
+	times       map[uint64]uint64 // block timestamps by number
+}
+
+// NewLogScanner creates a log scanner over source. store may be nil to scan
+// without keeping anything.
+func NewLogScanner(source ChainSource, store *LogStore, config LogScanConfig) *LogScanner {
+	if config.ChunkSize == 0 {
+		config.ChunkSize = DefaultLogChunkSize
+	}
+	if config.MinChunkSize == 0 {
+		config.MinChunkSize = 1
+	}
+	if config.MaxChunkSize == 0 {
+		config.MaxChunkSize = DefaultMaxLogChunkSize
+	}
+	config.MinChunkSize = min(config.MinChunkSize, config.ChunkSize)
+	config.MaxChunkSize = max(config.MaxChunkSize, config.ChunkSize)
+	if config.SparseLogs == 0 {
+		config.SparseLogs = DefaultSparseLogs
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultLogRetries
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = DefaultLogRetryDelay
+	}
+	if config.MaxRetryDelay == 0 {
+		config.MaxRetryDelay = DefaultMaxLogRetryDelay
+	}
+	if config.Confirmations == 0 {
+		config.Confirmations = DefaultLogConfirmations
+	}
+	return &LogScanner{
+		source: source,
+		store:  store,
+		config: config,
+		times:  make(map[uint64]uint64),
+	}
+}
+
+// Scan calls fn with every log of q in blocks [from, to], in chain order.
+// Blocks below the query's checkpoint are read from the store and the rest
+// are fetched from the source, and stored up to Confirmations below the
+// head. If fetching fails, the error is returned and the checkpoint stays
+// after the last stored chunk.
+func (s *LogScanner) Scan(ctx context.Context, q LogQuery, from, to uint64, fn func(types.Log) error) error {
+	if from > to {
+		return nil
+	}
+
+	next := from
+	var (
+		id []byte
+		cp *LogCheckpoint
+	)
+	if s.store != nil {
+		id = q.id(from)
+		var err error
+		if cp, err = s.store.checkpoint(id); err != nil {
+			return fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		if cp == nil {
+			cp = &LogCheckpoint{Addresses: q.Addresses, Topics: q.Topics, FromBlock: from, NextBlock: from}
+		}
+		if cp.NextBlock > from {
+			end := min(to, cp.NextBlock-1)
+			log.Printf("Resuming from checkpoint: blocks %d-%d already fetched", from, end)
+			if err := s.store.logs(id, from, end, fn); err != nil {
+				return err
+			}
+			next = end + 1
+		}
+	}
+
+	// Blocks from unstable on are too close to the head to be stored
+	unstable := to + 1
+	if s.store != nil && s.config.Confirmations > 0 && next <= to {
+		head, err := s.headNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain head: %w", err)
+		}
+		unstable = 0
+		if confirmations := uint64(s.config.Confirmations); head >= confirmations {
+			unstable = min(to+1, head-confirmations+1)
+		}
+		if unstable <= to {
+			log.Printf("Blocks %d-%d are within %d blocks of the head and will not be stored", max(next, unstable), to, s.config.Confirmations)
+		}
+	}
+
+	size, ceiling := s.config.ChunkSize, s.config.MaxChunkSize
+	for start := next; start <= to; {
+		end := to
+		if to-start >= size {
+			end = start + size - 1
+		}
+		if start < unstable && end >= unstable {
+			end = unstable - 1
+		}
+
+		logs, kind, err := s.fetch(ctx, q, start, end)
+		if err != nil {
+			split := kind == LogErrorTooManyResults || kind == LogErrorRangeTooLarge
+			if !split || end == start || end-start+1 <= s.config.MinChunkSize {
+				return fmt.Errorf("failed to get logs for blocks %d-%d: %w", start, end, err)
+			}
+			// Split the range, at the provider's suggestion if it made one.
+			// A block range cap holds for the rest of the scan, while a
+			// result cap depends on how busy the blocks are.
+			size = (end - start + 1) / 2
+			if hint, ok := suggestedEnd(err); ok && hint >= start && hint < end {
+				size = hint - start + 1
+			}
+			size = max(size, s.config.MinChunkSize)
+			if kind == LogErrorRangeTooLarge {
+				ceiling = size
+			}
+			log.Printf("Blocks %d-%d rejected as too large, retrying with %d blocks", start, end, size)
+			continue
+		}
+		if s.store != nil && end < unstable {
+			if err := s.store.commit(id, cp, logs, end+1); err != nil {
+				return fmt.Errorf("failed to store logs for blocks %d-%d: %w", start, end, err)
+			}
+		}
+		if len(logs) > 0 {
+			log.Printf("Fetched %d logs in blocks %d-%d", len(logs), start, end)
+		}
+		for _, l := range logs {
+			if err := fn(l); err != nil {
+				return err
+			}
+		}
+
+		if end == to {
+			break
+		}
+		if len(logs) < s.config.SparseLogs && size < ceiling {
+			size = min(size*2, ceiling)
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+// fetch requests the logs of q in blocks [start, end]. On failure it returns
+// the kind of the last error.
+func (s *LogScanner) fetch(ctx context.Context, q LogQuery, start, end uint64) ([]types.Log, LogErrorKind, error) {
+	filter := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(start),
+		ToBlock:   new(big.Int).SetUint64(end),
+		Addresses: q.Addresses,
+		Topics:    q.Topics,
+	}
+	var logs []types.Log
+	kind, err := s.request(ctx, fmt.Sprintf("get logs for blocks %d-%d", start, end), func() error {
+		var err error
+		logs, err = s.source.FilterLogs(ctx, filter)
+		return err
+	})
+	return logs, kind, err
+}
+
+// headNumber returns the height of the chain head
+func (s *LogScanner) headNumber(ctx context.Context) (uint64, error) {
+	var header *types.Header
+	if _, err := s.request(ctx, "get chain head", func() error {
+		var err error
+		header, err = s.source.HeaderByNumber(ctx, nil)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// BlockTime returns the timestamp of block number. Headers are requested
+// under the same rate limit and retries as logs, and each block's time is
+// only requested once per scanner.
+func (s *LogScanner) BlockTime(ctx context.Context, number uint64) (uint64, error) {
+	s.mu.Lock()
+	t, ok := s.times[number]
+	s.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	var header *types.Header
+	if _, err := s.request(ctx, fmt.Sprintf("get block %d", number), func() error {
+		var err error
+		header, err = s.source.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+
+	s.mu.Lock()
+	s.times[number] = header.Time
+	s.mu.Unlock()
+	return header.Time, nil
+}
+
+// request calls fn, which makes one request to the source, waiting for the
+// rate limit and retrying transient errors. On failure it returns the kind
+// of the last error.
+func (s *LogScanner) request(ctx context.Context, what string, fn func() error) (LogErrorKind, error) {
+	for attempt := 0; ; attempt++ {
+		if err := s.wait(ctx); err != nil {
+			return LogErrorFatal, err
+		}
+		err := fn()
+		if err == nil {
+			return LogErrorFatal, nil
+		}
+		if ctx.Err() != nil {
+			return LogErrorFatal, ctx.Err()
+		}
+
+		kind := ClassifyLogError(err)
+		if kind != LogErrorRetry {
+			return kind, err
+		}
+		if attempt >= s.config.MaxRetries {
+			return kind, fmt.Errorf("giving up after %d retries: %w", attempt, err)
+		}
+		delay := s.retryDelay(attempt)
+		log.Printf("Warning: failed to %s: %v (retrying in %s)", what, err, delay.Round(time.Millisecond))
+		if err := sleep(ctx, delay); err != nil {
+			return LogErrorFatal, err
+		}
+	}
+}
+
+// retryDelay returns the backoff before retry attempt+1: the base delay
+// doubled per attempt and capped, with up to half of it taken off at random
+// so that scanners sharing a provider do not retry in lockstep
+func (s *LogScanner) retryDelay(attempt int) time.Duration {
+	delay := s.config.MaxRetryDelay
+	if attempt < 32 {
+		delay = min(s.config.RetryDelay<<attempt, s.config.MaxRetryDelay)
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(rand.Int64N(half + 1))
+	}
+	return delay
+}
+
+// wait blocks until the rate limit allows the next request
+func (s *LogScanner) wait(ctx context.Context) error {
+	if s.config.RequestsPerSecond <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / s.config.RequestsPerSecond)
+
+	s.mu.Lock()
+	now := time.Now()
+	at := s.nextRequest
+	if at.Before(now) {
+		at = now
+	}
+	s.nextRequest = at.Add(interval)
+	s.mu.Unlock()
+
+	return sleep(ctx, at.Sub(now))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,455 @@
+package scanner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+// flakySource fails log requests reaching failAt and counts requests
+type flakySource struct {
+	*scanner.FakeSource
+	failAt   uint64
+	requests int
+}
+
+func (f *flakySource) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.requests++
+	if f.failAt > 0 && q.ToBlock.Uint64() >= f.failAt {
+		return nil, errors.New("connection reset")
+	}
+	return f.FakeSource.FilterLogs(ctx, q)
+}
+
+// fixedChunks fetches 10 blocks per request, does not retry and stores
+// blocks up to the head
+var fixedChunks = scanner.LogScanConfig{ChunkSize: 10, MaxChunkSize: 10, MaxRetries: -1, Confirmations: -1}
+
+func collectLogs(t *testing.T, s *scanner.LogScanner, q scanner.LogQuery, from, to uint64) ([]types.Log, error) {
+	t.Helper()
+	var logs []types.Log
+	err := s.Scan(context.Background(), q, from, to, func(l types.Log) error {
+		logs = append(logs, l)
+		return nil
+	})
+	return logs, err
+}
+
+func TestLogScannerResume(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+	path := filepath.Join(t.TempDir(), "logs")
+
+	fake := scanner.NewFakeSource()
+	fake.AddLogs(
+		erc20Transfer(5, 0, token, alice, bob, 1),
+		erc20Transfer(15, 0, token, alice, bob, 2),
+		erc20Transfer(15, 1, token, bob, alice, 3),
+		erc20Transfer(35, 0, token, alice, bob, 4),
+		erc20Transfer(45, 0, token, alice, bob, 5),
+	)
+	q := scanner.LogQuery{Addresses: []common.Address{token}, Topics: [][]common.Hash{{transferTopic}}}
+
+	// Blocks 0-9 and 10-19 are stored before the request for 20-29 fails
+	store, err := scanner.OpenLogStore(path)
+	require.NoError(t, err)
+	failing := &flakySource{FakeSource: fake, failAt: 20}
+	_, err = collectLogs(t, scanner.NewLogScanner(failing, store, fixedChunks), q, 0, 49)
+	require.ErrorContains(t, err, "blocks 20-29")
+	require.NoError(t, store.Close())
+
+	// After a restart only blocks 20-49 are fetched again
+	store, err = scanner.OpenLogStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	cp, err := store.Checkpoint(q, 0)
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.Equal(t, uint64(20), cp.NextBlock)
+	assert.Equal(t, uint64(3), cp.Logs)
+
+	source := &flakySource{FakeSource: fake}
+	logs, err := collectLogs(t, scanner.NewLogScanner(source, store, fixedChunks), q, 0, 49)
+	require.NoError(t, err)
+	assert.Equal(t, 3, source.requests)
+	require.Len(t, logs, 5)
+	for i, l := range logs {
+		assert.Equal(t, int64(i+1), new(big.Int).SetBytes(l.Data).Int64())
+	}
+	assert.Equal(t, uint(1), logs[2].Index)
+	assert.Equal(t, logs[2].TxHash, erc20Transfer(15, 1, token, bob, alice, 3).TxHash)
+}
+
+func TestLogScannerIncremental(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	store, err := scanner.OpenMemLogStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	fake := scanner.NewFakeSource()
+	fake.AddLogs(erc20Transfer(3, 0, token, alice, bob, 1))
+	source := &flakySource{FakeSource: fake}
+	s := scanner.NewLogScanner(source, store, scanner.LogScanConfig{ChunkSize: 100, Confirmations: -1})
+	q := scanner.LogQuery{Addresses: []common.Address{token}}
+
+	logs, err := collectLogs(t, s, q, 0, 9)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, 1, source.requests)
+
+	// The next snapshot only fetches the blocks added since
+	fake.AddLogs(erc20Transfer(12, 0, token, bob, alice, 2))
+	logs, err = collectLogs(t, s, q, 0, 19)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 2, source.requests)
+
+	// A rerun of a range that is already stored fetches nothing
+	logs, err = collectLogs(t, s, q, 0, 5)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, 2, source.requests)
+
+	// Another start block is another query
+	logs, err = collectLogs(t, s, q, 10, 19)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, 3, source.requests)
+
+	checkpoints, err := store.Checkpoints()
+	require.NoError(t, err)
+	assert.Len(t, checkpoints, 2)
+
+	require.NoError(t, store.Reset(q, 0))
+	cp, err := store.Checkpoint(q, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+	logs, err = collectLogs(t, s, q, 0, 19)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 4, source.requests)
+}
+
+func TestLogScannerConfirmations(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	store, err := scanner.OpenMemLogStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	fake := scanner.NewFakeSource()
+	fake.AddLogs(
+		erc20Transfer(5, 0, token, alice, bob, 1),
+		erc20Transfer(25, 0, token, alice, bob, 2),
+	)
+	fake.AddBlock(29, 0)
+	source := &flakySource{FakeSource: fake}
+	config := fixedChunks
+	config.Confirmations = 10
+	s := scanner.NewLogScanner(source, store, config)
+	q := scanner.LogQuery{Addresses: []common.Address{token}}
+
+	// Blocks 20-29 are within 10 blocks of head 29: passed on, not stored
+	logs, err := collectLogs(t, s, q, 0, 29)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 3, source.requests)
+	cp, err := store.Checkpoint(q, 0)
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.Equal(t, uint64(20), cp.NextBlock)
+	assert.Equal(t, uint64(1), cp.Logs)
+
+	// A rerun fetches the unconfirmed blocks again
+	logs, err = collectLogs(t, s, q, 0, 29)
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 4, source.requests)
+
+	// Once the head moves on they are stored; chunks stop at the last
+	// confirmed block
+	fake.AddLogs(erc20Transfer(35, 0, token, alice, bob, 3))
+	fake.AddBlock(45, 0)
+	logs, err = collectLogs(t, s, q, 0, 45)
+	require.NoError(t, err)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, 7, source.requests)
+	cp, err = store.Checkpoint(q, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(36), cp.NextBlock)
+	assert.Equal(t, uint64(3), cp.Logs)
+}
+
+func TestTokenBurnScannerWithStore(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	dead := common.HexToAddress(scanner.DeadAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+
+	store, err := scanner.OpenMemLogStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	fake := scanner.NewFakeSource()
+	fake.AddLogs(
+		erc20Transfer(10, 0, token, alice, dead, 100),
+		erc20Transfer(60, 0, token, alice, dead, 200),
+	)
+	failing := &flakySource{FakeSource: fake, failAt: 40}
+	config := &scanner.TokenBurnScanConfig{
+		TokenAddress: token.Hex(),
+		BurnAddress:  scanner.DeadAddress,
+		ToBlock:      60,
+		ChunkSize:    20,
+		LogSourceOptions: scanner.LogSourceOptions{
+			Source:        failing,
+			Store:         store,
+			Confirmations: -1,
+			MaxRetries:    -1,
+		},
+	}
+	s, err := scanner.NewTokenBurnScanner(config)
+	require.NoError(t, err)
+	_, err = s.ScanBurns()
+	require.Error(t, err)
+
+	source := &flakySource{FakeSource: fake}
+	config.Source = source
+	s, err = scanner.NewTokenBurnScanner(config)
+	require.NoError(t, err)
+	burns, err := s.ScanBurns()
+	require.NoError(t, err)
+	require.Len(t, burns, 2)
+	assert.Equal(t, "100", burns[0].Amount)
+	assert.Equal(t, "200", burns[1].Amount)
+	assert.Equal(t, 2, source.requests)
+}
+
+// limitedSource rejects requests returning more than limit logs, as
+// providers do, and records the requested ranges
+type limitedSource struct {
+	*scanner.FakeSource
+	limit    int
+	maxRange uint64
+	hint     bool
+	failures []error
+	ranges   [][2]uint64
+}
+
+func (l *limitedSource) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	l.ranges = append(l.ranges, [2]uint64{from, to})
+	if len(l.failures) > 0 {
+		err := l.failures[0]
+		l.failures = l.failures[1:]
+		return nil, err
+	}
+	if l.maxRange > 0 && to-from+1 > l.maxRange {
+		return nil, fmt.Errorf("exceed maximum block range: %d", l.maxRange)
+	}
+	logs, err := l.FakeSource.FilterLogs(ctx, q)
+	if err != nil || len(logs) <= l.limit {
+		return logs, err
+	}
+	if l.hint {
+		return nil, fmt.Errorf("query returned more than %d results. Try with this block range [%#x, %#x].", l.limit, from, logs[l.limit].BlockNumber-1)
+	}
+	return nil, fmt.Errorf("query returned more than %d results", l.limit)
+}
+
+func TestLogScannerAdaptiveChunks(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	fake := scanner.NewFakeSource()
+	var want []uint64
+	for block := uint64(0); block < 20; block++ { // dense
+		fake.AddLogs(erc20Transfer(block, 0, token, alice, bob, 1))
+		want = append(want, block)
+	}
+	for block := uint64(100); block < 400; block += 50 { // sparse
+		fake.AddLogs(erc20Transfer(block, 0, token, alice, bob, 1))
+		want = append(want, block)
+	}
+	q := scanner.LogQuery{Addresses: []common.Address{token}}
+	config := scanner.LogScanConfig{ChunkSize: 16, SparseLogs: 2}
+
+	for _, hint := range []bool{false, true} {
+		source := &limitedSource{FakeSource: fake, limit: 4, hint: hint}
+		logs, err := collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 0, 999)
+		require.NoError(t, err)
+
+		var got []uint64
+		for _, l := range logs {
+			got = append(got, l.BlockNumber)
+		}
+		assert.Equal(t, want, got)
+
+		// The range is split over the dense blocks and grows again after
+		widest := uint64(0)
+		for _, r := range source.ranges {
+			widest = max(widest, r[1]-r[0]+1)
+		}
+		assert.Greater(t, widest, uint64(100))
+		assert.Equal(t, [2]uint64{0, 15}, source.ranges[0])
+		if hint {
+			assert.Equal(t, [2]uint64{0, 3}, source.ranges[1])
+		}
+	}
+
+	// A block range cap is not exceeded again once hit
+	source := &limitedSource{FakeSource: fake, limit: 100, maxRange: 40}
+	logs, err := collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 0, 999)
+	require.NoError(t, err)
+	assert.Len(t, logs, len(want))
+	rejected := 0
+	for _, r := range source.ranges {
+		if r[1]-r[0]+1 > 40 {
+			rejected++
+		}
+	}
+	assert.Equal(t, 1, rejected)
+
+	// A single block over the limit cannot be split
+	fake.AddLogs(
+		erc20Transfer(500, 1, token, alice, bob, 1),
+		erc20Transfer(500, 2, token, alice, bob, 1),
+		erc20Transfer(500, 3, token, alice, bob, 1),
+		erc20Transfer(500, 4, token, alice, bob, 1),
+		erc20Transfer(500, 5, token, alice, bob, 1),
+	)
+	source = &limitedSource{FakeSource: fake, limit: 4}
+	_, err = collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 500, 500)
+	assert.ErrorContains(t, err, "query returned more than 4 results")
+}
+
+func TestLogScannerRetries(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	fake := scanner.NewFakeSource()
+	fake.AddLogs(erc20Transfer(5, 0, token, alice, bob, 1))
+	q := scanner.LogQuery{Addresses: []common.Address{token}}
+	config := scanner.LogScanConfig{MaxRetries: 2, RetryDelay: time.Millisecond}
+
+	source := &limitedSource{FakeSource: fake, limit: 100, failures: []error{
+		errors.New("429 Too Many Requests: rate limit exceeded"),
+		errors.New("503 Service Unavailable"),
+	}}
+	logs, err := collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 0, 9)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Len(t, source.ranges, 3)
+
+	// Retries run out
+	source = &limitedSource{FakeSource: fake, limit: 100, failures: []error{
+		io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF,
+	}}
+	_, err = collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 0, 9)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Len(t, source.ranges, 3)
+
+	// Other errors are not retried
+	source = &limitedSource{FakeSource: fake, limit: 100, failures: []error{
+		errors.New("invalid argument 0: hex string without 0x prefix"),
+	}}
+	_, err = collectLogs(t, scanner.NewLogScanner(source, nil, config), q, 0, 9)
+	assert.Error(t, err)
+	assert.Len(t, source.ranges, 1)
+}
+
+func TestLogScannerRateLimit(t *testing.T) {
+	fake := scanner.NewFakeSource()
+	source := &limitedSource{FakeSource: fake, limit: 100}
+	config := scanner.LogScanConfig{ChunkSize: 1, MaxChunkSize: 1, RequestsPerSecond: 100}
+
+	started := time.Now()
+	_, err := collectLogs(t, scanner.NewLogScanner(source, nil, config), scanner.LogQuery{}, 0, 5)
+	require.NoError(t, err)
+	assert.Len(t, source.ranges, 6)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+}
+
+// headerSource fails the first header requests and counts header requests
+type headerSource struct {
+	*scanner.FakeSource
+	failures []error
+	requests int
+}
+
+func (h *headerSource) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	h.requests++
+	if len(h.failures) > 0 {
+		err := h.failures[0]
+		h.failures = h.failures[1:]
+		return nil, err
+	}
+	return h.FakeSource.HeaderByNumber(ctx, number)
+}
+
+func TestLogScannerBlockTime(t *testing.T) {
+	ctx := context.Background()
+	fake := scanner.NewFakeSource()
+	fake.AddBlock(7, 1600000000)
+	config := scanner.LogScanConfig{MaxRetries: 1, RetryDelay: time.Millisecond}
+
+	// A transient failure is retried and the time is then cached
+	source := &headerSource{FakeSource: fake, failures: []error{errors.New("503 Service Unavailable")}}
+	logs := scanner.NewLogScanner(source, nil, config)
+	for range 3 {
+		blockTime, err := logs.BlockTime(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1600000000), blockTime)
+	}
+	assert.Equal(t, 2, source.requests)
+
+	// Failures past the retries are returned
+	source = &headerSource{FakeSource: fake, failures: []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF}}
+	_, err := scanner.NewLogScanner(source, nil, config).BlockTime(ctx, 7)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, 2, source.requests)
+}
+
+func TestClassifyLogError(t *testing.T) {
+	tests := []struct {
+		err  string
+		want scanner.LogErrorKind
+	}{
+		{"query returned more than 10000 results", scanner.LogErrorTooManyResults},
+		{"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range", scanner.LogErrorTooManyResults},
+		{"exceed maximum block range: 5000", scanner.LogErrorRangeTooLarge},
+		{"eth_getLogs is limited to a 10,000 range", scanner.LogErrorRangeTooLarge},
+		{"query timeout exceeded", scanner.LogErrorTooManyResults},
+		{"429 Too Many Requests", scanner.LogErrorRetry},
+		{"daily request count exceeded, request rate limited", scanner.LogErrorRetry},
+		{"Post \"https://bsc-dataseed.binance.org\": read: connection reset by peer", scanner.LogErrorRetry},
+		{"502 Bad Gateway", scanner.LogErrorRetry},
+		{"header not found", scanner.LogErrorRetry},
+		{"invalid argument 0: hex string without 0x prefix", scanner.LogErrorFatal},
+		{"execution reverted", scanner.LogErrorFatal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, scanner.ClassifyLogError(errors.New(tt.err)), tt.err)
+	}
+	assert.Equal(t, scanner.LogErrorRetry, scanner.ClassifyLogError(fmt.Errorf("read: %w", io.EOF)))
+}
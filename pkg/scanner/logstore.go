@@ -0,0 +1,277 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+)
+
+// Log store keys
+var (
+	// logCheckpointPrefix + query id -> LogCheckpoint (JSON)
+	logCheckpointPrefix = []byte("scan-checkpoint-")
+
+	// logEntryPrefix + query id + block (uint64 big endian) + log index (uint32 big endian) -> storedLog (RLP)
+	logEntryPrefix = []byte("scan-log-")
+)
+
+// LogStore keeps the logs fetched by log scans and a checkpoint per query
+// in a pebble database, so scans survive crashes and reruns
+type LogStore struct {
+	db *pebble.DB
+}
+
+// LogCheckpoint is the progress of one query. A query is identified by its
+// addresses, topics and first block; the logs of [FromBlock, NextBlock) are
+// in the store.
+type LogCheckpoint struct {
+	Addresses []common.Address `json:"addresses"`
+	Topics    [][]common.Hash  `json:"topics"`
+	FromBlock uint64           `json:"fromBlock"`
+	NextBlock uint64           `json:"nextBlock"` // high-water mark: first block not fetched yet
+	Logs      uint64           `json:"logs"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// storedLog is the persisted form of a log
+type storedLog struct {
+	Address     common.Address
+	Topics      []common.Hash
+	Data        []byte
+	BlockNumber uint64
+	TxHash      common.Hash
+	TxIndex     uint64
+	BlockHash   common.Hash
+	Index       uint64
+}
+
+// OpenLogStore opens or creates a log store at path
+func OpenLogStore(path string) (*LogStore, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log store: %w", err)
+	}
+	return &LogStore{db: db}, nil
+}
+
+// OpenMemLogStore creates a log store that lives in memory, for tests and
+// one-off scans
+func OpenMemLogStore() (*LogStore, error) {
+	db, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log store: %w", err)
+	}
+	return &LogStore{db: db}, nil
+}
+
+// Close closes the store
+func (s *LogStore) Close() error {
+	return s.db.Close()
+}
+
+// Checkpoint returns the checkpoint of a query scanned from block from, or
+// nil if it was never scanned
+func (s *LogStore) Checkpoint(q LogQuery, from uint64) (*LogCheckpoint, error) {
+	return s.checkpoint(q.id(from))
+}
+
+func (s *LogStore) checkpoint(id []byte) (*LogCheckpoint, error) {
+	data, closer, err := s.db.Get(checkpointKey(id))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	cp := new(LogCheckpoint)
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// Checkpoints returns the checkpoints of every query in the store
+func (s *LogStore) Checkpoints() ([]*LogCheckpoint, error) {
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: logCheckpointPrefix,
+		UpperBound: prefixEnd(logCheckpointPrefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var checkpoints []*LogCheckpoint
+	for iter.First(); iter.Valid(); iter.Next() {
+		cp := new(LogCheckpoint)
+		if err := json.Unmarshal(iter.Value(), cp); err != nil {
+			return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return checkpoints, iter.Error()
+}
+
+// Reset deletes the stored logs and checkpoint of a query, so the next
+// scan fetches it again from the source
+func (s *LogStore) Reset(q LogQuery, from uint64) error {
+	id := q.id(from)
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	prefix := logEntryKeyPrefix(id)
+	if err := batch.DeleteRange(prefix, prefixEnd(prefix), nil); err != nil {
+		return err
+	}
+	if err := batch.Delete(checkpointKey(id), nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// commit stores the logs of one fetched chunk and moves the checkpoint of
+// query id to next in a single synced write
+func (s *LogStore) commit(id []byte, cp *LogCheckpoint, logs []types.Log, next uint64) error {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	for i := range logs {
+		l := &logs[i]
+		enc, err := rlp.EncodeToBytes(&storedLog{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+			TxIndex:     uint64(l.TxIndex),
+			BlockHash:   l.BlockHash,
+			Index:       uint64(l.Index),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode log: %w", err)
+		}
+		if err := batch.Set(logEntryKey(id, l.BlockNumber, l.Index), enc, nil); err != nil {
+			return err
+		}
+	}
+
+	cp.NextBlock = next
+	cp.Logs += uint64(len(logs))
+	cp.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := batch.Set(checkpointKey(id), data, nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// logs calls fn with the stored logs of query id in blocks [from, to], in
+// chain order
+func (s *LogStore) logs(id []byte, from, to uint64, fn func(types.Log) error) error {
+	prefix := logEntryKeyPrefix(id)
+	upper := prefixEnd(prefix)
+	if to < ^uint64(0) {
+		upper = append(bytes.Clone(prefix), encodeUint64(to+1)...)
+	}
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: append(bytes.Clone(prefix), encodeUint64(from)...),
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		var stored storedLog
+		if err := rlp.DecodeBytes(iter.Value(), &stored); err != nil {
+			return fmt.Errorf("failed to decode stored log: %w", err)
+		}
+		err := fn(types.Log{
+			Address:     stored.Address,
+			Topics:      stored.Topics,
+			Data:        stored.Data,
+			BlockNumber: stored.BlockNumber,
+			TxHash:      stored.TxHash,
+			TxIndex:     uint(stored.TxIndex),
+			BlockHash:   stored.BlockHash,
+			Index:       uint(stored.Index),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// id identifies a query scanned from block from. Addresses and the topics
+// of each position are sorted, so equivalent queries share an id.
+func (q LogQuery) id(from uint64) []byte {
+	addresses := append([]common.Address(nil), q.Addresses...)
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	h := sha256.New()
+	h.Write(encodeUint64(from))
+	h.Write(encodeUint64(uint64(len(addresses))))
+	for _, addr := range addresses {
+		h.Write(addr[:])
+	}
+	h.Write(encodeUint64(uint64(len(q.Topics))))
+	for _, position := range q.Topics {
+		sorted := append([]common.Hash(nil), position...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+		})
+		h.Write(encodeUint64(uint64(len(sorted))))
+		for _, topic := range sorted {
+			h.Write(topic[:])
+		}
+	}
+	return h.Sum(nil)
+}
+
+func checkpointKey(id []byte) []byte {
+	return append(bytes.Clone(logCheckpointPrefix), id...)
+}
+
+func logEntryKeyPrefix(id []byte) []byte {
+	return append(bytes.Clone(logEntryPrefix), id...)
+}
+
+func logEntryKey(id []byte, block uint64, index uint) []byte {
+	key := append(logEntryKeyPrefix(id), encodeUint64(block)...)
+	return binary.BigEndian.AppendUint32(key, uint32(index))
+}
+
+func encodeUint64(n uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, n)
+}
+
+// prefixEnd returns the smallest key greater than every key with the prefix
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
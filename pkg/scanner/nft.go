@@ -6,12 +6,12 @@ import (
 	"log"
 	"math/big"
 	"strings"
-	"time"
 
 	// TODO: Replace with github.com/luxfi/geth when available
 	ethereum "github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 )
 
 func (s *Scanner) scanNFTHolders(contractAddr common.Address, currentBlock uint64) (map[string]*AssetHolder, error) {
@@ -85,47 +85,30 @@ func (s *Scanner) scanNFTHoldersByEvents(contractAddr common.Address, currentBlo
 		fromBlock = 0
 	}
 
-	// Scan in chunks
-	chunkSize := uint64(10000)
+	log.Printf("Scanning blocks %d to %d...", fromBlock, currentBlock)
 
-	for start := fromBlock; start < currentBlock; start += chunkSize {
-		end := start + chunkSize - 1
-		if end > currentBlock {
-			end = currentBlock
-		}
-
-		log.Printf("Scanning blocks %d to %d...", start, end)
-
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(start)),
-			ToBlock:   big.NewInt(int64(end)),
-			Addresses: []common.Address{contractAddr},
-			Topics:    [][]common.Hash{{nftABI.Events["Transfer"].ID}},
-		}
-
-		logs, err := s.client.FilterLogs(context.Background(), query)
-		if err != nil {
-			log.Printf("Warning: Failed to get logs for blocks %d-%d: %v", start, end, err)
-			continue
-		}
+	query := LogQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{nftABI.Events["Transfer"].ID}},
+	}
+	err = s.logScanner().Scan(context.Background(), query, fromBlock, currentBlock, func(vLog types.Log) error {
+		if len(vLog.Topics) >= 4 {
+			// from := common.HexToAddress(vLog.Topics[1].Hex()) // Not used yet
+			to := common.HexToAddress(vLog.Topics[2].Hex())
+			tokenID := new(big.Int).SetBytes(vLog.Topics[3].Bytes())
 
-		for _, vLog := range logs {
-			if len(vLog.Topics) >= 4 {
-				// from := common.HexToAddress(vLog.Topics[1].Hex()) // Not used yet
-				to := common.HexToAddress(vLog.Topics[2].Hex())
-				tokenID := new(big.Int).SetBytes(vLog.Topics[3].Bytes())
-
-				// Update ownership
-				if to == (common.Address{}) {
-					// Token burned
-					delete(nftOwnership, tokenID.String())
-				} else {
-					nftOwnership[tokenID.String()] = to
-				}
+			// Update ownership
+			if to == (common.Address{}) {
+				// Token burned
+				delete(nftOwnership, tokenID.String())
+			} else {
+				nftOwnership[tokenID.String()] = to
 			}
 		}
-
-		time.Sleep(100 * time.Millisecond) // Rate limiting
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Build holders map from ownership
@@ -167,7 +150,7 @@ func (s *Scanner) getNFTTotalSupply(contractAddr common.Address, abi abi.ABI) (*
 		Data: data,
 	}
 
-	result, err := s.client.CallContract(context.Background(), msg, nil)
+	result, err := s.source.CallContract(context.Background(), msg, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +175,7 @@ func (s *Scanner) getNFTOwner(contractAddr common.Address, abi abi.ABI, tokenID
 		Data: data,
 	}
 
-	result, err := s.client.CallContract(context.Background(), msg, nil)
+	result, err := s.source.CallContract(context.Background(), msg, nil)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -217,7 +200,7 @@ func (s *Scanner) getNFTTokenURI(contractAddr common.Address, abi abi.ABI, token
 		Data: data,
 	}
 
-	result, err := s.client.CallContract(context.Background(), msg, nil)
+	result, err := s.source.CallContract(context.Background(), msg, nil)
 	if err != nil {
 		return "", err
 	}
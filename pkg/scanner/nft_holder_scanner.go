@@ -7,11 +7,9 @@ import (
 	"math/big"
 	"strings"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
-	"github.com/luxfi/geth/ethclient"
 )
 
 // NFTHolder represents an NFT holder with their token count
@@ -23,7 +21,7 @@ type NFTHolder struct {
 
 // NFTHolderScanner scans for NFT holders
 type NFTHolderScanner struct {
-	client          *ethclient.Client
+	source          ChainSource
 	contractAddress common.Address
 	config          *NFTHolderScanConfig
 }
@@ -36,11 +34,13 @@ type NFTHolderScanConfig struct {
 	ToBlock         uint64 `json:"toBlock"`
 	ChunkSize       uint64 `json:"chunkSize"`
 	IncludeTokenIDs bool   `json:"includeTokenIds"`
+
+	LogSourceOptions
 }
 
 // NewNFTHolderScanner creates a new NFT holder scanner
 func NewNFTHolderScanner(config *NFTHolderScanConfig) (*NFTHolderScanner, error) {
-	client, err := ethclient.Dial(config.RPC)
+	source, err := sourceOrDial(config.Source, config.RPC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
@@ -50,7 +50,7 @@ func NewNFTHolderScanner(config *NFTHolderScanConfig) (*NFTHolderScanner, error)
 	}
 
 	scanner := &NFTHolderScanner{
-		client:          client,
+		source:          source,
 		contractAddress: common.HexToAddress(config.ContractAddress),
 		config:          config,
 	}
@@ -137,68 +137,46 @@ func (s *NFTHolderScanner) buildOwnershipMap() (map[string][]string, error) {
 
 	// Get latest block if not specified
 	if s.config.ToBlock == 0 {
-		header, err := s.client.HeaderByNumber(ctx, nil)
+		latest, err := latestBlock(ctx, s.source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get latest block: %w", err)
 		}
-		s.config.ToBlock = header.Number.Uint64()
+		s.config.ToBlock = latest
 	}
 
 	log.Printf("Scanning NFT transfers from block %d to %d", s.config.FromBlock, s.config.ToBlock)
 
-	// Scan in chunks
-	totalTransfers := 0
-	for startBlock := s.config.FromBlock; startBlock <= s.config.ToBlock; startBlock += s.config.ChunkSize {
-		endBlock := startBlock + s.config.ChunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{s.contractAddress},
-			Topics: [][]common.Hash{
-				{transferEventSig},
-			},
-		}
+	query := LogQuery{
+		Addresses: []common.Address{s.contractAddress},
+		Topics: [][]common.Hash{
+			{transferEventSig},
+		},
+	}
 
-		logs, err := s.client.FilterLogs(ctx, query)
+	totalTransfers := 0
+	logScanner := s.config.NewLogScanner(s.source, s.config.ChunkSize)
+	err = logScanner.Scan(ctx, query, s.config.FromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		from, to, tokenID, err := s.parseTransferLog(vLog)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
+			log.Printf("Warning: failed to parse log: %v", err)
+			return nil
 		}
 
-		// Process transfers
-		for _, vLog := range logs {
-			from, to, tokenID, err := s.parseTransferLog(vLog)
-			if err != nil {
-				log.Printf("Warning: failed to parse log: %v", err)
-				continue
-			}
-
-			// Remove from previous owner
-			if from != ZeroAddress {
-				s.removeTokenFromOwner(ownership, from, tokenID)
-			}
-
-			// Add to new owner (unless it's a burn)
-			if to != ZeroAddress {
-				s.addTokenToOwner(ownership, to, tokenID)
-			}
-
-			totalTransfers++
+		// Remove from previous owner
+		if from != ZeroAddress {
+			s.removeTokenFromOwner(ownership, from, tokenID)
 		}
 
-		if len(logs) > 0 {
-			log.Printf("Processed %d transfers in blocks %d-%d", len(logs), startBlock, endBlock)
+		// Add to new owner (unless it's a burn)
+		if to != ZeroAddress {
+			s.addTokenToOwner(ownership, to, tokenID)
 		}
 
-		// Progress update
-		if (endBlock-s.config.FromBlock) > 0 && (endBlock-s.config.FromBlock)%50000 == 0 {
-			progress := float64(endBlock-s.config.FromBlock) / float64(s.config.ToBlock-s.config.FromBlock) * 100
-			log.Printf("Scan progress: %.1f%% (block %d/%d)", progress, endBlock, s.config.ToBlock)
-		}
+		totalTransfers++
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("Total transfers processed: %d", totalTransfers)
@@ -319,8 +297,7 @@ func GetHolderDistribution(holders []NFTHolder) map[string]int {
 
 // Close closes the scanner
 func (s *NFTHolderScanner) Close() error {
-	s.client.Close()
-	return nil
+	return s.source.Close()
 }
 
 // ERC721TransferABI is the minimal ABI for Transfer events
@@ -15,7 +15,6 @@ import (
 	ethereum "github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
-	"github.com/luxfi/geth/ethclient"
 )
 
 // Config holds scanner configuration
@@ -27,13 +26,20 @@ type Config struct {
 	OutputPath      string
 	BlockRange      int64
 	ProjectName     string
+	ProjectFile     string // Optional JSON project config replacing the built-in one
 	CrossRefPath    string
+
+	// RequestsPerSecond caps log requests; 0 means 10
+	RequestsPerSecond float64
+
+	// Source, if set, is read instead of dialing RPC; Close closes it
+	Source ChainSource
 }
 
 // Scanner performs external asset scanning
 type Scanner struct {
 	config  Config
-	client  *ethclient.Client
+	source  ChainSource
 	project ProjectConfig
 }
 
@@ -59,13 +65,18 @@ type CrossRefStats struct {
 // New creates a new scanner
 func New(config Config) (*Scanner, error) {
 	// Get project config
+	if config.ProjectFile != "" {
+		if err := LoadProjectConfig(config.ProjectName, config.ProjectFile); err != nil {
+			return nil, err
+		}
+	}
 	projectConfig, exists := projectConfigs[config.ProjectName]
 	if !exists {
 		return nil, fmt.Errorf("unknown project: %s", config.ProjectName)
 	}
 
 	// Set up RPC URL
-	if config.RPC == "" {
+	if config.RPC == "" && config.Source == nil {
 		if defaultRPC, ok := chainRPCs[config.Chain]; ok {
 			config.RPC = defaultRPC
 			log.Printf("Using default RPC for %s", config.Chain)
@@ -75,11 +86,15 @@ func New(config Config) (*Scanner, error) {
 	}
 
 	// Connect to EVM chain
-	client, err := ethclient.Dial(config.RPC)
+	source, err := sourceOrDial(config.Source, config.RPC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to %s: %w", config.Chain, err)
 	}
 
+	if config.RequestsPerSecond == 0 {
+		config.RequestsPerSecond = 10
+	}
+
 	// Set default output path
 	if config.OutputPath == "" {
 		assetType := "assets"
@@ -91,7 +106,7 @@ func New(config Config) (*Scanner, error) {
 
 	return &Scanner{
 		config:  config,
-		client:  client,
+		source:  source,
 		project: projectConfig,
 	}, nil
 }
@@ -102,7 +117,7 @@ func (s *Scanner) Scan() (*Result, error) {
 	contractAddr := common.HexToAddress(s.config.ContractAddress)
 
 	// Get current block
-	currentBlock, err := s.client.BlockNumber(ctx)
+	currentBlock, err := latestBlock(ctx, s.source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block: %w", err)
 	}
@@ -175,6 +190,19 @@ func (s *Scanner) Scan() (*Result, error) {
 	return result, nil
 }
 
+// logScanner returns the log fetcher for holder scans
+func (s *Scanner) logScanner() *LogScanner {
+	return NewLogScanner(s.source, nil, LogScanConfig{
+		ChunkSize:         10000,
+		RequestsPerSecond: s.config.RequestsPerSecond,
+	})
+}
+
+// Close closes the scanner's chain source
+func (s *Scanner) Close() error {
+	return s.source.Close()
+}
+
 func (s *Scanner) detectContractType(contractAddr common.Address) (bool, error) {
 	// Try to call ERC721 totalSupply
 	nftABI, _ := abi.JSON(strings.NewReader(erc721ABI))
@@ -185,12 +213,12 @@ func (s *Scanner) detectContractType(contractAddr common.Address) (bool, error)
 		Data: data,
 	}
 
-	_, err := s.client.CallContract(context.Background(), msg, nil)
+	_, err := s.source.CallContract(context.Background(), msg, nil)
 	if err == nil {
 		// Also check if it has ownerOf function
 		data, _ = nftABI.Pack("ownerOf", big.NewInt(0))
 		msg.Data = data
-		_, err = s.client.CallContract(context.Background(), msg, nil)
+		_, err = s.source.CallContract(context.Background(), msg, nil)
 		if err == nil || strings.Contains(err.Error(), "owner query for nonexistent token") {
 			return true, nil // It's an NFT
 		}
@@ -201,7 +229,7 @@ func (s *Scanner) detectContractType(contractAddr common.Address) (bool, error)
 	data, _ = tokenABI.Pack("decimals")
 	msg.Data = data
 
-	_, err = s.client.CallContract(context.Background(), msg, nil)
+	_, err = s.source.CallContract(context.Background(), msg, nil)
 	if err == nil {
 		return false, nil // It's a token
 	}
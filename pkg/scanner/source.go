@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+)
+
+// ChainSource is the chain data the scanners read. RPCSource reads from a
+// node over JSON-RPC, PebbleSource from a local chain database and
+// FakeSource from memory.
+type ChainSource interface {
+	// FilterLogs returns the logs matching q, in chain order
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+
+	// HeaderByNumber returns the canonical header at number, or the head
+	// header when number is nil
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+
+	// CallContract executes a read-only call at blockNumber, or at the head
+	// when blockNumber is nil
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+
+	// BalanceAt returns the native balance of account at blockNumber, or at
+	// the head when blockNumber is nil
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+
+	// Close releases the source
+	Close() error
+}
+
+// RPCSource reads chain data from a node over JSON-RPC
+type RPCSource struct {
+	*ethclient.Client
+}
+
+var _ ChainSource = (*RPCSource)(nil)
+
+// NewRPCSource connects to the node at url
+func NewRPCSource(url string) (*RPCSource, error) {
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCSource{Client: client}, nil
+}
+
+// Close closes the RPC connection
+func (s *RPCSource) Close() error {
+	s.Client.Close()
+	return nil
+}
+
+// sourceOrDial returns source if set, otherwise an RPC source for url
+func sourceOrDial(source ChainSource, url string) (ChainSource, error) {
+	if source != nil {
+		return source, nil
+	}
+	if url == "" {
+		return nil, fmt.Errorf("no RPC URL or chain source given")
+	}
+	return NewRPCSource(url)
+}
+
+// latestBlock returns the number of the source's head block
+func latestBlock(ctx context.Context, source ChainSource) (uint64, error) {
+	header, err := source.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// matchLog reports whether l is from one of addresses (any if empty) and
+// matches topics: each position lists the accepted topics, an empty
+// position accepts any
+func matchLog(l *types.Log, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, addr := range addresses {
+			if l.Address == addr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(topics) > len(l.Topics) {
+		return false
+	}
+	for i, accepted := range topics {
+		if len(accepted) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range accepted {
+			if l.Topics[i] == topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// queryRange resolves the block range of a filter query. A missing or
+// negative (latest, pending, ...) bound means the head, as over RPC.
+func queryRange(ctx context.Context, source ChainSource, q ethereum.FilterQuery) (from, to uint64, err error) {
+	resolve := func(n *big.Int) (uint64, error) {
+		if n == nil || n.Sign() < 0 {
+			return latestBlock(ctx, source)
+		}
+		return n.Uint64(), nil
+	}
+	if from, err = resolve(q.FromBlock); err != nil {
+		return 0, 0, err
+	}
+	if to, err = resolve(q.ToBlock); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+// errFakeReverted is returned for calls the fake source has no result for
+var errFakeReverted = errors.New("execution reverted")
+
+// FakeSource is an in-memory ChainSource for tests and dry runs. Calls and
+// balances are the same at every block.
+type FakeSource struct {
+	mu       sync.Mutex
+	headers  map[uint64]*types.Header
+	head     uint64
+	logs     []types.Log
+	balances map[common.Address]*big.Int
+	calls    map[string][]byte
+	closed   bool
+}
+
+var _ ChainSource = (*FakeSource)(nil)
+
+// NewFakeSource creates an empty fake chain
+func NewFakeSource() *FakeSource {
+	return &FakeSource{
+		headers:  make(map[uint64]*types.Header),
+		balances: make(map[common.Address]*big.Int),
+		calls:    make(map[string][]byte),
+	}
+}
+
+// AddHeader adds a block header; the highest block is the head
+func (f *FakeSource) AddHeader(header *types.Header) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addHeader(header)
+}
+
+// AddBlock adds an empty block with the given number and timestamp
+func (f *FakeSource) AddBlock(number, time uint64) {
+	f.AddHeader(&types.Header{Number: new(big.Int).SetUint64(number), Time: time})
+}
+
+// AddLogs adds logs, creating an empty block for any block number not
+// added yet
+func (f *FakeSource) AddLogs(logs ...types.Log) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, l := range logs {
+		if _, ok := f.headers[l.BlockNumber]; !ok {
+			f.addHeader(&types.Header{Number: new(big.Int).SetUint64(l.BlockNumber)})
+		}
+		f.logs = append(f.logs, l)
+	}
+	sort.SliceStable(f.logs, func(i, j int) bool {
+		if f.logs[i].BlockNumber != f.logs[j].BlockNumber {
+			return f.logs[i].BlockNumber < f.logs[j].BlockNumber
+		}
+		return f.logs[i].Index < f.logs[j].Index
+	})
+}
+
+// SetBalance sets the native balance of account
+func (f *FakeSource) SetBalance(account common.Address, balance *big.Int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[account] = new(big.Int).Set(balance)
+}
+
+// SetCall sets the result of calling to with data. Calls without a result
+// revert.
+func (f *FakeSource) SetCall(to common.Address, data, result []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[callKey(to, data)] = common.CopyBytes(result)
+}
+
+// Closed reports whether Close was called
+func (f *FakeSource) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// FilterLogs returns the added logs matching q
+func (f *FakeSource) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var from, to uint64
+	if q.BlockHash == nil {
+		var err error
+		if from, to, err = queryRange(ctx, f, q); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var logs []types.Log
+	for i := range f.logs {
+		l := &f.logs[i]
+		if q.BlockHash != nil {
+			if l.BlockHash != *q.BlockHash {
+				continue
+			}
+		} else if l.BlockNumber < from || l.BlockNumber > to {
+			continue
+		}
+		if matchLog(l, q.Addresses, q.Topics) {
+			logs = append(logs, *l)
+		}
+	}
+	return logs, nil
+}
+
+// HeaderByNumber returns an added header, or the head when number is nil
+func (f *FakeSource) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := f.head
+	if number != nil && number.Sign() >= 0 {
+		n = number.Uint64()
+	}
+	header, ok := f.headers[n]
+	if !ok {
+		return nil, fmt.Errorf("block %d: %w", n, ethereum.NotFound)
+	}
+	return types.CopyHeader(header), nil
+}
+
+// CallContract returns the result set with SetCall
+func (f *FakeSource) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil {
+		return nil, errFakeReverted
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result, ok := f.calls[callKey(*msg.To, msg.Data)]
+	if !ok {
+		return nil, errFakeReverted
+	}
+	return common.CopyBytes(result), nil
+}
+
+// BalanceAt returns the balance set with SetBalance, or zero
+func (f *FakeSource) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if balance, ok := f.balances[account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return new(big.Int), nil
+}
+
+// Close marks the source closed
+func (f *FakeSource) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *FakeSource) addHeader(header *types.Header) {
+	n := header.Number.Uint64()
+	f.headers[n] = types.CopyHeader(header)
+	if n > f.head {
+		f.head = n
+	}
+}
+
+func callKey(to common.Address, data []byte) string {
+	return string(to.Bytes()) + string(data)
+}
@@ -0,0 +1,381 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core"
+	gethrawdb "github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/core/vm"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/ethdb/pebble"
+	"github.com/luxfi/geth/params"
+	"github.com/luxfi/geth/triedb"
+	"github.com/luxfi/geth/triedb/hashdb"
+	"github.com/luxfi/geth/triedb/pathdb"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+)
+
+// pebbleCallGas is the gas of calls that do not set one, as geth's RPC cap
+const pebbleCallGas = 50_000_000
+
+// PebbleSource reads chain data straight from a local pebble chain database,
+// such as an archived geth or Subnet-EVM chaindata directory, without a
+// running node.
+//
+// Logs are read from the stored receipts. Blocks are picked with the
+// address index of `index logs` where it covers the range, and with the
+// header blooms elsewhere. Calls and balances run against the stored state
+// with an in-process EVM, so they need the state of the requested block
+// (archive data, or the head of a pruned database).
+type PebbleSource struct {
+	disk   ethdb.Database // Unprefixed, closed by Close
+	db     ethdb.Database
+	triedb *triedb.Database
+	state  state.Database
+	config *params.ChainConfig
+}
+
+var _ ChainSource = (*PebbleSource)(nil)
+
+// NewPebbleSource opens a pebble chain database read-only. namespace is the
+// optional Subnet-EVM key prefix. Blocks moved to the ancient store (the
+// "ancient" directory of a geth chaindata directory) are read from there, and
+// state is read in the scheme the database was written with. Preimages are
+// enabled so state trie keys resolve back to addresses where they were
+// stored.
+func NewPebbleSource(dbPath string, namespace []byte) (*PebbleSource, error) {
+	kv, err := pebble.New(dbPath, 256, 256, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// Opening the freezer creates its directory, so only open one that exists
+	var ancient string
+	if info, err := os.Stat(filepath.Join(dbPath, "ancient")); err == nil && info.IsDir() {
+		ancient = filepath.Join(dbPath, "ancient")
+	}
+	var db ethdb.Database = gethrawdb.NewDatabase(kv)
+	if ancient != "" {
+		db, err = gethrawdb.Open(kv, gethrawdb.OpenOptions{Ancient: ancient, ReadOnly: true})
+		if err != nil {
+			kv.Close()
+			return nil, fmt.Errorf("failed to open ancient store: %w", err)
+		}
+	}
+	disk := db
+	if len(namespace) > 0 {
+		db = gethrawdb.NewTable(disk, string(namespace))
+	}
+
+	trieConfig := &triedb.Config{Preimages: true, HashDB: hashdb.Defaults}
+	if gethrawdb.ReadStateScheme(db) == gethrawdb.PathScheme {
+		trieConfig = &triedb.Config{Preimages: true, PathDB: pathdb.ReadOnly}
+	}
+	tdb := triedb.NewDatabase(db, trieConfig)
+	s := &PebbleSource{
+		disk:   disk,
+		db:     db,
+		triedb: tdb,
+		state:  state.NewDatabase(tdb, nil),
+		config: params.MergedTestChainConfig,
+	}
+
+	// Prefer the chain's own config so calls run under the right fork rules
+	if genesis := gethrawdb.ReadCanonicalHash(db, 0); genesis != (common.Hash{}) {
+		if config := gethrawdb.ReadChainConfig(db, genesis); config != nil {
+			s.config = config
+		}
+	}
+	return s, nil
+}
+
+// Close releases the database
+func (s *PebbleSource) Close() error {
+	s.triedb.Close()
+	return s.disk.Close()
+}
+
+// Database returns the chain database, with the namespace applied
+func (s *PebbleSource) Database() ethdb.Database {
+	return s.db
+}
+
+// TrieDB returns the trie database the state is read through
+func (s *PebbleSource) TrieDB() *triedb.Database {
+	return s.triedb
+}
+
+// StateDatabase returns the state database calls and balances run against
+func (s *PebbleSource) StateDatabase() state.Database {
+	return s.state
+}
+
+// ChainConfig returns the chain's stored config, or the latest fork rules
+// when the database does not record one
+func (s *PebbleSource) ChainConfig() *params.ChainConfig {
+	return s.config
+}
+
+// HeaderByNumber returns the canonical header at number, or the head header
+// when number is nil or negative
+func (s *PebbleSource) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var n uint64
+	if number == nil || number.Sign() < 0 {
+		var err error
+		if n, err = s.headNumber(); err != nil {
+			return nil, err
+		}
+	} else {
+		n = number.Uint64()
+	}
+
+	hash := gethrawdb.ReadCanonicalHash(s.db, n)
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("no canonical block %d: %w", n, ethereum.NotFound)
+	}
+	header := gethrawdb.ReadHeader(s.db, hash, n)
+	if header == nil {
+		return nil, fmt.Errorf("header %d (%s): %w", n, hash.Hex(), ethereum.NotFound)
+	}
+	return header, nil
+}
+
+// headNumber returns the height of the chain head, following the LastBlock,
+// acceptor tip and LastHeader pointers
+func (s *PebbleSource) headNumber() (uint64, error) {
+	for _, key := range [][]byte{rawdb.HeadBlockKey, rawdb.AcceptorTipKey, rawdb.HeadHeaderKey} {
+		data, err := s.db.Get(key)
+		if err != nil || len(data) != common.HashLength {
+			continue
+		}
+		if number, ok := s.headerNumber(common.BytesToHash(data)); ok {
+			return number, nil
+		}
+	}
+	return 0, fmt.Errorf("chain head not found; pass a block number")
+}
+
+// headerNumber returns the block number recorded for a block hash
+func (s *PebbleSource) headerNumber(hash common.Hash) (uint64, bool) {
+	data, err := s.db.Get(rawdb.HeaderNumberKey(hash))
+	if err != nil {
+		return 0, false
+	}
+	return rawdb.DecodeBlockNumber(data)
+}
+
+// FilterLogs returns the logs matching q from the stored receipts
+func (s *PebbleSource) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if q.BlockHash != nil {
+		number, ok := s.headerNumber(*q.BlockHash)
+		if !ok {
+			return nil, fmt.Errorf("block %s: %w", q.BlockHash.Hex(), ethereum.NotFound)
+		}
+		return s.blockLogs(number, *q.BlockHash, q), nil
+	}
+
+	from, to, err := queryRange(ctx, s, q)
+	if err != nil {
+		return nil, err
+	}
+	indexed, covered, err := s.indexedBlocks(q.Addresses, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []types.Log
+	for number := from; number <= to; number++ {
+		inIndex := covered.Contains(number)
+		if inIndex && !indexed[number] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hash := gethrawdb.ReadCanonicalHash(s.db, number)
+		if hash == (common.Hash{}) {
+			return nil, fmt.Errorf("missing canonical hash for block %d", number)
+		}
+		if !inIndex {
+			header := gethrawdb.ReadHeader(s.db, hash, number)
+			if header == nil {
+				return nil, fmt.Errorf("missing header for block %d", number)
+			}
+			if !bloomMatches(header.Bloom, q) {
+				continue
+			}
+		}
+		logs = append(logs, s.blockLogs(number, hash, q)...)
+	}
+	return logs, nil
+}
+
+// indexedBlocks looks up the blocks in [from, to] with logs from addresses
+// in the `index logs` address index. The index only decides for blocks in
+// the covered range it recorded; the range is empty when there is no index
+// or no address to look up.
+func (s *PebbleSource) indexedBlocks(addresses []common.Address, from, to uint64) (map[uint64]bool, rawdb.IndexRange, error) {
+	if len(addresses) == 0 {
+		return nil, rawdb.IndexRange{}, nil
+	}
+	data, err := s.db.Get(rawdb.LogIndexRangeKey)
+	if err != nil {
+		return nil, rawdb.IndexRange{}, nil
+	}
+	covered, ok := rawdb.DecodeIndexRange(data)
+	if !ok || covered.Next <= from || covered.Start > to {
+		return nil, rawdb.IndexRange{}, nil
+	}
+	last := min(to, covered.Next-1)
+
+	blocks := make(map[uint64]bool)
+	for _, addr := range addresses {
+		it := s.db.NewIterator(rawdb.LogIndexAddressPrefix(addr), rawdb.EncodeBlockNumber(max(from, covered.Start)))
+		for it.Next() {
+			key := it.Key()
+			number, _ := rawdb.DecodeBlockNumber(key[len(key)-8:])
+			if number > last {
+				break
+			}
+			blocks[number] = true
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return nil, rawdb.IndexRange{}, fmt.Errorf("failed to read log index: %w", err)
+		}
+	}
+	return blocks, covered, nil
+}
+
+// bloomMatches reports whether a block bloom may contain logs matching q
+func bloomMatches(bloom types.Bloom, q ethereum.FilterQuery) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, addr := range q.Addresses {
+			if bloom.Test(addr.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, accepted := range q.Topics {
+		if len(accepted) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range accepted {
+			if bloom.Test(topic.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// blockLogs returns the logs of a block matching q, with the positions
+// that are not stored in receipts derived as the node would
+func (s *PebbleSource) blockLogs(number uint64, hash common.Hash, q ethereum.FilterQuery) []types.Log {
+	body := gethrawdb.ReadBody(s.db, hash, number)
+	var logs []types.Log
+	logIndex := uint(0)
+	for i, receipt := range gethrawdb.ReadRawReceipts(s.db, hash, number) {
+		var txHash common.Hash
+		if body != nil && i < len(body.Transactions) {
+			txHash = body.Transactions[i].Hash()
+		}
+		for _, l := range receipt.Logs {
+			l.BlockNumber = number
+			l.BlockHash = hash
+			l.TxHash = txHash
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+			if matchLog(l, q.Addresses, q.Topics) {
+				logs = append(logs, *l)
+			}
+		}
+	}
+	return logs
+}
+
+// stateAt opens the state of block number, or of the head when nil
+func (s *PebbleSource) stateAt(ctx context.Context, number *big.Int) (*state.StateDB, *types.Header, error) {
+	header, err := s.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	statedb, err := state.New(header.Root, s.state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("state of block %d not available: %w", header.Number, err)
+	}
+	return statedb, header, nil
+}
+
+// CallContract executes a read-only call with an in-process EVM
+func (s *PebbleSource) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil {
+		return nil, fmt.Errorf("contract creation calls are not supported")
+	}
+	statedb, header, err := s.stateAt(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCtx := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash: func(n uint64) common.Hash {
+			return gethrawdb.ReadCanonicalHash(s.db, n)
+		},
+		Coinbase:    header.Coinbase,
+		GasLimit:    header.GasLimit,
+		BlockNumber: new(big.Int).Set(header.Number),
+		Time:        header.Time,
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+		Random:      &common.Hash{},
+	}
+	gas := msg.Gas
+	if gas == 0 {
+		gas = pebbleCallGas
+	}
+
+	evm := vm.NewEVM(blockCtx, statedb, s.config, vm.Config{NoBaseFee: true})
+	ret, _, err := evm.StaticCall(msg.From, *msg.To, msg.Data, gas)
+	if err != nil {
+		if errors.Is(err, vm.ErrExecutionReverted) {
+			if reason, unpackErr := abi.UnpackRevert(ret); unpackErr == nil {
+				return nil, fmt.Errorf("%w: %s", err, reason)
+			}
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+// BalanceAt reads the native balance of account from the state
+func (s *PebbleSource) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	statedb, _, err := s.stateAt(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetBalance(account).ToBig(), nil
+}
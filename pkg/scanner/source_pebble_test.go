@@ -0,0 +1,179 @@
+package scanner_test
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	gethrawdb "github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/tracing"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/ethdb/pebble"
+	"github.com/luxfi/geth/trie"
+	"github.com/luxfi/geth/triedb"
+	"github.com/luxfi/geth/triedb/hashdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/rawdb"
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+var (
+	pebbleToken = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	pebbleOther = common.HexToAddress("0x1000000000000000000000000000000000000002")
+	pebbleAlice = common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	pebbleBob   = common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+)
+
+// openTestDB opens a writable pebble chain database in dir
+func openTestDB(t *testing.T, dir string) ethdb.Database {
+	kv, err := pebble.New(dir, 16, 16, "", false)
+	require.NoError(t, err)
+	return gethrawdb.NewDatabase(kv)
+}
+
+// writeTestChain writes blocks 0-4 with the geth rawdb writers: token
+// transfers in blocks 1, 2 and 4, another contract's transfer in block 3, and
+// a state in which alice holds 1 ether
+func writeTestChain(t *testing.T, dir string) {
+	db := openTestDB(t, dir)
+	defer db.Close()
+
+	tdb := triedb.NewDatabase(db, &triedb.Config{HashDB: hashdb.Defaults})
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	require.NoError(t, err)
+	statedb.SetBalance(pebbleAlice, uint256.NewInt(1e18), tracing.BalanceChangeUnspecified)
+	root, err := statedb.Commit(0, false, false)
+	require.NoError(t, err)
+	require.NoError(t, tdb.Commit(root, false))
+
+	logs := map[uint64][]types.Log{
+		1: {erc20Transfer(1, 0, pebbleToken, pebbleAlice, pebbleBob, 1)},
+		2: {erc20Transfer(2, 0, pebbleToken, pebbleBob, pebbleAlice, 2)},
+		3: {erc20Transfer(3, 0, pebbleOther, pebbleAlice, pebbleBob, 3)},
+		4: {erc20Transfer(4, 0, pebbleToken, pebbleAlice, pebbleBob, 4)},
+	}
+
+	parent := common.Hash{}
+	for number := uint64(0); number <= 4; number++ {
+		var (
+			txs      []*types.Transaction
+			receipts []*types.Receipt
+		)
+		for i, l := range logs[number] {
+			txs = append(txs, types.NewTx(&types.LegacyTx{Nonce: number*10 + uint64(i), To: &l.Address}))
+			receipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: 21000 * uint64(i+1),
+				Logs:              []*types.Log{{Address: l.Address, Topics: l.Topics, Data: l.Data}},
+			}
+			receipt.Bloom = types.CreateBloom(receipt)
+			receipts = append(receipts, receipt)
+		}
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Root:       root,
+			Time:       1000 + number,
+			GasLimit:   8_000_000,
+			Difficulty: new(big.Int),
+		}
+		block := types.NewBlock(header, &types.Body{Transactions: txs}, receipts, trie.NewStackTrie(nil))
+
+		gethrawdb.WriteBlock(db, block)
+		gethrawdb.WriteReceipts(db, block.Hash(), number, receipts)
+		gethrawdb.WriteCanonicalHash(db, block.Hash(), number)
+		gethrawdb.WriteHeadBlockHash(db, block.Hash())
+		parent = block.Hash()
+	}
+}
+
+// logBlocks returns the block numbers of logs
+func logBlocks(logs []types.Log) []uint64 {
+	blocks := make([]uint64, len(logs))
+	for i, l := range logs {
+		blocks[i] = l.BlockNumber
+	}
+	return blocks
+}
+
+func TestPebbleSource(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeTestChain(t, dir)
+
+	source, err := scanner.NewPebbleSource(dir, nil)
+	require.NoError(t, err)
+
+	head, err := source.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), head.Number.Uint64())
+	header, err := source.HeaderByNumber(ctx, big.NewInt(2))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1002), header.Time)
+	_, err = source.HeaderByNumber(ctx, big.NewInt(5))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	balance, err := source.BalanceAt(ctx, pebbleAlice, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1e18), balance)
+	balance, err = source.BalanceAt(ctx, pebbleBob, big.NewInt(2))
+	require.NoError(t, err)
+	assert.Zero(t, balance.Sign())
+
+	// Without an index, blocks are picked by their header blooms
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(0), Addresses: []common.Address{pebbleToken}}
+	logs, err := source.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 4}, logBlocks(logs))
+	assert.Equal(t, pebbleToken, logs[0].Address)
+	assert.NotEqual(t, common.Hash{}, logs[0].TxHash)
+	assert.Equal(t, header.Hash(), logs[1].BlockHash)
+
+	logs, err = source.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Topics:    [][]common.Hash{{transferTopic}, {common.BytesToHash(pebbleAlice.Bytes())}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 3, 4}, logBlocks(logs))
+	require.NoError(t, source.Close())
+
+	// The index `index logs --from 2 --to 3` records: blocks 2-3 are covered
+	// and block 2 is listed for the token. Block 1 below the covered range and
+	// block 4 above it are still found through their blooms.
+	count := make([]byte, 4)
+	binary.BigEndian.PutUint32(count, 1)
+	db := openTestDB(t, dir)
+	require.NoError(t, db.Put(rawdb.LogIndexKey(pebbleToken, 2), count))
+	require.NoError(t, db.Put(rawdb.LogIndexKey(pebbleOther, 3), count))
+	require.NoError(t, db.Put(rawdb.LogIndexRangeKey, rawdb.IndexRange{Start: 2, Next: 4}.Encode()))
+	require.NoError(t, db.Close())
+
+	source, err = scanner.NewPebbleSource(dir, nil)
+	require.NoError(t, err)
+	logs, err = source.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 4}, logBlocks(logs))
+	require.NoError(t, source.Close())
+
+	// Within the covered range the index decides which blocks are read, so
+	// without its entry block 2 is skipped
+	db = openTestDB(t, dir)
+	require.NoError(t, db.Delete(rawdb.LogIndexKey(pebbleToken, 2)))
+	require.NoError(t, db.Close())
+
+	source, err = scanner.NewPebbleSource(dir, nil)
+	require.NoError(t, err)
+	defer source.Close()
+
+	logs, err = source.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 4}, logBlocks(logs))
+}
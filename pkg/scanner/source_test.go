@@ -0,0 +1,206 @@
+package scanner_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/genesis/pkg/scanner"
+)
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// erc20Transfer builds an ERC20 Transfer log
+func erc20Transfer(block uint64, index uint, token, from, to common.Address, value int64) types.Log {
+	return types.Log{
+		Address:     token,
+		Topics:      []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:        common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		BlockNumber: block,
+		TxHash:      common.BigToHash(big.NewInt(int64(block*1000) + int64(index))),
+		Index:       index,
+	}
+}
+
+// erc721Transfer builds an ERC721 Transfer log with an indexed token ID
+func erc721Transfer(block uint64, index uint, nft, from, to common.Address, tokenID int64) types.Log {
+	l := erc20Transfer(block, index, nft, from, to, 0)
+	l.Topics = append(l.Topics, common.BigToHash(big.NewInt(tokenID)))
+	l.Data = nil
+	return l
+}
+
+func TestFakeSource(t *testing.T) {
+	ctx := context.Background()
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	source := scanner.NewFakeSource()
+	source.AddBlock(10, 1000)
+	source.AddLogs(
+		erc20Transfer(10, 0, token, alice, bob, 5),
+		erc20Transfer(11, 0, other, alice, bob, 6),
+		erc20Transfer(12, 0, token, bob, alice, 7),
+	)
+
+	head, err := source.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), head.Number.Uint64())
+	header, err := source.HeaderByNumber(ctx, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), header.Time)
+	_, err = source.HeaderByNumber(ctx, big.NewInt(13))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	logs, err := source.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{token},
+	})
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	// Topic positions: any sender, only transfers to alice
+	logs, err = source.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		ToBlock:   big.NewInt(12),
+		Topics:    [][]common.Hash{{transferTopic}, nil, {common.BytesToHash(alice.Bytes())}},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint64(12), logs[0].BlockNumber)
+
+	logs, err = source.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(11), ToBlock: big.NewInt(11)})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, other, logs[0].Address)
+
+	source.SetBalance(alice, big.NewInt(42))
+	balance, err := source.BalanceAt(ctx, alice, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), balance.Int64())
+
+	_, err = source.CallContract(ctx, ethereum.CallMsg{To: &token, Data: []byte{1}}, nil)
+	assert.Error(t, err)
+	source.SetCall(token, []byte{1}, []byte{2})
+	result, err := source.CallContract(ctx, ethereum.CallMsg{To: &token, Data: []byte{1}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, result)
+}
+
+func TestTokenBurnScannerWithSource(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	dead := common.HexToAddress(scanner.DeadAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	source := scanner.NewFakeSource()
+	source.AddBlock(100, 1600000000)
+	source.AddLogs(
+		erc20Transfer(100, 0, token, alice, dead, 1000),
+		erc20Transfer(100, 1, token, alice, bob, 50), // not a burn
+		erc20Transfer(150, 0, token, bob, dead, 300),
+		erc20Transfer(160, 0, token, alice, dead, 500),
+	)
+
+	s, err := scanner.NewTokenBurnScanner(&scanner.TokenBurnScanConfig{
+		TokenAddress:     token.Hex(),
+		BurnAddress:      scanner.DeadAddress,
+		FromBlock:        90,
+		ChunkSize:        20,
+		LogSourceOptions: scanner.LogSourceOptions{Source: source},
+	})
+	require.NoError(t, err)
+
+	burns, err := s.ScanBurns()
+	require.NoError(t, err)
+	require.Len(t, burns, 3)
+	assert.Equal(t, alice.Hex(), burns[0].From)
+	assert.Equal(t, "1000", burns[0].Amount)
+	assert.Equal(t, int64(1600000000), burns[0].Timestamp.Unix())
+
+	byAddress, err := s.ScanBurnsByAddress()
+	require.NoError(t, err)
+	assert.Equal(t, "1500", byAddress[strings.ToLower(alice.Hex())].String())
+	assert.Equal(t, "300", byAddress[strings.ToLower(bob.Hex())].String())
+
+	require.NoError(t, s.Close())
+	assert.True(t, source.Closed())
+}
+
+func TestNFTHolderScannerWithSource(t *testing.T) {
+	nft := common.HexToAddress(testNFTAddress)
+	zero := common.Address{}
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	bob := common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+
+	source := scanner.NewFakeSource()
+	source.AddLogs(
+		erc721Transfer(1, 0, nft, zero, alice, 1),
+		erc721Transfer(1, 1, nft, zero, alice, 2),
+		erc721Transfer(2, 0, nft, zero, bob, 3),
+		erc721Transfer(3, 0, nft, alice, bob, 2),
+		erc721Transfer(4, 0, nft, bob, zero, 3), // burn
+	)
+
+	s, err := scanner.NewNFTHolderScanner(&scanner.NFTHolderScanConfig{
+		ContractAddress:  nft.Hex(),
+		IncludeTokenIDs:  true,
+		LogSourceOptions: scanner.LogSourceOptions{Source: source},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	holders, err := s.ScanHolders()
+	require.NoError(t, err)
+	owned := make(map[string][]string)
+	for _, h := range holders {
+		owned[h.Address] = h.TokenIDs
+	}
+	assert.Equal(t, map[string][]string{
+		strings.ToLower(alice.Hex()): {"1"},
+		strings.ToLower(bob.Hex()):   {"2"},
+	}, owned)
+}
+
+func TestCrossChainBalanceScannerWithSource(t *testing.T) {
+	token := common.HexToAddress(testTokenAddress)
+	alice := common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+
+	parsed, err := abi.JSON(strings.NewReader(scanner.ERC20BalanceABI))
+	require.NoError(t, err)
+	data, err := parsed.Pack("balanceOf", alice)
+	require.NoError(t, err)
+
+	chain := func(balance int64) *scanner.FakeSource {
+		source := scanner.NewFakeSource()
+		source.AddBlock(7, 0)
+		source.SetCall(token, data, common.LeftPadBytes(big.NewInt(balance).Bytes(), 32))
+		return source
+	}
+	s, err := scanner.NewCrossChainBalanceScanner(&scanner.CrossChainBalanceScanConfig{
+		Chains: []scanner.ChainConfig{
+			{Name: "bsc", ChainID: 56, TokenAddress: token.Hex(), Source: chain(900)},
+			{Name: "zoo", ChainID: 200200, TokenAddress: token.Hex(), Source: chain(400)},
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	comparisons, err := s.CompareBalances([]string{alice.Hex()})
+	require.NoError(t, err)
+	require.Len(t, comparisons, 1)
+	assert.Equal(t, "900", comparisons[0].SourceBalance)
+	assert.Equal(t, "400", comparisons[0].TargetBalance)
+	assert.Equal(t, "500", comparisons[0].Difference)
+}
@@ -6,12 +6,12 @@ import (
 	"log"
 	"math/big"
 	"strings"
-	"time"
 
 	// TODO: Replace with github.com/luxfi/geth when available
 	ethereum "github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
 )
 
 func (s *Scanner) scanTokenHolders(contractAddr common.Address, currentBlock uint64) (map[string]*AssetHolder, error) {
@@ -29,63 +29,44 @@ func (s *Scanner) scanTokenHolders(contractAddr common.Address, currentBlock uin
 		fromBlock = 0
 	}
 
-	// Scan in chunks to avoid timeout
-	chunkSize := uint64(10000)
+	log.Printf("Scanning blocks %d to %d...", fromBlock, currentBlock)
 
-	for start := fromBlock; start < currentBlock; start += chunkSize {
-		end := start + chunkSize - 1
-		if end > currentBlock {
-			end = currentBlock
-		}
-
-		log.Printf("Scanning blocks %d to %d...", start, end)
-
-		// Create filter query for Transfer events
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(start)),
-			ToBlock:   big.NewInt(int64(end)),
-			Addresses: []common.Address{contractAddr},
-			Topics:    [][]common.Hash{{tokenABI.Events["Transfer"].ID}},
-		}
-
-		// Get logs
-		logs, err := s.client.FilterLogs(context.Background(), query)
-		if err != nil {
-			log.Printf("Warning: Failed to get logs for blocks %d-%d: %v", start, end, err)
-			continue
-		}
-
-		// Process each transfer
-		for _, vLog := range logs {
-			// Extract from and to addresses from topics
-			if len(vLog.Topics) >= 3 {
-				// from := common.HexToAddress(vLog.Topics[1].Hex()) // Not used yet
-				to := common.HexToAddress(vLog.Topics[2].Hex())
-
-				// Skip zero addresses
-				if to != (common.Address{}) {
-					if _, exists := holders[to.Hex()]; !exists {
-						holders[to.Hex()] = &AssetHolder{
-							Address:         to,
-							Balance:         big.NewInt(0),
-							AssetType:       "Token",
-							CollectionType:  "Token",
-							StakingPower:    s.project.StakingPowers["Token"],
-							ChainName:       s.config.Chain,
-							ContractAddress: contractAddr.Hex(),
-							ProjectName:     s.config.ProjectName,
-							LastActivity:    vLog.BlockNumber,
-						}
-					}
-					// Update last activity
-					if vLog.BlockNumber > holders[to.Hex()].LastActivity {
-						holders[to.Hex()].LastActivity = vLog.BlockNumber
+	// Get Transfer events
+	query := LogQuery{
+		Addresses: []common.Address{contractAddr},
+		Topics:    [][]common.Hash{{tokenABI.Events["Transfer"].ID}},
+	}
+	err = s.logScanner().Scan(context.Background(), query, fromBlock, currentBlock, func(vLog types.Log) error {
+		// Extract from and to addresses from topics
+		if len(vLog.Topics) >= 3 {
+			// from := common.HexToAddress(vLog.Topics[1].Hex()) // Not used yet
+			to := common.HexToAddress(vLog.Topics[2].Hex())
+
+			// Skip zero addresses
+			if to != (common.Address{}) {
+				if _, exists := holders[to.Hex()]; !exists {
+					holders[to.Hex()] = &AssetHolder{
+						Address:         to,
+						Balance:         big.NewInt(0),
+						AssetType:       "Token",
+						CollectionType:  "Token",
+						StakingPower:    s.project.StakingPowers["Token"],
+						ChainName:       s.config.Chain,
+						ContractAddress: contractAddr.Hex(),
+						ProjectName:     s.config.ProjectName,
+						LastActivity:    vLog.BlockNumber,
 					}
 				}
+				// Update last activity
+				if vLog.BlockNumber > holders[to.Hex()].LastActivity {
+					holders[to.Hex()].LastActivity = vLog.BlockNumber
+				}
 			}
 		}
-
-		time.Sleep(100 * time.Millisecond) // Rate limiting
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Now get current balances for all holders
@@ -128,7 +109,7 @@ func (s *Scanner) getTokenBalance(contractAddr common.Address, holder common.Add
 		Data: data,
 	}
 
-	result, err := s.client.CallContract(context.Background(), msg, nil)
+	result, err := s.source.CallContract(context.Background(), msg, nil)
 	if err != nil {
 		return nil, err
 	}
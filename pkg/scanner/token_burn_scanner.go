@@ -8,11 +8,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
-	"github.com/luxfi/geth/ethclient"
 )
 
 // TokenBurn represents a token burn transaction
@@ -29,7 +27,7 @@ type TokenBurn struct {
 
 // TokenBurnScanner scans for token burns to specific addresses
 type TokenBurnScanner struct {
-	client       *ethclient.Client
+	source       ChainSource
 	tokenAddress common.Address
 	burnAddress  common.Address
 	config       *TokenBurnScanConfig
@@ -44,6 +42,8 @@ type TokenBurnScanConfig struct {
 	ToBlock      uint64   `json:"toBlock"`
 	ChunkSize    uint64   `json:"chunkSize"`
 	BurnAddresses []string `json:"burnAddresses,omitempty"` // Optional: multiple burn addresses
+
+	LogSourceOptions
 }
 
 // Common burn addresses
@@ -54,7 +54,7 @@ const (
 
 // NewTokenBurnScanner creates a new burn scanner
 func NewTokenBurnScanner(config *TokenBurnScanConfig) (*TokenBurnScanner, error) {
-	client, err := ethclient.Dial(config.RPC)
+	source, err := sourceOrDial(config.Source, config.RPC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
@@ -64,7 +64,7 @@ func NewTokenBurnScanner(config *TokenBurnScanConfig) (*TokenBurnScanner, error)
 	}
 
 	scanner := &TokenBurnScanner{
-		client:       client,
+		source:       source,
 		tokenAddress: common.HexToAddress(config.TokenAddress),
 		burnAddress:  common.HexToAddress(config.BurnAddress),
 		config:       config,
@@ -88,73 +88,57 @@ func (s *TokenBurnScanner) ScanBurns() ([]TokenBurn, error) {
 
 	// Get latest block if not specified
 	if s.config.ToBlock == 0 {
-		header, err := s.client.HeaderByNumber(ctx, nil)
+		latest, err := latestBlock(ctx, s.source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get latest block: %w", err)
 		}
-		s.config.ToBlock = header.Number.Uint64()
+		s.config.ToBlock = latest
 	}
 
-	// Scan in chunks
-	for startBlock := s.config.FromBlock; startBlock <= s.config.ToBlock; startBlock += s.config.ChunkSize {
-		endBlock := startBlock + s.config.ChunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-
-		// Build topics for burn addresses
-		burnAddresses := []common.Address{s.burnAddress}
-		if len(s.config.BurnAddresses) > 0 {
-			for _, addr := range s.config.BurnAddresses {
-				burnAddresses = append(burnAddresses, common.HexToAddress(addr))
-			}
+	// Build topics for burn addresses
+	burnAddresses := []common.Address{s.burnAddress}
+	if len(s.config.BurnAddresses) > 0 {
+		for _, addr := range s.config.BurnAddresses {
+			burnAddresses = append(burnAddresses, common.HexToAddress(addr))
 		}
+	}
 
-		// Convert addresses to hashes for topic filtering
-		burnTopics := []common.Hash{}
-		for _, addr := range burnAddresses {
-			burnTopics = append(burnTopics, common.BytesToHash(addr.Bytes()))
-		}
+	// Convert addresses to hashes for topic filtering
+	burnTopics := []common.Hash{}
+	for _, addr := range burnAddresses {
+		burnTopics = append(burnTopics, common.BytesToHash(addr.Bytes()))
+	}
 
-		// Filter for transfers TO burn addresses
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{s.tokenAddress},
-			Topics: [][]common.Hash{
-				{transferEventSig},
-				nil, // from (any)
-				burnTopics, // to (burn addresses)
-			},
-		}
+	// Filter for transfers TO burn addresses
+	query := LogQuery{
+		Addresses: []common.Address{s.tokenAddress},
+		Topics: [][]common.Hash{
+			{transferEventSig},
+			nil,        // from (any)
+			burnTopics, // to (burn addresses)
+		},
+	}
 
-		logs, err := s.client.FilterLogs(ctx, query)
+	logScanner := s.config.NewLogScanner(s.source, s.config.ChunkSize)
+	err = logScanner.Scan(ctx, query, s.config.FromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		burn, err := s.parseTransferLog(vLog)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
-		}
-
-		// Process burns
-		for _, vLog := range logs {
-			burn, err := s.parseTransferLog(vLog)
-			if err != nil {
-				log.Printf("Warning: failed to parse log: %v", err)
-				continue
-			}
-			burns = append(burns, *burn)
+			log.Printf("Warning: failed to parse log: %v", err)
+			return nil
 		}
-
-		if len(logs) > 0 {
-			log.Printf("Found %d burns in blocks %d-%d", len(logs), startBlock, endBlock)
-		}
-
-		// Progress update
-		if (endBlock-s.config.FromBlock) > 0 && (endBlock-s.config.FromBlock)%50000 == 0 {
-			progress := float64(endBlock-s.config.FromBlock) / float64(s.config.ToBlock-s.config.FromBlock) * 100
-			log.Printf("Scan progress: %.1f%% (block %d/%d)", progress, endBlock, s.config.ToBlock)
+		t, err := logScanner.BlockTime(ctx, vLog.BlockNumber)
+		if err != nil {
+			return err
 		}
+		burn.Timestamp = time.Unix(int64(t), 0)
+		burns = append(burns, *burn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	log.Printf("Found %d burns in blocks %d-%d", len(burns), s.config.FromBlock, s.config.ToBlock)
 	return burns, nil
 }
 
@@ -202,17 +186,9 @@ func (s *TokenBurnScanner) parseTransferLog(vLog types.Log) (*TokenBurn, error)
 		return nil, fmt.Errorf("invalid log data")
 	}
 
-	// Get block details for timestamp
-	ctx := context.Background()
-	block, err := s.client.BlockByNumber(ctx, big.NewInt(int64(vLog.BlockNumber)))
-	if err != nil {
-		log.Printf("Warning: failed to get block %d: %v", vLog.BlockNumber, err)
-	}
-
 	burn := &TokenBurn{
 		TxHash:      vLog.TxHash.Hex(),
 		BlockNumber: vLog.BlockNumber,
-		Timestamp:   time.Unix(int64(block.Time()), 0),
 		From:        from.Hex(),
 		To:          to.Hex(),
 		Amount:      value.String(),
@@ -254,8 +230,7 @@ func GetUniqueBurners(burns []TokenBurn) []string {
 
 // Close closes the scanner
 func (s *TokenBurnScanner) Close() error {
-	s.client.Close()
-	return nil
+	return s.source.Close()
 }
 
 // ERC20TransferABI is the minimal ABI for Transfer events
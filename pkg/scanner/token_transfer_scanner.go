@@ -8,11 +8,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/accounts/abi"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
-	"github.com/luxfi/geth/ethclient"
 )
 
 // TokenTransfer represents a token transfer
@@ -29,7 +27,7 @@ type TokenTransfer struct {
 
 // TokenTransferScanner scans for token transfers to/from specific addresses
 type TokenTransferScanner struct {
-	client       *ethclient.Client
+	source       ChainSource
 	tokenAddress common.Address
 	config       *TokenTransferScanConfig
 }
@@ -43,11 +41,13 @@ type TokenTransferScanConfig struct {
 	ToBlock         uint64   `json:"toBlock"`
 	ChunkSize       uint64   `json:"chunkSize"`
 	Direction       string   `json:"direction"` // "to", "from", or "both"
+
+	LogSourceOptions
 }
 
 // NewTokenTransferScanner creates a new transfer scanner
 func NewTokenTransferScanner(config *TokenTransferScanConfig) (*TokenTransferScanner, error) {
-	client, err := ethclient.Dial(config.RPC)
+	source, err := sourceOrDial(config.Source, config.RPC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
@@ -61,7 +61,7 @@ func NewTokenTransferScanner(config *TokenTransferScanConfig) (*TokenTransferSca
 	}
 
 	scanner := &TokenTransferScanner{
-		client:       client,
+		source:       source,
 		tokenAddress: common.HexToAddress(config.TokenAddress),
 		config:       config,
 	}
@@ -91,49 +91,40 @@ func (s *TokenTransferScanner) scanAllTransfers() ([]TokenTransfer, error) {
 
 	// Get latest block if not specified
 	if s.config.ToBlock == 0 {
-		header, err := s.client.HeaderByNumber(ctx, nil)
+		latest, err := latestBlock(ctx, s.source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get latest block: %w", err)
 		}
-		s.config.ToBlock = header.Number.Uint64()
+		s.config.ToBlock = latest
 	}
 
-	// Scan in chunks
-	for startBlock := s.config.FromBlock; startBlock <= s.config.ToBlock; startBlock += s.config.ChunkSize {
-		endBlock := startBlock + s.config.ChunkSize - 1
-		if endBlock > s.config.ToBlock {
-			endBlock = s.config.ToBlock
-		}
-
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(startBlock)),
-			ToBlock:   big.NewInt(int64(endBlock)),
-			Addresses: []common.Address{s.tokenAddress},
-			Topics: [][]common.Hash{
-				{transferEventSig},
-			},
-		}
+	query := LogQuery{
+		Addresses: []common.Address{s.tokenAddress},
+		Topics: [][]common.Hash{
+			{transferEventSig},
+		},
+	}
 
-		logs, err := s.client.FilterLogs(ctx, query)
+	logScanner := s.config.NewLogScanner(s.source, s.config.ChunkSize)
+	err = logScanner.Scan(ctx, query, s.config.FromBlock, s.config.ToBlock, func(vLog types.Log) error {
+		transfer, err := s.parseTransferLog(vLog)
 		if err != nil {
-			log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-			continue
-		}
-
-		for _, vLog := range logs {
-			transfer, err := s.parseTransferLog(vLog)
-			if err != nil {
-				log.Printf("Warning: failed to parse log: %v", err)
-				continue
-			}
-			transfers = append(transfers, *transfer)
+			log.Printf("Warning: failed to parse log: %v", err)
+			return nil
 		}
-
-		if len(logs) > 0 {
-			log.Printf("Found %d transfers in blocks %d-%d", len(logs), startBlock, endBlock)
+		t, err := logScanner.BlockTime(ctx, vLog.BlockNumber)
+		if err != nil {
+			return err
 		}
+		transfer.Timestamp = time.Unix(int64(t), 0)
+		transfers = append(transfers, *transfer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	log.Printf("Found %d transfers in blocks %d-%d", len(transfers), s.config.FromBlock, s.config.ToBlock)
 	return transfers, nil
 }
 
@@ -160,11 +151,11 @@ func (s *TokenTransferScanner) scanTargetedTransfers() ([]TokenTransfer, error)
 
 	// Get latest block if not specified
 	if s.config.ToBlock == 0 {
-		header, err := s.client.HeaderByNumber(ctx, nil)
+		latest, err := latestBlock(ctx, s.source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get latest block: %w", err)
 		}
-		s.config.ToBlock = header.Number.Uint64()
+		s.config.ToBlock = latest
 	}
 
 	// Scan based on direction
@@ -178,57 +169,50 @@ func (s *TokenTransferScanner) scanTargetedTransfers() ([]TokenTransfer, error)
 		scanTypes = []string{"to", "from"}
 	}
 
+	logScanner := s.config.NewLogScanner(s.source, s.config.ChunkSize)
 	for _, scanType := range scanTypes {
-		for startBlock := s.config.FromBlock; startBlock <= s.config.ToBlock; startBlock += s.config.ChunkSize {
-			endBlock := startBlock + s.config.ChunkSize - 1
-			if endBlock > s.config.ToBlock {
-				endBlock = s.config.ToBlock
+		var query LogQuery
+		if scanType == "to" {
+			query = LogQuery{
+				Addresses: []common.Address{s.tokenAddress},
+				Topics: [][]common.Hash{
+					{transferEventSig},
+					nil,          // from (any)
+					targetHashes, // to (target addresses)
+				},
 			}
-
-			var query ethereum.FilterQuery
-			if scanType == "to" {
-				query = ethereum.FilterQuery{
-					FromBlock: big.NewInt(int64(startBlock)),
-					ToBlock:   big.NewInt(int64(endBlock)),
-					Addresses: []common.Address{s.tokenAddress},
-					Topics: [][]common.Hash{
-						{transferEventSig},
-						nil, // from (any)
-						targetHashes, // to (target addresses)
-					},
-				}
-			} else { // from
-				query = ethereum.FilterQuery{
-					FromBlock: big.NewInt(int64(startBlock)),
-					ToBlock:   big.NewInt(int64(endBlock)),
-					Addresses: []common.Address{s.tokenAddress},
-					Topics: [][]common.Hash{
-						{transferEventSig},
-						targetHashes, // from (target addresses)
-						nil, // to (any)
-					},
-				}
+		} else { // from
+			query = LogQuery{
+				Addresses: []common.Address{s.tokenAddress},
+				Topics: [][]common.Hash{
+					{transferEventSig},
+					targetHashes, // from (target addresses)
+					nil,          // to (any)
+				},
 			}
+		}
 
-			logs, err := s.client.FilterLogs(ctx, query)
+		found := 0
+		err := logScanner.Scan(ctx, query, s.config.FromBlock, s.config.ToBlock, func(vLog types.Log) error {
+			transfer, err := s.parseTransferLog(vLog)
 			if err != nil {
-				log.Printf("Warning: failed to get logs for blocks %d-%d: %v", startBlock, endBlock, err)
-				continue
-			}
-
-			for _, vLog := range logs {
-				transfer, err := s.parseTransferLog(vLog)
-				if err != nil {
-					log.Printf("Warning: failed to parse log: %v", err)
-					continue
-				}
-				transfers = append(transfers, *transfer)
+				log.Printf("Warning: failed to parse log: %v", err)
+				return nil
 			}
-
-			if len(logs) > 0 {
-				log.Printf("Found %d %s transfers in blocks %d-%d", len(logs), scanType, startBlock, endBlock)
+			t, err := logScanner.BlockTime(ctx, vLog.BlockNumber)
+			if err != nil {
+				return err
 			}
+			transfer.Timestamp = time.Unix(int64(t), 0)
+			transfers = append(transfers, *transfer)
+			found++
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+
+		log.Printf("Found %d %s transfers in blocks %d-%d", found, scanType, s.config.FromBlock, s.config.ToBlock)
 	}
 
 	// Remove duplicates if scanning both directions
@@ -308,17 +292,9 @@ func (s *TokenTransferScanner) parseTransferLog(vLog types.Log) (*TokenTransfer,
 		return nil, fmt.Errorf("invalid log data")
 	}
 
-	// Get block details for timestamp
-	ctx := context.Background()
-	block, err := s.client.BlockByNumber(ctx, big.NewInt(int64(vLog.BlockNumber)))
-	if err != nil {
-		log.Printf("Warning: failed to get block %d: %v", vLog.BlockNumber, err)
-	}
-
 	transfer := &TokenTransfer{
 		TxHash:      vLog.TxHash.Hex(),
 		BlockNumber: vLog.BlockNumber,
-		Timestamp:   time.Unix(int64(block.Time()), 0),
 		From:        from.Hex(),
 		To:          to.Hex(),
 		Amount:      value.String(),
@@ -347,6 +323,5 @@ func deduplicateTransfers(transfers []TokenTransfer) []TokenTransfer {
 
 // Close closes the scanner
 func (s *TokenTransferScanner) Close() error {
-	s.client.Close()
-	return nil
+	return s.source.Close()
 }
\ No newline at end of file
@@ -1,7 +1,10 @@
 package scanner
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"os"
 
 	// TODO: Replace with github.com/luxfi/geth when available
 	"github.com/luxfi/geth/common"
@@ -24,10 +27,10 @@ type AssetHolder struct {
 
 // ProjectConfig holds project-specific configurations
 type ProjectConfig struct {
-	TokenContracts  map[string]string   // chain -> contract
-	NFTContracts    map[string]string   // chain -> contract
-	StakingPowers   map[string]*big.Int // NFT type -> staking power
-	TypeIdentifiers map[string][]string // NFT type -> keywords
+	TokenContracts  map[string]string   `json:"tokenContracts,omitempty"`  // chain -> contract
+	NFTContracts    map[string]string   `json:"nftContracts,omitempty"`    // chain -> contract
+	StakingPowers   map[string]*big.Int `json:"stakingPowers,omitempty"`   // NFT type -> staking power
+	TypeIdentifiers map[string][]string `json:"typeIdentifiers,omitempty"` // NFT type -> keywords
 }
 
 // Default project configurations
@@ -97,6 +100,26 @@ var projectConfigs = map[string]ProjectConfig{
 	},
 }
 
+// SetProjectConfig registers or replaces a project configuration
+func SetProjectConfig(name string, config ProjectConfig) {
+	projectConfigs[name] = config
+}
+
+// LoadProjectConfig reads a JSON project configuration, such as the one
+// written by 'analyze contracts --project-output', and registers it
+func LoadProjectConfig(name, filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read project config: %w", err)
+	}
+	var config ProjectConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse project config: %w", err)
+	}
+	SetProjectConfig(name, config)
+	return nil
+}
+
 // Common RPC endpoints
 var chainRPCs = map[string]string{
 	"ethereum":  "https://eth-mainnet.g.alchemy.com/v2/YOUR_API_KEY",